@@ -0,0 +1,173 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/selector"
+)
+
+const (
+	// defaultActivityPeriod is how far back admin activity looks by default, bounding the pages read per server.
+	defaultActivityPeriod = 24 * time.Hour
+	// defaultActivityConcurrency is how many logs admin activity reads in parallel by default.
+	defaultActivityConcurrency = 10
+)
+
+func newActivityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "activity [server-id|uuid]...",
+		Short: "Show the activity log of servers across the panel",
+		Long: "Show the activity logs of servers merged into one, newest first, with the server, actor, event and IP " +
+			"of every entry. Without servers or selectors the logs of all servers are read.\n\n" +
+			"The panel serves activity logs only through the Client API, so this needs the client API key of an " +
+			"administrator, who can read the log of every server.",
+		RunE: runActivity,
+	}
+	cmd.Flags().Duration("since", defaultActivityPeriod, "only show activity of this last period; 0 shows all of it")
+	cmd.Flags().String("event", "", "only show events containing this, e.g. server:power.start")
+	cmd.Flags().String("user", "", "only show activity of this user, by username or email")
+	cmd.Flags().Int("limit", 0, "show only the newest this many entries (default: all)")
+	cmd.Flags().Int("max-concurrency", defaultActivityConcurrency, "maximum servers whose log is read in parallel")
+	selector.AddTargetFlags(cmd, true)
+	completion.RegisterFlagFunc(cmd, "node", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteNodes(toComplete)
+	})
+	completion.RegisterFlagFunc(cmd, "owner", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteUsers(toComplete)
+	})
+	completion.RegisterFlagFunc(cmd, "user", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteUsers(toComplete)
+	})
+	cmd.ValidArgsFunction = adminServerValidArgsFunction
+	carapace.Gen(cmd).PositionalAnyCompletion(carapace.ActionCallback(adminServerCompletionAction))
+	return cmd
+}
+
+func runActivity(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	since, _ := cmd.Flags().GetDuration("since")
+	event, _ := cmd.Flags().GetString("event")
+	user, _ := cmd.Flags().GetString("user")
+	limit, _ := cmd.Flags().GetInt("limit")
+	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+	switch {
+	case since < 0:
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--since must not be negative"))
+	case limit < 0:
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--limit must not be negative"))
+	}
+
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	if len(args) == 0 && fromFile == "" && !selector.Given(cmd) {
+		_ = cmd.Flags().Set("all", "true")
+	}
+	servers, err := getServerUUIDs(ctx, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+	opts := api.ActivityOptions{Event: event}
+	if since > 0 {
+		opts.Since = time.Now().Add(-since)
+	}
+
+	logs := make([][]api.Activity, len(servers))
+	operations := make([]bulk.Operation, len(servers))
+	for i, server := range servers {
+		operations[i] = bulk.Operation{
+			ID:   server,
+			Name: server,
+			Exec: func(opCtx context.Context) error {
+				activity, listErr := client.ListServerActivity(opCtx, server, opts)
+				if listErr != nil {
+					return listErr
+				}
+				for j := range activity {
+					activity[j].Server = server
+				}
+				logs[i] = activity
+				return nil
+			},
+		}
+	}
+	// Reading the logs is a step of the command, not a bulk run to report.
+	results := bulk.NewExecutor(maxConcurrency, true, false).WithRecord(nil).Execute(ctx, operations)
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+			formatter.PrintWarning("Failed to read the activity of %s: %v",
+				output.ServerLabel(result.Operation.ID), result.Error)
+		}
+	}
+
+	activity := mergeActivity(logs, user, limit)
+	if getOutputFormat(cmd).IsJSON() {
+		err = formatter.Print(activity)
+	} else {
+		err = printActivity(formatter, activity)
+	}
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return apierrors.BulkFailure(
+			fmt.Errorf("activity of %d server(s) could not be read", failed), failed, len(servers))
+	}
+	return nil
+}
+
+// mergeActivity merges the logs of servers newest first, keeping the activity of user (by username or
+// email) if given, and the newest limit entries if limit is positive.
+func mergeActivity(logs [][]api.Activity, user string, limit int) []api.Activity {
+	merged := []api.Activity{}
+	for _, serverLog := range logs {
+		for _, entry := range serverLog {
+			if user == "" || strings.EqualFold(entry.Actor, user) || strings.EqualFold(entry.ActorEmail, user) {
+				merged = append(merged, entry)
+			}
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b api.Activity) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+func printActivity(formatter *output.Formatter, activity []api.Activity) error {
+	rows := make([][]string, 0, len(activity))
+	for _, entry := range activity {
+		rows = append(rows, []string{
+			formatter.FormatTime(entry.Timestamp),
+			output.ServerLabel(entry.Server),
+			entry.Actor,
+			entry.Event,
+			entry.IP,
+			entry.Description,
+		})
+	}
+	return formatter.PrintTable([]string{"Time", "Server", "Actor", "Event", "IP", "Description"}, rows)
+}
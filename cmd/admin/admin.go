@@ -14,9 +14,15 @@ func NewAdminCmd() *cobra.Command {
 	}
 
 	// Add subcommands
+	cmd.AddCommand(newActivityCmd())
+	cmd.AddCommand(newDatabaseHostCmd())
+	cmd.AddCommand(newEggCmd())
+	cmd.AddCommand(newFleetCmd())
 	cmd.AddCommand(newNodeCmd())
+	cmd.AddCommand(newRoleCmd())
 	cmd.AddCommand(newServerCmd())
 	cmd.AddCommand(newUserCmd())
+	cmd.AddCommand(newWingsCmd())
 
 	return cmd
 }
@@ -0,0 +1,241 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+const (
+	// maxPort is the highest valid TCP/UDP port.
+	maxPort = 65535
+	// maxPortRange is the most ports the panel accepts in a single range entry.
+	maxPortRange = 1000
+)
+
+func newNodeAllocationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allocation",
+		Short: "Manage node allocations",
+		Long:  "List, create, and delete the IP and port allocations of a node",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list <node-id>",
+		Short: "List allocations of a node",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runNodeAllocationList,
+	}
+	listCmd.Flags().Bool("unassigned", false, "only show allocations not assigned to a server")
+	listCmd.ValidArgsFunction = nodeAllocationValidArgs(false)
+
+	createCmd := &cobra.Command{
+		Use:   "create <node-id>",
+		Short: "Create allocations on a node",
+		Long: "Create allocations on a node for an IP address. --ports takes single ports and inclusive ranges, " +
+			"e.g. --ports 25565-25600,25700. Ranges larger than the panel accepts at once are split automatically.",
+		Args: cobra.ExactArgs(1),
+		RunE: runNodeAllocationCreate,
+	}
+	createCmd.Flags().String("ip", "", "IP address to allocate ports on (required)")
+	createCmd.Flags().String("alias", "", "alias shown instead of the IP address")
+	createCmd.Flags().StringSlice("ports", nil, "ports or port ranges to allocate, e.g. 25565-25600 (required)")
+	_ = createCmd.MarkFlagRequired("ip")
+	_ = createCmd.MarkFlagRequired("ports")
+	createCmd.ValidArgsFunction = nodeAllocationValidArgs(false)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <node-id> <allocation-id>...",
+		Short: "Delete allocations of a node",
+		Long:  "Delete allocations of a node by ID. Allocations assigned to a server cannot be deleted.",
+		Args:  cobra.MinimumNArgs(2), //nolint:mnd // node and at least one allocation
+		RunE:  runNodeAllocationDelete,
+	}
+	deleteCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	deleteCmd.ValidArgsFunction = nodeAllocationValidArgs(true)
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(createCmd)
+	cmd.AddCommand(deleteCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	nodeAction := carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+		completions, err := completion.CompleteNodes(c.Value)
+		if err != nil || len(completions) == 0 {
+			return carapace.ActionValues()
+		}
+		return completion.Action(completions)
+	})
+	carapace.Gen(listCmd).PositionalCompletion(nodeAction)
+	carapace.Gen(createCmd).PositionalCompletion(nodeAction)
+	carapace.Gen(deleteCmd).PositionalCompletion(nodeAction)
+	carapace.Gen(deleteCmd).PositionalAnyCompletion(
+		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completion.CompleteNodeAllocations(c.Args[0], c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}),
+	)
+
+	return cmd
+}
+
+// nodeAllocationValidArgs completes a node, followed by its allocations when withAllocations is set.
+func nodeAllocationValidArgs(
+	withAllocations bool,
+) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var completions []string
+		var err error
+		switch {
+		case len(args) == 0:
+			completions, err = completion.CompleteNodes(toComplete)
+		case withAllocations:
+			completions, err = completion.CompleteNodeAllocations(args[0], toComplete)
+		}
+		if err != nil || len(completions) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func runNodeAllocationList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	nodeID := args[0]
+	unassigned, _ := cmd.Flags().GetBool("unassigned")
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	allocations, err := client.ListNodeAllocations(ctx, nodeID)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	if unassigned {
+		filtered := make([]map[string]any, 0, len(allocations))
+		for _, allocation := range allocations {
+			if assigned, _ := attribute(allocation, "assigned").(bool); !assigned {
+				filtered = append(filtered, allocation)
+			}
+		}
+		allocations = filtered
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	return formatter.PrintWithConfig(allocations, output.ResourceTypeAdminAllocation)
+}
+
+func runNodeAllocationCreate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	nodeID := args[0]
+	ip, _ := cmd.Flags().GetString("ip")
+	alias, _ := cmd.Flags().GetString("alias")
+	portSpecs, _ := cmd.Flags().GetStringSlice("ports")
+
+	ports, count, err := parsePortRanges(portSpecs)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	if err := client.CreateNodeAllocations(ctx, nodeID, ip, alias, ports); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Created %d allocation(s) on %s for node %s", count, ip, nodeID)
+	return nil
+}
+
+func runNodeAllocationDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	nodeID, allocationIDs := args[0], args[1:]
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	shouldContinue, err := output.Confirm(cmd, formatter,
+		fmt.Sprintf("This will delete %d allocation(s) of node %s.", len(allocationIDs), nodeID))
+	if err != nil || !shouldContinue {
+		return err
+	}
+
+	var failed int
+	for _, allocationID := range allocationIDs {
+		if err := client.DeleteNodeAllocation(ctx, nodeID, allocationID); err != nil {
+			formatter.PrintError("Allocation %s: %s", allocationID, apierrors.HandleError(err))
+			failed++
+			continue
+		}
+		formatter.PrintSuccess("Allocation %s deleted", allocationID)
+		formatter.PrintAffected(allocationID)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d allocation(s)", failed, len(allocationIDs))
+	}
+	return nil
+}
+
+// parsePortRanges validates port specs ("25565" or "25565-25600") and returns them as API entries,
+// splitting ranges larger than the panel accepts at once. It also returns the total number of ports.
+func parsePortRanges(specs []string) ([]string, int, error) {
+	var entries []string
+	var count int
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		startStr, endStr, isRange := strings.Cut(spec, "-")
+		if !isRange {
+			endStr = startStr
+		}
+
+		start, startErr := strconv.Atoi(strings.TrimSpace(startStr))
+		end, endErr := strconv.Atoi(strings.TrimSpace(endStr))
+		if startErr != nil || endErr != nil {
+			return nil, 0, fmt.Errorf("invalid port or range: %q (use e.g. 25565 or 25565-25600)", spec)
+		}
+		if start < 1 || end > maxPort || start > end {
+			return nil, 0, fmt.Errorf("invalid port or range: %q (ports must be 1-%d, start <= end)", spec, maxPort)
+		}
+
+		for from := start; from <= end; from += maxPortRange {
+			to := min(from+maxPortRange-1, end)
+			if from == to {
+				entries = append(entries, strconv.Itoa(from))
+			} else {
+				entries = append(entries, fmt.Sprintf("%d-%d", from, to))
+			}
+		}
+		count += end - start + 1
+	}
+
+	if len(entries) == 0 {
+		return nil, 0, errors.New("no ports given")
+	}
+	return entries, count, nil
+}
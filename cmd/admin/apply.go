@@ -0,0 +1,326 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/manifest"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// Actions of an apply plan.
+const (
+	applyCreate    = "create"
+	applyUpdate    = "update"
+	applyUnchanged = "unchanged"
+)
+
+// applyStep is one resource of a manifest and what apply does to converge it.
+type applyStep struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Action  string   `json:"action"`
+	ID      string   `json:"id,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+
+	// resource is the resource as given in the manifest.
+	resource map[string]any
+}
+
+// NewApplyCmd creates the apply command.
+func NewApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply -f <manifest>",
+		Short: "Converge the panel to a declarative manifest",
+		Long: "Read a manifest of users, nodes and servers, diff it against the panel, and create or update " +
+			"resources until the panel matches. Users are matched by username, nodes by name, and servers by " +
+			"external_id or else by name. Resources missing from the manifest are left alone.\n\n" +
+			"Servers are created with every field of the manifest, but only their details (name, description, " +
+			"external_id, user) and build (limits, feature_limits, oom_killer) are updated later. " +
+			"Passwords of users are only used to create them.",
+		Args: cobra.NoArgs,
+		RunE: runApply,
+	}
+	cmd.Flags().StringP("filename", "f", "", "manifest file in YAML or JSON, or - for stdin (required)")
+	_ = cmd.MarkFlagRequired("filename")
+	cmd.Flags().Bool("dry-run", false, "show the plan without changing anything")
+	cmd.Flags().Bool("yes", false, "skip confirmation prompts")
+	addIgnoreCapacityFlag(cmd)
+	return cmd
+}
+
+func runApply(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	path, _ := cmd.Flags().GetString("filename")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	m, err := manifest.Load(path)
+	if err != nil {
+		return apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	planner, err := newApplyPlanner(ctx, client)
+	if err != nil {
+		return err
+	}
+	steps := planner.plan(m)
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	pending := slices.DeleteFunc(slices.Clone(steps), func(s applyStep) bool { return s.Action == applyUnchanged })
+	if dryRun || len(pending) == 0 {
+		if err := printApplyPlan(cmd, formatter, steps); err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			formatter.PrintInfo("The panel already matches the manifest")
+		}
+		return nil
+	}
+
+	if !yes {
+		if err := printApplyPlan(cmd, formatter, steps); err != nil {
+			return err
+		}
+		shouldContinue, err := output.Confirm(cmd, formatter, fmt.Sprintf("This will apply %d change(s).", len(pending)))
+		if err != nil || !shouldContinue {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, step := range pending {
+		if err := planner.apply(cmd, step); err != nil {
+			failed++
+			formatter.PrintError("Failed to %s %s %s: %v", step.Action, step.Kind, step.Name, apierrors.Handle(err))
+			continue
+		}
+		if step.Action == applyCreate {
+			formatter.PrintSuccess("Created %s %s", step.Kind, step.Name)
+		} else {
+			formatter.PrintSuccess("Updated %s %s (%s)", step.Kind, step.Name, strings.Join(step.Changes, ", "))
+		}
+	}
+	if failed > 0 {
+		return apierrors.BulkFailure(fmt.Errorf("%d change(s) failed", failed), failed, len(pending))
+	}
+	return nil
+}
+
+// printApplyPlan prints the plan as a table, or as a list of steps with --json.
+func printApplyPlan(cmd *cobra.Command, formatter *output.Formatter, steps []applyStep) error {
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(steps)
+	}
+	rows := make([][]string, 0, len(steps))
+	for _, step := range steps {
+		rows = append(rows, []string{step.Action, step.Kind, step.Name, strings.Join(step.Changes, ", ")})
+	}
+	return formatter.PrintTable([]string{"Action", "Kind", "Name", "Changes"}, rows)
+}
+
+// applyPlanner diffs a manifest against the users, nodes and servers on the panel and applies the difference.
+type applyPlanner struct {
+	client  *api.ApplicationAPI
+	users   []map[string]any
+	nodes   []map[string]any
+	servers []map[string]any
+	// userIDs maps lowercased usernames and emails to user IDs, including users apply created.
+	userIDs map[string]string
+}
+
+func newApplyPlanner(ctx context.Context, client *api.ApplicationAPI) (*applyPlanner, error) {
+	p := &applyPlanner{client: client, userIDs: map[string]string{}}
+	var err error
+	if p.users, err = client.ListUsers(ctx); err != nil {
+		return nil, apierrors.Handle(err)
+	}
+	if p.nodes, err = client.ListNodes(ctx); err != nil {
+		return nil, apierrors.Handle(err)
+	}
+	if p.servers, err = client.ListServers(ctx); err != nil {
+		return nil, apierrors.Handle(err)
+	}
+	for _, user := range p.users {
+		p.rememberUser(user)
+	}
+	return p, nil
+}
+
+// plan returns the steps converging the panel to m: users first, then nodes, then servers.
+func (p *applyPlanner) plan(m *manifest.Manifest) []applyStep {
+	steps := make([]applyStep, 0, len(m.Users)+len(m.Nodes)+len(m.Servers))
+	for _, user := range m.Users {
+		fields := slices.DeleteFunc(slices.Collect(maps.Keys(user)), func(f string) bool { return f == "password" })
+		steps = append(steps, diffResource(manifest.KindUser, user, p.users, user, fields))
+	}
+	for _, node := range m.Nodes {
+		steps = append(steps, diffResource(manifest.KindNode, node, p.nodes, node, slices.Collect(maps.Keys(node))))
+	}
+	for _, server := range m.Servers {
+		want := maps.Clone(server)
+		// Compare the owner by ID; an owner that apply creates first is a change.
+		if id, err := p.userID(server["user"]); err == nil {
+			if n, convErr := strconv.Atoi(id); convErr == nil {
+				want["user"] = float64(n)
+			}
+		}
+		fields := slices.Concat(api.ServerDetailFields, api.ServerBuildFields)
+		steps = append(steps, diffResource(manifest.KindServer, server, p.servers, want, fields))
+	}
+	return steps
+}
+
+// diffResource finds resource among existing and compares the fields of want against it.
+func diffResource(
+	kind string,
+	resource map[string]any,
+	existing []map[string]any,
+	want map[string]any,
+	fields []string,
+) applyStep {
+	step := applyStep{Kind: kind, Name: manifest.Name(kind, resource), resource: resource}
+	key := manifest.Key(kind, resource)
+	for _, candidate := range existing {
+		if value, _ := attribute(candidate, key).(string); !strings.EqualFold(value, step.Name) {
+			continue
+		}
+		step.ID = convertServerIDToString(attribute(candidate, "id"))
+		step.Changes = manifest.Changes(want, attributesOf(candidate), fields)
+		step.Action = applyUpdate
+		if len(step.Changes) == 0 {
+			step.Action = applyUnchanged
+		}
+		return step
+	}
+	step.Action = applyCreate
+	return step
+}
+
+// apply runs one step of the plan.
+func (p *applyPlanner) apply(cmd *cobra.Command, step applyStep) error {
+	ctx := cmd.Context()
+	switch step.Kind {
+	case manifest.KindUser:
+		if step.Action == applyCreate {
+			user, err := p.client.CreateUser(ctx, step.resource)
+			if err == nil {
+				p.rememberUser(user)
+			}
+			return err
+		}
+		_, err := p.client.UpdateUser(ctx, step.ID, pick(step.resource, step.Changes))
+		return err
+	case manifest.KindNode:
+		if step.Action == applyCreate {
+			_, err := p.client.CreateNode(ctx, step.resource)
+			return err
+		}
+		_, err := p.client.UpdateNode(ctx, step.ID, pick(step.resource, step.Changes))
+		return err
+	default:
+		return p.applyServer(cmd, step)
+	}
+}
+
+// applyServer creates a server, or updates its details and build where they changed.
+func (p *applyPlanner) applyServer(cmd *cobra.Command, step applyStep) error {
+	data := maps.Clone(step.resource)
+	if user, ok := data["user"]; ok {
+		id, err := p.userID(user)
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			return fmt.Errorf("invalid user ID: %s", id)
+		}
+		data["user"] = n
+	}
+
+	if step.Action == applyCreate {
+		if err := guardCapacity(cmd, p.client, data); err != nil {
+			return err
+		}
+		_, err := p.client.CreateServer(cmd.Context(), data)
+		return err
+	}
+
+	if details := pick(data, intersect(step.Changes, api.ServerDetailFields)); len(details) > 0 {
+		if _, err := p.client.UpdateServerDetails(cmd.Context(), step.ID, details); err != nil {
+			return err
+		}
+	}
+	if build := pick(data, intersect(step.Changes, api.ServerBuildFields)); len(build) > 0 {
+		if _, err := p.client.UpdateServerBuild(cmd.Context(), step.ID, build); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userID resolves the owner of a server, given by ID, username or email, to a user ID.
+func (p *applyPlanner) userID(user any) (string, error) {
+	switch v := user.(type) {
+	case float64:
+		return strconv.Itoa(int(v)), nil
+	case string:
+		if _, err := strconv.Atoi(v); err == nil {
+			return v, nil
+		}
+		if id, ok := p.userIDs[strings.ToLower(v)]; ok {
+			return id, nil
+		}
+		return "", apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("user %s not found", v))
+	default:
+		return "", errors.New("server has no user")
+	}
+}
+
+func (p *applyPlanner) rememberUser(user map[string]any) {
+	id := convertServerIDToString(attribute(user, "id"))
+	for _, field := range []string{"username", "email"} {
+		if value, _ := attribute(user, field).(string); value != "" {
+			p.userIDs[strings.ToLower(value)] = id
+		}
+	}
+}
+
+// attributesOf returns the attributes of a resource as listed by the Application API.
+func attributesOf(resource map[string]any) map[string]any {
+	if attrs, ok := resource["attributes"].(map[string]any); ok {
+		return attrs
+	}
+	return resource
+}
+
+// pick returns the given fields of resource.
+func pick(resource map[string]any, fields []string) map[string]any {
+	picked := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if value, ok := resource[field]; ok {
+			picked[field] = value
+		}
+	}
+	return picked
+}
+
+// intersect returns the fields that are also in allowed.
+func intersect(fields, allowed []string) []string {
+	return slices.DeleteFunc(slices.Clone(fields), func(f string) bool { return !slices.Contains(allowed, f) })
+}
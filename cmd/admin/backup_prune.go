@@ -0,0 +1,264 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+const hoursPerDay = 24
+
+// pruneCandidate is a backup considered by the retention policy.
+type pruneCandidate struct {
+	ServerID   string
+	BackupUUID string
+	Name       string
+	CreatedAt  time.Time
+	Locked     bool
+}
+
+func newBackupPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune <server-id|uuid>... --keep <n> --older-than <duration>",
+		Short: "Delete backups outside the retention policy",
+		Long: "Delete backups for server(s) that fall outside the retention policy. " +
+			"The newest --keep backups are always kept, and only backups older than --older-than are deleted. " +
+			"Locked backups are never deleted. Supports bulk operations with --all or --from-file.",
+		RunE: runBackupPrune,
+	}
+	addBulkFlags(cmd)
+	cmd.Flags().Int("keep", 0, "number of newest backups to keep per server")
+	cmd.Flags().String("older-than", "", "only delete backups older than this age (e.g. 30d, 12h)")
+	cmd.ValidArgsFunction = adminServerValidArgs
+	return cmd
+}
+
+func setupBackupPruneCompletion(cmd *cobra.Command) {
+	carapace.Gen(cmd).PositionalAnyCompletion(carapace.ActionCallback(adminServerCompletionAction))
+}
+
+// parseRetentionAge parses a duration that additionally accepts a "d" suffix for days.
+func parseRetentionAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		return time.Duration(n) * hoursPerDay * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	return d, nil
+}
+
+// backupField reads a backup field from the root of the map or from its attributes.
+func backupField(backup map[string]any, key string) any {
+	if val, ok := backup[key]; ok {
+		return val
+	}
+	if attrs, ok := backup["attributes"].(map[string]any); ok {
+		return attrs[key]
+	}
+	return nil
+}
+
+// selectPruneCandidates applies the retention policy to a server's backups.
+func selectPruneCandidates(
+	serverID string,
+	backups []map[string]any,
+	keep int,
+	olderThan time.Duration,
+	now time.Time,
+) []pruneCandidate {
+	candidates := make([]pruneCandidate, 0, len(backups))
+	for _, backup := range backups {
+		uuid, _ := backupField(backup, "uuid").(string)
+		if uuid == "" {
+			continue
+		}
+		createdStr, _ := backupField(backup, "created_at").(string)
+		createdAt, err := time.Parse(time.RFC3339, createdStr)
+		if err != nil {
+			// Without a creation time the backup cannot be ordered, so leave it alone
+			continue
+		}
+		name, _ := backupField(backup, "name").(string)
+		locked, _ := backupField(backup, "is_locked").(bool)
+		candidates = append(candidates, pruneCandidate{
+			ServerID:   serverID,
+			BackupUUID: uuid,
+			Name:       name,
+			CreatedAt:  createdAt,
+			Locked:     locked,
+		})
+	}
+
+	// Newest first
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreatedAt.After(candidates[j].CreatedAt)
+	})
+
+	var selected []pruneCandidate
+	for i, candidate := range candidates {
+		// Locked backups count towards --keep but are never deleted
+		if i < keep || candidate.Locked {
+			continue
+		}
+		if olderThan > 0 && now.Sub(candidate.CreatedAt) < olderThan {
+			continue
+		}
+		selected = append(selected, candidate)
+	}
+	return selected
+}
+
+// planBackupPrune lists backups for every server in parallel and collects the ones to delete.
+func planBackupPrune(
+	ctx context.Context,
+	client *api.ApplicationAPI,
+	uuids []string,
+	keep int,
+	olderThan time.Duration,
+	flags bulkFlags,
+) ([]pruneCandidate, []bulk.Result) {
+	var selected []pruneCandidate
+	var mu sync.Mutex
+	now := time.Now()
+
+	resolution := client.ResolveServerIDs(ctx, uuids)
+	operations := make([]bulk.Operation, len(uuids))
+	for i, uuid := range uuids {
+		operations[i] = bulk.Operation{
+			ID:   uuid,
+			Name: uuid,
+			Exec: func(opCtx context.Context) error {
+				serverID, err := resolution.Lookup(uuid)
+				if err != nil {
+					return err
+				}
+				backups, err := client.ListBackups(opCtx, serverID)
+				if err != nil {
+					return err
+				}
+				candidates := selectPruneCandidates(uuid, backups, keep, olderThan, now)
+				mu.Lock()
+				selected = append(selected, candidates...)
+				mu.Unlock()
+				return nil
+			},
+		}
+	}
+
+	// Listing is a step of planning, not a result of the command.
+	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast).WithRecord(nil)
+	results := executor.Execute(ctx, operations)
+
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].ServerID != selected[j].ServerID {
+			return selected[i].ServerID < selected[j].ServerID
+		}
+		return selected[i].CreatedAt.Before(selected[j].CreatedAt)
+	})
+	return selected, results
+}
+
+func runBackupPrune(cmd *cobra.Command, args []string) error {
+	flags := getBulkFlags(cmd)
+	keep, _ := cmd.Flags().GetInt("keep")
+	olderThanStr, _ := cmd.Flags().GetString("older-than")
+
+	if keep < 0 {
+		return errors.New("--keep must not be negative")
+	}
+	olderThan, err := parseRetentionAge(olderThanStr)
+	if err != nil {
+		return err
+	}
+	if keep == 0 && olderThan == 0 {
+		return errors.New("at least one of --keep or --older-than is required")
+	}
+
+	uuids, err := getBackupCreateServerUUIDs(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	ctx := cmd.Context()
+
+	candidates, listResults := planBackupPrune(ctx, client, uuids, keep, olderThan, flags)
+	for _, result := range listResults {
+		if !result.Success {
+			formatter.PrintError("%s: failed to list backups: %v", output.ServerLabel(result.Operation.ID), result.Error)
+		}
+	}
+	if listSummary := bulk.GetSummary(listResults); listSummary.Failed > 0 && !flags.continueOnError {
+		return fmt.Errorf("failed to list backups for %d server(s)", listSummary.Failed)
+	}
+
+	if len(candidates) == 0 {
+		formatter.PrintInfo("No backups to prune")
+		return nil
+	}
+
+	if flags.dryRun {
+		formatter.PrintInfo("Dry run - would delete %d backup(s):", len(candidates))
+		for _, candidate := range candidates {
+			formatter.PrintInfo("  - %s/%s %s (%s)",
+				candidate.ServerID, candidate.BackupUUID, candidate.Name, candidate.CreatedAt.Format(time.RFC3339))
+		}
+		return nil
+	}
+
+	shouldContinue, err := output.Confirm(cmd, formatter,
+		fmt.Sprintf("This will delete %d backup(s) across %d server(s).", len(candidates), len(uuids)))
+	if err != nil || !shouldContinue {
+		return err
+	}
+
+	operations := make([]bulk.Operation, len(candidates))
+	for i, candidate := range candidates {
+		operations[i] = bulk.Operation{
+			ID:   candidate.ServerID + "/" + candidate.BackupUUID,
+			Name: candidate.Name,
+			Exec: func(opCtx context.Context) error {
+				return client.DeleteBackup(opCtx, candidate.ServerID, candidate.BackupUUID)
+			},
+		}
+	}
+
+	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast).
+		WithRecord(bulk.KeyedRecord("backup"))
+	results := executor.Execute(ctx, operations)
+	summary := bulk.GetSummary(results)
+
+	if getOutputFormat(cmd).IsJSON() {
+		return bulk.PrintBulkJSONWithKey(formatter, results, summary, flags.continueOnError, "backup")
+	}
+
+	printResults(formatter, results, "deleted")
+	return handleSummary(formatter, results, flags.continueOnError)
+}
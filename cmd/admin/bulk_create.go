@@ -0,0 +1,164 @@
+package admin
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// bulkCreateKey is the result key of the resource a bulk create operation created.
+const bulkCreateKey = "name"
+
+// addBulkCreateFlags adds the flags of creating many resources from a file, named by fileFlag. The command
+// must have --data and --dry-run already.
+func addBulkCreateFlags(cmd *cobra.Command, fileFlag, usage string) {
+	cmd.Flags().String(fileFlag, "", usage)
+	const defaultMaxConcurrency = 10
+	cmd.Flags().Int("max-concurrency", defaultMaxConcurrency, "maximum parallel creations with --"+fileFlag)
+	cmd.Flags().Bool("continue-on-error", false, "exit successfully even if some creations fail")
+	cmd.Flags().Bool("fail-fast", false, "stop on the first failed creation")
+	cmd.MarkFlagsMutuallyExclusive(fileFlag, "data")
+	// A dry run shows the request of a single create.
+	cmd.MarkFlagsMutuallyExclusive(fileFlag, "dry-run")
+}
+
+// runBulkCreate creates every item through the bulk executor and reports the result of each one.
+// Items are named by the value of nameField, or by their position in the file.
+func runBulkCreate(
+	cmd *cobra.Command,
+	kind string,
+	items []map[string]any,
+	nameField string,
+	create func(context.Context, *api.ApplicationAPI, map[string]any) error,
+) error {
+	ctx := cmd.Context()
+	flags := getBulkFlags(cmd)
+	if len(items) == 0 {
+		return apierrors.WithExitCode(apierrors.ExitValidation, fmt.Errorf("no %ss to create", kind))
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	operations := make([]bulk.Operation, len(items))
+	for i, item := range items {
+		name, _ := item[nameField].(string)
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		operations[i] = bulk.Operation{
+			ID:   name,
+			Name: name,
+			Exec: func(opCtx context.Context) error { return create(opCtx, client, item) },
+		}
+	}
+
+	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast).
+		WithRecord(bulk.KeyedRecord(bulkCreateKey))
+	results := executor.Execute(ctx, operations)
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		return bulk.PrintBulkJSONWithKey(formatter, results, bulk.GetSummary(results), flags.continueOnError,
+			bulkCreateKey)
+	}
+	for _, result := range results {
+		if result.Success {
+			formatter.PrintSuccess("%s: created", result.Operation.ID)
+		} else {
+			formatter.PrintError("%s: %v", result.Operation.ID, result.Error)
+		}
+	}
+	return handleSummary(formatter, results, flags.continueOnError)
+}
+
+// readCreateFile reads the JSON array of create payloads in path, or stdin for "-".
+func readCreateFile(path string) ([]map[string]any, error) {
+	content, err := readCreateInput(path)
+	if err != nil {
+		return nil, err
+	}
+	var items []map[string]any
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("failed to parse %s: must be a JSON array of objects: %w", path, err))
+	}
+	return items, nil
+}
+
+// readCreateCSV reads create payloads from the CSV file in path, or stdin for "-". The header row names
+// the field of each column; empty cells are left out, and columns in boolFields read as booleans.
+func readCreateCSV(path string, boolFields ...string) ([]map[string]any, error) {
+	content, err := readCreateInput(path)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	if err != nil {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation, fmt.Errorf("failed to parse %s: %w", path, err))
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	for i, field := range header {
+		header[i] = strings.TrimSpace(field)
+	}
+	items := make([]map[string]any, 0, len(rows)-1)
+	for n := 1; n < len(rows); n++ {
+		row := rows[n]
+		item := make(map[string]any, len(row))
+		for i, value := range row {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			if !slices.Contains(boolFields, header[i]) {
+				item[header[i]] = value
+				continue
+			}
+			b, parseErr := strconv.ParseBool(value)
+			if parseErr != nil {
+				return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+					fmt.Errorf("%s line %d: %s must be true or false, not %q", path, n+1, header[i], value))
+			}
+			item[header[i]] = b
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func readCreateInput(path string) ([]byte, error) {
+	if path == "-" {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from stdin: %w", err)
+		}
+		return content, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("file %s not found", path))
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content, nil
+}
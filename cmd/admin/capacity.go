@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/capacity"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// addIgnoreCapacityFlag adds the flag that skips the node capacity pre-flight check.
+func addIgnoreCapacityFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("ignore-capacity", false, "create the server even if its node lacks free memory or disk")
+}
+
+// guardCapacity fails with a capacity report when the node of a new server cannot fit it,
+// unless --ignore-capacity is set.
+func guardCapacity(cmd *cobra.Command, client *api.ApplicationAPI, data map[string]any) error {
+	ctx := cmd.Context()
+	if ignore, _ := cmd.Flags().GetBool("ignore-capacity"); ignore {
+		return nil
+	}
+
+	report, err := checkNodeCapacity(ctx, client, data)
+	if err != nil {
+		return fmt.Errorf("capacity check failed (use --ignore-capacity to skip it): %w", err)
+	}
+	if report == nil || report.Fits() {
+		return nil
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		_ = formatter.Print(report)
+	} else {
+		formatter.PrintInfo("Capacity of node %s:", report.Node)
+		_ = formatter.PrintTable(report.Headers(), report.Rows())
+	}
+	return fmt.Errorf("%w (use --ignore-capacity to create it anyway)", report.Err())
+}
+
+// fitCapacity fails like guardCapacity, but without printing the capacity report, for servers created in bulk.
+func fitCapacity(cmd *cobra.Command, client *api.ApplicationAPI, data map[string]any) error {
+	if ignore, _ := cmd.Flags().GetBool("ignore-capacity"); ignore {
+		return nil
+	}
+	report, err := checkNodeCapacity(cmd.Context(), client, data)
+	if err != nil {
+		return fmt.Errorf("capacity check failed (use --ignore-capacity to skip it): %w", err)
+	}
+	if report == nil || report.Fits() {
+		return nil
+	}
+	return fmt.Errorf("%w (use --ignore-capacity to create it anyway)", report.Err())
+}
+
+// checkNodeCapacity builds the capacity report of the node that will host a new server.
+// Only servers given an explicit default allocation are checked: with deploy rules the panel
+// picks the node itself and already skips nodes without room. Returns nil if there is nothing to check.
+func checkNodeCapacity(ctx context.Context, client *api.ApplicationAPI, data map[string]any) (*capacity.Report, error) {
+	allocation, _ := data["allocation"].(map[string]any)
+	allocationID := convertServerIDToString(allocation["default"])
+	if allocationID == "" {
+		return nil, nil //nolint:nilnil // nothing to check
+	}
+	limits, _ := data["limits"].(map[string]any)
+
+	node, err := findAllocationNode(ctx, client, allocationID)
+	if err != nil {
+		return nil, err
+	}
+	nodeID := convertServerIDToString(attribute(node, "id"))
+
+	memoryAllocated, diskAllocated, err := allocatedOnNode(ctx, client, node, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := strconv.Atoi(nodeID)
+	name, _ := attribute(node, "name").(string)
+	return &capacity.Report{
+		NodeID: id,
+		Node:   name,
+		Resources: []capacity.Resource{
+			{
+				Name:         "memory",
+				Total:        toInt64(attribute(node, "memory")),
+				Allocated:    memoryAllocated,
+				Requested:    toInt64(limits["memory"]),
+				Overallocate: toInt64(attribute(node, "memory_overallocate")),
+			},
+			{
+				Name:         "disk",
+				Total:        toInt64(attribute(node, "disk")),
+				Allocated:    diskAllocated,
+				Requested:    toInt64(limits["disk"]),
+				Overallocate: toInt64(attribute(node, "disk_overallocate")),
+			},
+		},
+	}, nil
+}
+
+// findAllocationNode returns the node that owns an allocation.
+func findAllocationNode(ctx context.Context, client *api.ApplicationAPI, allocationID string) (map[string]any, error) {
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	for _, node := range nodes {
+		nodeID := convertServerIDToString(attribute(node, "id"))
+		allocations, err := client.ListNodeAllocations(ctx, nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list allocations of node %s: %w", nodeID, err)
+		}
+		for _, allocation := range allocations {
+			if convertServerIDToString(attribute(allocation, "id")) == allocationID {
+				return node, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("allocation %s not found on any node", allocationID)
+}
+
+// allocatedOnNode returns the memory and disk already allocated on a node, in MiB.
+// The panel reports this on the node itself; older panels don't, so it is summed from the servers instead.
+func allocatedOnNode(
+	ctx context.Context,
+	client *api.ApplicationAPI,
+	node map[string]any,
+	nodeID string,
+) (int64, int64, error) {
+	if allocated, ok := attribute(node, "allocated_resources").(map[string]any); ok {
+		return toInt64(allocated["memory"]), toInt64(allocated["disk"]), nil
+	}
+
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	var memory, disk int64
+	for _, server := range servers {
+		if convertServerIDToString(attribute(server, "node")) != nodeID {
+			continue
+		}
+		limits, _ := attribute(server, "limits").(map[string]any)
+		memory += toInt64(limits["memory"])
+		disk += toInt64(limits["disk"])
+	}
+	return memory, disk, nil
+}
+
+// attribute reads a field from the root of a resource or from its attributes.
+func attribute(resource map[string]any, key string) any {
+	if val, ok := resource[key]; ok {
+		return val
+	}
+	if attrs, ok := resource["attributes"].(map[string]any); ok {
+		return attrs[key]
+	}
+	return nil
+}
+
+// toInt64 converts a JSON number (or numeric string) to int64, treating anything else as 0.
+func toInt64(val any) int64 {
+	switch v := val.(type) {
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case string:
+		parsed, _ := strconv.ParseInt(v, 10, 64)
+		return parsed
+	default:
+		return 0
+	}
+}
@@ -1,15 +1,19 @@
 package admin
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/carapace-sh/carapace"
 	"github.com/spf13/cobra"
 
 	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	"go.lostcrafters.com/pelicanctl/internal/diff"
 	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
 	"go.lostcrafters.com/pelicanctl/internal/output"
 )
@@ -29,19 +33,67 @@ func getOutputFormat(cmd *cobra.Command) output.OutputFormat {
 	if jsonFlag {
 		return output.OutputFormatJSON
 	}
+	// --output was validated by the root command, which also set up a go-template.
+	outputFlag, _ := cmd.Root().PersistentFlags().GetString("output")
+	if format, _, err := output.ParseOutputFlag(outputFlag); err == nil {
+		return format
+	}
 	return output.OutputFormatTable
 }
 
+// addListFlags adds the pagination, filter, and include flags of a list command.
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("page", 0, "fetch only this page (starting at 1) instead of every page")
+	cmd.Flags().Int("per-page", 0, "number of items per page requested from the panel (default: panel default)")
+	cmd.Flags().Bool("all-pages", true, "follow every page of the list (--all-pages=false fetches only the first page)")
+	cmd.Flags().StringArray("filter", nil, "filter the list on the panel by attribute=value (repeatable)")
+	cmd.Flags().StringSlice("include", nil, "relationships the panel includes with every item (comma-separated)")
+	cmd.Flags().Int("max-concurrency", api.DefaultPageConcurrency, "maximum pages of the list fetched in parallel")
+}
+
+// getListOptions reads the list flags added by addListFlags.
+func getListOptions(cmd *cobra.Command) (api.ListOptions, error) {
+	page, _ := cmd.Flags().GetInt("page")
+	perPage, _ := cmd.Flags().GetInt("per-page")
+	allPages, _ := cmd.Flags().GetBool("all-pages")
+
+	if page < 0 || perPage < 0 {
+		return api.ListOptions{}, errors.New("--page and --per-page must not be negative")
+	}
+	if page > 0 && allPages && cmd.Flags().Changed("all-pages") {
+		return api.ListOptions{}, errors.New("--page cannot be combined with --all-pages")
+	}
+	if page == 0 && !allPages {
+		page = 1
+	}
+
+	filters, _ := cmd.Flags().GetStringArray("filter")
+	include, _ := cmd.Flags().GetStringSlice("include")
+	opts := api.ListOptions{Page: page, PerPage: perPage, Include: include}
+	for _, filter := range filters {
+		attribute, value, ok := strings.Cut(filter, "=")
+		if !ok || attribute == "" {
+			return api.ListOptions{}, fmt.Errorf("invalid filter %q (expected attribute=value)", filter)
+		}
+		if opts.Filter == nil {
+			opts.Filter = make(map[string]string)
+		}
+		opts.Filter[attribute] = value
+	}
+	return opts, nil
+}
+
 // runListCommand handles the common pattern for list operations.
 func runListCommand(
 	cmd *cobra.Command,
 	client *api.ApplicationAPI,
-	listFunc func(*api.ApplicationAPI) (any, error),
+	listFunc func(context.Context, *api.ApplicationAPI) (any, error),
 	resourceType output.ResourceType,
 ) error {
-	items, err := listFunc(client)
+	ctx := cmd.Context()
+	items, err := listFunc(ctx, client)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
@@ -53,11 +105,12 @@ func runViewCommand(
 	cmd *cobra.Command,
 	id string,
 	client *api.ApplicationAPI,
-	viewFunc func(*api.ApplicationAPI, string) (any, error),
+	viewFunc func(context.Context, *api.ApplicationAPI, string) (any, error),
 ) error {
-	item, err := viewFunc(client, id)
+	ctx := cmd.Context()
+	item, err := viewFunc(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
@@ -66,7 +119,7 @@ func runViewCommand(
 
 // makeListRunE creates a RunE function that handles client creation and list operations.
 func makeListRunE(
-	listFunc func(*api.ApplicationAPI) (any, error),
+	listFunc func(context.Context, *api.ApplicationAPI) (any, error),
 	resourceType output.ResourceType,
 ) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
@@ -78,8 +131,30 @@ func makeListRunE(
 	}
 }
 
+// makeListOptionsRunE creates a RunE function for list operations that honor the list flags.
+func makeListOptionsRunE(
+	listFunc func(context.Context, *api.ApplicationAPI, api.ListOptions) (any, error),
+	resourceType output.ResourceType,
+) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		opts, err := getListOptions(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := api.NewApplicationAPI()
+		if err != nil {
+			return err
+		}
+		return runListCommand(cmd, client, func(ctx context.Context, c *api.ApplicationAPI) (any, error) {
+			return listFunc(ctx, c, opts)
+		}, resourceType)
+	}
+}
+
 // makeViewRunE creates a RunE function that handles client creation and view operations.
-func makeViewRunE(viewFunc func(*api.ApplicationAPI, string) (any, error)) func(*cobra.Command, []string) error {
+func makeViewRunE(
+	viewFunc func(context.Context, *api.ApplicationAPI, string) (any, error),
+) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		client, err := api.NewApplicationAPI()
@@ -91,11 +166,13 @@ func makeViewRunE(viewFunc func(*api.ApplicationAPI, string) (any, error)) func(
 }
 
 type resourceCommandConfig struct {
-	name         string
-	short        string
-	long         string
-	listShort    string
-	listRunE     func(*cobra.Command, []string) error
+	name      string
+	short     string
+	long      string
+	listShort string
+	listRunE  func(*cobra.Command, []string) error
+	// listFlags adds the pagination, filter, and include flags to the list command.
+	listFlags    bool
 	viewUse      string
 	viewShort    string
 	viewRunE     func(*cobra.Command, []string) error
@@ -103,24 +180,52 @@ type resourceCommandConfig struct {
 }
 
 type crudResourceConfig struct {
-	name          string
-	short         string
-	long          string
-	listShort     string
-	listFunc      func(*api.ApplicationAPI) (any, error)
-	viewUse       string
-	viewShort     string
-	viewFunc      func(*api.ApplicationAPI, string) (any, error)
-	createFunc    func(*api.ApplicationAPI, map[string]any) (map[string]any, error)
-	updateFunc    func(*api.ApplicationAPI, string) (map[string]any, error)
-	deleteFunc    func(*api.ApplicationAPI, string) error
-	completeFunc  func(string) ([]string, error)
-	resourceType  output.ResourceType
-	createMessage string
-	updateMessage string
-	deleteMessage string
-	createLong    string
-	dataFlagHelp  string
+	name      string
+	short     string
+	long      string
+	listShort string
+	listFunc  func(context.Context, *api.ApplicationAPI) (any, error)
+	// listOptionsFunc, when set, replaces listFunc and adds the list flags (pages, filters, includes) to list.
+	listOptionsFunc func(context.Context, *api.ApplicationAPI, api.ListOptions) (any, error)
+	viewUse         string
+	viewShort       string
+	viewFunc        func(context.Context, *api.ApplicationAPI, string) (any, error)
+	createFunc      func(context.Context, *api.ApplicationAPI, map[string]any) (map[string]any, error)
+	// createFields are flags that set single fields of a create request; createDefaults fill in the
+	// fields the panel requires when the request is built from them.
+	createFields   []updateField
+	createDefaults map[string]any
+	updateFunc     func(context.Context, *api.ApplicationAPI, string) (map[string]any, error)
+	// updateDataFunc, when set, replaces updateFunc and receives the changed fields from --data, stdin,
+	// or the updateFields flags.
+	updateDataFunc func(context.Context, *api.ApplicationAPI, string, map[string]any) (map[string]any, error)
+	updateFields   []updateField
+	deleteFunc     func(context.Context, *api.ApplicationAPI, string) error
+	completeFunc   func(string) ([]string, error)
+	resourceType   output.ResourceType
+	createMessage  string
+	updateMessage  string
+	deleteMessage  string
+	createLong     string
+	dataFlagHelp   string
+}
+
+// updateFieldKind is the type of value a field flag takes.
+type updateFieldKind int
+
+const (
+	updateFieldString updateFieldKind = iota
+	updateFieldInt
+	updateFieldBool
+	updateFieldStringSlice
+)
+
+// updateField is a flag of a create or update command that sets one field of the request.
+type updateField struct {
+	flag  string
+	field string
+	kind  updateFieldKind
+	usage string
 }
 
 func newResourceCmd(config resourceCommandConfig) *cobra.Command {
@@ -135,6 +240,9 @@ func newResourceCmd(config resourceCommandConfig) *cobra.Command {
 		Short: config.listShort,
 		RunE:  config.listRunE,
 	}
+	if config.listFlags {
+		addListFlags(listCmd)
+	}
 
 	viewCmd := &cobra.Command{
 		Use:   config.viewUse,
@@ -165,7 +273,7 @@ func newResourceCmd(config resourceCommandConfig) *cobra.Command {
 				if err != nil || len(completions) == 0 {
 					return carapace.ActionValues()
 				}
-				return carapace.ActionValues(completions...)
+				return completion.Action(completions)
 			}),
 		)
 	}
@@ -226,25 +334,99 @@ func parseJSONData(cmd *cobra.Command) (map[string]any, error) {
 	return result, nil
 }
 
-// runCreateCommand handles the common pattern for create operations.
+// parseFieldData collects the fields of a create or update request from the per-field flags and from --data or
+// stdin. JSON data is only required when no per-field flag is set; per-field flags override the same fields in it.
+func parseFieldData(cmd *cobra.Command, fields []updateField) (map[string]any, error) {
+	changed := map[string]any{}
+	for _, field := range fields {
+		if !cmd.Flags().Changed(field.flag) {
+			continue
+		}
+		switch field.kind {
+		case updateFieldInt:
+			changed[field.field], _ = cmd.Flags().GetInt(field.flag)
+		case updateFieldBool:
+			changed[field.field], _ = cmd.Flags().GetBool(field.flag)
+		case updateFieldStringSlice:
+			changed[field.field], _ = cmd.Flags().GetStringSlice(field.flag)
+		default:
+			changed[field.field], _ = cmd.Flags().GetString(field.flag)
+		}
+	}
+
+	dataFlag, _ := cmd.Flags().GetString("data")
+	if dataFlag == "" && len(changed) > 0 {
+		return changed, nil
+	}
+
+	data, err := parseJSONData(cmd)
+	if err != nil {
+		return nil, err
+	}
+	for key, val := range changed {
+		data[key] = val
+	}
+	return data, nil
+}
+
+// addFieldFlags adds the per-field flags of a create or update command.
+func addFieldFlags(cmd *cobra.Command, fields []updateField) {
+	for _, field := range fields {
+		switch field.kind {
+		case updateFieldInt:
+			cmd.Flags().Int(field.flag, 0, field.usage)
+		case updateFieldBool:
+			cmd.Flags().Bool(field.flag, false, field.usage)
+		case updateFieldStringSlice:
+			cmd.Flags().StringSlice(field.flag, nil, field.usage)
+		default:
+			cmd.Flags().String(field.flag, "", field.usage)
+		}
+	}
+}
+
+// fieldFlagsChanged reports whether any of the per-field flags was given.
+func fieldFlagsChanged(cmd *cobra.Command, fields []updateField) bool {
+	for _, field := range fields {
+		if cmd.Flags().Changed(field.flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// runCreateCommand handles the common pattern for create operations. Requests built from field flags
+// get defaults for the fields they leave out.
 func runCreateCommand(
 	cmd *cobra.Command,
-	createFunc func(*api.ApplicationAPI, map[string]any) (map[string]any, error),
+	fields []updateField,
+	defaults map[string]any,
+	createFunc func(context.Context, *api.ApplicationAPI, map[string]any) (map[string]any, error),
 	successMessage string,
 ) error {
-	data, err := parseJSONData(cmd)
+	data, err := parseFieldData(cmd, fields)
 	if err != nil {
 		return err
 	}
+	if fieldFlagsChanged(cmd, fields) {
+		for key, val := range defaults {
+			if _, ok := data[key]; !ok {
+				data[key] = val
+			}
+		}
+	}
 
 	client, err := api.NewApplicationAPI()
 	if err != nil {
 		return err
 	}
 
-	result, err := createFunc(client, data)
+	result, err := createFunc(dryRunContext(cmd), client, data)
+	if req, ok := api.AsDryRun(err); ok {
+		return printDryRun(cmd, req)
+	}
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
@@ -256,9 +438,10 @@ func runCreateCommand(
 func runUpdateCommand(
 	cmd *cobra.Command,
 	args []string,
-	updateFunc func(*api.ApplicationAPI, string) (map[string]any, error),
+	updateFunc func(context.Context, *api.ApplicationAPI, string) (map[string]any, error),
 	successMessage string,
 ) error {
+	ctx := cmd.Context()
 	id := args[0]
 
 	client, err := api.NewApplicationAPI()
@@ -266,9 +449,9 @@ func runUpdateCommand(
 		return err
 	}
 
-	result, err := updateFunc(client, id)
+	result, err := updateFunc(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
@@ -280,7 +463,7 @@ func runUpdateCommand(
 func runDeleteCommand(
 	cmd *cobra.Command,
 	args []string,
-	deleteFunc func(*api.ApplicationAPI, string) error,
+	deleteFunc func(context.Context, *api.ApplicationAPI, string) error,
 	successMessage string,
 ) error {
 	id := args[0]
@@ -290,28 +473,54 @@ func runDeleteCommand(
 		return err
 	}
 
-	if deleteErr := deleteFunc(client, id); deleteErr != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(deleteErr))
+	deleteErr := deleteFunc(dryRunContext(cmd), client, id)
+	if req, ok := api.AsDryRun(deleteErr); ok {
+		return printDryRun(cmd, req)
+	}
+	if deleteErr != nil {
+		return apierrors.Handle(deleteErr)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 	formatter.PrintSuccess("%s", successMessage)
+	formatter.PrintAffected(id)
 	return nil
 }
 
+// addDryRunFlag adds --dry-run to a create or delete command.
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "print the request that would be sent without sending it")
+}
+
+// dryRunContext returns the context for the requests of a command, in which requests that change anything
+// are stopped before they are sent with --dry-run.
+func dryRunContext(cmd *cobra.Command) context.Context {
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return api.WithDryRun(cmd.Context())
+	}
+	return cmd.Context()
+}
+
+// printDryRun prints the request a dry run stopped.
+func printDryRun(cmd *cobra.Command, req *api.DryRunRequest) error {
+	return output.NewFormatter(getOutputFormat(cmd), os.Stdout).PrintDryRun(req.Output())
+}
+
 // makeCreateRunE creates a RunE function for create operations.
 func makeCreateRunE(
-	createFunc func(*api.ApplicationAPI, map[string]any) (map[string]any, error),
+	createFunc func(context.Context, *api.ApplicationAPI, map[string]any) (map[string]any, error),
+	fields []updateField,
+	defaults map[string]any,
 	successMessage string,
 ) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
-		return runCreateCommand(cmd, createFunc, successMessage)
+		return runCreateCommand(cmd, fields, defaults, createFunc, successMessage)
 	}
 }
 
 // makeUpdateRunE creates a RunE function for update operations.
 func makeUpdateRunE(
-	updateFunc func(*api.ApplicationAPI, string) (map[string]any, error),
+	updateFunc func(context.Context, *api.ApplicationAPI, string) (map[string]any, error),
 	successMessage string,
 ) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
@@ -319,9 +528,73 @@ func makeUpdateRunE(
 	}
 }
 
+// makeUpdateDataRunE creates a RunE function for update operations that take the changed fields. With
+// --diff, the resource is fetched first and the fields that would change are shown and confirmed.
+func makeUpdateDataRunE(config crudResourceConfig) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		data, err := parseFieldData(cmd, config.updateFields)
+		if err != nil {
+			return err
+		}
+		if showDiff, _ := cmd.Flags().GetBool("diff"); showDiff {
+			proceed, diffErr := confirmUpdateDiff(cmd, config, args[0], data)
+			if diffErr != nil || !proceed {
+				return diffErr
+			}
+		}
+		return runUpdateCommand(cmd, args, func(
+			ctx context.Context, c *api.ApplicationAPI, id string,
+		) (map[string]any, error) {
+			return config.updateDataFunc(ctx, c, id, data)
+		}, config.updateMessage)
+	}
+}
+
+// confirmUpdateDiff shows the fields an update changes against the current state of the resource and asks
+// for confirmation unless --yes is given. It reports whether to go on with the update; there is nothing to
+// update when no field changes.
+func confirmUpdateDiff(
+	cmd *cobra.Command,
+	config crudResourceConfig,
+	id string,
+	data map[string]any,
+) (bool, error) {
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return false, err
+	}
+	current, err := config.viewFunc(cmd.Context(), client, id)
+	if err != nil {
+		return false, apierrors.Handle(err)
+	}
+	currentMap, _ := current.(map[string]any)
+	return confirmFieldChanges(cmd, config.name, id, currentMap, data)
+}
+
+// confirmFieldChanges shows the fields of data that differ from the current resource, named kind and id in
+// messages, and asks for confirmation unless --yes is given, like confirmUpdateDiff.
+func confirmFieldChanges(cmd *cobra.Command, kind, id string, current, data map[string]any) (bool, error) {
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	changes := diff.Fields(attributesOf(current), data)
+	if len(changes) == 0 {
+		formatter.PrintInfo("No changes: %s %s already has these values", kind, id)
+		return false, nil
+	}
+	for i := range changes {
+		// The panel never returns passwords, and the new one stays off the screen.
+		if changes[i].Field == "password" {
+			changes[i].New = "(hidden)"
+		}
+	}
+	formatter.PrintInfo("Changes to %s %s:", kind, id)
+	formatter.PrintFieldChanges(changes)
+
+	return output.Confirm(cmd, formatter, fmt.Sprintf("This will change %d field(s).", len(changes)))
+}
+
 // makeDeleteRunE creates a RunE function for delete operations.
 func makeDeleteRunE(
-	deleteFunc func(*api.ApplicationAPI, string) error,
+	deleteFunc func(context.Context, *api.ApplicationAPI, string) error,
 	successMessage string,
 ) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
@@ -344,12 +617,17 @@ func makeCompletionValidArgsFunction(
 
 // newCRUDResourceCmd creates a complete CRUD command with list, view, create, update, and delete subcommands.
 func newCRUDResourceCmd(config crudResourceConfig) *cobra.Command {
+	listRunE := makeListRunE(config.listFunc, config.resourceType)
+	if config.listOptionsFunc != nil {
+		listRunE = makeListOptionsRunE(config.listOptionsFunc, config.resourceType)
+	}
 	cmd := newResourceCmd(resourceCommandConfig{
 		name:         config.name,
 		short:        config.short,
 		long:         config.long,
 		listShort:    config.listShort,
-		listRunE:     makeListRunE(config.listFunc, config.resourceType),
+		listRunE:     listRunE,
+		listFlags:    config.listOptionsFunc != nil,
 		viewUse:      config.viewUse,
 		viewShort:    config.viewShort,
 		viewRunE:     makeViewRunE(config.viewFunc),
@@ -360,9 +638,14 @@ func newCRUDResourceCmd(config crudResourceConfig) *cobra.Command {
 		Use:   "create",
 		Short: fmt.Sprintf("Create a new %s", config.name),
 		Long:  config.createLong,
-		RunE:  makeCreateRunE(config.createFunc, config.createMessage),
+		RunE:  makeCreateRunE(config.createFunc, config.createFields, config.createDefaults, config.createMessage),
 	}
 	createCmd.Flags().String("data", "", config.dataFlagHelp)
+	addDryRunFlag(createCmd)
+	if len(config.createFields) > 0 {
+		createCmd.Long += " Field flags set single fields and override the same fields in the JSON data."
+		addFieldFlags(createCmd, config.createFields)
+	}
 
 	updateCmd := &cobra.Command{
 		Use:   fmt.Sprintf("update <%s-id>", config.name),
@@ -371,6 +654,20 @@ func newCRUDResourceCmd(config crudResourceConfig) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  makeUpdateRunE(config.updateFunc, config.updateMessage),
 	}
+	if config.updateDataFunc != nil {
+		updateCmd.Long = fmt.Sprintf(
+			"Update a %s by ID. Provide the changed fields as JSON via --data flag or stdin.", config.name)
+		if len(config.updateFields) > 0 {
+			updateCmd.Long += " Field flags set single fields and override the same fields in the JSON data."
+		}
+		updateCmd.Long += " With --diff, the fields that change are shown against the current " + config.name +
+			" and confirmed first."
+		updateCmd.RunE = makeUpdateDataRunE(config)
+		updateCmd.Flags().String("data", "", config.dataFlagHelp)
+		updateCmd.Flags().Bool("diff", false, "show the fields that change and ask for confirmation before updating")
+		updateCmd.Flags().Bool("yes", false, "skip the confirmation prompt of --diff")
+		addFieldFlags(updateCmd, config.updateFields)
+	}
 	updateCmd.ValidArgsFunction = makeCompletionValidArgsFunction(config.completeFunc)
 
 	deleteCmd := &cobra.Command{
@@ -380,6 +677,7 @@ func newCRUDResourceCmd(config crudResourceConfig) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  makeDeleteRunE(config.deleteFunc, config.deleteMessage),
 	}
+	addDryRunFlag(deleteCmd)
 	deleteCmd.ValidArgsFunction = makeCompletionValidArgsFunction(config.completeFunc)
 
 	cmd.AddCommand(createCmd)
@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func newDatabaseHostCmd() *cobra.Command {
+	return newCRUDResourceCmd(crudResourceConfig{
+		name:      "database-host",
+		short:     "Manage database hosts",
+		long:      "List, view, create, update, and delete the database hosts servers create databases on",
+		listShort: "List all database hosts",
+		listFunc:  func(ctx context.Context, c *api.ApplicationAPI) (any, error) { return c.ListDatabaseHosts(ctx) },
+		viewUse:   "view <database-host-id>",
+		viewShort: "View database host details",
+		viewFunc: func(ctx context.Context, c *api.ApplicationAPI, id string) (any, error) {
+			return c.GetDatabaseHost(ctx, id)
+		},
+		createFunc: func(ctx context.Context, c *api.ApplicationAPI, data map[string]any) (map[string]any, error) {
+			return c.CreateDatabaseHost(ctx, data)
+		},
+		updateDataFunc: func(
+			ctx context.Context, c *api.ApplicationAPI, id string, data map[string]any,
+		) (map[string]any, error) {
+			return c.UpdateDatabaseHost(ctx, id, data)
+		},
+		deleteFunc: func(ctx context.Context, c *api.ApplicationAPI, id string) error {
+			return c.DeleteDatabaseHost(ctx, id)
+		},
+		completeFunc:  completion.CompleteDatabaseHosts,
+		resourceType:  output.ResourceTypeAdminDatabaseHost,
+		createMessage: "Database host created successfully",
+		updateMessage: "Database host updated successfully",
+		deleteMessage: "Database host deleted successfully",
+		createLong: "Create a new database host. Provide name, host, port, username, password and " +
+			"optionally node_ids as JSON via --data flag or stdin.",
+		dataFlagHelp: "JSON data for the database host (or read from stdin)",
+	})
+}
@@ -0,0 +1,177 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func newEggCmd() *cobra.Command {
+	cmd := newResourceCmd(resourceCommandConfig{
+		name:      "egg",
+		short:     "Manage eggs",
+		long:      "List, view, export, and import eggs",
+		listShort: "List all eggs",
+		listRunE: makeListRunE(
+			func(ctx context.Context, c *api.ApplicationAPI) (any, error) { return c.ListEggs(ctx) },
+			output.ResourceTypeAdminEgg,
+		),
+		viewUse:   "view <egg-id>",
+		viewShort: "View egg details",
+		viewRunE: makeViewRunE(func(ctx context.Context, c *api.ApplicationAPI, id string) (any, error) {
+			return c.GetEgg(ctx, id)
+		}),
+		completeFunc: completion.CompleteEggs,
+	})
+
+	exportCmd := &cobra.Command{
+		Use:   "export <egg-id> [file]",
+		Short: "Export an egg",
+		Long: "Export an egg as a JSON or YAML egg file, e.g. for backup. Writes to stdout unless a file is given; " +
+			"the format follows the file extension unless --format is set.",
+		Args: cobra.RangeArgs(1, 2), //nolint:mnd // egg and optional file
+		RunE: runEggExport,
+	}
+	exportCmd.Flags().String("format", "", "egg file format: json or yaml (default from file extension, else json)")
+	exportCmd.ValidArgsFunction = makeCompletionValidArgsFunction(completion.CompleteEggs)
+
+	importCmd := &cobra.Command{
+		Use:   "import <file|url>",
+		Short: "Import an egg",
+		Long: "Import a JSON or YAML egg file from a local file, a URL (e.g. a community egg), or stdin (-). " +
+			"An existing egg with the same UUID is overwritten.",
+		Args: cobra.ExactArgs(1),
+		RunE: runEggImport,
+	}
+	importCmd.Flags().String("format", "", "egg file format: json or yaml (default from file extension, else json)")
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(exportCmd)
+	cmd.AddCommand(importCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	formatAction := carapace.ActionValues(api.EggFormatJSON, api.EggFormatYAML)
+	carapace.Gen(exportCmd).PositionalCompletion(
+		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completion.CompleteEggs(c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}),
+		carapace.ActionFiles(),
+	)
+	carapace.Gen(exportCmd).FlagCompletion(carapace.ActionMap{"format": formatAction})
+	carapace.Gen(importCmd).PositionalCompletion(carapace.ActionFiles())
+	carapace.Gen(importCmd).FlagCompletion(carapace.ActionMap{"format": formatAction})
+
+	return cmd
+}
+
+func runEggExport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	eggID := args[0]
+	var file string
+	if len(args) > 1 {
+		file = args[1]
+	}
+
+	format, err := eggFormat(cmd, file)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	content, err := client.ExportEgg(ctx, eggID, format)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	if file == "" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+
+	if err := os.WriteFile(file, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write egg file: %w", err)
+	}
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Egg %s exported to %s", eggID, file)
+	return nil
+}
+
+func runEggImport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	source := args[0]
+	isURL := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+
+	format, err := eggFormat(cmd, source)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	switch {
+	case isURL:
+		content, err = api.FetchEggURL(ctx, source)
+	case source == "-":
+		content, err = readStdin()
+	default:
+		content, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read egg from %s: %w", source, err)
+	}
+	if len(content) == 0 {
+		return fmt.Errorf("egg from %s is empty", source)
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	egg, err := client.ImportEgg(ctx, content, format)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Egg imported successfully")
+	return formatter.Print(egg)
+}
+
+// eggFormat returns the egg file format from --format, or else from the extension of path.
+func eggFormat(cmd *cobra.Command, path string) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	switch strings.ToLower(format) {
+	case api.EggFormatJSON, api.EggFormatYAML:
+		return strings.ToLower(format), nil
+	case "":
+	default:
+		return "", fmt.Errorf("invalid format: %s (must be json or yaml)", format)
+	}
+
+	// Ignore any query string when the path is a URL.
+	path, _, _ = strings.Cut(path, "?")
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return api.EggFormatYAML, nil
+	default:
+		return api.EggFormatJSON, nil
+	}
+}
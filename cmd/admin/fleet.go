@@ -0,0 +1,362 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/capacity"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// serverStatusSuspended is the status the panel gives suspended servers.
+const serverStatusSuspended = "suspended"
+
+func newFleetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: "Report on the whole fleet",
+		Long:  "Reports that cover every node and server of the panel",
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Summarize nodes, servers and capacity in one report",
+		Long: "Summarize the fleet in one report: every node with its number of servers, its suspended and " +
+			"crashed servers, and the memory, disk and CPU allocated to its servers against its capacity, " +
+			"followed by the suspended and crashed servers.\n\n" +
+			"Crashes are detected by checking the health of every server that is not suspended; " +
+			"--no-health skips this for a quicker report.",
+		Args: cobra.NoArgs,
+		RunE: runFleetStatus,
+	}
+	const defaultMaxConcurrency = 10
+	statusCmd.Flags().Bool("no-health", false, "skip crash detection, which checks the health of every server")
+	statusCmd.Flags().Int("max-concurrency", defaultMaxConcurrency, "maximum parallel health checks")
+	cmd.AddCommand(statusCmd)
+
+	return cmd
+}
+
+// fleetReport is the report of admin fleet status.
+type fleetReport struct {
+	Nodes  []fleetNode `json:"nodes"`
+	Totals fleetTotals `json:"totals"`
+	// Suspended and Crashed list the servers of the fleet that are suspended or crashed.
+	Suspended []fleetServer `json:"suspended"`
+	Crashed   []fleetServer `json:"crashed"`
+	// HealthChecked is false with --no-health, in which case no crashes are reported.
+	HealthChecked bool `json:"health_checked"`
+	// HealthErrors counts the servers whose health could not be checked.
+	HealthErrors int `json:"health_errors"`
+}
+
+// fleetTotals sums the nodes of the fleet.
+type fleetTotals struct {
+	Nodes     int `json:"nodes"`
+	Servers   int `json:"servers"`
+	Suspended int `json:"suspended"`
+	Crashed   int `json:"crashed"`
+}
+
+// fleetNode is a node of the fleet report.
+type fleetNode struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Maintenance bool          `json:"maintenance_mode"`
+	Servers     int           `json:"servers"`
+	Suspended   int           `json:"suspended"`
+	Crashed     int           `json:"crashed"`
+	Memory      resourceUsage `json:"memory"`
+	Disk        resourceUsage `json:"disk"`
+	CPU         resourceUsage `json:"cpu"`
+}
+
+// fleetServer is a suspended or crashed server of the fleet report.
+type fleetServer struct {
+	ID   int    `json:"id"`
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+	Node string `json:"node"`
+}
+
+// resourceUsage is how much of a resource of a node is allocated to its servers: memory and disk in MiB,
+// CPU in percent of a core.
+type resourceUsage struct {
+	Total int64 `json:"total"`
+	// Limit is the most that may be allocated, including over-allocation; -1 if the node does not cap it.
+	Limit     int64 `json:"limit"`
+	Allocated int64 `json:"allocated"`
+	// Percent is Allocated in percent of Total; absent if the node does not cap the resource.
+	Percent *float64 `json:"percent,omitempty"`
+}
+
+func newResourceUsage(total, overallocate, allocated int64) resourceUsage {
+	limit := capacity.Resource{Total: total, Overallocate: overallocate}.Limit()
+	usage := resourceUsage{Total: total, Limit: limit, Allocated: allocated}
+	if total > 0 {
+		percent := roundPercent(float64(allocated) / float64(total) * percentScale)
+		usage.Percent = &percent
+	}
+	return usage
+}
+
+// column formats the usage for tables with the suffix of its unit, e.g. "12288/16384 MiB (75%)".
+func (r resourceUsage) column(suffix string) string {
+	if r.Percent == nil {
+		return fmt.Sprintf("%d%s (uncapped)", r.Allocated, suffix)
+	}
+	return fmt.Sprintf("%d/%d%s (%.0f%%)", r.Allocated, r.Total, suffix, *r.Percent)
+}
+
+// nodeAllocation is what the servers on a node add up to.
+type nodeAllocation struct {
+	servers, suspended int
+	memory, disk, cpu  int64
+	suspendedServers   []map[string]any
+}
+
+// allocationsByNode sums the limits of the servers per node ID. Unlimited resources of a server count as 0.
+func allocationsByNode(servers []map[string]any) map[string]*nodeAllocation {
+	allocations := make(map[string]*nodeAllocation)
+	for _, server := range servers {
+		nodeID := convertServerIDToString(attribute(server, "node"))
+		allocation, ok := allocations[nodeID]
+		if !ok {
+			allocation = &nodeAllocation{}
+			allocations[nodeID] = allocation
+		}
+		allocation.servers++
+		if serverSuspended(server) {
+			allocation.suspended++
+			allocation.suspendedServers = append(allocation.suspendedServers, server)
+		}
+		limits, _ := attribute(server, "limits").(map[string]any)
+		allocation.memory += toInt64(limits["memory"])
+		allocation.disk += toInt64(limits["disk"])
+		allocation.cpu += toInt64(limits["cpu"])
+	}
+	return allocations
+}
+
+// nodeUsage compares what is allocated on a node to its capacity.
+func nodeUsage(node map[string]any, allocation *nodeAllocation) (resourceUsage, resourceUsage, resourceUsage) {
+	if allocation == nil {
+		allocation = &nodeAllocation{}
+	}
+	return newResourceUsage(toInt64(attribute(node, "memory")), toInt64(attribute(node, "memory_overallocate")),
+			allocation.memory),
+		newResourceUsage(toInt64(attribute(node, "disk")), toInt64(attribute(node, "disk_overallocate")),
+			allocation.disk),
+		newResourceUsage(toInt64(attribute(node, "cpu")), toInt64(attribute(node, "cpu_overallocate")),
+			allocation.cpu)
+}
+
+// serverSuspended reports whether the panel lists a server as suspended.
+func serverSuspended(server map[string]any) bool {
+	if suspended, ok := attribute(server, "is_suspended").(bool); ok {
+		return suspended
+	}
+	status, _ := attribute(server, "status").(string)
+	return status == serverStatusSuspended
+}
+
+func runFleetStatus(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	noHealth, _ := cmd.Flags().GetBool("no-health")
+	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	report := buildFleetReport(nodes, servers)
+	if !noHealth {
+		checkFleetHealth(ctx, client, servers, nodes, maxConcurrency, &report)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(report)
+	}
+	return printFleetReport(formatter, report)
+}
+
+// buildFleetReport builds the report of the nodes and servers, without crashes.
+func buildFleetReport(nodes, servers []map[string]any) fleetReport {
+	allocations := allocationsByNode(servers)
+	names := nodeNames(nodes)
+	report := fleetReport{
+		Nodes:     make([]fleetNode, 0, len(nodes)),
+		Suspended: []fleetServer{},
+		Crashed:   []fleetServer{},
+		Totals:    fleetTotals{Nodes: len(nodes), Servers: len(servers)},
+	}
+	for _, node := range nodes {
+		nodeID := convertServerIDToString(attribute(node, "id"))
+		id, _ := strconv.Atoi(nodeID)
+		name, _ := attribute(node, "name").(string)
+		maintenance, _ := attribute(node, "maintenance_mode").(bool)
+		allocation := allocations[nodeID]
+		entry := fleetNode{ID: id, Name: name, Maintenance: maintenance}
+		entry.Memory, entry.Disk, entry.CPU = nodeUsage(node, allocation)
+		if allocation != nil {
+			entry.Servers, entry.Suspended = allocation.servers, allocation.suspended
+			for _, server := range allocation.suspendedServers {
+				report.Suspended = append(report.Suspended, newFleetServer(server, names))
+			}
+		}
+		report.Totals.Suspended += entry.Suspended
+		report.Nodes = append(report.Nodes, entry)
+	}
+	return report
+}
+
+// checkFleetHealth checks the health of every server that is not suspended and adds the crashed ones to the report.
+func checkFleetHealth(
+	ctx context.Context,
+	client *api.ApplicationAPI,
+	servers, nodes []map[string]any,
+	maxConcurrency int,
+	report *fleetReport,
+) {
+	names := nodeNames(nodes)
+	crashed := make([]bool, len(servers))
+	operations := make([]bulk.Operation, 0, len(servers))
+	for i, server := range servers {
+		if serverSuspended(server) {
+			continue
+		}
+		id := convertServerIDToString(attribute(server, "id"))
+		operations = append(operations, bulk.Operation{
+			ID:   id,
+			Name: id,
+			Exec: func(opCtx context.Context) error {
+				health, err := client.GetServerHealth(opCtx, id, nil, nil)
+				if err != nil {
+					return err
+				}
+				crashed[i], _ = health["crashed"].(bool)
+				return nil
+			},
+		})
+	}
+	// Health checks are steps of the report, not results of the command.
+	results := bulk.NewExecutor(maxConcurrency, true, false).WithRecord(nil).Execute(ctx, operations)
+	for _, result := range results {
+		if !result.Success {
+			report.HealthErrors++
+			output.LogDebug("failed to check server health", "server", result.Operation.ID, "error", result.Error)
+		}
+	}
+
+	report.HealthChecked = true
+	for i, server := range servers {
+		if !crashed[i] {
+			continue
+		}
+		report.Crashed = append(report.Crashed, newFleetServer(server, names))
+		report.Totals.Crashed++
+		nodeID := convertServerIDToString(attribute(server, "node"))
+		for j := range report.Nodes {
+			if strconv.Itoa(report.Nodes[j].ID) == nodeID {
+				report.Nodes[j].Crashed++
+			}
+		}
+	}
+}
+
+// nodeNames maps node IDs to their names.
+func nodeNames(nodes []map[string]any) map[string]string {
+	names := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		name, _ := attribute(node, "name").(string)
+		names[convertServerIDToString(attribute(node, "id"))] = name
+	}
+	return names
+}
+
+func newFleetServer(server map[string]any, names map[string]string) fleetServer {
+	id, _ := strconv.Atoi(convertServerIDToString(attribute(server, "id")))
+	uuid, _ := attribute(server, "uuid").(string)
+	name, _ := attribute(server, "name").(string)
+	nodeID := convertServerIDToString(attribute(server, "node"))
+	node := names[nodeID]
+	if node == "" {
+		node = nodeID
+	}
+	return fleetServer{ID: id, UUID: uuid, Name: name, Node: node}
+}
+
+func printFleetReport(formatter *output.Formatter, report fleetReport) error {
+	totals := report.Totals
+	crashed := strconv.Itoa(totals.Crashed)
+	if !report.HealthChecked {
+		crashed = "unchecked"
+	}
+	formatter.PrintInfo("Fleet: %d node(s), %d server(s), %d suspended, %s crashed",
+		totals.Nodes, totals.Servers, totals.Suspended, crashed)
+	if report.HealthErrors > 0 {
+		formatter.PrintWarning("Health of %d server(s) could not be checked", report.HealthErrors)
+	}
+
+	headers := []string{"ID", "Node", "Maintenance", "Servers", "Suspended", "Crashed", "Memory", "Disk", "CPU"}
+	rows := make([][]string, 0, len(report.Nodes))
+	for _, node := range report.Nodes {
+		nodeCrashed := strconv.Itoa(node.Crashed)
+		if !report.HealthChecked {
+			nodeCrashed = "-"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(node.ID),
+			node.Name,
+			strconv.FormatBool(node.Maintenance),
+			strconv.Itoa(node.Servers),
+			strconv.Itoa(node.Suspended),
+			nodeCrashed,
+			node.Memory.column(" MiB"),
+			node.Disk.column(" MiB"),
+			node.CPU.column("%"),
+		})
+	}
+	if err := formatter.PrintTable(headers, rows); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		title   string
+		servers []fleetServer
+	}{
+		{"Suspended servers", report.Suspended},
+		{"Crashed servers", report.Crashed},
+	}
+	for _, section := range sections {
+		if len(section.servers) == 0 {
+			continue
+		}
+		formatter.PrintInfo("%s", section.title)
+		serverRows := make([][]string, 0, len(section.servers))
+		for _, server := range section.servers {
+			serverRows = append(serverRows, []string{strconv.Itoa(server.ID), server.UUID, server.Name, server.Node})
+		}
+		if err := formatter.PrintTable([]string{"ID", "UUID", "Name", "Node"}, serverRows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
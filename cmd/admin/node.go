@@ -2,6 +2,7 @@
 package admin
 
 import (
+	"context"
 	"github.com/spf13/cobra"
 
 	"go.lostcrafters.com/pelicanctl/internal/api"
@@ -10,18 +11,51 @@ import (
 )
 
 func newNodeCmd() *cobra.Command {
-	return newCRUDResourceCmd(crudResourceConfig{
-		name:          "node",
-		short:         "Manage nodes",
-		long:          "List and view nodes",
-		listShort:     "List all nodes",
-		listFunc:      func(c *api.ApplicationAPI) (any, error) { return c.ListNodes() },
-		viewUse:       "view <node-id>",
-		viewShort:     "View node details",
-		viewFunc:      func(c *api.ApplicationAPI, id string) (any, error) { return c.GetNode(id) },
-		createFunc:    func(c *api.ApplicationAPI, data map[string]any) (map[string]any, error) { return c.CreateNode(data) },
-		updateFunc:    func(c *api.ApplicationAPI, id string) (map[string]any, error) { return c.UpdateNode(id) },
-		deleteFunc:    func(c *api.ApplicationAPI, id string) error { return c.DeleteNode(id) },
+	cmd := newCRUDResourceCmd(crudResourceConfig{
+		name:      "node",
+		short:     "Manage nodes",
+		long:      "List and view nodes",
+		listShort: "List all nodes",
+		listOptionsFunc: func(ctx context.Context, c *api.ApplicationAPI, opts api.ListOptions) (any, error) {
+			return c.ListNodesWithOptions(ctx, opts)
+		},
+		viewUse:   "view <node-id>",
+		viewShort: "View node details",
+		viewFunc:  func(ctx context.Context, c *api.ApplicationAPI, id string) (any, error) { return c.GetNode(ctx, id) },
+		createFunc: func(ctx context.Context, c *api.ApplicationAPI, data map[string]any) (map[string]any, error) {
+			return c.CreateNode(ctx, data)
+		},
+		createFields: []updateField{
+			{flag: "name", field: "name", usage: "node name"},
+			{flag: "description", field: "description", usage: "node description"},
+			{flag: "fqdn", field: "fqdn", usage: "fully qualified domain name or IP of the node"},
+			{flag: "scheme", field: "scheme", usage: "scheme Wings is reached with: https or http (default https)"},
+			{flag: "memory", field: "memory", kind: updateFieldInt, usage: "total memory in MiB"},
+			{flag: "disk", field: "disk", kind: updateFieldInt, usage: "total disk space in MiB"},
+			{flag: "tags", field: "tags", kind: updateFieldStringSlice, usage: "node tags"},
+		},
+		// Requests built from flags get the scheme and ports Wings uses by default.
+		createDefaults: map[string]any{
+			"scheme":         "https",
+			"daemon_listen":  8080,
+			"daemon_connect": 8080,
+			"daemon_sftp":    2022,
+		},
+		updateDataFunc: func(
+			ctx context.Context, c *api.ApplicationAPI, id string, data map[string]any,
+		) (map[string]any, error) {
+			return c.UpdateNode(ctx, id, data)
+		},
+		updateFields: []updateField{
+			{flag: "name", field: "name", usage: "node name"},
+			{flag: "description", field: "description", usage: "node description"},
+			{flag: "fqdn", field: "fqdn", usage: "fully qualified domain name or IP of the node"},
+			{flag: "memory", field: "memory", kind: updateFieldInt, usage: "total memory in MiB"},
+			{flag: "disk", field: "disk", kind: updateFieldInt, usage: "total disk space in MiB"},
+			{flag: "maintenance", field: "maintenance_mode", kind: updateFieldBool, usage: "put the node in maintenance mode"},
+			{flag: "public", field: "public", kind: updateFieldBool, usage: "allow automatic allocation to the node"},
+		},
+		deleteFunc:    func(ctx context.Context, c *api.ApplicationAPI, id string) error { return c.DeleteNode(ctx, id) },
 		completeFunc:  completion.CompleteNodes,
 		resourceType:  output.ResourceTypeAdminNode,
 		createMessage: "Node created successfully",
@@ -30,4 +64,11 @@ func newNodeCmd() *cobra.Command {
 		createLong:    "Create a new node. Provide node data as JSON via --data flag or stdin.",
 		dataFlagHelp:  "JSON data for the node (or read from stdin)",
 	})
+	cmd.AddCommand(newNodeAllocationCmd())
+	cmd.AddCommand(newNodeUsageCmd())
+	cmd.AddCommand(newNodeDrainCmd())
+	cmd.AddCommand(newNodeUndrainCmd())
+	cmd.AddCommand(newNodeConfigCmd())
+	cmd.AddCommand(newNodeWingsTokenCmd())
+	return cmd
 }
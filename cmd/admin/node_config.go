@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func newNodeConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config <node-id|name> [--format yaml|json]",
+		Short: "Print the Wings configuration of a node",
+		Long: "Print the Wings configuration of a node as the panel generates it, including the daemon token. " +
+			"The YAML output is the config.yml of Wings, so a new node can be bootstrapped with e.g.\n\n" +
+			"  pelicanctl admin node config 3 > /etc/pelican/config.yml",
+		Args: cobra.ExactArgs(1),
+		RunE: runNodeConfig,
+	}
+	cmd.Flags().String("format", string(output.OutputFormatYAML), "format of the configuration: yaml or json")
+	cmd.ValidArgsFunction = nodeValidArgs
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(nodeCompletionAction))
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		"format": carapace.ActionValues(string(output.OutputFormatYAML), string(output.OutputFormatJSON)),
+	})
+	return cmd
+}
+
+func newNodeWingsTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wings-token <node-id|name>",
+		Short: "Print the token Wings authenticates to the panel with",
+		Long: "Print the token ID and token of a node from its Wings configuration, " +
+			"for automation that writes the configuration of Wings itself.",
+		Args: cobra.ExactArgs(1),
+		RunE: runNodeWingsToken,
+	}
+	cmd.ValidArgsFunction = nodeValidArgs
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(nodeCompletionAction))
+	return cmd
+}
+
+func runNodeConfig(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	outputFormat := output.OutputFormat(format)
+	if outputFormat != output.OutputFormatYAML && outputFormat != output.OutputFormatJSON {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("invalid --format %q: must be yaml or json", format))
+	}
+
+	configuration, _, err := fetchNodeConfiguration(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	return output.NewFormatter(outputFormat, os.Stdout).Print(configuration)
+}
+
+// wingsToken is the token of a node in the output of node wings-token.
+type wingsToken struct {
+	Node    string `json:"node"`
+	UUID    string `json:"uuid"`
+	TokenID string `json:"token_id"`
+	Token   string `json:"token"`
+}
+
+func runNodeWingsToken(cmd *cobra.Command, args []string) error {
+	configuration, nodeID, err := fetchNodeConfiguration(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	token := wingsToken{Node: nodeID}
+	if err = json.Unmarshal(configuration, &token); err != nil {
+		return fmt.Errorf("failed to decode configuration: %w", err)
+	}
+	if token.TokenID == "" || token.Token == "" {
+		return errors.New("the configuration of the node has no token")
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(token)
+	}
+	return formatter.PrintTable(
+		[]string{"Node", "UUID", "Token ID", "Token"},
+		[][]string{{token.Node, token.UUID, token.TokenID, token.Token}},
+	)
+}
+
+// fetchNodeConfiguration returns the Wings configuration of a node given by ID or name, and its ID.
+func fetchNodeConfiguration(cmd *cobra.Command, node string) (json.RawMessage, string, error) {
+	ctx := cmd.Context()
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return nil, "", err
+	}
+	nodeID, err := resolveNodeID(ctx, client, node)
+	if err != nil {
+		return nil, "", err
+	}
+	configuration, err := client.GetNodeConfiguration(ctx, nodeID)
+	if err != nil {
+		return nil, "", apierrors.Handle(err)
+	}
+	return configuration, nodeID, nil
+}
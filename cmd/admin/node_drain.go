@@ -0,0 +1,299 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/selector"
+)
+
+func newNodeDrainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drain <node-id|name> [--stop-servers | --transfer-to <node-id|name>]",
+		Short: "Put a node in maintenance mode and optionally stop or move its servers",
+		Long: "Put a node in maintenance mode, so no new servers are deployed to it. " +
+			"With --stop-servers every server on the node is stopped; with --transfer-to every server is " +
+			"transferred to the given node, each getting one of its unassigned allocations. " +
+			"Servers are stopped or transferred in parallel like other bulk commands.",
+		Args: cobra.ExactArgs(1),
+		RunE: runNodeDrain,
+	}
+	cmd.Flags().Bool("stop-servers", false, "stop every server on the node")
+	cmd.Flags().String("transfer-to", "", "transfer every server on the node to this node")
+	cmd.Flags().Bool("watch", false, "with --transfer-to, wait until every transfer has finished")
+	cmd.Flags().Duration("timeout", defaultTransferTimeout, "with --watch, how long to wait for each transfer")
+	selector.AddProtectedFlag(cmd)
+	addExecutionFlags(cmd)
+	cmd.ValidArgsFunction = nodeValidArgs
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(nodeCompletionAction))
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		"transfer-to": carapace.ActionCallback(nodeCompletionAction),
+	})
+	return cmd
+}
+
+func newNodeUndrainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undrain <node-id|name>",
+		Short: "Take a node out of maintenance mode",
+		Long:  "Take a node out of maintenance mode, so servers can be deployed to it again.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runNodeUndrain,
+	}
+	cmd.ValidArgsFunction = nodeValidArgs
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(nodeCompletionAction))
+	return cmd
+}
+
+func nodeValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	completions, err := completion.CompleteNodes(toComplete)
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func nodeCompletionAction(c carapace.Context) carapace.Action {
+	completions, err := completion.CompleteNodes(c.Value)
+	if err != nil {
+		return carapace.ActionValues()
+	}
+	return completion.Action(completions)
+}
+
+// drainPlan is what node drain does to the servers of a node.
+type drainPlan struct {
+	// action is "stop" or "transfer", empty if the servers are left alone.
+	action string
+	// servers are the UUIDs of the servers on the node, and serverIDs their IDs.
+	servers   []string
+	serverIDs map[string]string
+	// target is the ID of the node servers are transferred to, and allocations the allocation
+	// each server gets there.
+	target      string
+	allocations map[string]int
+}
+
+func runNodeDrain(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	stopServers, _ := cmd.Flags().GetBool("stop-servers")
+	transferTo, _ := cmd.Flags().GetString("transfer-to")
+	watch, _ := cmd.Flags().GetBool("watch")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	flags := getBulkFlags(cmd)
+	switch {
+	case stopServers && transferTo != "":
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--stop-servers and --transfer-to cannot be used together"))
+	case watch && transferTo == "":
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--watch requires --transfer-to"))
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	node, err := selectNodes(nodes, args)
+	if err != nil {
+		return err
+	}
+	nodeID := convertServerIDToString(attribute(node[0], "id"))
+	nodeName, _ := attribute(node[0], "name").(string)
+
+	plan, err := planDrain(ctx, cmd, formatter, client, nodes, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.servers) > 0 {
+		shouldContinue, confirmErr := output.Confirm(cmd, formatter, fmt.Sprintf(
+			"This will drain node %s and %s its %d server(s).", nodeName, plan.action, len(plan.servers)))
+		if confirmErr != nil || !shouldContinue {
+			return confirmErr
+		}
+	}
+
+	if flags.dryRun {
+		formatter.PrintInfo("Dry run - would put node %s in maintenance mode", nodeName)
+		printDrainPlan(formatter, plan)
+		return nil
+	}
+
+	if _, err = client.UpdateNode(ctx, nodeID, map[string]any{"maintenance_mode": true}); err != nil {
+		return apierrors.Handle(err)
+	}
+	if len(plan.servers) == 0 {
+		formatter.PrintSuccess("Node %s is in maintenance mode", nodeName)
+		if plan.action != "" {
+			formatter.PrintInfo("No servers on node %s to %s", nodeName, plan.action)
+		}
+		return nil
+	}
+	// JSON output is the results of the bulk run alone.
+	if !getOutputFormat(cmd).IsJSON() {
+		formatter.PrintSuccess("Node %s is in maintenance mode", nodeName)
+	}
+
+	operations := make([]bulk.Operation, len(plan.servers))
+	for i, uuid := range plan.servers {
+		serverID := plan.serverIDs[uuid]
+		operations[i] = bulk.Operation{
+			ID:   uuid,
+			Name: uuid,
+			Exec: func(opCtx context.Context) error {
+				if plan.action == "stop" {
+					return client.SendPowerCommand(opCtx, serverID, "stop")
+				}
+				if transferErr := client.TransferServer(
+					opCtx, serverID, plan.target, plan.allocations[uuid],
+				); transferErr != nil {
+					return transferErr
+				}
+				if !watch {
+					return nil
+				}
+				return waitForTransfer(opCtx, client, serverID, plan.target, timeout)
+			},
+		}
+	}
+	results := flags.executor().WithRecord(fieldRecord("action", plan.action)).Execute(ctx, operations)
+
+	if getOutputFormat(cmd).IsJSON() {
+		return printResultsJSON(formatter, results, plan.action, bulk.GetSummary(results), flags.continueOnError)
+	}
+	printResults(formatter, results, plan.action)
+	return handleSummary(formatter, results, flags.continueOnError)
+}
+
+// planDrain finds the servers to stop or transfer on the node to drain. Transfers hand every server its own
+// unassigned allocation on the target node, failing before anything is changed if there are too few.
+func planDrain(
+	ctx context.Context,
+	cmd *cobra.Command,
+	formatter *output.Formatter,
+	client *api.ApplicationAPI,
+	nodes []map[string]any,
+	nodeID string,
+) (drainPlan, error) {
+	stopServers, _ := cmd.Flags().GetBool("stop-servers")
+	transferTo, _ := cmd.Flags().GetString("transfer-to")
+	plan := drainPlan{}
+	switch {
+	case stopServers:
+		plan.action = "stop"
+	case transferTo != "":
+		plan.action = "transfer"
+	default:
+		return plan, nil
+	}
+
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		return plan, apierrors.Handle(err)
+	}
+	plan.serverIDs = map[string]string{}
+	for _, server := range servers {
+		if convertServerIDToString(attribute(server, "node")) != nodeID {
+			continue
+		}
+		uuid, _ := attribute(server, "uuid").(string)
+		plan.servers = append(plan.servers, uuid)
+		plan.serverIDs[uuid] = convertServerIDToString(attribute(server, "id"))
+	}
+	plan.servers = selector.SkipProtected(ctx, cmd, formatter, client, plan.action, plan.servers)
+	if plan.action != "transfer" {
+		return plan, nil
+	}
+
+	target, err := selectNodes(nodes, []string{transferTo})
+	if err != nil {
+		return plan, err
+	}
+	plan.target = convertServerIDToString(attribute(target[0], "id"))
+	if plan.target == nodeID {
+		return plan, apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--transfer-to must be another node than the one drained"))
+	}
+	allocations, err := client.ListNodeAllocations(ctx, plan.target)
+	if err != nil {
+		return plan, apierrors.Handle(err)
+	}
+	var free []int
+	for _, allocation := range allocations {
+		if assigned, _ := attribute(allocation, "assigned").(bool); assigned {
+			continue
+		}
+		if id := toInt64(attribute(allocation, "id")); id > 0 {
+			free = append(free, int(id))
+		}
+	}
+	if len(free) < len(plan.servers) {
+		return plan, apierrors.WithExitCode(apierrors.ExitValidation, fmt.Errorf(
+			"node %s has %d unassigned allocation(s) for %d server(s); create more before draining",
+			transferTo, len(free), len(plan.servers)))
+	}
+	plan.allocations = make(map[string]int, len(plan.servers))
+	for i, uuid := range plan.servers {
+		plan.allocations[uuid] = free[i]
+	}
+	return plan, nil
+}
+
+func printDrainPlan(formatter *output.Formatter, plan drainPlan) {
+	if plan.action == "" {
+		return
+	}
+	formatter.PrintInfo("Dry run - would %s %d server(s):", plan.action, len(plan.servers))
+	for _, uuid := range plan.servers {
+		if plan.action == "transfer" {
+			formatter.PrintInfo("  - %s to node %s, allocation %d", uuid, plan.target, plan.allocations[uuid])
+			continue
+		}
+		formatter.PrintInfo("  - %s", uuid)
+	}
+}
+
+func runNodeUndrain(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	node, err := selectNodes(nodes, args)
+	if err != nil {
+		return err
+	}
+	nodeID := convertServerIDToString(attribute(node[0], "id"))
+	nodeName, _ := attribute(node[0], "name").(string)
+
+	if _, err = client.UpdateNode(ctx, nodeID, map[string]any{"maintenance_mode": false}); err != nil {
+		return apierrors.Handle(err)
+	}
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Node %s is out of maintenance mode", nodeName)
+	formatter.PrintAffected(nodeID)
+	return nil
+}
@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/capacity"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// defaultUsageThreshold is the allocation, in percent of a node's capacity, above which node usage flags it.
+const defaultUsageThreshold = 90
+
+func newNodeUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage [node-id|name]...",
+		Short: "Show how much of the capacity of nodes is allocated",
+		Long: "Sum the memory, disk and CPU allocated to the servers of each node and compare them to the capacity " +
+			"of the node and the limit its over-allocation settings allow. Nodes allocated more than --threshold " +
+			"percent of a resource, or more than its limit, are flagged.\n\n" +
+			"Give nodes by ID or name, or --all for every node.",
+		RunE: runNodeUsage,
+	}
+	cmd.Flags().Bool("all", false, "report every node")
+	cmd.Flags().Float64("threshold", defaultUsageThreshold,
+		"flag nodes allocated more than this percentage of their memory, disk or CPU")
+	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions, err := completion.CompleteNodes(toComplete)
+		if err != nil || len(completions) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	carapace.Gen(cmd).PositionalAnyCompletion(carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+		completions, err := completion.CompleteNodes(c.Value)
+		if err != nil {
+			return carapace.ActionValues()
+		}
+		return completion.Action(completions)
+	}))
+	return cmd
+}
+
+// nodeUsageReport is the usage of one node in the output of node usage.
+type nodeUsageReport struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Servers int           `json:"servers"`
+	Memory  resourceUsage `json:"memory"`
+	Disk    resourceUsage `json:"disk"`
+	CPU     resourceUsage `json:"cpu"`
+	// Flags lists why the node is flagged, e.g. "memory 95% over 90%"; Flagged is set if there are any.
+	Flagged bool     `json:"flagged"`
+	Flags   []string `json:"flags"`
+}
+
+func runNodeUsage(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	all, _ := cmd.Flags().GetBool("all")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	switch {
+	case all == (len(args) > 0):
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("give node IDs or names, or --all"))
+	case threshold <= 0:
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--threshold must be positive"))
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	if !all {
+		if nodes, err = selectNodes(nodes, args); err != nil {
+			return err
+		}
+	}
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	allocations := allocationsByNode(servers)
+	reports := make([]nodeUsageReport, 0, len(nodes))
+	for _, node := range nodes {
+		reports = append(reports, newNodeUsageReport(node, allocations, threshold))
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(reports)
+	}
+	return printNodeUsage(formatter, reports)
+}
+
+// selectNodes returns the nodes given by ID or name, in the order given.
+func selectNodes(nodes []map[string]any, identifiers []string) ([]map[string]any, error) {
+	selected := make([]map[string]any, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		found := false
+		for _, node := range nodes {
+			name, _ := attribute(node, "name").(string)
+			if convertServerIDToString(attribute(node, "id")) == identifier || strings.EqualFold(name, identifier) {
+				selected = append(selected, node)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("node %q not found", identifier))
+		}
+	}
+	return selected, nil
+}
+
+func newNodeUsageReport(
+	node map[string]any,
+	allocations map[string]*nodeAllocation,
+	threshold float64,
+) nodeUsageReport {
+	nodeID := convertServerIDToString(attribute(node, "id"))
+	id, _ := strconv.Atoi(nodeID)
+	name, _ := attribute(node, "name").(string)
+	report := nodeUsageReport{ID: id, Name: name, Flags: []string{}}
+	if allocation := allocations[nodeID]; allocation != nil {
+		report.Servers = allocation.servers
+	}
+	report.Memory, report.Disk, report.CPU = nodeUsage(node, allocations[nodeID])
+
+	resources := []struct {
+		name  string
+		usage resourceUsage
+	}{
+		{"memory", report.Memory},
+		{"disk", report.Disk},
+		{"cpu", report.CPU},
+	}
+	for _, resource := range resources {
+		usage := resource.usage
+		switch {
+		case usage.Limit != capacity.Unlimited && usage.Allocated > usage.Limit:
+			report.Flags = append(report.Flags, fmt.Sprintf("%s over the limit of %d", resource.name, usage.Limit))
+		case usage.Percent != nil && *usage.Percent > threshold:
+			report.Flags = append(report.Flags,
+				fmt.Sprintf("%s %.0f%% over %s%%", resource.name, *usage.Percent, formatThreshold(threshold)))
+		}
+	}
+	report.Flagged = len(report.Flags) > 0
+	return report
+}
+
+func printNodeUsage(formatter *output.Formatter, reports []nodeUsageReport) error {
+	headers := []string{
+		"ID", "Node", "Servers", "Memory", "Disk", "CPU", "Memory Limit", "Disk Limit", "CPU Limit", "Flags",
+	}
+	rows := make([][]string, 0, len(reports))
+	flagged := 0
+	for _, report := range reports {
+		if report.Flagged {
+			flagged++
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(report.ID),
+			report.Name,
+			strconv.Itoa(report.Servers),
+			report.Memory.column(" MiB"),
+			report.Disk.column(" MiB"),
+			report.CPU.column("%"),
+			limitColumn(report.Memory.Limit, " MiB"),
+			limitColumn(report.Disk.Limit, " MiB"),
+			limitColumn(report.CPU.Limit, "%"),
+			strings.Join(report.Flags, ", "),
+		})
+	}
+	if err := formatter.PrintTable(headers, rows); err != nil {
+		return err
+	}
+	if flagged > 0 {
+		formatter.PrintWarning("%d of %d node(s) flagged", flagged, len(reports))
+	}
+	return nil
+}
+
+// limitColumn formats the limit of a resource including over-allocation for tables.
+func limitColumn(limit int64, suffix string) string {
+	if limit == capacity.Unlimited {
+		return "unlimited"
+	}
+	return strconv.FormatInt(limit, 10) + suffix
+}
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func newRoleCmd() *cobra.Command {
+	return newCRUDResourceCmd(crudResourceConfig{
+		name:      "role",
+		short:     "Manage roles",
+		long:      "List, view, create, update, and delete the roles that grant admin permissions",
+		listShort: "List all roles",
+		listFunc:  func(ctx context.Context, c *api.ApplicationAPI) (any, error) { return c.ListRoles(ctx) },
+		viewUse:   "view <role-id>",
+		viewShort: "View role details",
+		viewFunc:  func(ctx context.Context, c *api.ApplicationAPI, id string) (any, error) { return c.GetRole(ctx, id) },
+		createFunc: func(ctx context.Context, c *api.ApplicationAPI, data map[string]any) (map[string]any, error) {
+			return c.CreateRole(ctx, data)
+		},
+		updateDataFunc: func(
+			ctx context.Context, c *api.ApplicationAPI, id string, data map[string]any,
+		) (map[string]any, error) {
+			return c.UpdateRole(ctx, id, data)
+		},
+		updateFields: []updateField{
+			{flag: "name", field: "name", usage: "role name"},
+		},
+		deleteFunc:    func(ctx context.Context, c *api.ApplicationAPI, id string) error { return c.DeleteRole(ctx, id) },
+		completeFunc:  completion.CompleteRoles,
+		resourceType:  output.ResourceTypeAdminRole,
+		createMessage: "Role created successfully",
+		updateMessage: "Role updated successfully",
+		deleteMessage: "Role deleted successfully",
+		createLong: "Create a new role. Provide the name and optionally permissions as JSON " +
+			"via --data flag or stdin.",
+		dataFlagHelp: "JSON data for the role (or read from stdin)",
+	})
+}
+
+// newUserRoleCmds creates the commands that assign roles to and remove roles from a user.
+func newUserRoleCmds() []*cobra.Command {
+	assignCmd := &cobra.Command{
+		Use:   "assign-role <user> <role>...",
+		Short: "Assign roles to a user",
+		Long:  "Assign one or more roles, by ID or name, to a user by ID, username or email",
+		Args:  cobra.MinimumNArgs(2), //nolint:mnd // user and at least one role
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserRoles(cmd, args, true)
+		},
+	}
+	assignCmd.ValidArgsFunction = userRoleValidArgs
+
+	removeCmd := &cobra.Command{
+		Use:   "remove-role <user> <role>...",
+		Short: "Remove roles from a user",
+		Long:  "Remove one or more roles, by ID or name, from a user by ID, username or email",
+		Args:  cobra.MinimumNArgs(2), //nolint:mnd // user and at least one role
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserRoles(cmd, args, false)
+		},
+	}
+	removeCmd.ValidArgsFunction = userRoleValidArgs
+
+	return []*cobra.Command{assignCmd, removeCmd}
+}
+
+func setupUserRoleCompletion(cmds []*cobra.Command) {
+	for _, cmd := range cmds {
+		carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completion.CompleteUsers(c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}))
+		carapace.Gen(cmd).PositionalAnyCompletion(carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completion.CompleteRoles(c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}))
+	}
+}
+
+// userRoleValidArgs completes a user, followed by roles.
+func userRoleValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	complete := completion.CompleteRoles
+	if len(args) == 0 {
+		complete = completion.CompleteUsers
+	}
+	completions, err := complete(toComplete)
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func runUserRoles(cmd *cobra.Command, args []string, assign bool) error {
+	ctx := cmd.Context()
+	userID := args[0]
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	roleIDs, err := resolveRoleIDs(ctx, client, args[1:])
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if assign {
+		if assignErr := client.AssignUserRoles(ctx, userID, roleIDs); assignErr != nil {
+			return apierrors.Handle(assignErr)
+		}
+		formatter.PrintSuccess("Assigned %d role(s) to user %s", len(roleIDs), userID)
+		formatter.PrintAffected(userID)
+		return nil
+	}
+
+	if removeErr := client.RemoveUserRoles(ctx, userID, roleIDs); removeErr != nil {
+		return apierrors.Handle(removeErr)
+	}
+	formatter.PrintSuccess("Removed %d role(s) from user %s", len(roleIDs), userID)
+	formatter.PrintAffected(userID)
+	return nil
+}
+
+// resolveRoleIDs converts role IDs and names to IDs. Roles are only listed when a name is given.
+func resolveRoleIDs(ctx context.Context, client *api.ApplicationAPI, roles []string) ([]int, error) {
+	ids := make([]int, 0, len(roles))
+	var byName map[string]int
+	for _, role := range roles {
+		if id, err := strconv.Atoi(role); err == nil {
+			ids = append(ids, id)
+			continue
+		}
+
+		if byName == nil {
+			list, err := client.ListRoles(ctx)
+			if err != nil {
+				return nil, apierrors.Handle(err)
+			}
+			byName = make(map[string]int, len(list))
+			for _, r := range list {
+				name, _ := attribute(r, "name").(string)
+				byName[strings.ToLower(name)] = int(toInt64(attribute(r, "id")))
+			}
+		}
+
+		id, ok := byName[strings.ToLower(role)]
+		if !ok {
+			return nil, fmt.Errorf("role %q not found", role)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,8 +20,12 @@ import (
 	"go.lostcrafters.com/pelicanctl/internal/api"
 	"go.lostcrafters.com/pelicanctl/internal/bulk"
 	"go.lostcrafters.com/pelicanctl/internal/completion"
+	"go.lostcrafters.com/pelicanctl/internal/config"
 	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
 	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/selector"
+	"go.lostcrafters.com/pelicanctl/internal/watch"
 )
 
 func adminServerCompletionAction(c carapace.Context) carapace.Action {
@@ -27,7 +33,7 @@ func adminServerCompletionAction(c carapace.Context) carapace.Action {
 	if err != nil || len(completions) == 0 {
 		return carapace.ActionValues()
 	}
-	return carapace.ActionValues(completions...)
+	return completion.Action(completions)
 }
 
 func adminServerValidArgs(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -38,28 +44,74 @@ func adminServerValidArgs(_ *cobra.Command, _ []string, toComplete string) ([]st
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeAdminBackupPair completes server+backup pairs: servers in even positions,
+// backups of the preceding server in odd positions.
+func completeAdminBackupPair(args []string, toComplete string) ([]string, error) {
+	if len(args)%2 == 0 {
+		return completion.CompleteServers("admin", toComplete)
+	}
+	return completion.CompleteAdminBackups(args[len(args)-1], toComplete)
+}
+
+func adminBackupPairValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions, err := completeAdminBackupPair(args, toComplete)
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func adminBackupPairCompletionAction(c carapace.Context) carapace.Action {
+	completions, err := completeAdminBackupPair(c.Args, c.Value)
+	if err != nil || len(completions) == 0 {
+		return carapace.ActionValues()
+	}
+	return completion.Action(completions)
+}
+
 func newServerBasicCommands() []*cobra.Command {
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all servers",
 		RunE:  runServerList,
 	}
+	listCmd.Flags().Bool("suspended", false,
+		"list only suspended servers, with when and why they were suspended if noted by 'admin server suspend'")
+	addListFlags(listCmd)
+	watch.AddFlags(listCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new server",
-		Long:  "Create a new server. Provide server data as JSON via --data flag or stdin.",
-		RunE:  runServerCreate,
+		Long: "Create a new server. Provide server data as JSON via --data flag or stdin, build it with the " +
+			"field flags (--name, --user, --egg, --node, --memory, ...), or create it from a template saved with " +
+			"'pelicanctl template save' using --template and --set. Field flags override the same fields " +
+			"of the JSON data or template. " +
+			"Fails if the node of the default allocation lacks free memory or disk, unless --ignore-capacity is set.",
+		RunE: runServerCreate,
 	}
 	createCmd.Flags().String("data", "", "JSON data for the server (or read from stdin)")
+	createCmd.Flags().String("template", "", "create the server from this saved template")
+	addTemplateSetFlag(createCmd)
+	addServerCreateFlags(createCmd)
+	addDryRunFlag(createCmd)
+	addBulkCreateFlags(createCmd, "from-file",
+		"create every server of a JSON array of server data in this file (- for stdin)")
+	createCmd.MarkFlagsMutuallyExclusive("from-file", "template")
+	createCmd.MarkFlagsMutuallyExclusive("data", "template")
+	completion.RegisterFlagFunc(createCmd, "template", completeTemplateNames)
+	addIgnoreCapacityFlag(createCmd)
 
 	viewCmd := &cobra.Command{
 		Use:   "view <id|uuid>",
 		Short: "View server details",
-		Long:  "View server details by ID (integer) or UUID (string)",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runServerView,
+		Long: "View server details by ID (integer) or UUID (string). " +
+			"--expand resolves referenced IDs into summaries, e.g. --expand owner,node,egg,allocations.",
+		Args: cobra.ExactArgs(1),
+		RunE: runServerView,
 	}
+	viewCmd.Flags().StringSlice("expand", nil,
+		"references to resolve: "+strings.Join(expandableReferences, ", "))
 	viewCmd.ValidArgsFunction = adminServerValidArgs
 
 	deleteCmd := &cobra.Command{
@@ -70,6 +122,8 @@ func newServerBasicCommands() []*cobra.Command {
 		RunE:  runServerDelete,
 	}
 	deleteCmd.Flags().Bool("force", false, "Force delete the server")
+	selector.AddProtectedFlag(deleteCmd)
+	addDryRunFlag(deleteCmd)
 	deleteCmd.ValidArgsFunction = adminServerValidArgs
 
 	return []*cobra.Command{listCmd, createCmd, viewCmd, deleteCmd}
@@ -79,17 +133,20 @@ func newServerActionCommands() []*cobra.Command {
 	suspendCmd := &cobra.Command{
 		Use:   "suspend <id|uuid>...",
 		Short: "Suspend server(s)",
-		Long:  "Suspend server(s) by ID (integer) or UUID (string)",
-		RunE:  runSuspendServer,
+		Long: "Suspend server(s) by ID (integer) or UUID (string). " +
+			"When and why each server was suspended is noted locally for 'admin server list --suspended'.",
+		RunE: runSuspendServer,
 	}
+	suspendCmd.Flags().String("reason", "", "why the servers are suspended, e.g. an unpaid invoice")
 	addBulkFlags(suspendCmd)
+	selector.AddProtectedFlag(suspendCmd)
 	suspendCmd.ValidArgsFunction = adminServerValidArgs
 	carapace.Gen(suspendCmd).PositionalAnyCompletion(carapace.ActionCallback(adminServerCompletionAction))
 
 	unsuspendCmd := &cobra.Command{
 		Use:   "unsuspend <id|uuid>...",
 		Short: "Unsuspend server(s)",
-		Long:  "Unsuspend server(s) by ID (integer) or UUID (string)",
+		Long:  "Unsuspend server(s) by ID (integer) or UUID (string), forgetting the suspensions noted for them.",
 		RunE:  runUnsuspendServer,
 	}
 	addBulkFlags(unsuspendCmd)
@@ -103,29 +160,53 @@ func newServerActionCommands() []*cobra.Command {
 		RunE:  runReinstallServer,
 	}
 	addBulkFlags(reinstallCmd)
+	selector.AddProtectedFlag(reinstallCmd)
 	reinstallCmd.ValidArgsFunction = adminServerValidArgs
 	carapace.Gen(reinstallCmd).PositionalAnyCompletion(carapace.ActionCallback(adminServerCompletionAction))
 
 	healthCmd := &cobra.Command{
 		Use:   "health <id|uuid>...",
 		Short: "Get server health status",
-		Long:  "Get the health status of server(s) by ID (integer) or UUID (string), including container status and optional crash detection",
-		RunE:  runServerHealth,
+		Long: "Get the health status of server(s) by ID (integer) or UUID (string), including container status " +
+			"and optional crash detection.\n\n" +
+			"With --cpu-threshold or --memory-threshold servers using more of their limits are reported as unhealthy, " +
+			"as are crashed ones; this reads resource usage with the client token. With --fail-on-unhealthy the " +
+			"command exits with code 2 if any server is unhealthy, for use as a Nagios or cron check.",
+		RunE: runServerHealth,
 	}
 	addBulkFlags(healthCmd)
 	healthCmd.Flags().String("since", "", "check for crashes since this date-time (RFC3339 format)")
 	healthCmd.Flags().Int("window", 0, "time window in minutes (1-1440) for crash detection")
+	addHealthCheckFlags(healthCmd)
+	watch.AddFlags(healthCmd)
 	healthCmd.ValidArgsFunction = adminServerValidArgs
 	carapace.Gen(healthCmd).PositionalAnyCompletion(carapace.ActionCallback(adminServerCompletionAction))
 
 	return []*cobra.Command{suspendCmd, unsuspendCmd, reinstallCmd, healthCmd}
 }
 
+// serverIncludes are the relationships the panel can include with servers, offered by --include completion.
+//
+//nolint:gochecknoglobals // Fixed list used by completion
+var serverIncludes = []string{
+	"allocations", "user", "subusers", "egg", "variables", "node", "databases", "transfer",
+}
+
 func setupServerCommandCompletion(cmds []*cobra.Command) {
 	for _, cmd := range cmds {
 		if cmd.Use == "view" || cmd.Use == "delete" {
 			carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(adminServerCompletionAction))
 		}
+		if cmd.Flags().Lookup("expand") != nil {
+			carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+				"expand": carapace.ActionValues(expandableReferences...).UniqueList(","),
+			})
+		}
+		if cmd.Flags().Lookup("include") != nil {
+			carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+				"include": carapace.ActionValues(serverIncludes...).UniqueList(","),
+			})
+		}
 	}
 }
 
@@ -140,6 +221,8 @@ func newServerCmd() *cobra.Command {
 	actionCmds := newServerActionCommands()
 	powerCmd := newPowerCmd()
 	backupCmd := newBackupCmd()
+	transferCmd := newServerTransferCmd()
+	buildCmd := newServerBuildCmd()
 
 	// Add all commands FIRST (matching carapace example pattern)
 	for _, c := range basicCmds {
@@ -150,53 +233,92 @@ func newServerCmd() *cobra.Command {
 	}
 	cmd.AddCommand(powerCmd)
 	cmd.AddCommand(backupCmd)
+	cmd.AddCommand(newServerDatabaseCmd())
+	cmd.AddCommand(transferCmd)
+	cmd.AddCommand(buildCmd)
 	cmd.AddCommand(newCommandCmd())
 
 	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
 	setupServerCommandCompletion(basicCmds)
+	setupServerTransferCompletion(transferCmd)
+	carapace.Gen(buildCmd).PositionalCompletion(carapace.ActionCallback(adminServerCompletionAction))
 
 	return cmd
 }
 
-func runServerList(cmd *cobra.Command, _ []string) error {
-	client, err := api.NewApplicationAPI()
+func runServerList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	suspended, _ := cmd.Flags().GetBool("suspended")
+	opts, err := getListOptions(cmd)
 	if err != nil {
 		return err
 	}
+	// The owner column of the wide table needs the user of each server.
+	if getOutputFormat(cmd) == output.OutputFormatWide && !slices.Contains(opts.Include, "user") {
+		opts.Include = append(opts.Include, "user")
+	}
 
-	servers, err := client.ListServers()
+	interval, err := watch.Interval(cmd)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return err
 	}
 
-	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
-	return formatter.PrintWithConfig(servers, output.ResourceTypeAdminServer)
-}
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
 
-func runServerCreate(cmd *cobra.Command, _ []string) error {
-	return runCreateCommand(
-		cmd,
-		func(c *api.ApplicationAPI, data map[string]any) (map[string]any, error) {
-			return c.CreateServer(data)
-		},
-		"Server created successfully",
-	)
+	render := func(out io.Writer) error {
+		servers, listErr := client.ListServersWithOptions(ctx, opts)
+		if listErr != nil {
+			return apierrors.Handle(listErr)
+		}
+
+		formatter := output.NewFormatter(getOutputFormat(cmd), out)
+		if !suspended {
+			return formatter.PrintWithConfig(servers, output.ResourceTypeAdminServer)
+		}
+
+		listed, notes := suspendedServers(servers)
+		now := time.Now()
+		if getOutputFormat(cmd).IsJSON() {
+			for _, server := range listed {
+				id := convertServerIDToString(attribute(server, "id"))
+				server["suspension"] = suspensionRecord(notes[id].Suspension, now)
+			}
+			return formatter.Print(listed)
+		}
+		return printSuspendedServers(formatter, listed, notes, now)
+	}
+	if interval > 0 {
+		return watch.Run(cmd.Context(), os.Stdout, watch.Title(cmd, args), interval, render)
+	}
+	return render(os.Stdout)
 }
 
 func runServerView(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	uuid := args[0]
+	expandValues, _ := cmd.Flags().GetStringSlice("expand")
+	refs, err := parseExpand(expandValues)
+	if err != nil {
+		return err
+	}
 
 	client, err := api.NewApplicationAPI()
 	if err != nil {
 		return err
 	}
 
-	server, err := client.GetServer(uuid)
+	server, err := client.GetServer(ctx, uuid)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if len(refs) > 0 {
+		expandServer(ctx, client, formatter, server, refs)
+	}
 	return formatter.Print(server)
 }
 
@@ -208,32 +330,38 @@ func runServerDelete(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if err := selector.CheckProtected(cmd.Context(), cmd, client, "delete", identifier); err != nil {
+		return err
+	}
 
-	deleteErr := client.DeleteServer(identifier, force)
+	deleteErr := client.DeleteServer(dryRunContext(cmd), identifier, force)
+	if req, ok := api.AsDryRun(deleteErr); ok {
+		return printDryRun(cmd, req)
+	}
 	if deleteErr != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(deleteErr))
+		return apierrors.Handle(deleteErr)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 	formatter.PrintSuccess("Server deleted successfully")
+	formatter.PrintAffected(identifier)
 	return nil
 }
 
 func runSuspendServer(cmd *cobra.Command, args []string) error {
-	return runServerAction(cmd, args, "suspend", func(client *api.ApplicationAPI, uuid string) error {
-		return client.SuspendServer(uuid)
-	}, false)
+	reason, _ := cmd.Flags().GetString("reason")
+	return runServerAction(cmd, args, "suspend", suspendServer(reason), false)
 }
 
 func runUnsuspendServer(cmd *cobra.Command, args []string) error {
-	return runServerAction(cmd, args, "unsuspend", func(client *api.ApplicationAPI, uuid string) error {
-		return client.UnsuspendServer(uuid)
-	}, false)
+	return runServerAction(cmd, args, "unsuspend", unsuspendServer, false)
 }
 
 func runReinstallServer(cmd *cobra.Command, args []string) error {
-	return runServerAction(cmd, args, "reinstall", func(client *api.ApplicationAPI, uuid string) error {
-		return client.ReinstallServer(uuid)
+	return runServerAction(cmd, args, "reinstall", func(
+		ctx context.Context, client *api.ApplicationAPI, uuid string,
+	) error {
+		return client.ReinstallServer(ctx, uuid)
 	}, false)
 }
 
@@ -265,21 +393,10 @@ func parseWindowFlag(cmd *cobra.Command) (*int, error) {
 	return &windowVal, nil
 }
 
-func validateHealthArgs(args []string, flags bulkFlags) error {
-	if len(args) == 0 && !flags.all && flags.fromFile == "" {
-		return errors.New("no servers specified")
-	}
-	return nil
-}
-
-func getHealthServerUUIDs(cmd *cobra.Command, args []string, flags bulkFlags) ([]string, error) {
-	uuids := args
-	if flags.all || flags.fromFile != "" {
-		var err error
-		uuids, err = getServerUUIDs(cmd, args, flags.all, flags.fromFile)
-		if err != nil {
-			return nil, err
-		}
+func getHealthServerUUIDs(cmd *cobra.Command, args []string) ([]string, error) {
+	uuids, err := getServerUUIDs(cmd.Context(), cmd, args)
+	if err != nil {
+		return nil, err
 	}
 	if len(uuids) == 0 {
 		return nil, errors.New("no servers found - try 'pelicanctl admin server list' to see available servers")
@@ -288,15 +405,16 @@ func getHealthServerUUIDs(cmd *cobra.Command, args []string, flags bulkFlags) ([
 }
 
 func runServerHealthSingle(
+	ctx context.Context,
 	client *api.ApplicationAPI,
 	formatter *output.Formatter,
 	uuid string,
 	since *time.Time,
 	window *int,
 ) error {
-	health, healthErr := client.GetServerHealth(uuid, since, window)
+	health, healthErr := client.GetServerHealth(ctx, uuid, since, window)
 	if healthErr != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(healthErr))
+		return apierrors.Handle(healthErr)
 	}
 	return formatter.Print(health)
 }
@@ -309,17 +427,25 @@ func runServerHealthMultiple(
 	since *time.Time,
 	window *int,
 	flags bulkFlags,
+	checks healthChecks,
 ) error {
-	ctx := context.Background()
-	results := executeHealthOperations(ctx, client, uuids, since, window, flags)
+	ctx := cmd.Context()
+	results := executeHealthOperations(ctx, client, uuids, since, window, flags, checks)
 
-	if getOutputFormat(cmd) == output.OutputFormatJSON {
-		return printHealthResultsJSON(formatter, results)
+	var err error
+	if getOutputFormat(cmd).IsJSON() {
+		err = printHealthResultsJSON(formatter, results)
+	} else {
+		err = printHealthResultsTable(formatter, results, checks)
 	}
-	return printHealthResultsTable(formatter, results)
+	if err != nil || !checks.failOnUnhealthy {
+		return err
+	}
+	return unhealthyError(results)
 }
 
 func runServerHealth(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	flags := getBulkFlags(cmd)
 
 	since, err := parseSinceFlag(cmd)
@@ -332,11 +458,17 @@ func runServerHealth(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err = validateHealthArgs(args, flags); err != nil {
+	interval, err := watch.Interval(cmd)
+	if err != nil {
+		return err
+	}
+
+	checks, err := getHealthChecks(cmd, interval > 0)
+	if err != nil {
 		return err
 	}
 
-	uuids, err := getHealthServerUUIDs(cmd, args, flags)
+	uuids, err := getHealthServerUUIDs(cmd, args)
 	if err != nil {
 		return err
 	}
@@ -355,18 +487,35 @@ func runServerHealth(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-
-	if len(uuids) == 1 {
-		return runServerHealthSingle(client, formatter, uuids[0], since, window)
+	if checks.thresholds() {
+		if checks.client, err = api.NewClientAPI(); err != nil {
+			return fmt.Errorf("--cpu-threshold and --memory-threshold read resource usage with the client token: %w", err)
+		}
 	}
 
-	return runServerHealthMultiple(cmd, client, formatter, uuids, since, window, flags)
+	render := func(out io.Writer) error {
+		formatter := output.NewFormatter(getOutputFormat(cmd), out)
+		// With checks every server is reported with its problems, even a single one.
+		if len(uuids) == 1 && !checks.enabled() {
+			return runServerHealthSingle(ctx, client, formatter, uuids[0], since, window)
+		}
+		return runServerHealthMultiple(cmd, client, formatter, uuids, since, window, flags, checks)
+	}
+	if interval > 0 {
+		return watch.Run(cmd.Context(), os.Stdout, watch.Title(cmd, args), interval, render)
+	}
+	return render(os.Stdout)
 }
 
 type healthResult struct {
 	Server string
 	Health map[string]any
 	Error  error
+	// Checked is set for results of health checks, see healthChecks. Problems lists why the server is
+	// unhealthy, and Usage is its resource usage if thresholds were checked.
+	Checked  bool
+	Problems []string
+	Usage    *serverUsage
 }
 
 func executeHealthOperations(
@@ -376,6 +525,7 @@ func executeHealthOperations(
 	since *time.Time,
 	window *int,
 	flags bulkFlags,
+	checks healthChecks,
 ) []healthResult {
 	operations := make([]bulk.Operation, len(uuids))
 	resultsMap := make(map[string]*healthResult, len(uuids))
@@ -386,24 +536,47 @@ func executeHealthOperations(
 	}
 
 	// Create operations
+	resolution := client.ResolveServerIDs(ctx, uuids)
 	for i, uuid := range uuids {
 		result := resultsMap[uuid]
 		operations[i] = bulk.Operation{
 			ID:   uuid,
 			Name: uuid,
-			Exec: func() error {
-				health, err := client.GetServerHealth(uuid, since, window)
+			Exec: func(opCtx context.Context) error {
+				serverID, err := resolution.Lookup(uuid)
+				if err != nil {
+					result.Error = err
+					return err
+				}
+				health, err := client.GetServerHealth(opCtx, serverID, since, window)
 				if err != nil {
 					result.Error = err
 					return err
 				}
-				result.Health = health
+				var usage *serverUsage
+				if checks.thresholds() {
+					if usage, err = checks.fetchUsage(opCtx, client, serverID, health); err != nil {
+						result.Error = err
+						return err
+					}
+				}
+				result.Health, result.Error = health, nil
+				if checks.enabled() {
+					result.Checked, result.Usage, result.Problems = true, usage, checks.problems(health, usage)
+				}
 				return nil
 			},
 		}
 	}
 
-	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast)
+	executor := flags.executor().
+		WithRecord(func(bulkResult bulk.Result) map[string]any {
+			result := *resultsMap[bulkResult.Operation.ID]
+			if !bulkResult.Success && result.Error == nil {
+				result.Error = bulkResult.Error
+			}
+			return healthRecord(result)
+		})
 	bulkResults := executor.Execute(ctx, operations)
 
 	// Update results with errors from bulk executor if not already set
@@ -425,54 +598,56 @@ func executeHealthOperations(
 }
 
 func printHealthResultsJSON(formatter *output.Formatter, results []healthResult) error {
-	outputData := make([]map[string]any, 0, len(results))
-	var succeeded, failed int
+	records := make([]map[string]any, 0, len(results))
+	summary := bulk.Summary{Total: len(results)}
 
 	for _, result := range results {
+		records = append(records, healthRecord(result))
 		if result.Error != nil {
-			outputData = append(outputData, map[string]any{
-				"server_identifier": result.Server,
-				"status":            "error",
-				"error":             result.Error.Error(),
-			})
-			failed++
+			summary.Failed++
 		} else {
-			// Include full health data (checked_at, container, crash_details, crashed, server)
-			healthData := make(map[string]any)
-			maps.Copy(healthData, result.Health)
-			// Add server_identifier and status to the health data
-			healthData["server_identifier"] = result.Server
-			healthData["status"] = "success"
-			outputData = append(outputData, healthData)
-			succeeded++
+			summary.Success++
 		}
 	}
 
-	response := map[string]any{
-		"results": outputData,
-		"summary": map[string]any{
-			"succeeded": succeeded,
-			"failed":    failed,
-		},
-	}
-
-	if err := formatter.Print(response); err != nil {
+	if err := formatter.Print(bulk.Response(records, summary)); err != nil {
 		return err
 	}
 
-	if failed > 0 {
-		return fmt.Errorf("%d operation(s) failed", failed)
+	if summary.Failed > 0 {
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
 }
 
-const unknownStatus = "unknown"
+// healthRecord is the JSON record of a health check result.
+func healthRecord(result healthResult) map[string]any {
+	if result.Error != nil {
+		return map[string]any{
+			"server_identifier": result.Server,
+			"status":            "error",
+			"error":             result.Error.Error(),
+		}
+	}
 
-const (
-	statusSuccess = "success"
-	statusError   = "error"
-)
+	// Include full health data (checked_at, container, crash_details, crashed, server)
+	healthData := make(map[string]any)
+	maps.Copy(healthData, result.Health)
+	// Add server_identifier and status to the health data
+	healthData["server_identifier"] = result.Server
+	healthData["status"] = "success"
+	if result.Checked {
+		healthData["unhealthy"] = len(result.Problems) > 0
+		healthData["problems"] = result.Problems
+		if result.Usage != nil {
+			healthData["usage"] = usageRecord(result.Usage)
+		}
+	}
+	return healthData
+}
+
+const unknownStatus = "unknown"
 
 func extractServerName(health map[string]any) string {
 	server, ok := health["server"].(map[string]any)
@@ -517,17 +692,17 @@ func extractCrashedStatus(health map[string]any) string {
 	return "false"
 }
 
-func extractCheckedAt(health map[string]any) string {
+func extractCheckedAt(formatter *output.Formatter, health map[string]any) string {
 	ca, ok := health["checked_at"].(string)
 	if !ok {
 		return unknownStatus
 	}
-	return ca
+	return formatter.RelativeTime(ca)
 }
 
-func buildHealthRow(result healthResult) []string {
+func buildHealthRow(formatter *output.Formatter, result healthResult, checks healthChecks) []string {
 	if result.Error != nil {
-		return []string{
+		row := []string{
 			result.Server,
 			"",
 			"error",
@@ -535,32 +710,52 @@ func buildHealthRow(result healthResult) []string {
 			"",
 			"",
 		}
+		if checks.thresholds() {
+			row = append(row, "", "")
+		}
+		if checks.enabled() {
+			row = append(row, "")
+		}
+		return row
 	}
 
 	serverName := extractServerName(result.Health)
 	containerStatus, healthy := extractContainerInfo(result.Health)
 	crashed := extractCrashedStatus(result.Health)
-	checkedAt := extractCheckedAt(result.Health)
+	checkedAt := extractCheckedAt(formatter, result.Health)
 
-	return []string{
-		result.Server,
+	row := []string{
+		output.CanonicalServer(result.Server),
 		serverName,
 		containerStatus,
 		healthy,
 		crashed,
 		checkedAt,
 	}
+	if checks.thresholds() {
+		row = append(row, usageColumns(result.Usage)...)
+	}
+	if checks.enabled() {
+		row = append(row, strings.Join(result.Problems, ", "))
+	}
+	return row
 }
 
-func printHealthResultsTable(formatter *output.Formatter, results []healthResult) error {
+func printHealthResultsTable(formatter *output.Formatter, results []healthResult, checks healthChecks) error {
 	headers := []string{"Server", "Name", "Container Status", "Healthy", "Crashed", "Checked At"}
+	if checks.thresholds() {
+		headers = append(headers, "CPU", "Memory")
+	}
+	if checks.enabled() {
+		headers = append(headers, "Problems")
+	}
 	rows := make([][]string, 0, len(results))
 
 	for _, result := range results {
 		if result.Error != nil {
-			formatter.PrintError("%s: %v", result.Server, result.Error)
+			formatter.PrintError("%s: %v", output.ServerLabel(result.Server), result.Error)
 		}
-		rows = append(rows, buildHealthRow(result))
+		rows = append(rows, buildHealthRow(formatter, result, checks))
 	}
 
 	return formatter.PrintTable(headers, rows)
@@ -586,6 +781,7 @@ func createPowerSubcommand(use, short, long string, runE func(*cobra.Command, []
 		RunE:  runE,
 	}
 	addBulkFlags(cmd)
+	selector.AddProtectedFlag(cmd)
 	cmd.ValidArgsFunction = adminServerValidArgsFunction
 	return cmd
 }
@@ -630,8 +826,10 @@ func newPowerCmd() *cobra.Command {
 }
 
 func runPowerCommand(cmd *cobra.Command, args []string, command string) error {
-	return runServerAction(cmd, args, command, func(client *api.ApplicationAPI, identifier string) error {
-		return client.SendPowerCommand(identifier, command)
+	return runServerAction(cmd, args, command, func(
+		ctx context.Context, client *api.ApplicationAPI, identifier string,
+	) error {
+		return client.SendPowerCommand(ctx, identifier, command)
 	}, true)
 }
 
@@ -668,30 +866,25 @@ func newCommandCmd() *cobra.Command {
 }
 
 func runServerCommand(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	command, _ := cmd.Flags().GetString("command")
 	if command == "" {
 		return errors.New("--command flag is required")
 	}
 
-	// For command, we need special handling to include the command in JSON output
-	if len(args) == 0 {
-		return errors.New("no servers specified")
-	}
-
 	flags := getBulkFlags(cmd)
 
-	uuids := args
-	if flags.all || flags.fromFile != "" {
-		var err error
-		uuids, err = getServerUUIDs(cmd, args, flags.all, flags.fromFile)
-		if err != nil {
-			return err
-		}
+	uuids, err := getServerUUIDs(ctx, cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(uuids) == 0 {
+		return errors.New("no servers specified")
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 
-	shouldContinue, err := handleConfirmation(formatter, "command", len(uuids), flags.yes)
+	shouldContinue, err := handleConfirmation(cmd, formatter, "command", len(uuids))
 	if err != nil {
 		return err
 	}
@@ -709,13 +902,14 @@ func runServerCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ctx := context.Background()
-	results := executeBulkOperations(ctx, client, uuids, func(client *api.ApplicationAPI, identifier string) error {
-		return client.SendCommand(identifier, command)
-	}, flags)
+	results := executeBulkOperations(ctx, client, uuids, func(
+		ctx context.Context, client *api.ApplicationAPI, identifier string,
+	) error {
+		return client.SendCommand(ctx, identifier, command)
+	}, fieldRecord("command", command), flags)
 
 	// Handle JSON output specially
-	if getOutputFormat(cmd) == output.OutputFormatJSON {
+	if getOutputFormat(cmd).IsJSON() {
 		summary := bulk.GetSummary(results)
 		return printCommandResultsJSON(formatter, results, command, summary, flags.continueOnError)
 	}
@@ -735,60 +929,57 @@ func printCommandResultsJSON(
 	return printResultsJSONWithField(formatter, results, "command", command, summary, continueOnError)
 }
 
-type serverActionFunc func(client *api.ApplicationAPI, uuid string) error
+type serverActionFunc func(ctx context.Context, client *api.ApplicationAPI, uuid string) error
 
 type bulkFlags struct {
-	all             bool
-	fromFile        string
 	maxConcurrency  int
 	continueOnError bool
 	failFast        bool
+	retries         int
+	retryBackoff    time.Duration
+	opTimeout       time.Duration
 	dryRun          bool
-	yes             bool
 }
 
 func getBulkFlags(cmd *cobra.Command) bulkFlags {
-	all, _ := cmd.Flags().GetBool("all")
-	fromFile, _ := cmd.Flags().GetString("from-file")
 	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
 	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
 	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	retries, _ := cmd.Flags().GetInt("op-retries")
+	retryBackoff, _ := cmd.Flags().GetDuration("op-retry-backoff")
+	opTimeout, _ := cmd.Flags().GetDuration("op-timeout")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
-	yes, _ := cmd.Flags().GetBool("yes")
 
 	return bulkFlags{
-		all:             all,
-		fromFile:        fromFile,
 		maxConcurrency:  maxConcurrency,
 		continueOnError: continueOnError,
 		failFast:        failFast,
+		retries:         retries,
+		retryBackoff:    retryBackoff,
+		opTimeout:       opTimeout,
 		dryRun:          dryRun,
-		yes:             yes,
 	}
 }
 
-func handleConfirmation(formatter *output.Formatter, actionName string, uuidCount int, yes bool) (bool, error) {
-	if yes {
-		return true, nil
-	}
+// executor creates the executor of a bulk command, with the retries and timeout of its flags.
+func (f bulkFlags) executor() *bulk.Executor {
+	return bulk.NewExecutor(f.maxConcurrency, f.continueOnError, f.failFast).
+		WithRetry(f.retries, f.retryBackoff).
+		WithTimeout(f.opTimeout)
+}
 
+func handleConfirmation(
+	cmd *cobra.Command,
+	formatter *output.Formatter,
+	actionName string,
+	uuidCount int,
+) (bool, error) {
 	// Require confirmation for destructive actions
 	needsConfirmation := actionName == "reinstall" || actionName == "kill" || (actionName == "stop" && uuidCount > 1)
 	if !needsConfirmation {
 		return true, nil
 	}
-
-	formatter.PrintInfo("This will %s %d server(s). Continue? (y/N): ", actionName, uuidCount)
-	var response string
-	if _, err := fmt.Scanln(&response); err != nil {
-		return false, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-		return false, nil
-	}
-
-	return true, nil
+	return output.Confirm(cmd, formatter, fmt.Sprintf("This will %s %d server(s).", actionName, uuidCount))
 }
 
 func handleDryRun(formatter *output.Formatter, actionName string, uuids []string) {
@@ -803,20 +994,27 @@ func executeBulkOperations(
 	client *api.ApplicationAPI,
 	uuids []string,
 	action serverActionFunc,
+	record bulk.RecordFunc,
 	flags bulkFlags,
 ) []bulk.Result {
+	// Resolve every server up front, so the operations don't each look theirs up.
+	resolution := client.ResolveServerIDs(ctx, uuids)
 	operations := make([]bulk.Operation, len(uuids))
 	for i, uuid := range uuids {
 		operations[i] = bulk.Operation{
 			ID:   uuid,
 			Name: uuid,
-			Exec: func() error {
-				return action(client, uuid)
+			Exec: func(opCtx context.Context) error {
+				serverID, err := resolution.Lookup(uuid)
+				if err != nil {
+					return err
+				}
+				return action(opCtx, client, serverID)
 			},
 		}
 	}
 
-	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast)
+	executor := flags.executor().WithRecord(record)
 	return executor.Execute(ctx, operations)
 }
 
@@ -840,30 +1038,13 @@ func printResultsJSONWithField(
 	summary bulk.Summary,
 	continueOnError bool,
 ) error {
-	outputData := make([]map[string]any, 0, len(results))
-
+	record := fieldRecord(fieldName, fieldValue)
+	records := make([]map[string]any, 0, len(results))
 	for _, result := range results {
-		resultData := map[string]any{
-			"server_identifier": result.Operation.ID,
-			fieldName:           fieldValue,
-		}
-		if result.Success {
-			resultData["status"] = statusSuccess
-		} else {
-			resultData["status"] = statusError
-			resultData["error"] = result.Error.Error()
-		}
-		outputData = append(outputData, resultData)
+		records = append(records, record(result))
 	}
 
-	// Include summary in the output
-	response := map[string]any{
-		"results": outputData,
-		"summary": map[string]any{
-			"succeeded": summary.Success,
-			"failed":    summary.Failed,
-		},
-	}
+	response := bulk.Response(records, summary)
 
 	if err := formatter.Print(response); err != nil {
 		return err
@@ -871,28 +1052,38 @@ func printResultsJSONWithField(
 
 	// Check failures based on continue-on-error flag
 	if summary.Failed > 0 && !continueOnError {
-		return fmt.Errorf("%d operation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
 }
 
+// fieldRecord returns the JSON record of a result of an operation on a server, with an extra field
+// naming what was done.
+func fieldRecord(fieldName, fieldValue string) bulk.RecordFunc {
+	return func(result bulk.Result) map[string]any {
+		record := bulk.ServerRecord(result)
+		record[fieldName] = fieldValue
+		return record
+	}
+}
+
 func printResults(formatter *output.Formatter, results []bulk.Result, actionName string) {
 	for _, result := range results {
 		if result.Success {
-			formatter.PrintSuccess("%s: %s", result.Operation.ID, actionName)
+			formatter.PrintSuccess("%s: %s", output.ServerLabel(result.Operation.ID), actionName)
 		} else {
-			formatter.PrintError("%s: %v", result.Operation.ID, result.Error)
+			formatter.PrintError("%s: %v", output.ServerLabel(result.Operation.ID), result.Error)
 		}
 	}
 }
 
 func handleSummary(formatter *output.Formatter, results []bulk.Result, continueOnError bool) error {
 	summary := bulk.GetSummary(results)
-	formatter.PrintInfo("Summary: %d succeeded, %d failed", summary.Success, summary.Failed)
+	formatter.PrintInfo("Summary: %s", summary)
 
 	if summary.Failed > 0 && !continueOnError {
-		return fmt.Errorf("%d operation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
@@ -905,26 +1096,32 @@ func runServerAction(
 	action serverActionFunc,
 	minimalJSON bool,
 ) error {
-	if len(args) == 0 {
-		return errors.New("no servers specified")
-	}
-
+	ctx := cmd.Context()
 	flags := getBulkFlags(cmd)
 
-	uuids := args
-	if flags.all || flags.fromFile != "" {
-		var err error
-		uuids, err = getServerUUIDs(cmd, args, flags.all, flags.fromFile)
-		if err != nil {
-			return err
-		}
+	uuids, err := getServerUUIDs(ctx, cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(uuids) == 0 {
+		return errors.New("no servers specified")
 	}
 
 	// Store output format to ensure consistency
 	outputFormat := getOutputFormat(cmd)
 	formatter := output.NewFormatter(outputFormat, os.Stdout)
 
-	shouldContinue, err := handleConfirmation(formatter, actionName, len(uuids), flags.yes)
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	uuids = selector.SkipProtected(ctx, cmd, formatter, client, actionName, uuids)
+	if len(uuids) == 0 {
+		return errors.New("all specified servers are protected")
+	}
+
+	shouldContinue, err := handleConfirmation(cmd, formatter, actionName, len(uuids))
 	if err != nil {
 		return err
 	}
@@ -937,18 +1134,16 @@ func runServerAction(
 		return nil
 	}
 
-	client, err := api.NewApplicationAPI()
-	if err != nil {
-		return err
+	record := fieldRecord("action", actionName)
+	if minimalJSON {
+		record = bulk.ServerRecord
 	}
-
-	ctx := context.Background()
-	results := executeBulkOperations(ctx, client, uuids, action, flags)
+	results := executeBulkOperations(ctx, client, uuids, action, record, flags)
 
 	summary := bulk.GetSummary(results)
 
 	// Handle JSON output specially
-	if outputFormat == output.OutputFormatJSON {
+	if outputFormat.IsJSON() {
 		if minimalJSON {
 			return bulk.PrintBulkJSON(formatter, results, summary, flags.continueOnError)
 		}
@@ -961,12 +1156,30 @@ func runServerAction(
 }
 
 func addBulkFlags(cmd *cobra.Command) {
-	cmd.Flags().Bool("all", false, "operate on all servers")
-	cmd.Flags().String("from-file", "", "read server UUIDs from file (one per line)")
+	selector.AddTargetFlags(cmd, true)
+	completion.RegisterFlagFunc(cmd, "node", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteNodes(toComplete)
+	})
+	completion.RegisterFlagFunc(cmd, "owner", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteUsers(toComplete)
+	})
+	completion.RegisterFlagFunc(cmd, "group", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteGroups(toComplete)
+	})
+	addExecutionFlags(cmd)
+}
+
+// addExecutionFlags adds the flags that control how the operations of a bulk command run, for
+// commands that pick their servers themselves rather than from the target flags.
+func addExecutionFlags(cmd *cobra.Command) {
 	const defaultMaxConcurrency = 10
 	cmd.Flags().Int("max-concurrency", defaultMaxConcurrency, "maximum parallel operations")
 	cmd.Flags().Bool("continue-on-error", false, "continue on errors")
 	cmd.Flags().Bool("fail-fast", false, "stop on first error")
+	cmd.Flags().Int("op-retries", 0, "run a failed operation again up to this many times")
+	cmd.Flags().Duration("op-retry-backoff", time.Second,
+		"wait before retrying an operation, doubled for every further retry")
+	cmd.Flags().Duration("op-timeout", 0, "give up on an attempt of an operation after this long; 0 means no limit")
 	cmd.Flags().Bool("dry-run", false, "preview operations without executing")
 	cmd.Flags().Bool("yes", false, "skip confirmation prompts")
 }
@@ -986,108 +1199,74 @@ func convertServerIDToString(id any) string {
 	}
 }
 
-func extractUUIDsFromServers(servers []map[string]any) ([]string, error) {
-	if len(servers) == 0 {
-		return nil, errors.New("no servers found in the system")
-	}
-
-	var uuids []string
-	for _, server := range servers {
-		// Try UUID first
-		if uuid, ok := server["uuid"].(string); ok && uuid != "" {
-			uuids = append(uuids, uuid)
-			continue
-		}
-		// Fallback to ID if UUID not available
-		if id := extractServerID(server); id != nil {
-			if idStr := convertServerIDToString(id); idStr != "" {
-				uuids = append(uuids, idStr)
-			}
-		}
-	}
-
-	if len(uuids) == 0 {
-		return nil, errors.New("no valid server identifiers found (servers may be missing uuid or id fields)")
-	}
-	return uuids, nil
-}
-
-func getServerUUIDsFromAll() ([]string, error) {
+// adminServerSource lists every server on the panel for the server selection of bulk commands.
+func adminServerSource(ctx context.Context) ([]index.Entry, error) {
 	client, err := api.NewApplicationAPI()
 	if err != nil {
 		return nil, err
 	}
-
-	servers, err := client.ListServers()
+	entries, err := api.ServerEntries(ctx, client)
 	if err != nil {
-		return nil, err
+		return nil, apierrors.Handle(err)
 	}
-
-	return extractUUIDsFromServers(servers)
+	return entries, nil
 }
 
-func getServerUUIDsFromFile(fromFile string) ([]string, error) {
-	data, err := os.ReadFile(fromFile)
+// resolveSelector resolves the node and owner of --node and --owner given by name to their IDs.
+func resolveSelector(ctx context.Context, sel *selector.Selector) error {
+	if sel.Node == "" && sel.Owner == "" {
+		return nil
+	}
+	client, err := api.NewApplicationAPI()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return err
 	}
-
-	var uuids []string
-	for line := range strings.SplitSeq(string(data), "\n") {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			uuids = append(uuids, line)
-		}
+	if sel.Node, err = resolveNodeID(ctx, client, sel.Node); err != nil {
+		return err
 	}
-	return uuids, nil
+	sel.Owner, err = resolveUserID(ctx, client, sel.Owner)
+	return err
 }
 
-func getServerUUIDsFromArgs(args []string) []string {
-	var uuids []string
-	// Support both space-separated and comma-separated arguments
-	// e.g., "123 456" or "123,456" or "123,456 789" (mixed)
-	for _, arg := range args {
-		// Split by comma and trim whitespace
-		parts := strings.SplitSeq(arg, ",")
-		for part := range parts {
-			part = strings.TrimSpace(part)
-			if part != "" {
-				uuids = append(uuids, part)
-			}
+// resolveNodeID returns the ID of a node given by ID or name; an empty node stays empty.
+func resolveNodeID(ctx context.Context, client *api.ApplicationAPI, node string) (string, error) {
+	if _, err := strconv.Atoi(node); node == "" || err == nil {
+		return node, nil
+	}
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return "", apierrors.Handle(err)
+	}
+	for _, n := range nodes {
+		if name, _ := attribute(n, "name").(string); strings.EqualFold(name, node) {
+			return convertServerIDToString(attribute(n, "id")), nil
 		}
 	}
-	return uuids
+	return "", apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("node %s not found", node))
 }
 
-func getServerUUIDs(_ *cobra.Command, args []string, all bool, fromFile string) ([]string, error) {
-	switch {
-	case all:
-		return getServerUUIDsFromAll()
-	case fromFile != "":
-		return getServerUUIDsFromFile(fromFile)
-	default:
-		return getServerUUIDsFromArgs(args), nil
+// resolveUserID returns the ID of a user given by ID, username or email; an empty user stays empty.
+func resolveUserID(ctx context.Context, client *api.ApplicationAPI, user string) (string, error) {
+	if user == "" {
+		return user, nil
+	}
+	id, err := client.ResolveUserID(ctx, user)
+	if err != nil {
+		return "", apierrors.Handle(err)
 	}
+	return id, nil
 }
 
-// extractServerID extracts the server ID from a server map, checking both root and attributes.
-func extractServerID(server map[string]any) any {
-	if id, hasID := server["id"]; hasID {
-		return id
-	}
-	if attrs, hasAttrs := server["attributes"].(map[string]any); hasAttrs {
-		if id, hasID := attrs["id"]; hasID {
-			return id
-		}
-	}
-	return nil
+// getServerUUIDs returns the servers a bulk command acts on, see selector.Servers.
+func getServerUUIDs(ctx context.Context, cmd *cobra.Command, args []string) ([]string, error) {
+	return selector.Servers(ctx, cmd, args, adminServerSource, resolveSelector)
 }
 
 func newBackupCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "backup",
 		Short: "Manage server backups",
-		Long:  "List, create, view, and manage server backups",
+		Long:  "List, create, view, prune, and manage server backups",
 	}
 
 	listCmd := &cobra.Command{
@@ -1132,6 +1311,7 @@ func newBackupCmd() *cobra.Command {
 		RunE: runBackupView,
 	}
 	viewCmd.Flags().String("from-file", "", "File containing server+backup pairs (one per line: server-id,backup-uuid)")
+	viewCmd.ValidArgsFunction = adminBackupPairValidArgs
 
 	deleteCmd := &cobra.Command{
 		Use:   "delete <server-id|uuid> <backup-uuid>",
@@ -1140,22 +1320,31 @@ func newBackupCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(minBackupViewArgs),
 		RunE:  runBackupDelete,
 	}
-	deleteCmd.ValidArgsFunction = adminServerValidArgs
+	deleteCmd.ValidArgsFunction = adminBackupPairValidArgs
+
+	pruneCmd := newBackupPruneCmd()
 
 	// Add subcommands
 	cmd.AddCommand(listCmd)
 	cmd.AddCommand(createCmd)
 	cmd.AddCommand(viewCmd)
 	cmd.AddCommand(deleteCmd)
+	cmd.AddCommand(pruneCmd)
 
 	// Set up carapace completion
 	carapace.Gen(listCmd).PositionalCompletion(carapace.ActionCallback(adminServerCompletionAction))
-	carapace.Gen(deleteCmd).PositionalCompletion(carapace.ActionCallback(adminServerCompletionAction))
+	carapace.Gen(viewCmd).PositionalAnyCompletion(carapace.ActionCallback(adminBackupPairCompletionAction))
+	carapace.Gen(deleteCmd).PositionalCompletion(
+		carapace.ActionCallback(adminServerCompletionAction),
+		carapace.ActionCallback(adminBackupPairCompletionAction),
+	)
+	setupBackupPruneCompletion(pruneCmd)
 
 	return cmd
 }
 
 func runBackupList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	serverIdentifier := args[0]
 
 	client, err := api.NewApplicationAPI()
@@ -1163,9 +1352,9 @@ func runBackupList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	backups, err := client.ListBackups(serverIdentifier)
+	backups, err := client.ListBackups(ctx, serverIdentifier)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
@@ -1212,14 +1401,11 @@ func buildBackupData(name, ignorePatterns string, locked, override bool) map[str
 }
 
 // getBackupCreateServerUUIDs gets server UUIDs for backup creation.
-func getBackupCreateServerUUIDs(cmd *cobra.Command, args []string, flags bulkFlags) ([]string, error) {
-	uuids := args
-	if flags.all || flags.fromFile != "" {
-		var err error
-		uuids, err = getServerUUIDs(cmd, args, flags.all, flags.fromFile)
-		if err != nil {
-			return nil, err
-		}
+func getBackupCreateServerUUIDs(cmd *cobra.Command, args []string) ([]string, error) {
+	ctx := cmd.Context()
+	uuids, err := getServerUUIDs(ctx, cmd, args)
+	if err != nil {
+		return nil, err
 	}
 	if len(uuids) == 0 {
 		return nil, errors.New("no servers specified")
@@ -1229,19 +1415,26 @@ func getBackupCreateServerUUIDs(cmd *cobra.Command, args []string, flags bulkFla
 
 // createBackupOperations creates bulk operations for backup creation.
 func createBackupOperations(
+	ctx context.Context,
 	client *api.ApplicationAPI,
 	uuids []string,
 	backupData map[string]any,
 	pairs *[]backupPair,
 	pairsMu *sync.Mutex,
 ) []bulk.Operation {
+	resolution := client.ResolveServerIDs(ctx, uuids)
 	operations := make([]bulk.Operation, len(uuids))
 	for i, uuid := range uuids {
+		serverData := backupDataForServer(backupData, uuid)
 		operations[i] = bulk.Operation{
 			ID:   uuid,
 			Name: uuid,
-			Exec: func() error {
-				backup, createErr := client.CreateBackup(uuid, backupData)
+			Exec: func(opCtx context.Context) error {
+				serverID, lookupErr := resolution.Lookup(uuid)
+				if lookupErr != nil {
+					return lookupErr
+				}
+				backup, createErr := client.CreateBackup(opCtx, serverID, serverData)
 				if createErr != nil {
 					return createErr
 				}
@@ -1257,6 +1450,22 @@ func createBackupOperations(
 	return operations
 }
 
+// backupDataForServer applies the server's configured backup_ignore patterns when no
+// ignore patterns were given on the command line.
+func backupDataForServer(backupData map[string]any, uuid string) map[string]any {
+	if _, ok := backupData["ignored"]; ok {
+		return backupData
+	}
+	patterns := config.Get().ServerSettings(uuid).BackupIgnore
+	if len(patterns) == 0 {
+		return backupData
+	}
+
+	serverData := maps.Clone(backupData)
+	serverData["ignored"] = strings.Join(patterns, "\n")
+	return serverData
+}
+
 // appendBackupPair appends a backup pair to the pairs slice with proper locking.
 func appendBackupPair(pairsMu *sync.Mutex, pairs *[]backupPair, uuid, backupUUID string) {
 	pairsMu.Lock()
@@ -1323,33 +1532,12 @@ func printBackupCreateResultsJSON(
 	continueOnError bool,
 ) error {
 	pairsMap := buildBackupPairsMap(pairs)
-	outputData := make([]map[string]any, 0, len(results))
-
+	records := make([]map[string]any, 0, len(results))
 	for _, result := range results {
-		resultData := map[string]any{
-			"server_identifier": result.Operation.ID,
-		}
-		if result.Success {
-			resultData["status"] = "success"
-			// Include backup UUID if available
-			if backupUUID, ok := pairsMap[result.Operation.ID]; ok {
-				resultData["backup_uuid"] = backupUUID
-			}
-		} else {
-			resultData["status"] = "error"
-			resultData["error"] = result.Error.Error()
-		}
-		outputData = append(outputData, resultData)
+		records = append(records, backupCreateRecord(result, pairsMap))
 	}
 
-	// Include summary in the output
-	response := map[string]any{
-		"results": outputData,
-		"summary": map[string]any{
-			"succeeded": summary.Success,
-			"failed":    summary.Failed,
-		},
-	}
+	response := bulk.Response(records, summary)
 
 	if err := formatter.Print(response); err != nil {
 		return err
@@ -1357,19 +1545,29 @@ func printBackupCreateResultsJSON(
 
 	// Check failures based on continue-on-error flag
 	if summary.Failed > 0 && !continueOnError {
-		return fmt.Errorf("%d backup creation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d backup creation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
 }
 
+// backupCreateRecord is the JSON record of a backup creation result, with the UUID of the created
+// backup if it is known.
+func backupCreateRecord(result bulk.Result, pairsMap map[string]string) map[string]any {
+	record := bulk.ServerRecord(result)
+	if backupUUID, ok := pairsMap[result.Operation.ID]; ok && result.Success {
+		record["backup_uuid"] = backupUUID
+	}
+	return record
+}
+
 // printBackupCreateResults prints the results of backup creation operations.
 func printBackupCreateResults(formatter *output.Formatter, results []bulk.Result) {
 	for _, result := range results {
 		if result.Success {
-			formatter.PrintSuccess("%s: backup created", result.Operation.ID)
+			formatter.PrintSuccess("%s: backup created", output.ServerLabel(result.Operation.ID))
 		} else {
-			formatter.PrintError("%s: %v", result.Operation.ID, result.Error)
+			formatter.PrintError("%s: %v", output.ServerLabel(result.Operation.ID), result.Error)
 		}
 	}
 }
@@ -1459,7 +1657,7 @@ func runBackupCreate(cmd *cobra.Command, args []string) error {
 	backupData := buildBackupData(name, ignorePatterns, locked, override)
 
 	// Get server identifiers
-	uuids, err := getBackupCreateServerUUIDs(cmd, args, flags)
+	uuids, err := getBackupCreateServerUUIDs(cmd, args)
 	if err != nil {
 		return err
 	}
@@ -1486,15 +1684,20 @@ func runBackupCreate(cmd *cobra.Command, args []string) error {
 	var pairsMu sync.Mutex
 
 	// Create and execute operations
-	ctx := context.Background()
-	operations := createBackupOperations(client, uuids, backupData, &pairs, &pairsMu)
-	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast)
+	ctx := cmd.Context()
+	operations := createBackupOperations(ctx, client, uuids, backupData, &pairs, &pairsMu)
+	executor := flags.executor().
+		WithRecord(func(result bulk.Result) map[string]any {
+			pairsMu.Lock()
+			defer pairsMu.Unlock()
+			return backupCreateRecord(result, buildBackupPairsMap(pairs))
+		})
 	results := executor.Execute(ctx, operations)
 
 	summary := bulk.GetSummary(results)
 
 	// Handle JSON output specially
-	isJSON := getOutputFormat(cmd) == output.OutputFormatJSON
+	isJSON := getOutputFormat(cmd).IsJSON()
 	if isJSON {
 		// Save pairs if requested (before JSON output)
 		if saveErr := saveBackupPairs(formatter, pairs, savePairs, isJSON); saveErr != nil {
@@ -1513,7 +1716,7 @@ func runBackupCreate(cmd *cobra.Command, args []string) error {
 
 	// Print summary
 	if summary.Failed > 0 && !flags.continueOnError {
-		return fmt.Errorf("%d backup creation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d backup creation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
@@ -1567,11 +1770,16 @@ func parseBackupPairsFromArgs(args []string) ([]backupPair, error) {
 }
 
 // runBackupViewJSON fetches backups for each pair and prints bulk-style JSON.
-func runBackupViewJSON(client *api.ApplicationAPI, formatter *output.Formatter, pairs []backupPair) error {
+func runBackupViewJSON(
+	ctx context.Context,
+	client *api.ApplicationAPI,
+	formatter *output.Formatter,
+	pairs []backupPair,
+) error {
 	results := make([]map[string]any, 0, len(pairs))
 	var succeeded, failed int
 	for _, pair := range pairs {
-		backup, getErr := client.GetBackup(pair.ServerID, pair.BackupUUID)
+		backup, getErr := client.GetBackup(ctx, pair.ServerID, pair.BackupUUID)
 		if getErr != nil {
 			results = append(results, map[string]any{
 				"server_identifier": pair.ServerID,
@@ -1610,12 +1818,13 @@ func runBackupViewJSON(client *api.ApplicationAPI, formatter *output.Formatter,
 		return printErr
 	}
 	if failed > 0 {
-		return fmt.Errorf("%d operation(s) failed", failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", failed), failed, len(results))
 	}
 	return nil
 }
 
 func runBackupView(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	fromFile, _ := cmd.Flags().GetString("from-file")
 
 	var pairs []backupPair
@@ -1640,14 +1849,14 @@ func runBackupView(cmd *cobra.Command, args []string) error {
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 
-	if getOutputFormat(cmd) == output.OutputFormatJSON {
-		return runBackupViewJSON(client, formatter, pairs)
+	if getOutputFormat(cmd).IsJSON() {
+		return runBackupViewJSON(ctx, client, formatter, pairs)
 	}
 
 	// Table path: unchanged
 	var allBackups []map[string]any
 	for _, pair := range pairs {
-		backup, getErr := client.GetBackup(pair.ServerID, pair.BackupUUID)
+		backup, getErr := client.GetBackup(ctx, pair.ServerID, pair.BackupUUID)
 		if getErr != nil {
 			formatter.PrintError("%s/%s: %v", pair.ServerID, pair.BackupUUID, getErr)
 			continue
@@ -1663,6 +1872,7 @@ func runBackupView(cmd *cobra.Command, args []string) error {
 }
 
 func runBackupDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	serverIdentifier := args[0]
 	backupUUID := args[1]
 
@@ -1671,16 +1881,17 @@ func runBackupDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	err = client.DeleteBackup(serverIdentifier, backupUUID)
+	err = client.DeleteBackup(ctx, serverIdentifier, backupUUID)
 	if err != nil {
 		// Return formatted error message directly to avoid duplicate printing
-		return errors.New(apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	// Only show success message if no error was returned.
 	// API returns 204 No Content on success, so if we get here, deletion succeeded.
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 	formatter.PrintSuccess("Backup deleted successfully")
+	formatter.PrintAffected(backupUUID)
 	return nil
 }
 
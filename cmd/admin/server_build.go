@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// serverBuildLimits are the flags of server build that set one limit, with the object of the build it is in.
+//
+//nolint:gochecknoglobals // Fixed list of flags
+var serverBuildLimits = []struct {
+	object string
+	flag   string
+	usage  string
+}{
+	{"limits", "memory", "memory limit in MiB (0 for unlimited)"},
+	{"limits", "swap", "swap limit in MiB (-1 for unlimited)"},
+	{"limits", "disk", "disk limit in MiB (0 for unlimited)"},
+	{"limits", "io", "block IO weight (10 to 1000)"},
+	{"limits", "cpu", "CPU limit in percent of a core (0 for unlimited)"},
+	{"feature_limits", "databases", "number of databases the server may create"},
+	{"feature_limits", "allocations", "number of allocations the server may use"},
+	{"feature_limits", "backups", "number of backups the server may keep"},
+}
+
+func newServerBuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build <id|uuid>",
+		Short: "Update the resource and feature limits of a server",
+		Long: "Update the resource and feature limits of a server by ID (integer) or UUID (string). Limits " +
+			"not given keep their current value. Set limits with flags, or as JSON via --data flag or stdin " +
+			`(e.g. {"limits": {"memory": 8192}}); flags override the same limits in the JSON data. With ` +
+			"--diff, the limits that change are shown against the current server and confirmed first.",
+		Args: cobra.ExactArgs(1),
+		RunE: runServerBuild,
+	}
+	for _, limit := range serverBuildLimits {
+		cmd.Flags().Int(limit.flag, 0, limit.usage)
+	}
+	cmd.Flags().Bool("oom-killer", false, "whether the OOM killer may stop the server when it runs out of memory")
+	cmd.Flags().String("data", "", "JSON with the limits, feature_limits or oom_killer to change")
+	cmd.Flags().Bool("diff", false, "show the limits that change and ask for confirmation before updating")
+	cmd.Flags().Bool("yes", false, "skip the confirmation prompt of --diff")
+	cmd.ValidArgsFunction = adminServerValidArgs
+	return cmd
+}
+
+func runServerBuild(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverID := args[0]
+
+	changes, err := serverBuildChanges(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	if showDiff, _ := cmd.Flags().GetBool("diff"); showDiff {
+		current, getErr := client.GetServer(ctx, serverID)
+		if getErr != nil {
+			return apierrors.Handle(getErr)
+		}
+		proceed, confirmErr := confirmFieldChanges(cmd, "server", serverID, current, changes)
+		if confirmErr != nil || !proceed {
+			return confirmErr
+		}
+	}
+
+	result, err := client.UpdateServerBuild(ctx, serverID, changes)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Limits of server %s updated", output.ServerLabel(serverID))
+	return formatter.Print(result)
+}
+
+// serverBuildChanges collects the build fields to change from the limit flags and from --data or stdin.
+// JSON data is only required when no limit flag is given.
+func serverBuildChanges(cmd *cobra.Command) (map[string]any, error) {
+	flagsGiven := cmd.Flags().Changed("oom-killer")
+	for _, limit := range serverBuildLimits {
+		flagsGiven = flagsGiven || cmd.Flags().Changed(limit.flag)
+	}
+
+	changes := map[string]any{}
+	if dataFlag, _ := cmd.Flags().GetString("data"); dataFlag != "" || !flagsGiven {
+		data, err := parseJSONData(cmd)
+		if err != nil {
+			return nil, err
+		}
+		changes = data
+	}
+	for field := range changes {
+		if !slices.Contains(api.ServerBuildFields, field) {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation, fmt.Errorf(
+				"%s is not a build field (must be one of %s)", field, strings.Join(api.ServerBuildFields, ", ")))
+		}
+	}
+
+	for _, limit := range serverBuildLimits {
+		if cmd.Flags().Changed(limit.flag) {
+			nestedMap(changes, limit.object)[limit.flag], _ = cmd.Flags().GetInt(limit.flag)
+		}
+	}
+	if cmd.Flags().Changed("oom-killer") {
+		changes["oom_killer"], _ = cmd.Flags().GetBool("oom-killer")
+	}
+	if len(changes) == 0 {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation, errors.New("no limits to change"))
+	}
+	return changes, nil
+}
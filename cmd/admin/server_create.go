@@ -0,0 +1,267 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// serverCreateFlags are the flags of server create that set single fields of the create request.
+//
+//nolint:gochecknoglobals // Fixed list of flags
+var serverCreateFlags = []string{"name", "user", "egg", "node", "allocation", "memory", "disk", "cpu", "env"}
+
+// Limits that --memory, --disk and --cpu leave unset when a server is created from flags.
+const (
+	defaultServerSwap = 0
+	defaultServerIO   = 500
+)
+
+// addServerCreateFlags adds the flags that build a server create request without JSON.
+func addServerCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "name of the server")
+	cmd.Flags().String("user", "", "owner of the server, by ID, username or email")
+	cmd.Flags().Int("egg", 0, "egg ID of the server")
+	cmd.Flags().String("node", "", "node to create the server on, by ID or name; picks a free allocation "+
+		"unless --allocation is given")
+	cmd.Flags().String("allocation", "", "default allocation, by ID, or by port or ip:port on --node")
+	cmd.Flags().Int("memory", 0, "memory limit in MiB (0 for unlimited)")
+	cmd.Flags().Int("disk", 0, "disk limit in MiB (0 for unlimited)")
+	cmd.Flags().Int("cpu", 0, "CPU limit in percent of a core (0 for unlimited)")
+	cmd.Flags().StringArray("env", nil, "egg variable as KEY=VALUE (repeatable)")
+
+	completion.RegisterFlagFunc(cmd, "node", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteNodes(toComplete)
+	})
+	completion.RegisterFlagFunc(cmd, "egg", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteEggs(toComplete)
+	})
+	completion.RegisterFlagFunc(cmd, "user", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteUsers(toComplete)
+	})
+}
+
+// serverCreateFlagsChanged reports whether any flag that sets a field of the create request was given.
+func serverCreateFlagsChanged(cmd *cobra.Command) bool {
+	for _, flag := range serverCreateFlags {
+		if cmd.Flags().Changed(flag) {
+			return true
+		}
+	}
+	return false
+}
+
+func runServerCreate(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if name, _ := cmd.Flags().GetString("template"); name != "" {
+		return createServerFromTemplate(cmd, name)
+	}
+	if cmd.Flags().Changed("set") {
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--set requires --template"))
+	}
+
+	if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+		return createServersFromFile(cmd, path)
+	}
+
+	// Field flags build the request on their own; with --data they override the same fields in it.
+	data := map[string]any{}
+	if dataFlag, _ := cmd.Flags().GetString("data"); dataFlag != "" || !serverCreateFlagsChanged(cmd) {
+		var err error
+		if data, err = parseJSONData(cmd); err != nil {
+			return err
+		}
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	if err := applyServerCreateFlags(ctx, cmd, client, data); err != nil {
+		return err
+	}
+	if err := resolveServerOwner(ctx, client, data); err != nil {
+		return err
+	}
+
+	if err := guardCapacity(cmd, client, data); err != nil {
+		return apierrors.Handle(err)
+	}
+	result, err := client.CreateServer(dryRunContext(cmd), data)
+	if req, ok := api.AsDryRun(err); ok {
+		return printDryRun(cmd, req)
+	}
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Server created successfully")
+	return formatter.Print(result)
+}
+
+// applyServerCreateFlags sets the fields of the create request given by flags. Limits and feature limits
+// the panel requires get defaults when the request is built from flags.
+func applyServerCreateFlags(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client *api.ApplicationAPI,
+	data map[string]any,
+) error {
+	if !serverCreateFlagsChanged(cmd) {
+		return nil
+	}
+	flags := cmd.Flags()
+
+	if flags.Changed("name") {
+		data["name"], _ = flags.GetString("name")
+	}
+	if flags.Changed("user") {
+		data["user"], _ = flags.GetString("user")
+	}
+	if flags.Changed("egg") {
+		data["egg"], _ = flags.GetInt("egg")
+	}
+
+	limits := nestedMap(data, "limits")
+	for _, limit := range []string{"memory", "disk", "cpu"} {
+		if flags.Changed(limit) {
+			limits[limit], _ = flags.GetInt(limit)
+		}
+	}
+	setDefault(limits, "memory", 0)
+	setDefault(limits, "disk", 0)
+	setDefault(limits, "cpu", 0)
+	setDefault(limits, "swap", defaultServerSwap)
+	setDefault(limits, "io", defaultServerIO)
+
+	featureLimits := nestedMap(data, "feature_limits")
+	for _, limit := range []string{"databases", "allocations", "backups"} {
+		setDefault(featureLimits, limit, 0)
+	}
+
+	envs, _ := flags.GetStringArray("env")
+	environment := nestedMap(data, "environment")
+	for _, env := range envs {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok || key == "" {
+			return apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("invalid --env %q (must be KEY=VALUE)", env))
+		}
+		environment[key] = value
+	}
+
+	node, _ := flags.GetString("node")
+	allocation, _ := flags.GetString("allocation")
+	if node == "" && allocation == "" {
+		return nil
+	}
+	allocationID, err := resolveCreateAllocation(ctx, client, node, allocation)
+	if err != nil {
+		return err
+	}
+	nestedMap(data, "allocation")["default"] = allocationID
+	return nil
+}
+
+// resolveCreateAllocation returns the ID of the default allocation of a new server: the allocation given by ID,
+// the free allocation of node given by ID, port or ip:port, or else the first free allocation of node.
+func resolveCreateAllocation(ctx context.Context, client *api.ApplicationAPI, node, allocation string) (string, error) {
+	if node == "" {
+		if _, err := strconv.Atoi(allocation); err != nil {
+			return "", apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("invalid allocation %s (give an ID, or a port or ip:port with --node)", allocation))
+		}
+		return allocation, nil
+	}
+
+	nodeID, err := resolveNodeID(ctx, client, node)
+	if err != nil {
+		return "", err
+	}
+	allocations, err := client.ListNodeAllocations(ctx, nodeID)
+	if err != nil {
+		return "", apierrors.Handle(err)
+	}
+	for _, alloc := range allocations {
+		if assigned, _ := attribute(alloc, "assigned").(bool); assigned {
+			continue
+		}
+		ip, _ := attribute(alloc, "ip").(string)
+		port := convertServerIDToString(attribute(alloc, "port"))
+		id := convertServerIDToString(attribute(alloc, "id"))
+		if allocation == "" || allocation == id || allocation == port || allocation == ip+":"+port {
+			return id, nil
+		}
+	}
+	if allocation == "" {
+		return "", apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("node %s has no free allocation", node))
+	}
+	return "", apierrors.WithExitCode(apierrors.ExitNotFound,
+		fmt.Errorf("no free allocation %s on node %s", allocation, node))
+}
+
+// resolveServerOwner replaces an owner given by username or email with its user ID.
+func resolveServerOwner(ctx context.Context, client *api.ApplicationAPI, data map[string]any) error {
+	user, ok := data["user"].(string)
+	if !ok {
+		return nil
+	}
+	id, err := resolveUserID(ctx, client, user)
+	if err != nil {
+		return err
+	}
+	if data["user"], err = strconv.Atoi(id); err != nil {
+		return fmt.Errorf("invalid user ID: %s", id)
+	}
+	return nil
+}
+
+// nestedMap returns the object under key in data, creating it if it is missing.
+func nestedMap(data map[string]any, key string) map[string]any {
+	nested, ok := data[key].(map[string]any)
+	if !ok {
+		nested = map[string]any{}
+		data[key] = nested
+	}
+	return nested
+}
+
+func setDefault(data map[string]any, key string, value any) {
+	if _, ok := data[key]; !ok {
+		data[key] = value
+	}
+}
+
+// createServersFromFile creates every server of a JSON array of create payloads.
+func createServersFromFile(cmd *cobra.Command, path string) error {
+	if serverCreateFlagsChanged(cmd) {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("field flags cannot be combined with --from-file; set the fields in the file"))
+	}
+	servers, err := readCreateFile(path)
+	if err != nil {
+		return err
+	}
+	return runBulkCreate(cmd, "server", servers, "name",
+		func(ctx context.Context, client *api.ApplicationAPI, data map[string]any) error {
+			if err := resolveServerOwner(ctx, client, data); err != nil {
+				return err
+			}
+			if err := fitCapacity(cmd, client, data); err != nil {
+				return err
+			}
+			_, err := client.CreateServer(ctx, data)
+			return err
+		})
+}
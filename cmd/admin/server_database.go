@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// dumpCommands are the dump programs looked up on PATH, in order of preference.
+//
+//nolint:gochecknoglobals // Fixed list of supported dump programs
+var dumpCommands = []string{"mariadb-dump", "mysqldump"}
+
+func newServerDatabaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "database",
+		Short: "Manage server databases",
+		Long:  "Dump server databases",
+	}
+
+	dumpCmd := &cobra.Command{
+		Use:   "dump <server-id|uuid> <database> [--output file.sql]",
+		Short: "Dump a server database",
+		Long: "Dump a server database as SQL using a local mariadb-dump or mysqldump. " +
+			"The connection credentials are retrieved from the panel; the database can be given by ID, " +
+			"full name, or the name it was created with. Writes to stdout unless --output is set.",
+		Args: cobra.ExactArgs(2), //nolint:mnd // server and database
+		RunE: runServerDatabaseDump,
+	}
+	// --output names the dump file here; it takes the place of the global output format flag.
+	dumpCmd.Flags().StringP("output", "o", "", "file to write the dump to (default stdout)")
+	dumpCmd.Flags().String("dump-command", "", "dump program to run (default mariadb-dump or mysqldump from PATH)")
+	dumpCmd.ValidArgsFunction = func(
+		cmd *cobra.Command, args []string, toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return adminServerValidArgs(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(dumpCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	carapace.Gen(dumpCmd).PositionalCompletion(carapace.ActionCallback(adminServerCompletionAction))
+	carapace.Gen(dumpCmd).FlagCompletion(carapace.ActionMap{
+		"output":       carapace.ActionFiles(".sql"),
+		"dump-command": carapace.ActionExecutables(),
+	})
+
+	return cmd
+}
+
+func runServerDatabaseDump(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverID, database := args[0], args[1]
+	outputFile, _ := cmd.Flags().GetString("output")
+	dumpCommand, _ := cmd.Flags().GetString("dump-command")
+
+	dumpPath, err := findDumpCommand(dumpCommand)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	creds, err := client.GetServerDatabaseCredentials(ctx, serverID, database)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	if creds.Password == "" {
+		return fmt.Errorf("the panel did not return a password for database %s", creds.Database)
+	}
+
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		file, createErr := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if createErr != nil {
+			return fmt.Errorf("failed to create dump file: %w", createErr)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	dump := exec.CommandContext(cmd.Context(), dumpPath, //nolint:gosec // the dump program is chosen by the user
+		"--single-transaction", "--quick", "--no-tablespaces",
+		"--host", creds.Host,
+		"--port", strconv.Itoa(creds.Port),
+		"--user", creds.Username,
+		creds.Database,
+	)
+	// Pass the password through the environment so it doesn't show up in the process list.
+	dump.Env = append(os.Environ(), "MYSQL_PWD="+creds.Password)
+	dump.Stdout = out
+	dump.Stderr = os.Stderr
+
+	if runErr := dump.Run(); runErr != nil {
+		if outputFile != "" {
+			_ = os.Remove(outputFile)
+		}
+		return fmt.Errorf("%s failed: %w", dumpPath, runErr)
+	}
+
+	if outputFile != "" {
+		formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+		formatter.PrintSuccess("Database %s dumped to %s", creds.Database, outputFile)
+	}
+	return nil
+}
+
+// findDumpCommand returns the dump program to run: the one given, or the first supported one on PATH.
+func findDumpCommand(dumpCommand string) (string, error) {
+	if dumpCommand != "" {
+		path, err := exec.LookPath(dumpCommand)
+		if err != nil {
+			return "", fmt.Errorf("dump command %s not found: %w", dumpCommand, err)
+		}
+		return path, nil
+	}
+
+	for _, name := range dumpCommands {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("neither mariadb-dump nor mysqldump was found on PATH; install one or set --dump-command")
+}
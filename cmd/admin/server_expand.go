@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// expandableReferences are the server references --expand can resolve, in display order.
+//
+//nolint:gochecknoglobals // Fixed list shared by flag help, validation and completion
+var expandableReferences = []string{"owner", "node", "egg", "allocations"}
+
+// parseExpand validates the references given to --expand.
+func parseExpand(values []string) ([]string, error) {
+	var refs []string
+	for _, value := range values {
+		ref := strings.ToLower(strings.TrimSpace(value))
+		if ref == "" {
+			continue
+		}
+		if !slices.Contains(expandableReferences, ref) {
+			return nil, fmt.Errorf("unknown reference to expand: %q (valid: %s)",
+				value, strings.Join(expandableReferences, ", "))
+		}
+		if !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// expandServer resolves the referenced IDs of a server into summaries under its "expanded" key.
+// A reference that cannot be resolved is reported as a warning and left out.
+func expandServer(
+	ctx context.Context,
+	client *api.ApplicationAPI,
+	formatter *output.Formatter,
+	server map[string]any,
+	refs []string,
+) {
+	expanded := make(map[string]any, len(refs))
+	for _, ref := range refs {
+		summary, err := expandServerReference(ctx, client, server, ref)
+		if err != nil {
+			formatter.PrintWarning("Could not expand %s: %v", ref, err)
+			continue
+		}
+		if summary != nil {
+			expanded[ref] = summary
+		}
+	}
+	server["expanded"] = expanded
+}
+
+func expandServerReference(
+	ctx context.Context,
+	client *api.ApplicationAPI,
+	server map[string]any,
+	ref string,
+) (any, error) {
+	switch ref {
+	case "owner":
+		userID := convertServerIDToString(attribute(server, "user"))
+		if userID == "" {
+			return nil, nil //nolint:nilnil // no reference to expand
+		}
+		user, err := client.GetUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return summarize(user, "id", "username", "email"), nil
+	case "node":
+		nodeID := convertServerIDToString(attribute(server, "node"))
+		if nodeID == "" {
+			return nil, nil //nolint:nilnil // no reference to expand
+		}
+		node, err := client.GetNode(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return summarize(node, "id", "name", "fqdn"), nil
+	case "egg":
+		eggID := convertServerIDToString(attribute(server, "egg"))
+		if eggID == "" {
+			return nil, nil //nolint:nilnil // no reference to expand
+		}
+		egg, err := client.GetEgg(ctx, eggID)
+		if err != nil {
+			return nil, err
+		}
+		return summarize(egg, "id", "uuid", "name"), nil
+	case "allocations":
+		serverID := convertServerIDToString(attribute(server, "id"))
+		allocations, err := client.ListServerAllocations(ctx, serverID)
+		if err != nil {
+			return nil, err
+		}
+		primary := convertServerIDToString(attribute(server, "allocation"))
+		summaries := make([]any, 0, len(allocations))
+		for _, allocation := range allocations {
+			summary := summarize(allocation, "id", "alias", "notes")
+			ip, _ := attribute(allocation, "ip").(string)
+			summary["address"] = net.JoinHostPort(ip, convertServerIDToString(attribute(allocation, "port")))
+			summary["primary"] = convertServerIDToString(attribute(allocation, "id")) == primary
+			summaries = append(summaries, summary)
+		}
+		return summaries, nil
+	default:
+		return nil, fmt.Errorf("unknown reference: %s", ref)
+	}
+}
+
+// summarize copies the given fields of a resource, skipping those it doesn't have.
+func summarize(resource map[string]any, keys ...string) map[string]any {
+	summary := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if val := attribute(resource, key); val != nil {
+			summary[key] = val
+		}
+	}
+	return summary
+}
@@ -0,0 +1,226 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+)
+
+const (
+	// percentScale turns fractions into percentages.
+	percentScale = 100
+	// bytesPerMiB converts the MiB limits of the panel to bytes.
+	bytesPerMiB = 1024 * 1024
+)
+
+// healthChecks are the checks admin server health runs on top of fetching the health of servers:
+// resource thresholds, and failing the command on unhealthy servers for use as a Nagios or cron check.
+type healthChecks struct {
+	// cpuThreshold is the CPU usage, in percent of the CPU limit of a server, above which it is
+	// unhealthy. Zero turns the check off.
+	cpuThreshold float64
+	// memoryThreshold is the memory usage, in percent of the memory limit of a server, above which
+	// it is unhealthy. Zero turns the check off.
+	memoryThreshold float64
+	failOnUnhealthy bool
+
+	// client reads the resource usage of servers for the thresholds, which only the Client API reports.
+	client *api.ClientAPI
+}
+
+func addHealthCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64("cpu-threshold", 0,
+		"report servers using more CPU than this percentage of their limit (of one core if unlimited) as unhealthy")
+	cmd.Flags().Float64("memory-threshold", 0,
+		"report servers using more memory than this percentage of their limit as unhealthy")
+	cmd.Flags().Bool("fail-on-unhealthy", false,
+		"exit with code 2 if any server is crashed or over a threshold, e.g. for Nagios or cron checks")
+}
+
+// getHealthChecks reads and validates the check flags of admin server health.
+func getHealthChecks(cmd *cobra.Command, watching bool) (healthChecks, error) {
+	cpuThreshold, _ := cmd.Flags().GetFloat64("cpu-threshold")
+	memoryThreshold, _ := cmd.Flags().GetFloat64("memory-threshold")
+	failOnUnhealthy, _ := cmd.Flags().GetBool("fail-on-unhealthy")
+
+	switch {
+	case cpuThreshold < 0:
+		return healthChecks{}, apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--cpu-threshold must not be negative"))
+	case memoryThreshold < 0 || memoryThreshold > percentScale:
+		return healthChecks{}, apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--memory-threshold must be between 0 and 100"))
+	case failOnUnhealthy && watching:
+		return healthChecks{}, apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--fail-on-unhealthy cannot be used with --watch"))
+	}
+	return healthChecks{
+		cpuThreshold:    cpuThreshold,
+		memoryThreshold: memoryThreshold,
+		failOnUnhealthy: failOnUnhealthy,
+	}, nil
+}
+
+// enabled reports whether any check is on, in which case health is reported per server with its problems.
+func (c healthChecks) enabled() bool {
+	return c.thresholds() || c.failOnUnhealthy
+}
+
+// thresholds reports whether resource usage is checked.
+func (c healthChecks) thresholds() bool {
+	return c.cpuThreshold > 0 || c.memoryThreshold > 0
+}
+
+// serverUsage is the resource usage of a server against its limits.
+type serverUsage struct {
+	// cpu is the CPU usage in percent of one core; cpuLimit is the CPU limit in the same unit, 0 if unlimited.
+	cpu, cpuLimit float64
+	// memory and memoryLimit are in bytes; memoryLimit is 0 if unlimited.
+	memory, memoryLimit float64
+}
+
+// cpuPercent is the CPU usage in percent of the CPU limit, or of one core if unlimited.
+func (u serverUsage) cpuPercent() float64 {
+	if u.cpuLimit > 0 {
+		return u.cpu / u.cpuLimit * percentScale
+	}
+	return u.cpu
+}
+
+// memoryPercent is the memory usage in percent of the memory limit; false if unlimited.
+func (u serverUsage) memoryPercent() (float64, bool) {
+	if u.memoryLimit <= 0 {
+		return 0, false
+	}
+	return u.memory / u.memoryLimit * percentScale, true
+}
+
+// fetchUsage reads the resource usage of a server from the Client API, and its limits from the Application API.
+func (c healthChecks) fetchUsage(
+	ctx context.Context,
+	admin *api.ApplicationAPI,
+	serverID string,
+	health map[string]any,
+) (*serverUsage, error) {
+	server, _ := health["server"].(map[string]any)
+	uuid, _ := server["uuid"].(string)
+	if uuid == "" {
+		return nil, errors.New("health response has no server UUID")
+	}
+	resources, err := c.client.GetServerResources(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource usage: %w", err)
+	}
+	details, err := admin.GetServer(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server limits: %w", err)
+	}
+
+	usage, _ := attribute(resources, "resources").(map[string]any)
+	limits, _ := attribute(details, "limits").(map[string]any)
+	return &serverUsage{
+		cpu:         toFloat64(usage["cpu_absolute"]),
+		cpuLimit:    toFloat64(limits["cpu"]),
+		memory:      toFloat64(usage["memory_bytes"]),
+		memoryLimit: toFloat64(limits["memory"]) * bytesPerMiB,
+	}, nil
+}
+
+// problems lists why a server is unhealthy: a crash, or usage over a threshold.
+func (c healthChecks) problems(health map[string]any, usage *serverUsage) []string {
+	problems := []string{}
+	if crashed, _ := health["crashed"].(bool); crashed {
+		problems = append(problems, "crashed")
+	}
+	if usage == nil {
+		return problems
+	}
+	if cpu := usage.cpuPercent(); c.cpuThreshold > 0 && cpu > c.cpuThreshold {
+		problems = append(problems, fmt.Sprintf("cpu %.0f%% over %s%%", cpu, formatThreshold(c.cpuThreshold)))
+	}
+	if memory, ok := usage.memoryPercent(); ok && c.memoryThreshold > 0 && memory > c.memoryThreshold {
+		problems = append(problems,
+			fmt.Sprintf("memory %.0f%% over %s%%", memory, formatThreshold(c.memoryThreshold)))
+	}
+	return problems
+}
+
+// usageRecord is the JSON form of the usage of a server, in percent of its limits.
+func usageRecord(usage *serverUsage) map[string]any {
+	record := map[string]any{"cpu_percent": roundPercent(usage.cpuPercent())}
+	if memory, ok := usage.memoryPercent(); ok {
+		record["memory_percent"] = roundPercent(memory)
+	}
+	return record
+}
+
+// usageColumns are the CPU and memory columns of the usage of a server in health tables.
+func usageColumns(usage *serverUsage) []string {
+	if usage == nil {
+		return []string{"", ""}
+	}
+	memory := "-"
+	if percent, ok := usage.memoryPercent(); ok {
+		memory = fmt.Sprintf("%.0f%%", percent)
+	}
+	return []string{fmt.Sprintf("%.0f%%", usage.cpuPercent()), memory}
+}
+
+func formatThreshold(threshold float64) string {
+	return strconv.FormatFloat(threshold, 'f', -1, 64)
+}
+
+// roundPercent rounds a percentage to one decimal.
+func roundPercent(percent float64) float64 {
+	const precision = 10
+	return math.Round(percent*precision) / precision
+}
+
+// unhealthyError is the error of a health check with unhealthy servers, which ends the command with
+// ExitUnhealthy. Servers whose health could not be checked fail the command as a bulk failure instead,
+// so a check never passes without having seen every server.
+func unhealthyError(results []healthResult) error {
+	var unhealthy []string
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			failed++
+		case len(result.Problems) > 0:
+			unhealthy = append(unhealthy, result.Server)
+		}
+	}
+	if failed > 0 {
+		return apierrors.BulkFailure(fmt.Errorf("health of %d server(s) could not be checked", failed), failed, len(results))
+	}
+	if len(unhealthy) == 0 {
+		return nil
+	}
+	return apierrors.WithExitCode(apierrors.ExitUnhealthy,
+		fmt.Errorf("%d of %d server(s) unhealthy: %s", len(unhealthy), len(results), strings.Join(unhealthy, ", ")))
+}
+
+// toFloat64 converts a JSON number (or numeric string) to float64, treating anything else as 0.
+func toFloat64(val any) float64 {
+	switch v := val.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/annotation"
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// suspensionDay is the unit of suspension ages over a day.
+const suspensionDay = 24 * time.Hour
+
+// suspendServer returns the action of admin server suspend, which notes why and when each server was
+// suspended in the local annotation store.
+func suspendServer(reason string) serverActionFunc {
+	return func(ctx context.Context, client *api.ApplicationAPI, serverID string) error {
+		if err := client.SuspendServer(ctx, serverID); err != nil {
+			return err
+		}
+		// The server is suspended either way, so a note that cannot be written does not fail the operation.
+		suspension := annotation.Suspension{Reason: reason, SuspendedAt: time.Now().UTC().Truncate(time.Second)}
+		if err := annotation.SetSuspension(serverID, suspension); err != nil {
+			output.LogWarn("failed to note the suspension", "server", serverID, "error", err)
+		}
+		return nil
+	}
+}
+
+// unsuspendServer is the action of admin server unsuspend, which forgets the noted suspension of each server.
+func unsuspendServer(ctx context.Context, client *api.ApplicationAPI, serverID string) error {
+	if err := client.UnsuspendServer(ctx, serverID); err != nil {
+		return err
+	}
+	if err := annotation.ClearSuspension(serverID); err != nil {
+		output.LogWarn("failed to clear the noted suspension", "server", serverID, "error", err)
+	}
+	return nil
+}
+
+// suspendedServers returns the suspended servers with the suspension noted for them, if any.
+func suspendedServers(servers []map[string]any) ([]map[string]any, map[string]annotation.Server) {
+	notes, err := annotation.Servers()
+	if err != nil {
+		output.LogWarn("failed to read the noted suspensions", "error", err)
+	}
+	suspended := make([]map[string]any, 0, len(servers))
+	for _, server := range servers {
+		if serverSuspended(server) {
+			suspended = append(suspended, server)
+		}
+	}
+	return suspended, notes
+}
+
+// suspensionRecord is the JSON form of the noted suspension of a server.
+func suspensionRecord(suspension *annotation.Suspension, now time.Time) map[string]any {
+	if suspension == nil {
+		return nil
+	}
+	return map[string]any{
+		"reason":       suspension.Reason,
+		"suspended_at": suspension.SuspendedAt,
+		"age_seconds":  int64(now.Sub(suspension.SuspendedAt).Seconds()),
+	}
+}
+
+// printSuspendedServers prints the suspension report of admin server list --suspended. Servers suspended
+// without pelicanctl have no noted reason or age.
+func printSuspendedServers(
+	formatter *output.Formatter,
+	servers []map[string]any,
+	notes map[string]annotation.Server,
+	now time.Time,
+) error {
+	rows := make([][]string, 0, len(servers))
+	for _, server := range servers {
+		id := convertServerIDToString(attribute(server, "id"))
+		uuid, _ := attribute(server, "uuid").(string)
+		name, _ := attribute(server, "name").(string)
+		age, suspendedAt, reason := "-", "-", ""
+		if suspension := notes[id].Suspension; suspension != nil {
+			age = formatSuspensionAge(now.Sub(suspension.SuspendedAt))
+			suspendedAt = formatter.FormatTime(suspension.SuspendedAt)
+			reason = suspension.Reason
+		}
+		rows = append(rows, []string{
+			id, output.ServerLabel(uuid), name, convertServerIDToString(attribute(server, "node")),
+			suspendedAt, age, reason,
+		})
+	}
+	return formatter.PrintTable(
+		[]string{"ID", "Server", "Name", "Node", "Suspended At", "Suspended For", "Reason"}, rows)
+}
+
+// formatSuspensionAge formats how long a server has been suspended, in days and hours once over a day.
+func formatSuspensionAge(d time.Duration) string {
+	if d < suspensionDay {
+		return d.Round(time.Minute).String()
+	}
+	days := d / suspensionDay
+	hours := (d % suspensionDay) / time.Hour
+	return fmt.Sprintf("%dd%dh", days, hours)
+}
@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+const (
+	// defaultTransferTimeout is how long --watch waits for a transfer to finish.
+	defaultTransferTimeout = time.Hour
+	// transferPollInterval is how often the transfer status is polled with --watch.
+	transferPollInterval = 5 * time.Second
+)
+
+func newServerTransferCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer <server-id|uuid> --node <node-id> [--allocation <id>]",
+		Short: "Transfer a server to another node",
+		Long: "Transfer a server by ID (integer) or UUID (string) to another node. " +
+			"The server gets --allocation on the target node as its primary allocation, " +
+			"or the first unassigned allocation of the node if none is given. " +
+			"Use --watch to wait until the transfer has finished.",
+		Args: cobra.ExactArgs(1),
+		RunE: runServerTransfer,
+	}
+	cmd.Flags().String("node", "", "ID of the node to transfer the server to")
+	_ = cmd.MarkFlagRequired("node")
+	cmd.Flags().Int("allocation", 0, "ID of the allocation on the target node to use as primary allocation")
+	cmd.Flags().Bool("watch", false, "wait until the transfer has finished")
+	cmd.Flags().Duration("timeout", defaultTransferTimeout, "with --watch, how long to wait for the transfer")
+	cmd.ValidArgsFunction = adminServerValidArgs
+	return cmd
+}
+
+func setupServerTransferCompletion(cmd *cobra.Command) {
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(adminServerCompletionAction))
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		"node": carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completion.CompleteNodes(c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}),
+		"allocation": carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			// Allocations are completed from the node given with --node.
+			nodeID, _ := cmd.Flags().GetString("node")
+			if nodeID == "" {
+				return carapace.ActionValues()
+			}
+			completions, err := completion.CompleteNodeAllocations(nodeID, c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}),
+	})
+}
+
+func runServerTransfer(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverID := args[0]
+	nodeID, _ := cmd.Flags().GetString("node")
+	allocationID, _ := cmd.Flags().GetInt("allocation")
+	watch, _ := cmd.Flags().GetBool("watch")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	if allocationID == 0 {
+		allocationID, err = firstUnassignedAllocation(ctx, client, nodeID)
+		if err != nil {
+			return err
+		}
+		formatter.PrintInfo("Using allocation %d on node %s", allocationID, nodeID)
+	}
+
+	if transferErr := client.TransferServer(ctx, serverID, nodeID, allocationID); transferErr != nil {
+		return apierrors.Handle(transferErr)
+	}
+
+	label := output.ServerLabel(serverID)
+	if !watch {
+		formatter.PrintSuccess("Transfer of server %s to node %s started", label, nodeID)
+		formatter.PrintAffected(serverID)
+		return nil
+	}
+
+	formatter.PrintInfo("Transfer of server %s to node %s started, waiting for it to finish...", label, nodeID)
+	if waitErr := waitForTransfer(ctx, client, serverID, nodeID, timeout); waitErr != nil {
+		return fmt.Errorf("server %s: %w", label, waitErr)
+	}
+	formatter.PrintSuccess("Server %s transferred to node %s", label, nodeID)
+	formatter.PrintAffected(serverID)
+	return nil
+}
+
+// firstUnassignedAllocation returns the ID of the first allocation of a node that no server uses.
+func firstUnassignedAllocation(ctx context.Context, client *api.ApplicationAPI, nodeID string) (int, error) {
+	allocations, err := client.ListNodeAllocations(ctx, nodeID)
+	if err != nil {
+		return 0, apierrors.Handle(err)
+	}
+	for _, allocation := range allocations {
+		if assigned, _ := attribute(allocation, "assigned").(bool); assigned {
+			continue
+		}
+		if id := toInt64(attribute(allocation, "id")); id > 0 {
+			return int(id), nil
+		}
+	}
+	return 0, fmt.Errorf("node %s has no unassigned allocations; create one or pass --allocation", nodeID)
+}
+
+// waitForTransfer polls a server until it runs on the target node or its transfer fails.
+func waitForTransfer(
+	ctx context.Context,
+	client *api.ApplicationAPI,
+	serverID, nodeID string,
+	timeout time.Duration,
+) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := client.GetServerTransferStatus(ctx, serverID)
+		if err != nil {
+			return apierrors.Handle(err)
+		}
+		switch {
+		case status.Failed:
+			return errors.New("transfer failed")
+		case !status.Pending && strconv.Itoa(status.Node) == nodeID:
+			return nil
+		case !status.Pending:
+			return errors.New("transfer is no longer in progress and the server is still on its old node")
+		}
+
+		if time.Now().Add(transferPollInterval).After(deadline) {
+			return fmt.Errorf("transfer did not finish within %s", timeout)
+		}
+		time.Sleep(transferPollInterval)
+	}
+}
@@ -0,0 +1,229 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/servertemplate"
+)
+
+// NewTemplateCmd creates the template command.
+func NewTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage server creation templates",
+		Long: "Templates are reusable server creation payloads kept in the templates directory next to the " +
+			"config file. String values may contain {{placeholders}}, filled in with --set when a server is " +
+			"created from the template; a value that is a single placeholder takes the type of its value, " +
+			"so \"{{memory}}\" with --set memory=4096 is a number.",
+	}
+
+	saveCmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save a server creation payload as a template",
+		Long:  "Save the JSON data of 'admin server create', given with --data or on stdin, as a template.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTemplateSave,
+	}
+	saveCmd.Flags().String("data", "", "JSON data for the server, with {{placeholders}} (or read from stdin)")
+	saveCmd.Flags().Bool("force", false, "replace an existing template")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List server templates",
+		Args:  cobra.NoArgs,
+		RunE:  runTemplateList,
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a server template",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTemplateShow,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply <name> --set <placeholder>=<value>...",
+		Short: "Create a server from a template",
+		Long:  "Create a server from a template, like 'admin server create --template'.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return createServerFromTemplate(cmd, args[0])
+		},
+	}
+	addTemplateSetFlag(applyCmd)
+	applyCmd.Flags().Bool("dry-run", false, "print the server data without creating the server")
+	addIgnoreCapacityFlag(applyCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a server template",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTemplateDelete,
+	}
+
+	for _, sub := range []*cobra.Command{showCmd, applyCmd, deleteCmd} {
+		sub.ValidArgsFunction = templateValidArgs
+	}
+	cmd.AddCommand(saveCmd, listCmd, showCmd, applyCmd, deleteCmd)
+	return cmd
+}
+
+// addTemplateSetFlag adds the flag that fills in the placeholders of a template.
+func addTemplateSetFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("set", nil, "value of a template placeholder as name=value (repeatable)")
+}
+
+func templateValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, _ := completeTemplateNames(nil, toComplete)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplateNames returns the names of the saved templates starting with toComplete.
+func completeTemplateNames(_ []string, toComplete string) ([]string, error) {
+	templates, err := servertemplate.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		if strings.HasPrefix(tmpl.Name, toComplete) {
+			names = append(names, tmpl.Name)
+		}
+	}
+	return names, nil
+}
+
+func runTemplateSave(cmd *cobra.Command, args []string) error {
+	data, err := parseJSONData(cmd)
+	if err != nil {
+		return err
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	if err := servertemplate.Save(args[0], data, force); err != nil {
+		return apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	placeholders := servertemplate.Placeholders(data)
+	if len(placeholders) == 0 {
+		formatter.PrintSuccess("Saved template %s", args[0])
+	} else {
+		formatter.PrintSuccess("Saved template %s with placeholders %s", args[0], strings.Join(placeholders, ", "))
+	}
+	formatter.PrintAffected(args[0])
+	return nil
+}
+
+func runTemplateList(cmd *cobra.Command, _ []string) error {
+	templates, err := servertemplate.List()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(templates)
+	}
+	if len(templates) == 0 {
+		formatter.PrintInfo("No templates saved. Save one with 'pelicanctl template save'")
+		return nil
+	}
+	rows := make([][]string, 0, len(templates))
+	for _, tmpl := range templates {
+		egg := convertServerIDToString(tmpl.Data["egg"])
+		rows = append(rows, []string{tmpl.Name, egg, strings.Join(tmpl.Placeholders, ", ")})
+	}
+	return formatter.PrintTable([]string{"Name", "Egg", "Placeholders"}, rows)
+}
+
+func runTemplateShow(cmd *cobra.Command, args []string) error {
+	tmpl, err := loadTemplate(args[0])
+	if err != nil {
+		return err
+	}
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(tmpl)
+	}
+	return formatter.Print(tmpl.Data)
+}
+
+func runTemplateDelete(cmd *cobra.Command, args []string) error {
+	if err := servertemplate.Delete(args[0]); err != nil {
+		if errors.Is(err, servertemplate.ErrNotFound) {
+			return apierrors.WithExitCode(apierrors.ExitNotFound, err)
+		}
+		return err
+	}
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Deleted template %s", args[0])
+	formatter.PrintAffected(args[0])
+	return nil
+}
+
+// createServerFromTemplate creates a server from the template name, filled in with --set.
+func createServerFromTemplate(cmd *cobra.Command, name string) error {
+	ctx := cmd.Context()
+	tmpl, err := loadTemplate(name)
+	if err != nil {
+		return err
+	}
+	pairs, _ := cmd.Flags().GetStringArray("set")
+	values, err := servertemplate.ParseValues(pairs)
+	if err != nil {
+		return apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+	data, err := tmpl.Render(values)
+	if err != nil {
+		return apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	// Field flags of server create override the fields of the template.
+	if err := applyServerCreateFlags(ctx, cmd, client, data); err != nil {
+		return err
+	}
+	if err := resolveServerOwner(ctx, client, data); err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		formatter.PrintInfo("Dry run - would create a server from template %s with:", name)
+		return formatter.Print(data)
+	}
+
+	if err := guardCapacity(cmd, client, data); err != nil {
+		return apierrors.Handle(err)
+	}
+	result, err := client.CreateServer(ctx, data)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	formatter.PrintSuccess("Server created from template %s", name)
+	return formatter.Print(result)
+}
+
+// loadTemplate loads a template, failing with the not found exit code if it is not saved.
+func loadTemplate(name string) (servertemplate.Template, error) {
+	tmpl, err := servertemplate.Load(name)
+	if errors.Is(err, servertemplate.ErrNotFound) {
+		return tmpl, apierrors.WithExitCode(apierrors.ExitNotFound,
+			fmt.Errorf("%w. List saved templates with 'pelicanctl template list'", err))
+	}
+	return tmpl, err
+}
@@ -2,32 +2,146 @@
 package admin
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"go.lostcrafters.com/pelicanctl/internal/api"
 	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
 	"go.lostcrafters.com/pelicanctl/internal/output"
 )
 
+// rootAdminField makes a new user a root admin. The panel grants admin through roles, so it is
+// not sent with the user but applied by assigning rootAdminRole.
+const (
+	rootAdminField = "root_admin"
+	rootAdminRole  = "Root Admin"
+)
+
 func newUserCmd() *cobra.Command {
-	return newCRUDResourceCmd(crudResourceConfig{
-		name:          "user",
-		short:         "Manage users",
-		long:          "List and view users",
-		listShort:     "List all users",
-		listFunc:      func(c *api.ApplicationAPI) (any, error) { return c.ListUsers() },
-		viewUse:       "view <user-id>",
-		viewShort:     "View user details",
-		viewFunc:      func(c *api.ApplicationAPI, id string) (any, error) { return c.GetUser(id) },
-		createFunc:    func(c *api.ApplicationAPI, data map[string]any) (map[string]any, error) { return c.CreateUser(data) },
-		updateFunc:    func(c *api.ApplicationAPI, id string) (map[string]any, error) { return c.UpdateUser(id) },
-		deleteFunc:    func(c *api.ApplicationAPI, id string) error { return c.DeleteUser(id) },
+	userCreateFields := []updateField{
+		{flag: "email", field: "email", usage: "email address"},
+		{flag: "username", field: "username", usage: "username"},
+		{flag: "password", field: "password", usage: "password (the user sets one by email when omitted)"},
+		{flag: "admin", field: rootAdminField, kind: updateFieldBool, usage: "make the user a root admin"},
+	}
+	cmd := newCRUDResourceCmd(crudResourceConfig{
+		name:      "user",
+		short:     "Manage users",
+		long:      "List and view users",
+		listShort: "List all users",
+		listOptionsFunc: func(ctx context.Context, c *api.ApplicationAPI, opts api.ListOptions) (any, error) {
+			return c.ListUsersWithOptions(ctx, opts)
+		},
+		viewUse:   "view <user>",
+		viewShort: "View user details",
+		viewFunc:  func(ctx context.Context, c *api.ApplicationAPI, id string) (any, error) { return c.GetUser(ctx, id) },
+		createFunc: func(ctx context.Context, c *api.ApplicationAPI, data map[string]any) (map[string]any, error) {
+			return createUser(ctx, c, data)
+		},
+		createFields: userCreateFields,
+		updateDataFunc: func(
+			ctx context.Context, c *api.ApplicationAPI, id string, data map[string]any,
+		) (map[string]any, error) {
+			return c.UpdateUser(ctx, id, data)
+		},
+		updateFields: []updateField{
+			{flag: "email", field: "email", usage: "email address"},
+			{flag: "username", field: "username", usage: "username"},
+			{flag: "external-id", field: "external_id", usage: "identifier of the user in an external system"},
+			{flag: "language", field: "language", usage: "language code, e.g. en"},
+			{flag: "timezone", field: "timezone", usage: "timezone, e.g. Europe/Berlin"},
+		},
+		deleteFunc:    func(ctx context.Context, c *api.ApplicationAPI, id string) error { return c.DeleteUser(ctx, id) },
 		completeFunc:  completion.CompleteUsers,
 		resourceType:  output.ResourceTypeAdminUser,
 		createMessage: "User created successfully",
 		updateMessage: "User updated successfully",
 		deleteMessage: "User deleted successfully",
-		createLong:    "Create a new user. Provide user data as JSON via --data flag or stdin.",
-		dataFlagHelp:  "JSON data for the user (or read from stdin)",
+		createLong: "Create a new user. Provide user data as JSON via --data flag or stdin. " +
+			"With root_admin (--admin), the user is given the Root Admin role after it is created.",
+		dataFlagHelp: "JSON data for the user (or read from stdin)",
 	})
+
+	if createCmd, _, err := cmd.Find([]string{"create"}); err == nil {
+		setupUserBulkCreate(createCmd, userCreateFields)
+	}
+	// Users are taken by username or email as well as by ID.
+	for _, name := range []string{"update", "delete"} {
+		if userCmd, _, err := cmd.Find([]string{name}); err == nil {
+			userCmd.Use = name + " <user>"
+			userCmd.Long = strings.Replace(userCmd.Long, "by ID", "by ID, username or email", 1)
+		}
+	}
+
+	cmd.AddCommand(newUserExportCmd())
+	cmd.AddCommand(newUserImportCmd())
+
+	roleCmds := newUserRoleCmds()
+	// Add subcommands FIRST (matching carapace example pattern)
+	for _, c := range roleCmds {
+		cmd.AddCommand(c)
+	}
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	setupUserRoleCompletion(roleCmds)
+	return cmd
+}
+
+// createUser creates a user and, if the data sets root_admin, assigns it the Root Admin role.
+func createUser(ctx context.Context, c *api.ApplicationAPI, data map[string]any) (map[string]any, error) {
+	admin, _ := data[rootAdminField].(bool)
+	delete(data, rootAdminField)
+
+	user, err := c.CreateUser(ctx, data)
+	if err != nil || !admin {
+		return user, err
+	}
+	roleIDs, err := resolveRoleIDs(ctx, c, []string{rootAdminRole})
+	if err != nil {
+		return user, fmt.Errorf("user created, but failed to make it a root admin: %w", err)
+	}
+	userID := convertServerIDToString(attribute(user, "id"))
+	if err := c.AssignUserRoles(ctx, userID, roleIDs); err != nil {
+		return user, fmt.Errorf("user created, but failed to make it a root admin: %w", err)
+	}
+	return user, nil
+}
+
+// setupUserBulkCreate adds --from-csv to user create, creating a user for every row of a CSV file.
+func setupUserBulkCreate(createCmd *cobra.Command, fields []updateField) {
+	addBulkCreateFlags(createCmd, "from-csv", "create a user for every row of this CSV file (- for stdin); "+
+		"the header names the fields, e.g. username,email,password,admin")
+	createCmd.Long += " With --from-csv, a user is created for every row of a CSV file."
+
+	createOne := createCmd.RunE
+	createCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("from-csv")
+		if path == "" {
+			return createOne(cmd, args)
+		}
+		if fieldFlagsChanged(cmd, fields) {
+			return apierrors.WithExitCode(apierrors.ExitValidation,
+				errors.New("field flags cannot be combined with --from-csv; set the fields in the file"))
+		}
+		users, err := readCreateCSV(path, "admin", rootAdminField)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			// The admin column reads like the --admin flag.
+			if admin, ok := user["admin"]; ok {
+				user[rootAdminField] = admin
+				delete(user, "admin")
+			}
+		}
+		return runBulkCreate(cmd, "user", users, "username",
+			func(ctx context.Context, c *api.ApplicationAPI, data map[string]any) error {
+				_, err := createUser(ctx, c, data)
+				return err
+			})
+	}
 }
@@ -0,0 +1,213 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// userExportColumns are the columns of admin user export, named like the fields admin user import reads.
+//
+//nolint:gochecknoglobals // Fixed column list shared by export and import
+var userExportColumns = []string{"id", "username", "email", "external_id", "language", "timezone", "admin"}
+
+func newUserExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "Export every user",
+		Long: "Print every user with the fields admin user import reads. " +
+			"Export with -o csv to get a file that can be edited and imported into another panel.",
+		Args: cobra.NoArgs,
+		RunE: runUserExport,
+	}
+}
+
+func newUserImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import --from-csv <file> [--send-invite]",
+		Short: "Create users in bulk from a CSV file",
+		Long: "Create a user for every row of a CSV file whose header names the fields, e.g. username,email,admin. " +
+			"Rows without a password get a generated one, which is reported with the result of the row; " +
+			"with --send-invite they get none, and the panel emails them to set their own. " +
+			"The id column of admin user export is ignored.",
+		Args: cobra.NoArgs,
+		RunE: runUserImport,
+	}
+	cmd.Flags().String("from-csv", "", "CSV file of the users to create (- for stdin)")
+	_ = cmd.MarkFlagRequired("from-csv")
+	cmd.Flags().Bool("send-invite", false,
+		"create users without a password in the file with none, so the panel emails them to set one")
+	const defaultMaxConcurrency = 10
+	cmd.Flags().Int("max-concurrency", defaultMaxConcurrency, "maximum parallel creations")
+	cmd.Flags().Bool("continue-on-error", false, "exit successfully even if some creations fail")
+	cmd.Flags().Bool("fail-fast", false, "stop on the first failed creation")
+	cmd.Flags().Bool("dry-run", false, "validate the file and preview the users without creating them")
+	return cmd
+}
+
+func runUserExport(cmd *cobra.Command, _ []string) error {
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	users, err := client.ListUsers(cmd.Context())
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	records := make([]map[string]any, 0, len(users))
+	rows := make([][]string, 0, len(users))
+	for _, user := range users {
+		record := make(map[string]any, len(userExportColumns))
+		row := make([]string, 0, len(userExportColumns))
+		for _, column := range userExportColumns {
+			field := column
+			if column == "admin" {
+				field = rootAdminField
+			}
+			value := attribute(user, field)
+			if column == "id" {
+				value = toInt64(value)
+			}
+			record[column] = value
+			row = append(row, exportCell(value))
+		}
+		records = append(records, record)
+		rows = append(rows, row)
+	}
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(records)
+	}
+	return formatter.PrintTable(userExportColumns, rows)
+}
+
+// exportCell formats a user field for tables, leaving unset fields empty so they are left out on import.
+func exportCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// importedUser is the result of a row of admin user import.
+type importedUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// Password is the password generated for the user, empty if the file set one or with --send-invite.
+	Password string `json:"password,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runUserImport(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	path, _ := cmd.Flags().GetString("from-csv")
+	sendInvite, _ := cmd.Flags().GetBool("send-invite")
+	flags := getBulkFlags(cmd)
+
+	users, err := readCreateCSV(path, "admin", rootAdminField)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("no users to create"))
+	}
+	imported := make([]importedUser, len(users))
+	for i, user := range users {
+		// The admin column reads like the --admin flag, and ids of an export belong to the other panel.
+		if admin, ok := user["admin"]; ok {
+			user[rootAdminField] = admin
+			delete(user, "admin")
+		}
+		delete(user, "id")
+		username, _ := user["username"].(string)
+		email, _ := user["email"].(string)
+		if username == "" || email == "" {
+			return apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("%s row %d: username and email are required", path, i+1))
+		}
+		imported[i] = importedUser{Username: username, Email: email}
+		if _, ok := user["password"]; !ok && !sendInvite {
+			imported[i].Password = rand.Text()
+			user["password"] = imported[i].Password
+		}
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if flags.dryRun {
+		formatter.PrintInfo("Dry run - would create %d user(s):", len(imported))
+		for _, user := range imported {
+			formatter.PrintInfo("  - %s <%s>", user.Username, user.Email)
+		}
+		return nil
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	operations := make([]bulk.Operation, len(users))
+	for i, user := range users {
+		operations[i] = bulk.Operation{
+			ID:   imported[i].Username,
+			Name: imported[i].Username,
+			Exec: func(opCtx context.Context) error {
+				_, createErr := createUser(opCtx, client, user)
+				return createErr
+			},
+		}
+	}
+	// The per-row results are printed below, with the generated passwords, which must not reach the
+	// records of progress streams and notifications.
+	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast).
+		WithRecord(bulk.KeyedRecord("username"))
+	results := executor.Execute(ctx, operations)
+	for i, result := range results {
+		imported[i].Status = result.Status()
+		if !result.Success {
+			imported[i].Password = ""
+			imported[i].Error = result.Error.Error()
+		}
+	}
+
+	if getOutputFormat(cmd).IsJSON() {
+		if err = formatter.Print(imported); err != nil {
+			return err
+		}
+	} else if err = printImportedUsers(formatter, imported); err != nil {
+		return err
+	}
+	summary := bulk.GetSummary(results)
+	if summary.Failed > 0 && !flags.continueOnError {
+		return apierrors.BulkFailure(fmt.Errorf("%d user(s) could not be created", summary.Failed),
+			summary.Failed, summary.Total)
+	}
+	return nil
+}
+
+func printImportedUsers(formatter *output.Formatter, imported []importedUser) error {
+	rows := make([][]string, 0, len(imported))
+	for _, user := range imported {
+		rows = append(rows, []string{user.Username, user.Email, user.Password, user.Status, user.Error})
+	}
+	return formatter.PrintTable([]string{"username", "email", "password", "status", "error"}, rows)
+}
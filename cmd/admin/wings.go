@@ -0,0 +1,177 @@
+package admin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func wingsNodeCompletionAction(c carapace.Context) carapace.Action {
+	completions, err := completion.CompleteWingsNodes(c.Value)
+	if err != nil || len(completions) == 0 {
+		return carapace.ActionValues()
+	}
+	return completion.Action(completions)
+}
+
+func wingsNodeValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	completions, err := completion.CompleteWingsNodes(toComplete)
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func newWingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wings",
+		Short: "Query the Wings daemon directly",
+		Long: "Diagnostics that talk directly to the Wings daemon on a node, bypassing the panel. " +
+			"Each node must be configured under 'wings' in the config file with its URL and daemon token.",
+	}
+
+	nodesCmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "List nodes configured for direct Wings access",
+		Args:  cobra.NoArgs,
+		RunE:  runWingsNodes,
+	}
+
+	systemCmd := &cobra.Command{
+		Use:   "system <node>",
+		Short: "Show Wings system information",
+		Long:  "Show system information reported by Wings, including versions, CPU, memory and Docker details",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWingsSystem,
+	}
+	systemCmd.ValidArgsFunction = wingsNodeValidArgs
+
+	dockerPruneCmd := &cobra.Command{
+		Use:   "docker-prune <node>",
+		Short: "Prune unused Docker images on a node",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWingsDockerPrune,
+	}
+	dockerPruneCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	dockerPruneCmd.ValidArgsFunction = wingsNodeValidArgs
+
+	transferStatusCmd := &cobra.Command{
+		Use:   "transfer-status <node> <server-uuid>",
+		Short: "Show the transfer state of a server on a node",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // node and server UUID
+		RunE:  runWingsTransferStatus,
+	}
+	transferStatusCmd.ValidArgsFunction = func(
+		cmd *cobra.Command, args []string, toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return adminServerValidArgs(cmd, args, toComplete)
+		}
+		return wingsNodeValidArgs(cmd, args, toComplete)
+	}
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(nodesCmd)
+	cmd.AddCommand(systemCmd)
+	cmd.AddCommand(dockerPruneCmd)
+	cmd.AddCommand(transferStatusCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	carapace.Gen(systemCmd).PositionalCompletion(carapace.ActionCallback(wingsNodeCompletionAction))
+	carapace.Gen(dockerPruneCmd).PositionalCompletion(carapace.ActionCallback(wingsNodeCompletionAction))
+	carapace.Gen(transferStatusCmd).PositionalCompletion(
+		carapace.ActionCallback(wingsNodeCompletionAction),
+		carapace.ActionCallback(adminServerCompletionAction),
+	)
+
+	return cmd
+}
+
+func runWingsNodes(cmd *cobra.Command, _ []string) error {
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	nodes := api.WingsNodes()
+	if len(nodes) == 0 {
+		formatter.PrintInfo("No Wings nodes configured")
+		return nil
+	}
+
+	rows := make([]map[string]any, 0, len(nodes))
+	for _, node := range nodes {
+		rows = append(rows, map[string]any{"node": node})
+	}
+	return formatter.Print(rows)
+}
+
+func runWingsSystem(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	wings, err := api.NewWingsAPI(args[0])
+	if err != nil {
+		return err
+	}
+
+	info, err := wings.GetSystemInfo(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	return formatter.Print(info)
+}
+
+func runWingsDockerPrune(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	node := args[0]
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	wings, err := api.NewWingsAPI(node)
+	if err != nil {
+		return err
+	}
+
+	shouldContinue, err := output.Confirm(cmd, formatter,
+		fmt.Sprintf("This will remove unused Docker images on node %s.", node))
+	if err != nil || !shouldContinue {
+		return err
+	}
+
+	report, err := wings.PruneDockerImages(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(report)
+	}
+	formatter.PrintSuccess("Pruned Docker images on node %s", node)
+	if len(report) > 0 {
+		return formatter.Print(report)
+	}
+	return nil
+}
+
+func runWingsTransferStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	wings, err := api.NewWingsAPI(args[0])
+	if err != nil {
+		return err
+	}
+
+	status, err := wings.GetTransferStatus(ctx, args[1])
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	return formatter.Print(status)
+}
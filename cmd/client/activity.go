@@ -0,0 +1,87 @@
+package client
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func newActivityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "activity <id|uuid>",
+		Short: "Show the activity log of a server",
+		Long: "Show the activity log of a server by ID (integer) or UUID (string), newest first, " +
+			"with the actor, event and IP of every entry. " +
+			"--event filters on the panel to events containing it, e.g. server:power or server:file.",
+		Args: cobra.ExactArgs(1),
+		RunE: runActivity,
+	}
+	cmd.Flags().Duration("since", 0, "only show activity of this last period, e.g. 24h (default: all)")
+	cmd.Flags().String("event", "", "only show events containing this, e.g. server:power.start")
+	cmd.Flags().String("user", "", "only show activity of this user, by username or email")
+	addListFlags(cmd)
+	cmd.ValidArgsFunction = clientServerValidArgsFunction
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(clientServerCompletionAction))
+	return cmd
+}
+
+func runActivity(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	event, _ := cmd.Flags().GetString("event")
+	user, _ := cmd.Flags().GetString("user")
+	if since < 0 {
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--since must not be negative"))
+	}
+	listOpts, err := getListOptions(cmd)
+	if err != nil {
+		return apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+	opts := api.ActivityOptions{ListOptions: listOpts, Event: event}
+	if since > 0 {
+		opts.Since = time.Now().Add(-since)
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+	activity, err := client.ListServerActivity(cmd.Context(), args[0], opts)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	activity = activityOfUser(activity, user)
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(activity)
+	}
+	rows := make([][]string, 0, len(activity))
+	for _, entry := range activity {
+		rows = append(rows, []string{
+			formatter.FormatTime(entry.Timestamp), entry.Actor, entry.Event, entry.IP, entry.Description,
+		})
+	}
+	return formatter.PrintTable([]string{"Time", "Actor", "Event", "IP", "Description"}, rows)
+}
+
+// activityOfUser keeps the activity of the user given by username or email; an empty user keeps all of it.
+func activityOfUser(activity []api.Activity, user string) []api.Activity {
+	if user == "" {
+		return activity
+	}
+	kept := activity[:0]
+	for _, entry := range activity {
+		if strings.EqualFold(entry.Actor, user) || strings.EqualFold(entry.ActorEmail, user) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+)
+
+// addAPIFlag adds --api to a command that works the same through the Client and Application APIs,
+// so it can also be run with an admin token.
+func addAPIFlag(cmd *cobra.Command) {
+	cmd.Flags().String("api", api.TypeClient, "API to go through: client, or admin to use the admin token")
+	_ = cmd.RegisterFlagCompletionFunc("api", cobra.FixedCompletions(
+		[]string{api.TypeClient, api.TypeAdmin}, cobra.ShellCompDirectiveNoFileComp))
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{"api": carapace.ActionValues(api.TypeClient, api.TypeAdmin)})
+}
+
+// apiType returns the API selected with --api, the Client API for commands without the flag.
+func apiType(cmd *cobra.Command) string {
+	if flag := cmd.Flags().Lookup("api"); flag != nil {
+		return flag.Value.String()
+	}
+	return api.TypeClient
+}
+
+// newServerAPI creates the client of the API selected with --api.
+func newServerAPI(cmd *cobra.Command) (api.ServerAPI, error) {
+	return api.NewServerAPI(apiType(cmd))
+}
+
+// completeServers completes the servers of the API selected with --api.
+func completeServers(cmd *cobra.Command, toComplete string) ([]string, error) {
+	return completion.CompleteServers(apiType(cmd), toComplete)
+}
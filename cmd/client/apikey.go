@@ -0,0 +1,164 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/auth"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func newAPIKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apikey",
+		Short: "Manage your Client API keys",
+		Long:  "List, create, and delete the Client API keys of your account, e.g. to rotate tokens from scripts",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your API keys",
+		Long:  "List your Client API keys with their allowed IPs and when they were last used",
+		Args:  cobra.NoArgs,
+		RunE:  runAPIKeyList,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API key",
+		Long: "Create a Client API key. The token is printed once and cannot be retrieved later.\n" +
+			"With --json the token is in meta.secret_token.",
+		Args: cobra.NoArgs,
+		RunE: runAPIKeyCreate,
+	}
+	createCmd.Flags().String("description", "", "description of the key (required)")
+	createCmd.Flags().StringSlice("allowed-ip", nil, "IP address or CIDR range allowed to use the key (repeatable)")
+	_ = createCmd.MarkFlagRequired("description")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <identifier>",
+		Short: "Delete an API key",
+		Long:  "Delete a Client API key by its identifier. Anything still using the key stops working.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAPIKeyDelete,
+	}
+	deleteCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	deleteCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		completions, err := completion.CompleteAPIKeys(toComplete)
+		if err != nil || len(completions) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(createCmd)
+	cmd.AddCommand(deleteCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	carapace.Gen(deleteCmd).PositionalCompletion(
+		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completion.CompleteAPIKeys(c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}),
+	)
+
+	return cmd
+}
+
+func runAPIKeyList(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	keys, err := client.ListAPIKeys(ctx)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	return formatter.PrintWithConfig(keys, output.ResourceTypeClientAPIKey)
+}
+
+func runAPIKeyCreate(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	description, _ := cmd.Flags().GetString("description")
+	allowedIPs, _ := cmd.Flags().GetStringSlice("allowed-ip")
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	key, err := client.CreateAPIKey(ctx, description, allowedIPs)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	format := getOutputFormat(cmd)
+	formatter := output.NewFormatter(format, os.Stdout)
+	if format.IsJSON() {
+		return formatter.Print(key)
+	}
+
+	formatter.PrintSuccess("API key created")
+	if err := formatter.PrintWithConfig([]map[string]any{key}, output.ResourceTypeClientAPIKey); err != nil {
+		return err
+	}
+	if meta, ok := key["meta"].(map[string]any); ok {
+		if token, ok := meta["secret_token"].(string); ok {
+			formatter.PrintInfo("Token (shown only once): %s", token)
+		}
+	}
+	return nil
+}
+
+func runAPIKeyDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	identifier := args[0]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	message := fmt.Sprintf("This will delete API key %s.", identifier)
+	// Client tokens start with the key identifier, so this spots the key pelicanctl is using right now.
+	token, tokenErr := auth.GetToken("client")
+	if tokenErr == nil && identifier != "" && strings.HasPrefix(token, identifier) {
+		message += " It is the key pelicanctl is currently using, so further client commands will fail."
+	}
+	shouldContinue, err := output.Confirm(cmd, formatter, message)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	if err := client.DeleteAPIKey(ctx, identifier); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("API key %s deleted", identifier)
+	formatter.PrintAffected(identifier)
+	return nil
+}
@@ -17,7 +17,7 @@ func newBackupCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "backup",
 		Short: "Manage server backups",
-		Long:  "List and create server backups",
+		Long:  "List, create, copy, delete, and lock server backups",
 	}
 
 	listCmd := &cobra.Command{
@@ -27,8 +27,9 @@ func newBackupCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  runBackupList,
 	}
-	listCmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		completions, err := completion.CompleteServers("client", toComplete)
+	addAPIFlag(listCmd)
+	listCmd.ValidArgsFunction = func(c *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions, err := completeServers(c, toComplete)
 		if err != nil || len(completions) == 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
@@ -50,18 +51,74 @@ func newBackupCmd() *cobra.Command {
 		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
 
+	copyCmd := &cobra.Command{
+		Use:   "copy <src-id|uuid> <backup-uuid> <dst-id|uuid>",
+		Short: "Copy a backup archive to another server",
+		Long: "Copy a backup archive from one server into another server's files. " +
+			"The archive is streamed from the source to the destination without being stored locally. " +
+			"Use --extract to unpack it on the destination, e.g. for world transfers.",
+		Args: cobra.ExactArgs(3), //nolint:mnd // source server, backup, destination server
+		RunE: runBackupCopy,
+	}
+	copyCmd.Flags().String("dir", "/", "remote directory on the destination server")
+	copyCmd.Flags().Bool("extract", false, "decompress the archive on the destination server after upload")
+	copyCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions, err := completeBackupCopyArg(args, toComplete)
+		if err != nil || len(completions) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <id|uuid> <backup-uuid>",
+		Short: "Delete a backup",
+		Long:  "Delete a backup for a server by ID (integer) or UUID (string)",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and backup
+		RunE:  runBackupDelete,
+	}
+	deleteCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	addAPIFlag(deleteCmd)
+	deleteCmd.ValidArgsFunction = serverBackupValidArgs
+
+	lockCmd := &cobra.Command{
+		Use:   "lock <id|uuid> <backup-uuid>",
+		Short: "Lock a backup",
+		Long:  "Lock a backup so it cannot be deleted until unlocked",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and backup
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackupSetLocked(cmd, args, true)
+		},
+	}
+	lockCmd.ValidArgsFunction = serverBackupValidArgs
+
+	unlockCmd := &cobra.Command{
+		Use:   "unlock <id|uuid> <backup-uuid>",
+		Short: "Unlock a backup",
+		Long:  "Unlock a previously locked backup",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and backup
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackupSetLocked(cmd, args, false)
+		},
+	}
+	unlockCmd.ValidArgsFunction = serverBackupValidArgs
+
 	// Add subcommands FIRST (matching carapace example pattern)
 	cmd.AddCommand(listCmd)
 	cmd.AddCommand(createCmd)
+	cmd.AddCommand(copyCmd)
+	cmd.AddCommand(deleteCmd)
+	cmd.AddCommand(lockCmd)
+	cmd.AddCommand(unlockCmd)
 
 	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
 	carapace.Gen(listCmd).PositionalCompletion(
 		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
-			completions, err := completion.CompleteServers("client", c.Value)
+			completions, err := completeServers(listCmd, c.Value)
 			if err != nil || len(completions) == 0 {
 				return carapace.ActionValues()
 			}
-			return carapace.ActionValues(completions...)
+			return completion.Action(completions)
 		}),
 	)
 	carapace.Gen(createCmd).PositionalCompletion(
@@ -70,31 +127,94 @@ func newBackupCmd() *cobra.Command {
 			if err != nil || len(completions) == 0 {
 				return carapace.ActionValues()
 			}
-			return carapace.ActionValues(completions...)
+			return completion.Action(completions)
 		}),
 	)
 
+	carapace.Gen(copyCmd).PositionalAnyCompletion(
+		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completeBackupCopyArg(c.Args, c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}),
+	)
+
+	for _, backupCmd := range []*cobra.Command{deleteCmd, lockCmd, unlockCmd} {
+		carapace.Gen(backupCmd).PositionalCompletion(
+			carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+				completions, err := completeServers(backupCmd, c.Value)
+				if err != nil || len(completions) == 0 {
+					return carapace.ActionValues()
+				}
+				return completion.Action(completions)
+			}),
+			carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+				completions, err := completion.CompleteBackups(c.Args[0], c.Value)
+				if err != nil || len(completions) == 0 {
+					return carapace.ActionValues()
+				}
+				return completion.Action(completions)
+			}),
+		)
+	}
+
 	return cmd
 }
 
+// serverBackupValidArgs completes a server followed by one of its backups.
+func serverBackupValidArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	var err error
+	switch len(args) {
+	case 0:
+		completions, err = completeServers(cmd, toComplete)
+	case 1:
+		completions, err = completion.CompleteBackups(args[0], toComplete)
+	}
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBackupCopyArg completes servers for the source and destination and backups of the source.
+func completeBackupCopyArg(args []string, toComplete string) ([]string, error) {
+	switch len(args) {
+	case 0, 2: //nolint:mnd // source and destination server positions
+		return completion.CompleteServers("client", toComplete)
+	case 1:
+		return completion.CompleteBackups(args[0], toComplete)
+	default:
+		return nil, nil
+	}
+}
+
 func runBackupList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	serverUUID := args[0]
 
-	client, err := api.NewClientAPI()
+	client, err := newServerAPI(cmd)
 	if err != nil {
 		return err
 	}
 
-	backups, err := client.ListBackups(serverUUID)
+	backups, err := client.ListBackups(ctx, serverUUID)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
+	resourceType := output.ResourceTypeClientBackup
+	if client.Type() == api.TypeAdmin {
+		resourceType = output.ResourceTypeAdminBackup
+	}
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
-	return formatter.PrintWithConfig(backups, output.ResourceTypeClientBackup)
+	return formatter.PrintWithConfig(backups, resourceType)
 }
 
 func runBackupCreate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	serverUUID := args[0]
 
 	client, err := api.NewClientAPI()
@@ -102,12 +222,90 @@ func runBackupCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	backup, err := client.CreateBackup(serverUUID)
+	backup, err := client.CreateBackup(ctx, serverUUID)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 	formatter.PrintSuccess("Backup created successfully")
 	return formatter.Print(backup)
 }
+
+func runBackupCopy(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	srcServer, backupUUID, dstServer := args[0], args[1], args[2]
+	remoteDir, _ := cmd.Flags().GetString("dir")
+	extract, _ := cmd.Flags().GetBool("extract")
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintInfo("Copying backup %s from %s to %s...", backupUUID, srcServer, dstServer)
+
+	archivePath, err := client.CopyBackup(ctx, srcServer, backupUUID, dstServer, remoteDir, extract)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	if extract {
+		formatter.PrintSuccess("Backup copied to %s and extracted into %s", archivePath, remoteDir)
+	} else {
+		formatter.PrintSuccess("Backup copied to %s", archivePath)
+	}
+	return nil
+}
+
+func runBackupDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, backupUUID := args[0], args[1]
+
+	client, err := newServerAPI(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	shouldContinue, err := output.Confirm(cmd, formatter, fmt.Sprintf("This will delete backup %s.", backupUUID))
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	if err := client.DeleteBackup(ctx, serverUUID, backupUUID); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("Backup %s deleted", backupUUID)
+	formatter.PrintAffected(backupUUID)
+	return nil
+}
+
+func runBackupSetLocked(cmd *cobra.Command, args []string, locked bool) error {
+	ctx := cmd.Context()
+	serverUUID, backupUUID := args[0], args[1]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	backup, err := client.SetBackupLocked(ctx, serverUUID, backupUUID, locked)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if locked {
+		formatter.PrintSuccess("Backup %s locked", backupUUID)
+	} else {
+		formatter.PrintSuccess("Backup %s unlocked", backupUUID)
+	}
+	return formatter.Print(backup)
+}
@@ -18,7 +18,12 @@ func NewClientCmd() *cobra.Command {
 	cmd.AddCommand(newFileCmd())
 	cmd.AddCommand(newBackupCmd())
 	cmd.AddCommand(newDatabaseCmd())
+	cmd.AddCommand(newScheduleCmd())
+	cmd.AddCommand(newNetworkCmd())
+	cmd.AddCommand(newSettingsCmd())
+	cmd.AddCommand(newAPIKeyCmd())
 	cmd.AddCommand(newPowerCmd())
+	cmd.AddCommand(newActivityCmd())
 
 	return cmd
 }
@@ -17,7 +17,7 @@ func newDatabaseCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "database",
 		Short: "Manage server databases",
-		Long:  "List databases for a server",
+		Long:  "List, create, delete, and rotate passwords for server databases",
 	}
 
 	listCmd := &cobra.Command{
@@ -35,8 +35,50 @@ func newDatabaseCmd() *cobra.Command {
 		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
 
+	createCmd := &cobra.Command{
+		Use:   "create <id|uuid> <name>",
+		Short: "Create a database for a server",
+		Long:  "Create a database for a server by ID (integer) or UUID (string)",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and database name
+		RunE:  runDatabaseCreate,
+	}
+	createCmd.Flags().String("remote", "%", "hosts allowed to connect (% for any host)")
+	createCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		completions, err := completion.CompleteServers("client", toComplete)
+		if err != nil || len(completions) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <id|uuid> <database>",
+		Short: "Delete a database",
+		Long:  "Delete a database by ID or name for a server by ID (integer) or UUID (string)",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and database
+		RunE:  runDatabaseDelete,
+	}
+	deleteCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	deleteCmd.ValidArgsFunction = serverDatabaseValidArgs
+
+	rotateCmd := &cobra.Command{
+		Use:   "rotate-password <id|uuid> <database>",
+		Short: "Rotate a database password",
+		Long:  "Generate a new password for a database by ID or name. The new password is printed once.",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and database
+		RunE:  runDatabaseRotatePassword,
+	}
+	rotateCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	rotateCmd.ValidArgsFunction = serverDatabaseValidArgs
+
 	// Add subcommand FIRST (matching carapace example pattern)
 	cmd.AddCommand(listCmd)
+	cmd.AddCommand(createCmd)
+	cmd.AddCommand(deleteCmd)
+	cmd.AddCommand(rotateCmd)
 
 	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
 	carapace.Gen(listCmd).PositionalCompletion(
@@ -45,14 +87,59 @@ func newDatabaseCmd() *cobra.Command {
 			if err != nil || len(completions) == 0 {
 				return carapace.ActionValues()
 			}
-			return carapace.ActionValues(completions...)
+			return completion.Action(completions)
 		}),
 	)
 
+	carapace.Gen(createCmd).PositionalCompletion(
+		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := completion.CompleteServers("client", c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}),
+	)
+	for _, c := range []*cobra.Command{deleteCmd, rotateCmd} {
+		carapace.Gen(c).PositionalCompletion(
+			carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+				completions, err := completion.CompleteServers("client", c.Value)
+				if err != nil || len(completions) == 0 {
+					return carapace.ActionValues()
+				}
+				return completion.Action(completions)
+			}),
+			carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+				completions, err := completion.CompleteDatabases(c.Args[0], c.Value)
+				if err != nil || len(completions) == 0 {
+					return carapace.ActionValues()
+				}
+				return completion.Action(completions)
+			}),
+		)
+	}
+
 	return cmd
 }
 
+// serverDatabaseValidArgs completes a server followed by one of its databases.
+func serverDatabaseValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	var err error
+	switch len(args) {
+	case 0:
+		completions, err = completion.CompleteServers("client", toComplete)
+	case 1:
+		completions, err = completion.CompleteDatabases(args[0], toComplete)
+	}
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
 func runDatabaseList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	serverUUID := args[0]
 
 	client, err := api.NewClientAPI()
@@ -60,11 +147,89 @@ func runDatabaseList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	databases, err := client.ListDatabases(serverUUID)
+	databases, err := client.ListDatabases(ctx, serverUUID)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 	return formatter.PrintWithConfig(databases, output.ResourceTypeClientDatabase)
 }
+
+func runDatabaseCreate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, name := args[0], args[1]
+	remote, _ := cmd.Flags().GetString("remote")
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	database, err := client.CreateDatabase(ctx, serverUUID, name, remote)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Database created successfully")
+	return formatter.Print(database)
+}
+
+func runDatabaseDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, database := args[0], args[1]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	message := fmt.Sprintf("This will permanently delete database %s and all of its data.", database)
+	shouldContinue, err := output.Confirm(cmd, formatter, message)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	if err := client.DeleteDatabase(ctx, serverUUID, database); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("Database %s deleted", database)
+	formatter.PrintAffected(database)
+	return nil
+}
+
+func runDatabaseRotatePassword(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, database := args[0], args[1]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	message := fmt.Sprintf("This will invalidate the current password of database %s.", database)
+	shouldContinue, err := output.Confirm(cmd, formatter, message)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	result, err := client.RotateDatabasePassword(ctx, serverUUID, database)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("Password rotated for database %s", database)
+	return formatter.Print(result)
+}
@@ -1,15 +1,20 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/carapace-sh/carapace"
 	"github.com/spf13/cobra"
 
 	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
 	"go.lostcrafters.com/pelicanctl/internal/completion"
 	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
 	"go.lostcrafters.com/pelicanctl/internal/output"
@@ -20,7 +25,7 @@ func clientServerCompletionAction(c carapace.Context) carapace.Action {
 	if err != nil || len(completions) == 0 {
 		return carapace.ActionValues()
 	}
-	return carapace.ActionValues(completions...)
+	return completion.Action(completions)
 }
 
 func clientFileCompletionAction(serverUUID string) carapace.Action {
@@ -29,7 +34,7 @@ func clientFileCompletionAction(serverUUID string) carapace.Action {
 		if err != nil || len(completions) == 0 {
 			return carapace.ActionValues()
 		}
-		return carapace.ActionValues(completions...)
+		return completion.Action(completions)
 	})
 }
 
@@ -82,11 +87,195 @@ func setupDownloadCmdCompletion(cmd *cobra.Command) {
 	)
 }
 
+func setupDeleteCmdCompletion(cmd *cobra.Command) {
+	carapace.Gen(cmd).PositionalCompletion(
+		carapace.ActionCallback(clientServerCompletionAction),
+	)
+	carapace.Gen(cmd).PositionalAnyCompletion(
+		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			if len(c.Args) > 0 {
+				return clientFileCompletionAction(c.Args[0])
+			}
+			return carapace.ActionValues()
+		}),
+	)
+}
+
+func setupUploadCmdCompletion(cmd *cobra.Command) {
+	carapace.Gen(cmd).PositionalCompletion(
+		carapace.ActionCallback(clientServerCompletionAction),
+	)
+	carapace.Gen(cmd).PositionalAnyCompletion(carapace.ActionFiles())
+}
+
+// fileBulkFlags holds the flags shared by multi-file operations.
+type fileBulkFlags struct {
+	fromFile        string
+	maxConcurrency  int
+	continueOnError bool
+	failFast        bool
+	dryRun          bool
+}
+
+// setupFileBulkFlags adds the flags used to run file operations through the bulk executor.
+func setupFileBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from-file", "", "read file paths from file (one per line)")
+	const defaultMaxConcurrency = 10
+	cmd.Flags().Int("max-concurrency", defaultMaxConcurrency, "maximum parallel operations")
+	cmd.Flags().Bool("continue-on-error", false, "continue on errors")
+	cmd.Flags().Bool("fail-fast", false, "stop on first error")
+	cmd.Flags().Bool("dry-run", false, "preview operations without executing")
+}
+
+func getFileBulkFlags(cmd *cobra.Command) fileBulkFlags {
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	return fileBulkFlags{
+		fromFile:        fromFile,
+		maxConcurrency:  maxConcurrency,
+		continueOnError: continueOnError,
+		failFast:        failFast,
+		dryRun:          dryRun,
+	}
+}
+
+// readPathsFromFile reads one path per line, skipping empty lines and # comments.
+func readPathsFromFile(fromFile string) ([]string, error) {
+	data, err := os.ReadFile(fromFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var paths []string
+	for line := range strings.SplitSeq(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// collectFilePaths combines paths given as arguments with paths read from --from-file.
+func collectFilePaths(args []string, fromFile string) ([]string, error) {
+	paths := append([]string{}, args...)
+	if fromFile != "" {
+		filePaths, err := readPathsFromFile(fromFile)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, filePaths...)
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no files specified")
+	}
+	return paths, nil
+}
+
+// executeFileOperations runs fn for every path through the bulk executor.
+func executeFileOperations(
+	ctx context.Context,
+	paths []string,
+	flags fileBulkFlags,
+	fn func(ctx context.Context, path string) error,
+) []bulk.Result {
+	operations := make([]bulk.Operation, len(paths))
+	for i, path := range paths {
+		operations[i] = bulk.Operation{
+			ID:   path,
+			Name: path,
+			Exec: func(opCtx context.Context) error {
+				return fn(opCtx, path)
+			},
+		}
+	}
+
+	executor := bulk.NewExecutor(flags.maxConcurrency, flags.continueOnError, flags.failFast).
+		WithRecord(bulk.KeyedRecord("path"))
+	return executor.Execute(ctx, operations)
+}
+
+// printFileResults prints per-path results and the summary for a bulk file operation.
+func printFileResults(
+	cmd *cobra.Command,
+	formatter *output.Formatter,
+	results []bulk.Result,
+	action string,
+	continueOnError bool,
+) error {
+	if getOutputFormat(cmd).IsJSON() {
+		summary := bulk.GetSummary(results)
+		return bulk.PrintBulkJSONWithKey(formatter, results, summary, continueOnError, "path")
+	}
+
+	for _, result := range results {
+		if result.Success {
+			formatter.PrintSuccess("%s: %s", result.Operation.ID, action)
+		} else {
+			formatter.PrintError("%s: %v", result.Operation.ID, result.Error)
+		}
+	}
+
+	return handleCommandSummary(formatter, results, continueOnError)
+}
+
+// stoppedRequests runs calls in a dry run and returns the requests they would send, in order. Reads still go
+// out, so the server and remote files are looked up as usual.
+func stoppedRequests(ctx context.Context, calls ...func(context.Context) error) ([]output.Request, error) {
+	ctx = api.WithDryRun(ctx)
+	var requests []output.Request
+	for _, call := range calls {
+		err := call(ctx)
+		if req, ok := api.AsDryRun(err); ok {
+			requests = append(requests, req.Output())
+			continue
+		}
+		if err != nil {
+			return nil, apierrors.Handle(err)
+		}
+	}
+	return requests, nil
+}
+
+// printDryRunRequests prints the requests of a dry run one after another, or as a list in the output formats
+// other than tables.
+func printDryRunRequests(cmd *cobra.Command, formatter *output.Formatter, requests []output.Request) error {
+	if format := getOutputFormat(cmd); format != output.OutputFormatTable && format != output.OutputFormatWide {
+		return formatter.Print(requestData(requests))
+	}
+	for _, req := range requests {
+		if err := formatter.PrintDryRun(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestData returns requests as data, for the output formats other than tables.
+func requestData(requests []output.Request) []map[string]any {
+	data := make([]map[string]any, 0, len(requests))
+	for _, req := range requests {
+		data = append(data, req.Data())
+	}
+	return data
+}
+
+func handleFileDryRun(formatter *output.Formatter, action string, paths []string) {
+	formatter.PrintInfo("Dry run - would %s %d file(s):", action, len(paths))
+	for _, path := range paths {
+		formatter.PrintInfo("  - %s", path)
+	}
+}
+
 func newFileCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "file",
 		Short: "Manage server files",
-		Long:  "List, download, and upload server files",
+		Long:  "List, download, upload, and delete server files",
 	}
 
 	listCmd := &cobra.Command{
@@ -104,12 +293,22 @@ func newFileCmd() *cobra.Command {
 	}
 
 	downloadCmd := &cobra.Command{
-		Use:   "download <id|uuid> <remote-path> [local-path]",
-		Short: "Download a file from the server",
-		Long:  "Download a file from a server by ID (integer) or UUID (string)",
-		Args:  cobra.RangeArgs(2, 3), //nolint:mnd // Valid range for optional local-path argument
-		RunE:  runFileDownload,
+		Use:   "download <id|uuid> <remote-path> [local-path] | <id|uuid> --from-file <file>",
+		Short: "Download file(s) from the server",
+		Long: "Download a file from a server by ID (integer) or UUID (string). " +
+			"Use --from-file to download many files in parallel into --output-dir, where they keep their " +
+			"paths relative to the server root.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			fromFile, _ := cmd.Flags().GetString("from-file")
+			if fromFile != "" {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.RangeArgs(2, 3)(cmd, args) //nolint:mnd // Valid range for optional local-path argument
+		},
+		RunE: runFileDownload,
 	}
+	setupFileBulkFlags(downloadCmd)
+	downloadCmd.Flags().String("output-dir", ".", "local directory for files downloaded with --from-file, under their remote paths")
 	downloadCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return clientServerValidArgsFunction(nil, nil, toComplete)
@@ -120,18 +319,63 @@ func newFileCmd() *cobra.Command {
 		return nil, cobra.ShellCompDirectiveDefault
 	}
 
+	uploadCmd := &cobra.Command{
+		Use:   "upload <id|uuid> <local-path>... [--dir <remote-dir>]",
+		Short: "Upload file(s) to the server",
+		Long: "Upload one or more local files to a server by ID (integer) or UUID (string). " +
+			"Files are uploaded in parallel; use --from-file to read local paths from a file. " +
+			"Before uploading, the remote files are diffed against the local ones and overwriting " +
+			"existing files asks for confirmation; --dry-run shows the diff and the requests that would be " +
+			"sent, and uploads nothing.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: runFileUpload,
+	}
+	setupFileBulkFlags(uploadCmd)
+	uploadCmd.Flags().String("dir", "/", "remote directory to upload into")
+	uploadCmd.Flags().Bool("yes", false, "skip the diff preview and confirmation prompt")
+	uploadCmd.Flags().Bool("backup-remote", false, "save each overwritten remote file as <name>.bak-<timestamp> first")
+	uploadCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return clientServerValidArgsFunction(nil, nil, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <id|uuid> [remote-path]... [--from-file <file>]",
+		Short: "Delete file(s) from the server",
+		Long: "Delete one or more files or directories from a server by ID (integer) or UUID (string). " +
+			"Files are deleted in parallel; use --from-file to read remote paths from a file. " +
+			"--dry-run prints the requests that would be sent and deletes nothing.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: runFileDelete,
+	}
+	setupFileBulkFlags(deleteCmd)
+	deleteCmd.Flags().Bool("yes", false, "skip confirmation prompts")
+	deleteCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return clientServerValidArgsFunction(nil, nil, toComplete)
+		}
+		return clientFileValidArgsFunction(args[0])(nil, nil, toComplete)
+	}
+
 	// Add subcommands FIRST (matching carapace example pattern)
 	cmd.AddCommand(listCmd)
 	cmd.AddCommand(downloadCmd)
+	cmd.AddCommand(uploadCmd)
+	cmd.AddCommand(deleteCmd)
 
 	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
 	setupListCmdCompletion(listCmd)
 	setupDownloadCmdCompletion(downloadCmd)
+	setupUploadCmdCompletion(uploadCmd)
+	setupDeleteCmdCompletion(deleteCmd)
 
 	return cmd
 }
 
 func runFileList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	serverUUID := args[0]
 	directory := ""
 	if len(args) > 1 {
@@ -143,9 +387,9 @@ func runFileList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	files, err := client.ListFiles(serverUUID, directory)
+	files, err := client.ListFiles(ctx, serverUUID, directory)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
@@ -153,7 +397,14 @@ func runFileList(cmd *cobra.Command, args []string) error {
 }
 
 func runFileDownload(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	serverUUID := args[0]
+	flags := getFileBulkFlags(cmd)
+
+	if flags.fromFile != "" {
+		return runFileDownloadMany(cmd, serverUUID, flags)
+	}
+
 	remotePath := args[1]
 	localPath := filepath.Base(remotePath)
 	const maxArgsWithOptional = 3
@@ -166,12 +417,84 @@ func runFileDownload(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	reader, err := client.DownloadFile(serverUUID, remotePath)
+	if downloadErr := downloadFile(ctx, client, serverUUID, remotePath, localPath); downloadErr != nil {
+		return downloadErr
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Downloaded %s to %s", remotePath, localPath)
+	return nil
+}
+
+// runFileDownloadMany downloads every path listed in --from-file into --output-dir in parallel.
+func runFileDownloadMany(cmd *cobra.Command, serverUUID string, flags fileBulkFlags) error {
+	ctx := cmd.Context()
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+
+	remotePaths, err := collectFilePaths(nil, flags.fromFile)
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	if flags.dryRun {
+		handleFileDryRun(formatter, "download", remotePaths)
+		return nil
+	}
+
+	localPaths, err := downloadTargets(outputDir, remotePaths)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	results := executeFileOperations(ctx, remotePaths, flags, func(opCtx context.Context, remotePath string) error {
+		return downloadFile(opCtx, client, serverUUID, remotePath, localPaths[remotePath])
+	})
+
+	return printFileResults(cmd, formatter, results, "downloaded", flags.continueOnError)
+}
+
+// downloadTargets maps every remote path to the local path it is downloaded to: its path relative to the
+// server root, under outputDir, so files of the same name in different directories don't overwrite each
+// other. Paths listed twice are rejected for the same reason.
+func downloadTargets(outputDir string, remotePaths []string) (map[string]string, error) {
+	localPaths := make(map[string]string, len(remotePaths))
+	downloadedFrom := make(map[string]string, len(remotePaths))
+	for _, remotePath := range remotePaths {
+		// Cleaning the path as an absolute one keeps it from leaving outputDir.
+		relative := strings.TrimPrefix(path.Clean("/"+remotePath), "/")
+		if relative == "" {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("%s is not a file to download", remotePath))
+		}
+		localPath := filepath.Join(outputDir, filepath.FromSlash(relative))
+		if other, ok := downloadedFrom[localPath]; ok {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("%s and %s would both be downloaded to %s", other, remotePath, localPath))
+		}
+		downloadedFrom[localPath] = remotePath
+		localPaths[remotePath] = localPath
+	}
+	return localPaths, nil
+}
+
+// downloadFile downloads a single remote file to localPath, creating its directory if needed.
+func downloadFile(ctx context.Context, client *api.ClientAPI, serverUUID, remotePath, localPath string) error {
+	reader, err := client.DownloadFile(ctx, serverUUID, remotePath)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 	defer reader.Close()
 
+	if mkdirErr := os.MkdirAll(filepath.Dir(localPath), 0750); mkdirErr != nil {
+		return fmt.Errorf("failed to create output directory: %w", mkdirErr)
+	}
 	localFile, err := os.Create(localPath)
 	if err != nil {
 		return fmt.Errorf("failed to create local file: %w", err)
@@ -182,7 +505,128 @@ func runFileDownload(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to write file: %w", copyErr)
 	}
 
-	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
-	formatter.PrintSuccess("Downloaded %s to %s", remotePath, localPath)
 	return nil
 }
+
+func runFileUpload(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID := args[0]
+	flags := getFileBulkFlags(cmd)
+	remoteDir, _ := cmd.Flags().GetString("dir")
+	yes, _ := cmd.Flags().GetBool("yes")
+	backupRemote, _ := cmd.Flags().GetBool("backup-remote")
+
+	localPaths, err := collectFilePaths(args[1:], flags.fromFile)
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	// With --yes and nothing to back up there is nothing to compare, so upload straight away.
+	var plans map[string]*uploadPlan
+	if !yes || flags.dryRun || backupRemote {
+		plans, err = planUploads(ctx, client, serverUUID, remoteDir, localPaths, flags)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.dryRun {
+		if planErr := planUploadRequests(ctx, client, serverUUID, remoteDir, plans, backupRemote); planErr != nil {
+			return planErr
+		}
+		return printUploadPlans(formatter, getOutputFormat(cmd).IsJSON(), localPaths, plans)
+	}
+
+	if !yes {
+		if !getOutputFormat(cmd).IsJSON() {
+			if printErr := printUploadPlans(formatter, false, localPaths, plans); printErr != nil {
+				return printErr
+			}
+		}
+		if changed := countUploadChanges(plans); changed > 0 {
+			shouldContinue, confirmErr := output.Confirm(
+				cmd, formatter, fmt.Sprintf("This will overwrite %d remote file(s).", changed),
+			)
+			if confirmErr != nil {
+				return confirmErr
+			}
+			if !shouldContinue {
+				return nil
+			}
+		}
+	}
+
+	results := executeFileOperations(ctx, localPaths, flags, func(opCtx context.Context, localPath string) error {
+		plan := plans[localPath]
+		if plan != nil && plan.status == uploadStatusUnchanged {
+			return nil
+		}
+		if backupRemote && plan != nil && plan.status == uploadStatusChanged {
+			if _, backupErr := client.BackupFile(opCtx, serverUUID, plan.remotePath, plan.previous); backupErr != nil {
+				return fmt.Errorf("failed to back up %s: %w", plan.remotePath, apierrors.Handle(backupErr))
+			}
+		}
+		if uploadErr := client.UploadFile(opCtx, serverUUID, localPath, remoteDir); uploadErr != nil {
+			return apierrors.Handle(uploadErr)
+		}
+		return nil
+	})
+
+	return printFileResults(cmd, formatter, results, "uploaded", flags.continueOnError)
+}
+
+func runFileDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID := args[0]
+	flags := getFileBulkFlags(cmd)
+
+	remotePaths, err := collectFilePaths(args[1:], flags.fromFile)
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	if flags.dryRun {
+		requests := make([]output.Request, 0, len(remotePaths))
+		for _, remotePath := range remotePaths {
+			stopped, stopErr := stoppedRequests(ctx, func(ctx context.Context) error {
+				return client.DeleteFile(ctx, serverUUID, remotePath)
+			})
+			if stopErr != nil {
+				return fmt.Errorf("%s: %w", remotePath, stopErr)
+			}
+			requests = append(requests, stopped...)
+		}
+		return printDryRunRequests(cmd, formatter, requests)
+	}
+
+	shouldContinue, err := output.Confirm(cmd, formatter, fmt.Sprintf("This will delete %d file(s).", len(remotePaths)))
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	results := executeFileOperations(ctx, remotePaths, flags, func(opCtx context.Context, remotePath string) error {
+		if deleteErr := client.DeleteFile(opCtx, serverUUID, remotePath); deleteErr != nil {
+			return apierrors.Handle(deleteErr)
+		}
+		return nil
+	})
+
+	return printFileResults(cmd, formatter, results, "deleted", flags.continueOnError)
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/diff"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// maxDiffSize is the largest file shown as a diff; larger files are only summarized.
+const maxDiffSize = 1 << 20
+
+// Remote file states found before an upload.
+const (
+	uploadStatusNew       = "new"
+	uploadStatusChanged   = "changed"
+	uploadStatusUnchanged = "unchanged"
+)
+
+// uploadPlan compares one local file with the remote file it would replace.
+type uploadPlan struct {
+	localPath  string
+	remotePath string
+	status     string
+	// previous is the remote content, kept for --backup-remote.
+	previous []byte
+	diff     string
+	// requests are the requests the upload would send, for --dry-run.
+	requests []output.Request
+}
+
+// planUploads fetches the remote version of every file to be uploaded and diffs it against the local file.
+func planUploads(
+	ctx context.Context,
+	client *api.ClientAPI,
+	serverUUID, remoteDir string,
+	localPaths []string,
+	flags fileBulkFlags,
+) (map[string]*uploadPlan, error) {
+	plans := make(map[string]*uploadPlan, len(localPaths))
+	var mu sync.Mutex
+
+	results := executeFileOperations(ctx, localPaths, flags, func(opCtx context.Context, localPath string) error {
+		plan, err := planUpload(opCtx, client, serverUUID, remoteDir, localPath)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		plans[localPath] = plan
+		mu.Unlock()
+		return nil
+	})
+
+	for _, result := range results {
+		if !result.Success {
+			return nil, fmt.Errorf("%s: %w", result.Operation.ID, result.Error)
+		}
+	}
+	return plans, nil
+}
+
+func planUpload(
+	ctx context.Context,
+	client *api.ClientAPI,
+	serverUUID, remoteDir, localPath string,
+) (*uploadPlan, error) {
+	plan := &uploadPlan{
+		localPath:  localPath,
+		remotePath: path.Join("/", remoteDir, filepath.Base(localPath)),
+	}
+
+	local, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local file: %w", err)
+	}
+
+	remote, exists, err := client.GetFileContents(ctx, serverUUID, plan.remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote file: %w", apierrors.Handle(err))
+	}
+	plan.previous = remote
+
+	switch {
+	case !exists:
+		plan.status = uploadStatusNew
+		remote = nil
+	case string(remote) == string(local):
+		plan.status = uploadStatusUnchanged
+		return plan, nil
+	default:
+		plan.status = uploadStatusChanged
+	}
+
+	switch {
+	case diff.IsBinary(local) || diff.IsBinary(remote):
+		plan.diff = fmt.Sprintf("Binary files differ (%d -> %d bytes)\n", len(remote), len(local))
+	case len(local) > maxDiffSize || len(remote) > maxDiffSize:
+		plan.diff = fmt.Sprintf("Files too large to diff (%d -> %d bytes)\n", len(remote), len(local))
+	default:
+		oldName := plan.remotePath
+		if !exists {
+			oldName = "/dev/null"
+		}
+		plan.diff = diff.Unified(oldName, plan.remotePath+" (local: "+localPath+")", remote, local)
+	}
+	return plan, nil
+}
+
+// planUploadRequests records the requests every upload that changes a file would send, including the backup
+// of the remote file with backupRemote, by running them in a dry run.
+func planUploadRequests(
+	ctx context.Context,
+	client *api.ClientAPI,
+	serverUUID, remoteDir string,
+	plans map[string]*uploadPlan,
+	backupRemote bool,
+) error {
+	for localPath, plan := range plans {
+		if plan.status == uploadStatusUnchanged {
+			continue
+		}
+		var calls []func(context.Context) error
+		if backupRemote && plan.status == uploadStatusChanged {
+			calls = append(calls, func(ctx context.Context) error {
+				_, err := client.BackupFile(ctx, serverUUID, plan.remotePath, plan.previous)
+				return err
+			})
+		}
+		calls = append(calls, func(ctx context.Context) error {
+			return client.UploadFile(ctx, serverUUID, localPath, remoteDir)
+		})
+		requests, err := stoppedRequests(ctx, calls...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", localPath, err)
+		}
+		plan.requests = requests
+	}
+	return nil
+}
+
+// printUploadPlans shows the diff of every upload, or the plans as a list in JSON mode.
+func printUploadPlans(
+	formatter *output.Formatter,
+	jsonOutput bool,
+	localPaths []string,
+	plans map[string]*uploadPlan,
+) error {
+	if jsonOutput {
+		list := make([]map[string]any, 0, len(localPaths))
+		for _, localPath := range localPaths {
+			plan := plans[localPath]
+			list = append(list, map[string]any{
+				"path":        plan.localPath,
+				"remote_path": plan.remotePath,
+				"status":      plan.status,
+				"diff":        plan.diff,
+				"requests":    requestData(plan.requests),
+			})
+		}
+		return formatter.Print(list)
+	}
+
+	for _, localPath := range localPaths {
+		plan := plans[localPath]
+		formatter.PrintInfo("%s -> %s (%s)", plan.localPath, plan.remotePath, plan.status)
+		if plan.diff != "" {
+			_, _ = fmt.Fprint(os.Stdout, plan.diff)
+		}
+		for _, req := range plan.requests {
+			if err := formatter.PrintDryRun(req); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// countUploadChanges returns how many uploads overwrite an existing remote file.
+func countUploadChanges(plans map[string]*uploadPlan) int {
+	changed := 0
+	for _, plan := range plans {
+		if plan.status == uploadStatusChanged {
+			changed++
+		}
+	}
+	return changed
+}
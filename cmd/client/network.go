@@ -0,0 +1,219 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+func newNetworkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Manage server network allocations",
+		Long: "List, assign, unassign, and annotate the network allocations (ports) of a server. " +
+			"Allocations can be given by ID, port, or ip:port.",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list <id|uuid>",
+		Short: "List allocations for a server",
+		Long:  "List network allocations for a server by ID (integer) or UUID (string)",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runNetworkList,
+	}
+	listCmd.ValidArgsFunction = clientServerValidArgsFunction
+
+	assignCmd := &cobra.Command{
+		Use:   "assign <id|uuid>",
+		Short: "Assign a new allocation to a server",
+		Long: "Assign a new allocation from the node's pool to a server. " +
+			"The panel picks the port; this requires automatic allocation to be enabled for the node.",
+		Args: cobra.ExactArgs(1),
+		RunE: runNetworkAssign,
+	}
+	assignCmd.ValidArgsFunction = clientServerValidArgsFunction
+
+	unassignCmd := &cobra.Command{
+		Use:   "unassign <id|uuid> <allocation>",
+		Short: "Remove an allocation from a server",
+		Long:  "Remove an allocation from a server. The primary allocation cannot be removed.",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and allocation
+		RunE:  runNetworkUnassign,
+	}
+	unassignCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	unassignCmd.ValidArgsFunction = serverAllocationValidArgs
+
+	setPrimaryCmd := &cobra.Command{
+		Use:   "set-primary <id|uuid> <allocation>",
+		Short: "Make an allocation the primary allocation",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and allocation
+		RunE:  runNetworkSetPrimary,
+	}
+	setPrimaryCmd.ValidArgsFunction = serverAllocationValidArgs
+
+	setNoteCmd := &cobra.Command{
+		Use:   "set-note <id|uuid> <allocation> [note]",
+		Short: "Set the note of an allocation",
+		Long:  "Set the note of an allocation. Omit the note to clear it.",
+		Args:  cobra.RangeArgs(2, 3), //nolint:mnd // server, allocation and optional note
+		RunE:  runNetworkSetNote,
+	}
+	setNoteCmd.ValidArgsFunction = serverAllocationValidArgs
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(assignCmd)
+	cmd.AddCommand(unassignCmd)
+	cmd.AddCommand(setPrimaryCmd)
+	cmd.AddCommand(setNoteCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	for _, c := range []*cobra.Command{listCmd, assignCmd} {
+		carapace.Gen(c).PositionalCompletion(carapace.ActionCallback(clientServerCompletionAction))
+	}
+	for _, c := range []*cobra.Command{unassignCmd, setPrimaryCmd, setNoteCmd} {
+		carapace.Gen(c).PositionalCompletion(
+			carapace.ActionCallback(clientServerCompletionAction),
+			carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+				completions, err := completion.CompleteAllocations(c.Args[0], c.Value)
+				if err != nil || len(completions) == 0 {
+					return carapace.ActionValues()
+				}
+				return completion.Action(completions)
+			}),
+		)
+	}
+
+	return cmd
+}
+
+// serverAllocationValidArgs completes a server followed by one of its allocations.
+func serverAllocationValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	var err error
+	switch len(args) {
+	case 0:
+		completions, err = completion.CompleteServers("client", toComplete)
+	case 1:
+		completions, err = completion.CompleteAllocations(args[0], toComplete)
+	}
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func runNetworkList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	allocations, err := client.ListAllocations(ctx, args[0])
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	return formatter.PrintWithConfig(allocations, output.ResourceTypeClientNetwork)
+}
+
+func runNetworkAssign(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	allocation, err := client.AssignAllocation(ctx, args[0])
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Allocation assigned successfully")
+	return formatter.Print(allocation)
+}
+
+func runNetworkUnassign(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, allocation := args[0], args[1]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	message := fmt.Sprintf("This will remove allocation %s from the server.", allocation)
+	shouldContinue, err := output.Confirm(cmd, formatter, message)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	if err := client.UnassignAllocation(ctx, serverUUID, allocation); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("Allocation %s unassigned", allocation)
+	formatter.PrintAffected(allocation)
+	return nil
+}
+
+func runNetworkSetPrimary(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, allocation := args[0], args[1]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.SetPrimaryAllocation(ctx, serverUUID, allocation)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Allocation %s is now the primary allocation", allocation)
+	return formatter.Print(result)
+}
+
+func runNetworkSetNote(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, allocation := args[0], args[1]
+	note := ""
+	if len(args) > 2 { //nolint:mnd // optional note argument
+		note = args[2]
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.SetAllocationNote(ctx, serverUUID, allocation, note)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	if note == "" {
+		formatter.PrintSuccess("Note cleared for allocation %s", allocation)
+	} else {
+		formatter.PrintSuccess("Note updated for allocation %s", allocation)
+	}
+	return formatter.Print(result)
+}
@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/carapace-sh/carapace"
 	"github.com/spf13/cobra"
@@ -13,20 +13,110 @@ import (
 	"go.lostcrafters.com/pelicanctl/internal/api"
 	"go.lostcrafters.com/pelicanctl/internal/bulk"
 	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
 	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/selector"
 )
 
 func setupBulkFlags(cmd *cobra.Command) {
-	cmd.Flags().Bool("all", false, "operate on all servers")
-	cmd.Flags().String("from-file", "", "read server IDs or UUIDs from file (one per line)")
+	selector.AddTargetFlags(cmd, false)
+	completion.RegisterFlagFunc(cmd, "group", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteGroups(toComplete)
+	})
 	const defaultMaxConcurrency = 10
 	cmd.Flags().Int("max-concurrency", defaultMaxConcurrency, "maximum parallel operations")
 	cmd.Flags().Bool("continue-on-error", false, "continue on errors")
 	cmd.Flags().Bool("fail-fast", false, "stop on first error")
+	addOperationFlags(cmd)
 	cmd.Flags().Bool("dry-run", false, "preview operations without executing")
 	cmd.Flags().Bool("yes", false, "skip confirmation prompts")
 }
 
+// addOperationFlags adds the flags retrying the operations of a bulk command, see newExecutor.
+func addOperationFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("op-retries", 0, "run a failed operation again up to this many times")
+	cmd.Flags().Duration("op-retry-backoff", time.Second,
+		"wait before retrying an operation, doubled for every further retry")
+	cmd.Flags().Duration("op-timeout", 0, "give up on an attempt of an operation after this long; 0 means no limit")
+}
+
+// newExecutor creates the executor of a bulk command, with the retries and timeout of its flags.
+func newExecutor(cmd *cobra.Command, maxConcurrency int, continueOnError, failFast bool) *bulk.Executor {
+	retries, _ := cmd.Flags().GetInt("op-retries")
+	backoff, _ := cmd.Flags().GetDuration("op-retry-backoff")
+	timeout, _ := cmd.Flags().GetDuration("op-timeout")
+	return bulk.NewExecutor(maxConcurrency, continueOnError, failFast).
+		WithRetry(retries, backoff).
+		WithTimeout(timeout)
+}
+
+// setupWaitFlags adds --wait and --wait-timeout to a power command that brings servers to a state.
+func setupWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "wait for the servers to be running, or offline for stop (needs the client API)")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "with --wait, how long to wait for a server")
+}
+
+// setupPreCommandFlags adds the flags sending console commands before a server is stopped or restarted,
+// e.g. to warn players and save the world.
+func setupPreCommandFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("pre-command", nil,
+		"send this console command before the power signal, e.g. 'say Restarting in 60s' or save-all (repeatable)")
+	cmd.Flags().Duration("pre-delay", 0, "wait this long between the pre-commands and the power signal")
+}
+
+// powerSteps are what a power operation does besides sending the power command.
+type powerSteps struct {
+	// preCommands are sent to the console first, preDelay before the power command.
+	preCommands []string
+	preDelay    time.Duration
+	// wait, if set, waits for the server to reach the state of the power command.
+	wait powerWait
+	// formatter reports pre-commands that failed.
+	formatter *output.Formatter
+}
+
+// runPreCommands sends the pre-commands to a server and waits the pre-delay. A failed pre-command is only
+// warned about, as a server that is already offline cannot take it; only cancellation stops the operation.
+func (s powerSteps) runPreCommands(ctx context.Context, client api.ServerAPI, uuid string) error {
+	if len(s.preCommands) == 0 {
+		return nil
+	}
+	for _, command := range s.preCommands {
+		if err := client.SendCommand(ctx, uuid, command); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.formatter.PrintWarning("%s: pre-command %q failed: %v", output.ServerLabel(uuid), command, err)
+		}
+	}
+	if s.preDelay <= 0 {
+		return nil
+	}
+	return pause(ctx, s.preDelay)
+}
+
+// powerWait waits for a server sent a power command at sentAt to reach the state the command leads to.
+type powerWait func(ctx context.Context, uuid string, sentAt time.Time) error
+
+// newPowerWait returns how --wait waits for the servers of a power command.
+func newPowerWait(client *api.ClientAPI, command string, timeout time.Duration) powerWait {
+	if command == "restart" {
+		// The server is running before and after a restart, so its uptime tells whether it came back.
+		check := resourcesHealthCheck(client)
+		return func(ctx context.Context, uuid string, sentAt time.Time) error {
+			return waitForHealthy(ctx, check, uuid, sentAt, timeout)
+		}
+	}
+	target := serverStateRunning
+	if command == "stop" {
+		target = serverStateOffline
+	}
+	return func(ctx context.Context, uuid string, _ time.Time) error {
+		return waitForState(ctx, client, uuid, target, timeout)
+	}
+}
+
 type powerCommandConfig struct {
 	use    string
 	short  string
@@ -38,22 +128,24 @@ func createPowerSubcommand(config powerCommandConfig) *cobra.Command {
 		Use:   config.use,
 		Short: config.short,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			all, _ := cmd.Flags().GetBool("all")
-			fromFile, _ := cmd.Flags().GetString("from-file")
-			maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
-			continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
-			failFast, _ := cmd.Flags().GetBool("fail-fast")
-			dryRun, _ := cmd.Flags().GetBool("dry-run")
-			yes, _ := cmd.Flags().GetBool("yes")
-
-			return runPowerCommand(
-				cmd, args, config.action, all, fromFile, maxConcurrency,
-				continueOnError, failFast, dryRun, yes)
+			return runPowerWithFlags(cmd, args, config.action)
 		},
 	}
 	setupBulkFlags(cmd)
-	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		completions, err := completion.CompleteServers("client", toComplete)
+	addAPIFlag(cmd)
+	if config.action != "kill" {
+		setupWaitFlags(cmd)
+	}
+	if config.action == "stop" || config.action == "restart" {
+		setupPreCommandFlags(cmd)
+	}
+	if config.action == "start" {
+		setupGroupStartFlags(cmd)
+	} else {
+		selector.AddProtectedFlag(cmd)
+	}
+	cmd.ValidArgsFunction = func(c *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions, err := completeServers(c, toComplete)
 		if err != nil || len(completions) == 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
@@ -63,6 +155,28 @@ func createPowerSubcommand(config powerCommandConfig) *cobra.Command {
 	return cmd
 }
 
+func runPowerWithFlags(cmd *cobra.Command, args []string, action string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if group, _ := cmd.Flags().GetString("group"); group != "" && action == "start" {
+		if all || fromFile != "" || len(args) > 0 || selector.Given(cmd, "group") {
+			return errors.New("--group cannot be combined with server arguments, --all, --from-file or other selectors")
+		}
+		if apiType(cmd) != api.TypeClient {
+			return apierrors.WithExitCode(apierrors.ExitValidation,
+				errors.New("starting a group in dependency order needs the client API"))
+		}
+		return runGroupStart(cmd, group, maxConcurrency, continueOnError, failFast, dryRun)
+	}
+
+	return runPowerCommand(cmd, args, action, maxConcurrency, continueOnError, failFast, dryRun)
+}
+
 func newPowerCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "power",
@@ -80,17 +194,18 @@ func newPowerCmd() *cobra.Command {
 	for _, pc := range powerCommands {
 		cmd.AddCommand(createPowerSubcommand(pc))
 	}
+	cmd.AddCommand(newRollingRestartCmd())
 
 	// Set up carapace completion AFTER subcommands are added (matching carapace example pattern)
 	// Use PositionalAnyCompletion for commands that accept multiple server arguments
 	for _, subCmd := range cmd.Commands() {
 		carapace.Gen(subCmd).PositionalAnyCompletion(
 			carapace.ActionCallback(func(c carapace.Context) carapace.Action {
-				completions, err := completion.CompleteServers("client", c.Value)
+				completions, err := completeServers(subCmd, c.Value)
 				if err != nil || len(completions) == 0 {
 					return carapace.ActionValues()
 				}
-				return carapace.ActionValues(completions...)
+				return completion.Action(completions)
 			}),
 		)
 	}
@@ -98,27 +213,17 @@ func newPowerCmd() *cobra.Command {
 	return cmd
 }
 
-func handlePowerConfirmation(formatter *output.Formatter, command string, uuidCount int, yes bool) (bool, error) {
-	if yes {
-		return true, nil
-	}
-
+func handlePowerConfirmation(
+	cmd *cobra.Command,
+	formatter *output.Formatter,
+	command string,
+	uuidCount int,
+) (bool, error) {
 	needsConfirmation := command == "kill" || (command == "stop" && uuidCount > 1)
 	if !needsConfirmation {
 		return true, nil
 	}
-
-	formatter.PrintInfo("This will %s %d server(s). Continue? (y/N): ", command, uuidCount)
-	var response string
-	if _, scanErr := fmt.Scanln(&response); scanErr != nil {
-		return false, fmt.Errorf("failed to read response: %w", scanErr)
-	}
-
-	if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-		return false, nil
-	}
-
-	return true, nil
+	return output.Confirm(cmd, formatter, fmt.Sprintf("This will %s %d server(s).", command, uuidCount))
 }
 
 func handlePowerDryRun(formatter *output.Formatter, command string, uuids []string) {
@@ -130,44 +235,58 @@ func handlePowerDryRun(formatter *output.Formatter, command string, uuids []stri
 
 func executePowerOperations(
 	ctx context.Context,
-	client *api.ClientAPI,
+	client api.ServerAPI,
+	executor *bulk.Executor,
 	uuids []string,
 	command string,
-	maxConcurrency int,
-	continueOnError bool,
-	failFast bool,
+	steps powerSteps,
 ) []bulk.Result {
+	// Resolve every server up front, so the operations don't each look theirs up.
+	resolution := client.ResolveServers(ctx, uuids)
 	operations := make([]bulk.Operation, len(uuids))
 	for i, uuid := range uuids {
 		operations[i] = bulk.Operation{
 			ID:   uuid,
 			Name: uuid,
-			Exec: func() error {
-				return client.SendPowerCommand(uuid, command)
+			Exec: func(opCtx context.Context) error {
+				serverUUID, err := resolution.Lookup(uuid)
+				if err != nil {
+					return err
+				}
+				if preErr := steps.runPreCommands(opCtx, client, serverUUID); preErr != nil {
+					return preErr
+				}
+				sentAt := time.Now()
+				if sendErr := client.SendPowerCommand(opCtx, serverUUID, command); sendErr != nil {
+					return sendErr
+				}
+				if steps.wait == nil {
+					return nil
+				}
+				return steps.wait(opCtx, serverUUID, sentAt)
 			},
 		}
 	}
 
-	executor := bulk.NewExecutor(maxConcurrency, continueOnError, failFast)
 	return executor.Execute(ctx, operations)
 }
 
 func printPowerResults(formatter *output.Formatter, results []bulk.Result, command string) {
 	for _, result := range results {
 		if result.Success {
-			formatter.PrintSuccess("%s: %s", result.Operation.ID, command)
+			formatter.PrintSuccess("%s: %s", output.ServerLabel(result.Operation.ID), command)
 		} else {
-			formatter.PrintError("%s: %v", result.Operation.ID, result.Error)
+			formatter.PrintError("%s: %v", output.ServerLabel(result.Operation.ID), result.Error)
 		}
 	}
 }
 
 func handlePowerSummary(formatter *output.Formatter, results []bulk.Result, continueOnError bool) error {
 	summary := bulk.GetSummary(results)
-	formatter.PrintInfo("Summary: %d succeeded, %d failed", summary.Success, summary.Failed)
+	formatter.PrintInfo("Summary: %s", summary)
 
 	if summary.Failed > 0 && !continueOnError {
-		return fmt.Errorf("%d operation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
@@ -177,17 +296,21 @@ func runPowerCommand(
 	cmd *cobra.Command,
 	args []string,
 	command string,
-	all bool,
-	fromFile string,
 	maxConcurrency int,
 	continueOnError bool,
 	failFast bool,
 	dryRun bool,
-	yes bool,
 ) error {
+	ctx := cmd.Context()
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 
-	uuids, err := getServerUUIDs(cmd, args, all, fromFile)
+	wait, _ := cmd.Flags().GetBool("wait")
+	if wait && apiType(cmd) != api.TypeClient {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--wait needs the client API, which reports the server state"))
+	}
+
+	uuids, err := getServerUUIDs(ctx, cmd, args)
 	if err != nil {
 		return err
 	}
@@ -196,7 +319,17 @@ func runPowerCommand(
 		return errors.New("no servers specified")
 	}
 
-	shouldContinue, err := handlePowerConfirmation(formatter, command, len(uuids), yes)
+	client, err := newServerAPI(cmd)
+	if err != nil {
+		return err
+	}
+
+	uuids = selector.SkipProtected(ctx, cmd, formatter, client, command, uuids)
+	if len(uuids) == 0 {
+		return errors.New("all specified servers are protected")
+	}
+
+	shouldContinue, err := handlePowerConfirmation(cmd, formatter, command, len(uuids))
 	if err != nil {
 		return err
 	}
@@ -209,18 +342,25 @@ func runPowerCommand(
 		return nil
 	}
 
-	client, err := api.NewClientAPI()
-	if err != nil {
-		return err
+	steps := powerSteps{formatter: formatter}
+	steps.preCommands, _ = cmd.Flags().GetStringArray("pre-command")
+	steps.preDelay, _ = cmd.Flags().GetDuration("pre-delay")
+	if wait {
+		stateClient, clientErr := api.NewClientAPI()
+		if clientErr != nil {
+			return clientErr
+		}
+		timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+		steps.wait = newPowerWait(stateClient, command, timeout)
 	}
 
-	ctx := context.Background()
-	results := executePowerOperations(ctx, client, uuids, command, maxConcurrency, continueOnError, failFast)
+	executor := newExecutor(cmd, maxConcurrency, continueOnError, failFast)
+	results := executePowerOperations(ctx, client, executor, uuids, command, steps)
 
 	summary := bulk.GetSummary(results)
 
 	// Handle JSON output specially
-	if getOutputFormat(cmd) == output.OutputFormatJSON {
+	if getOutputFormat(cmd).IsJSON() {
 		return bulk.PrintBulkJSON(formatter, results, summary, continueOnError)
 	}
 
@@ -229,66 +369,18 @@ func runPowerCommand(
 	return handlePowerSummary(formatter, results, continueOnError)
 }
 
-func getClientServerUUIDsFromAll() ([]string, error) {
-	client, err := api.NewClientAPI()
-	if err != nil {
-		return nil, err
-	}
-
-	servers, err := client.ListServers()
-	if err != nil {
-		return nil, err
-	}
-
-	var uuids []string
-	for _, server := range servers {
-		if uuid, ok := server["uuid"].(string); ok {
-			uuids = append(uuids, uuid)
+// getServerUUIDs returns the servers a bulk command acts on, see selector.Servers.
+func getServerUUIDs(ctx context.Context, cmd *cobra.Command, args []string) ([]string, error) {
+	source := func(ctx context.Context) ([]index.Entry, error) {
+		client, err := newServerAPI(cmd)
+		if err != nil {
+			return nil, err
 		}
-	}
-	return uuids, nil
-}
-
-func getClientServerUUIDsFromFile(fromFile string) ([]string, error) {
-	data, err := os.ReadFile(fromFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
-	}
-
-	var uuids []string
-	for line := range strings.SplitSeq(string(data), "\n") {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			uuids = append(uuids, line)
-		}
-	}
-	return uuids, nil
-}
-
-func getClientServerUUIDsFromArgs(args []string) []string {
-	var uuids []string
-	// Support both space-separated and comma-separated arguments
-	// e.g., "123 456" or "123,456" or "123,456 789" (mixed)
-	for _, arg := range args {
-		// Split by comma and trim whitespace
-		parts := strings.SplitSeq(arg, ",")
-		for part := range parts {
-			part = strings.TrimSpace(part)
-			if part != "" {
-				uuids = append(uuids, part)
-			}
+		entries, err := api.ServerEntries(ctx, client)
+		if err != nil {
+			return nil, apierrors.Handle(err)
 		}
+		return entries, nil
 	}
-	return uuids
-}
-
-func getServerUUIDs(_ *cobra.Command, args []string, all bool, fromFile string) ([]string, error) {
-	switch {
-	case all:
-		return getClientServerUUIDsFromAll()
-	case fromFile != "":
-		return getClientServerUUIDsFromFile(fromFile)
-	default:
-		return getClientServerUUIDsFromArgs(args), nil
-	}
+	return selector.Servers(ctx, cmd, args, source, nil)
 }
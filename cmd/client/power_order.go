@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/ordering"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+const (
+	// defaultWaitTimeout is how long a server may take to reach a power state, e.g. running before its
+	// dependents start.
+	defaultWaitTimeout = 5 * time.Minute
+	// serverStatePollInterval is how often the server state is polled while waiting.
+	serverStatePollInterval = 5 * time.Second
+	// serverStateRunning is the power state of a server that is up.
+	serverStateRunning = "running"
+	// serverStateOffline is the power state of a server that is stopped.
+	serverStateOffline = "offline"
+)
+
+// setupGroupStartFlags adapts the flags of power start to starting a configured group in dependency order.
+func setupGroupStartFlags(cmd *cobra.Command) {
+	cmd.Flags().Lookup("group").Usage = "start the servers of a group in dependency order"
+	cmd.Flags().Lookup("wait-timeout").Usage = "with --wait, how long to wait for a server to be running; " +
+		"with --group, how long to wait before starting its dependents"
+}
+
+// groupStartPlan is a group resolved to server UUIDs and ordered into start waves.
+type groupStartPlan struct {
+	waves     [][]string
+	dependsOn map[string][]string
+}
+
+// planGroupStart resolves a configured group and its dependencies into start waves.
+func planGroupStart(ctx context.Context, client *api.ClientAPI, group string) (groupStartPlan, error) {
+	cfg := config.Get()
+	if cfg == nil {
+		return groupStartPlan{}, errors.New("config not loaded")
+	}
+
+	members, ok := cfg.GroupMembers(group)
+	if !ok {
+		return groupStartPlan{}, fmt.Errorf("group %q not configured. Create it with 'pelicanctl group create'", group)
+	}
+	if len(members) == 0 {
+		return groupStartPlan{}, fmt.Errorf("group %q has no servers", group)
+	}
+
+	resolved := make(map[string]string)
+	resolve := func(identifier string) (string, error) {
+		if uuid, found := resolved[identifier]; found {
+			return uuid, nil
+		}
+		uuid, err := client.ResolveServerUUID(ctx, identifier)
+		if err != nil {
+			return "", apierrors.Handle(err)
+		}
+		resolved[identifier] = uuid
+		return uuid, nil
+	}
+
+	uuids := make([]string, 0, len(members))
+	dependsOn := make(map[string][]string, len(members))
+	for _, member := range members {
+		uuid, err := resolve(member)
+		if err != nil {
+			return groupStartPlan{}, err
+		}
+		uuids = append(uuids, uuid)
+
+		for _, dep := range cfg.ServerSettings(member, uuid).DependsOn {
+			depUUID, depErr := resolve(dep)
+			if depErr != nil {
+				return groupStartPlan{}, depErr
+			}
+			dependsOn[uuid] = append(dependsOn[uuid], depUUID)
+		}
+	}
+
+	waves, err := ordering.Waves(uuids, dependsOn)
+	if err != nil {
+		return groupStartPlan{}, err
+	}
+	return groupStartPlan{waves: waves, dependsOn: dependsOn}, nil
+}
+
+// waitForState polls a server until it reports the power state target or the timeout expires.
+func waitForState(ctx context.Context, client *api.ClientAPI, uuid, target string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var state string
+	for {
+		resources, err := client.GetServerResources(ctx, uuid)
+		if err != nil {
+			return apierrors.Handle(err)
+		}
+		state = api.ServerState(resources)
+		if state == target {
+			return nil
+		}
+		if time.Now().Add(serverStatePollInterval).After(deadline) {
+			return fmt.Errorf("did not reach %s state within %s (last state: %s)", target, timeout, state)
+		}
+		if pauseErr := pause(ctx, serverStatePollInterval); pauseErr != nil {
+			return pauseErr
+		}
+	}
+}
+
+func runGroupStart(
+	cmd *cobra.Command,
+	group string,
+	maxConcurrency int,
+	continueOnError bool,
+	failFast bool,
+	dryRun bool,
+) error {
+	ctx := cmd.Context()
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	plan, err := planGroupStart(ctx, client, group)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		formatter.PrintInfo("Dry run - would start group %s in %d wave(s):", group, len(plan.waves))
+		for i, wave := range plan.waves {
+			formatter.PrintInfo("  %d. %s", i+1, strings.Join(wave, ", "))
+		}
+		return nil
+	}
+
+	executor := newExecutor(cmd, maxConcurrency, continueOnError, failFast)
+	failed := make(map[string]bool)
+	var results []bulk.Result
+	stopped := false
+
+	for _, wave := range plan.waves {
+		var operations []bulk.Operation
+		for _, uuid := range wave {
+			op := bulk.Operation{ID: uuid, Name: uuid}
+
+			if stopped {
+				results = append(results, executor.Skip(op, errors.New("skipped due to previous error")))
+				failed[uuid] = true
+				continue
+			}
+			if dep := failedDependency(plan.dependsOn[uuid], failed); dep != "" {
+				results = append(results, executor.Skip(op, fmt.Errorf("skipped: dependency %s is not running", dep)))
+				failed[uuid] = true
+				continue
+			}
+
+			op.Exec = func(opCtx context.Context) error {
+				if startErr := client.SendPowerCommand(opCtx, uuid, "start"); startErr != nil {
+					return apierrors.Handle(startErr)
+				}
+				return waitForState(opCtx, client, uuid, serverStateRunning, timeout)
+			}
+			operations = append(operations, op)
+		}
+
+		for _, result := range executor.Execute(ctx, operations) {
+			if !result.Success {
+				failed[result.Operation.ID] = true
+				if !continueOnError {
+					stopped = true
+				}
+			}
+			results = append(results, result)
+		}
+	}
+
+	summary := bulk.GetSummary(results)
+	if getOutputFormat(cmd).IsJSON() {
+		return bulk.PrintBulkJSON(formatter, results, summary, continueOnError)
+	}
+
+	printPowerResults(formatter, results, "started")
+	return handlePowerSummary(formatter, results, continueOnError)
+}
+
+// failedDependency returns the first dependency that failed to start, or an empty string.
+func failedDependency(deps []string, failed map[string]bool) string {
+	for _, dep := range deps {
+		if failed[dep] {
+			return dep
+		}
+	}
+	return ""
+}
@@ -0,0 +1,352 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/selector"
+)
+
+// Health checks of rolling-restart --health-check.
+const (
+	healthCheckResources = "resources"
+	healthCheckAdmin     = "admin"
+)
+
+func newRollingRestartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rolling-restart <uuid>...",
+		Short: "Restart servers in batches",
+		Long: "Restart servers a batch at a time. With --wait-healthy the next batch only starts once every server " +
+			"of the current one is up again, and --pause waits between batches. The rollout is aborted, and the " +
+			"remaining servers left alone, once the share of failed servers exceeds --max-failure-rate.",
+		RunE: runRollingRestart,
+	}
+	selector.AddTargetFlags(cmd, false)
+	completion.RegisterFlagFunc(cmd, "group", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteGroups(toComplete)
+	})
+	cmd.Flags().Int("batch-size", 1, "servers restarted at the same time")
+	cmd.Flags().Duration("pause", 0, "wait this long between batches")
+	cmd.Flags().Bool("wait-healthy", false, "wait for the servers of a batch to be up again before the next batch")
+	cmd.Flags().String("health-check", healthCheckResources,
+		"with --wait-healthy, how servers are checked: resources (power state) or admin (health endpoint, needs the admin token)")
+	cmd.Flags().Duration("health-timeout", defaultWaitTimeout,
+		"with --wait-healthy, how long a server may take to be up again")
+	cmd.Flags().Float64("max-failure-rate", 0,
+		"abort once more than this share of the restarted servers failed, from 0 to 1; 0 aborts on the first failure")
+	setupPreCommandFlags(cmd)
+	addOperationFlags(cmd)
+	cmd.Flags().Bool("dry-run", false, "show the batches without restarting")
+	selector.AddProtectedFlag(cmd)
+	_ = cmd.RegisterFlagCompletionFunc("health-check", cobra.FixedCompletions(
+		[]string{healthCheckResources, healthCheckAdmin}, cobra.ShellCompDirectiveNoFileComp))
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		"health-check": carapace.ActionValues(healthCheckResources, healthCheckAdmin),
+	})
+	cmd.ValidArgsFunction = func(c *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions, err := completeServers(c, toComplete)
+		if err != nil || len(completions) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	return cmd
+}
+
+// rollingOptions are the flags of rolling-restart.
+type rollingOptions struct {
+	batchSize      int
+	pause          time.Duration
+	waitHealthy    bool
+	healthCheck    string
+	healthTimeout  time.Duration
+	maxFailureRate float64
+	dryRun         bool
+}
+
+func getRollingOptions(cmd *cobra.Command) (rollingOptions, error) {
+	var opts rollingOptions
+	opts.batchSize, _ = cmd.Flags().GetInt("batch-size")
+	opts.pause, _ = cmd.Flags().GetDuration("pause")
+	opts.waitHealthy, _ = cmd.Flags().GetBool("wait-healthy")
+	opts.healthCheck, _ = cmd.Flags().GetString("health-check")
+	opts.healthTimeout, _ = cmd.Flags().GetDuration("health-timeout")
+	opts.maxFailureRate, _ = cmd.Flags().GetFloat64("max-failure-rate")
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+
+	switch {
+	case opts.batchSize < 1:
+		return opts, apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--batch-size must be at least 1"))
+	case opts.pause < 0:
+		return opts, apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--pause cannot be negative"))
+	case opts.maxFailureRate < 0 || opts.maxFailureRate > 1:
+		return opts, apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--max-failure-rate must be between 0 and 1"))
+	case opts.healthCheck != healthCheckResources && opts.healthCheck != healthCheckAdmin:
+		return opts, apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("invalid --health-check %q (must be %s or %s)",
+				opts.healthCheck, healthCheckResources, healthCheckAdmin))
+	}
+	return opts, nil
+}
+
+func runRollingRestart(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	opts, err := getRollingOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	uuids, err := getServerUUIDs(ctx, cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(uuids) == 0 {
+		return errors.New("no servers specified")
+	}
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+	uuids = selector.SkipProtected(ctx, cmd, formatter, client, "restart", uuids)
+	if len(uuids) == 0 {
+		return errors.New("all specified servers are protected")
+	}
+
+	batches := batchServers(uuids, opts.batchSize)
+	if opts.dryRun {
+		formatter.PrintInfo("Dry run - would restart %d server(s) in %d batch(es):", len(uuids), len(batches))
+		for i, batch := range batches {
+			formatter.PrintInfo("  %d. %s", i+1, strings.Join(batch, ", "))
+		}
+		return nil
+	}
+
+	steps := powerSteps{formatter: formatter}
+	steps.preCommands, _ = cmd.Flags().GetStringArray("pre-command")
+	steps.preDelay, _ = cmd.Flags().GetDuration("pre-delay")
+	var check healthCheck
+	if opts.waitHealthy {
+		if check, err = newHealthCheck(client, opts.healthCheck); err != nil {
+			return err
+		}
+	}
+
+	executor := newExecutor(cmd, opts.batchSize, true, false)
+	results := rollingRestart(ctx, formatter, executor, client, steps, check, batches, opts)
+
+	summary := bulk.GetSummary(results)
+	if getOutputFormat(cmd).IsJSON() {
+		return bulk.PrintBulkJSON(formatter, results, summary, false)
+	}
+	printPowerResults(formatter, results, "restarted")
+	return handlePowerSummary(formatter, results, false)
+}
+
+// rollingRestart restarts the batches one after the other, cancelling the remaining ones once the failure
+// rate exceeds the threshold or ctx is done.
+func rollingRestart(
+	ctx context.Context,
+	formatter *output.Formatter,
+	executor *bulk.Executor,
+	client *api.ClientAPI,
+	steps powerSteps,
+	check healthCheck,
+	batches [][]string,
+	opts rollingOptions,
+) []bulk.Result {
+	var results []bulk.Result
+	var aborted error
+	restarted, failed := 0, 0
+
+	for i, batch := range batches {
+		if aborted == nil && i > 0 && opts.pause > 0 {
+			formatter.PrintInfo("Pausing %s before batch %d/%d", opts.pause, i+1, len(batches))
+			_ = pause(ctx, opts.pause)
+		}
+		if aborted == nil && ctx.Err() != nil {
+			aborted = context.Cause(ctx)
+		}
+		if aborted != nil {
+			for _, uuid := range batch {
+				results = append(results, executor.Cancel(bulk.Operation{ID: uuid, Name: uuid}, aborted))
+			}
+			continue
+		}
+
+		operations := make([]bulk.Operation, len(batch))
+		for j, uuid := range batch {
+			operations[j] = bulk.Operation{
+				ID:   uuid,
+				Name: uuid,
+				Exec: func(opCtx context.Context) error {
+					if err := steps.runPreCommands(opCtx, client, uuid); err != nil {
+						return err
+					}
+					restartedAt := time.Now()
+					if err := client.SendPowerCommand(opCtx, uuid, "restart"); err != nil {
+						return apierrors.Handle(err)
+					}
+					if check == nil {
+						return nil
+					}
+					return waitForHealthy(opCtx, check, uuid, restartedAt, opts.healthTimeout)
+				},
+			}
+		}
+
+		batchFailed := 0
+		for _, result := range executor.Execute(ctx, operations) {
+			restarted++
+			if !result.Success {
+				batchFailed++
+			}
+			results = append(results, result)
+		}
+		failed += batchFailed
+		formatter.PrintInfo("Batch %d/%d: %d restarted, %d failed", i+1, len(batches), len(batch)-batchFailed, batchFailed)
+
+		if failed > 0 && float64(failed)/float64(restarted) > opts.maxFailureRate {
+			aborted = fmt.Errorf("%d of %d restarted server(s) failed, more than --max-failure-rate %g allows",
+				failed, restarted, opts.maxFailureRate)
+			if i < len(batches)-1 {
+				formatter.PrintError("Aborting the rolling restart: %v", aborted)
+			}
+		}
+	}
+	return results
+}
+
+// batchServers splits servers into batches of at most size servers, keeping their order.
+func batchServers(uuids []string, size int) [][]string {
+	var batches [][]string
+	for start := 0; start < len(uuids); start += size {
+		batches = append(batches, uuids[start:min(start+size, len(uuids))])
+	}
+	return batches
+}
+
+// healthCheck reports whether a server restarted at restartedAt is up again, and the state it is in.
+type healthCheck func(ctx context.Context, uuid string, restartedAt time.Time) (bool, string, error)
+
+// newHealthCheck returns the health check named by --health-check.
+func newHealthCheck(client *api.ClientAPI, name string) (healthCheck, error) {
+	if name == healthCheckResources {
+		return resourcesHealthCheck(client), nil
+	}
+	admin, err := api.NewApplicationAPI()
+	if err != nil {
+		return nil, err
+	}
+	return adminHealthCheck(admin), nil
+}
+
+// resourcesHealthCheck considers a server up once it is running again after the restart.
+func resourcesHealthCheck(client *api.ClientAPI) healthCheck {
+	return func(ctx context.Context, uuid string, restartedAt time.Time) (bool, string, error) {
+		resources, err := client.GetServerResources(ctx, uuid)
+		if err != nil {
+			return false, "", apierrors.Handle(err)
+		}
+		state := api.ServerState(resources)
+		if state != serverStateRunning {
+			return false, state, nil
+		}
+		// A server still reports running for a moment after the restart is sent; an uptime longer than
+		// the time since tells that it has not gone down yet.
+		if uptime, ok := serverUptime(resources); ok && uptime > time.Since(restartedAt) {
+			return false, "running (not restarted yet)", nil
+		}
+		return true, state, nil
+	}
+}
+
+// serverUptime returns the uptime in the resources of a server, if they report one.
+func serverUptime(resources map[string]any) (time.Duration, bool) {
+	attrs := resources
+	if nested, ok := resources["attributes"].(map[string]any); ok {
+		attrs = nested
+	}
+	usage, _ := attrs["resources"].(map[string]any)
+	millis, ok := usage["uptime"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(millis) * time.Millisecond, true
+}
+
+// adminHealthCheck considers a server up once the health endpoint of the Application API reports its
+// container healthy, or running if it has no health check, without a crash since the restart.
+func adminHealthCheck(client *api.ApplicationAPI) healthCheck {
+	return func(ctx context.Context, uuid string, restartedAt time.Time) (bool, string, error) {
+		health, err := client.GetServerHealth(ctx, uuid, &restartedAt, nil)
+		if err != nil {
+			return false, "", apierrors.Handle(err)
+		}
+		if crashed, _ := health["crashed"].(bool); crashed {
+			return false, "crashed", nil
+		}
+		container, _ := health["container"].(map[string]any)
+		status, _ := container["status"].(string)
+		if healthy, ok := container["healthy"].(bool); ok {
+			if !healthy {
+				return false, status + " (unhealthy)", nil
+			}
+			return true, status, nil
+		}
+		return status == serverStateRunning, status, nil
+	}
+}
+
+// waitForHealthy polls a server with check until it is up again or the timeout expires.
+func waitForHealthy(
+	ctx context.Context,
+	check healthCheck,
+	uuid string,
+	restartedAt time.Time,
+	timeout time.Duration,
+) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		healthy, state, err := check(ctx, uuid, restartedAt)
+		if err != nil {
+			return err
+		}
+		if healthy {
+			return nil
+		}
+		if time.Now().Add(serverStatePollInterval).After(deadline) {
+			return fmt.Errorf("not up again within %s (last state: %s)", timeout, state)
+		}
+		if pauseErr := pause(ctx, serverStatePollInterval); pauseErr != nil {
+			return pauseErr
+		}
+	}
+}
+
+// pause waits for d, or returns the error of ctx if it is done first.
+func pause(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
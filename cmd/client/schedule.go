@@ -0,0 +1,582 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// cronFieldCount is the number of fields in a cron expression (minute hour day-of-month month day-of-week).
+const cronFieldCount = 5
+
+// completeScheduleArg completes a server, then one of its schedules, then one of the schedule's tasks.
+func completeScheduleArg(args []string, toComplete string) ([]string, error) {
+	switch len(args) {
+	case 0:
+		return completion.CompleteServers("client", toComplete)
+	case 1:
+		return completion.CompleteSchedules(args[0], toComplete)
+	case 2: //nolint:mnd // task position after server and schedule
+		return completion.CompleteScheduleTasks(args[0], args[1], toComplete)
+	default:
+		return nil, nil
+	}
+}
+
+func scheduleValidArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions, err := completeScheduleArg(args, toComplete)
+	if err != nil || len(completions) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func scheduleCompletionAction(c carapace.Context) carapace.Action {
+	completions, err := completeScheduleArg(c.Args, c.Value)
+	if err != nil || len(completions) == 0 {
+		return carapace.ActionValues()
+	}
+	return completion.Action(completions)
+}
+
+func setupScheduleFlags(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "schedule name")
+	cmd.Flags().String("cron", "", "cron expression: minute hour day-of-month month day-of-week (e.g. \"0 4 * * *\")")
+	cmd.Flags().Bool("active", true, "whether the schedule is active")
+	cmd.Flags().Bool("only-when-online", false, "only run the schedule while the server is online")
+}
+
+func setupTaskFlags(cmd *cobra.Command) {
+	cmd.Flags().String("action", "", "task action (command, power, backup, delete_files)")
+	cmd.Flags().String("payload", "", "task payload (console command, power signal, or ignored files)")
+	cmd.Flags().Int("time-offset", 0, "seconds to wait after the previous task before running")
+	cmd.Flags().Bool("continue-on-failure", false, "continue with the next task if this one fails")
+}
+
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Manage server schedules",
+		Long:  "List, view, create, update, delete, and run server schedules and their tasks",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list <id|uuid>",
+		Short: "List schedules for a server",
+		Long:  "List schedules for a server by ID (integer) or UUID (string)",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runScheduleList,
+	}
+	listCmd.ValidArgsFunction = scheduleValidArgs
+
+	viewCmd := &cobra.Command{
+		Use:   "view <id|uuid> <schedule-id>",
+		Short: "View a schedule and its tasks",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and schedule
+		RunE:  runScheduleView,
+	}
+	viewCmd.ValidArgsFunction = scheduleValidArgs
+
+	createCmd := &cobra.Command{
+		Use:   "create <id|uuid> --name <name> --cron <expression>",
+		Short: "Create a schedule",
+		Long:  "Create a schedule for a server by ID (integer) or UUID (string)",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runScheduleCreate,
+	}
+	setupScheduleFlags(createCmd)
+	createCmd.ValidArgsFunction = scheduleValidArgs
+
+	updateCmd := &cobra.Command{
+		Use:   "update <id|uuid> <schedule-id>",
+		Short: "Update a schedule",
+		Long:  "Update a schedule. Only the flags that are given are changed.",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and schedule
+		RunE:  runScheduleUpdate,
+	}
+	setupScheduleFlags(updateCmd)
+	updateCmd.ValidArgsFunction = scheduleValidArgs
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <id|uuid> <schedule-id>",
+		Short: "Delete a schedule",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and schedule
+		RunE:  runScheduleDelete,
+	}
+	deleteCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	deleteCmd.ValidArgsFunction = scheduleValidArgs
+
+	runNowCmd := &cobra.Command{
+		Use:   "run-now <id|uuid> <schedule-id>",
+		Short: "Run a schedule immediately",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and schedule
+		RunE:  runScheduleRunNow,
+	}
+	runNowCmd.ValidArgsFunction = scheduleValidArgs
+
+	taskCmd := newScheduleTaskCmd()
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(viewCmd)
+	cmd.AddCommand(createCmd)
+	cmd.AddCommand(updateCmd)
+	cmd.AddCommand(deleteCmd)
+	cmd.AddCommand(runNowCmd)
+	cmd.AddCommand(taskCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	for _, c := range []*cobra.Command{listCmd, viewCmd, createCmd, updateCmd, deleteCmd, runNowCmd} {
+		carapace.Gen(c).PositionalAnyCompletion(carapace.ActionCallback(scheduleCompletionAction))
+	}
+	for _, c := range taskCmd.Commands() {
+		carapace.Gen(c).PositionalAnyCompletion(carapace.ActionCallback(scheduleCompletionAction))
+		completion.RegisterFlagValues(c, "action", completion.ScheduleActions...)
+		registerPayloadCompletion(c)
+	}
+
+	return cmd
+}
+
+// registerPayloadCompletion completes --payload with the power signals when --action is power; other
+// payloads are free-form.
+func registerPayloadCompletion(cmd *cobra.Command) {
+	_ = cmd.RegisterFlagCompletionFunc("payload", func(
+		c *cobra.Command, _ []string, _ string,
+	) ([]string, cobra.ShellCompDirective) {
+		if action, _ := c.Flags().GetString("action"); action != "power" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.PowerSignals, cobra.ShellCompDirectiveNoFileComp
+	})
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		"payload": carapace.ActionCallback(func(_ carapace.Context) carapace.Action {
+			if action, _ := cmd.Flags().GetString("action"); action != "power" {
+				return carapace.ActionValues()
+			}
+			return carapace.ActionValues(completion.PowerSignals...)
+		}),
+	})
+}
+
+func newScheduleTaskCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task",
+		Short: "Manage schedule tasks",
+		Long:  "Add, update, and remove tasks of a schedule",
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <id|uuid> <schedule-id> --action <action> [--payload <payload>]",
+		Short: "Add a task to a schedule",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and schedule
+		RunE:  runScheduleTaskAdd,
+	}
+	setupTaskFlags(addCmd)
+	addCmd.ValidArgsFunction = scheduleValidArgs
+
+	updateCmd := &cobra.Command{
+		Use:   "update <id|uuid> <schedule-id> <task-id>",
+		Short: "Update a schedule task",
+		Long:  "Update a schedule task. Only the flags that are given are changed.",
+		Args:  cobra.ExactArgs(3), //nolint:mnd // server, schedule, and task
+		RunE:  runScheduleTaskUpdate,
+	}
+	setupTaskFlags(updateCmd)
+	updateCmd.ValidArgsFunction = scheduleValidArgs
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <id|uuid> <schedule-id> <task-id>",
+		Short: "Remove a task from a schedule",
+		Args:  cobra.ExactArgs(3), //nolint:mnd // server, schedule, and task
+		RunE:  runScheduleTaskRemove,
+	}
+	removeCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	removeCmd.ValidArgsFunction = scheduleValidArgs
+
+	cmd.AddCommand(addCmd)
+	cmd.AddCommand(updateCmd)
+	cmd.AddCommand(removeCmd)
+
+	return cmd
+}
+
+// parseResourceID parses an integer resource ID argument such as a schedule or task ID.
+func parseResourceID(kind, value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s ID: %s", kind, value)
+	}
+	return id, nil
+}
+
+// resourceAttributes returns the attributes of a panel resource, or the resource itself if it is flat.
+func resourceAttributes(resource map[string]any) map[string]any {
+	if attrs, ok := resource["attributes"].(map[string]any); ok {
+		return attrs
+	}
+	return resource
+}
+
+// applyCronExpression splits a five-field cron expression into the schedule request fields.
+func applyCronExpression(data map[string]any, expression string) error {
+	fields := strings.Fields(expression)
+	if len(fields) != cronFieldCount {
+		return fmt.Errorf("invalid cron expression %q: expected %d fields", expression, cronFieldCount)
+	}
+	data["minute"] = fields[0]
+	data["hour"] = fields[1]
+	data["day_of_month"] = fields[2]
+	data["month"] = fields[3]
+	data["day_of_week"] = fields[4]
+	return nil
+}
+
+// applyScheduleFlags copies the schedule flags that were set (or all of them if all is true) into data.
+func applyScheduleFlags(cmd *cobra.Command, data map[string]any, all bool) error {
+	if all || cmd.Flags().Changed("name") {
+		data["name"], _ = cmd.Flags().GetString("name")
+	}
+	if all || cmd.Flags().Changed("cron") {
+		cron, _ := cmd.Flags().GetString("cron")
+		if err := applyCronExpression(data, cron); err != nil {
+			return err
+		}
+	}
+	if all || cmd.Flags().Changed("active") {
+		data["is_active"], _ = cmd.Flags().GetBool("active")
+	}
+	if all || cmd.Flags().Changed("only-when-online") {
+		data["only_when_online"], _ = cmd.Flags().GetBool("only-when-online")
+	}
+	return nil
+}
+
+// applyTaskFlags copies the task flags that were set (or all of them if all is true) into data.
+func applyTaskFlags(cmd *cobra.Command, data map[string]any, all bool) error {
+	if all || cmd.Flags().Changed("action") {
+		action, _ := cmd.Flags().GetString("action")
+		if !slices.Contains(completion.ScheduleActions, action) {
+			return fmt.Errorf("invalid task action: %s (must be one of %s)",
+				action, strings.Join(completion.ScheduleActions, ", "))
+		}
+		data["action"] = action
+	}
+	if all || cmd.Flags().Changed("payload") {
+		data["payload"], _ = cmd.Flags().GetString("payload")
+	}
+	if all || cmd.Flags().Changed("time-offset") {
+		data["time_offset"], _ = cmd.Flags().GetInt("time-offset")
+	}
+	if all || cmd.Flags().Changed("continue-on-failure") {
+		data["continue_on_failure"], _ = cmd.Flags().GetBool("continue-on-failure")
+	}
+	return nil
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	schedules, err := client.ListSchedules(ctx, args[0])
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	return formatter.PrintWithConfig(schedules, output.ResourceTypeClientSchedule)
+}
+
+func runScheduleView(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID, err := parseResourceID("schedule", args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	schedule, err := client.GetSchedule(ctx, args[0], scheduleID)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	outputFormat := getOutputFormat(cmd)
+	formatter := output.NewFormatter(outputFormat, os.Stdout)
+	if outputFormat.IsJSON() {
+		return formatter.Print(schedule)
+	}
+
+	if printErr := formatter.PrintWithConfig(
+		[]map[string]any{schedule}, output.ResourceTypeClientSchedule,
+	); printErr != nil {
+		return printErr
+	}
+
+	tasks := api.ScheduleTasks(schedule)
+	if len(tasks) == 0 {
+		formatter.PrintInfo("No tasks")
+		return nil
+	}
+	return formatter.PrintWithConfig(tasks, output.ResourceTypeClientTask)
+}
+
+func runScheduleCreate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if !cmd.Flags().Changed("name") || !cmd.Flags().Changed("cron") {
+		return errors.New("--name and --cron are required")
+	}
+
+	data := map[string]any{}
+	if err := applyScheduleFlags(cmd, data, true); err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	schedule, err := client.CreateSchedule(ctx, args[0], data)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Schedule created successfully")
+	return formatter.Print(schedule)
+}
+
+func runScheduleUpdate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID, err := parseResourceID("schedule", args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	// The panel requires the full schedule, so start from the current values.
+	current, err := client.GetSchedule(ctx, args[0], scheduleID)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	attrs := resourceAttributes(current)
+	data := map[string]any{
+		"name":             attrs["name"],
+		"is_active":        attrs["is_active"],
+		"only_when_online": attrs["only_when_online"],
+	}
+	if cron, ok := attrs["cron"].(map[string]any); ok {
+		for _, key := range []string{"minute", "hour", "day_of_month", "month", "day_of_week"} {
+			data[key] = cron[key]
+		}
+	}
+
+	if applyErr := applyScheduleFlags(cmd, data, false); applyErr != nil {
+		return applyErr
+	}
+
+	schedule, err := client.UpdateSchedule(ctx, args[0], scheduleID, data)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Schedule updated successfully")
+	return formatter.Print(schedule)
+}
+
+func runScheduleDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID, err := parseResourceID("schedule", args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	message := fmt.Sprintf("This will delete schedule %d and its tasks.", scheduleID)
+	shouldContinue, err := output.Confirm(cmd, formatter, message)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	if err := client.DeleteSchedule(ctx, args[0], scheduleID); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("Schedule %d deleted", scheduleID)
+	formatter.PrintAffected(strconv.Itoa(scheduleID))
+	return nil
+}
+
+func runScheduleRunNow(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID, err := parseResourceID("schedule", args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	if err := client.ExecuteSchedule(ctx, args[0], scheduleID); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Schedule %d triggered", scheduleID)
+	formatter.PrintAffected(strconv.Itoa(scheduleID))
+	return nil
+}
+
+func runScheduleTaskAdd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID, err := parseResourceID("schedule", args[1])
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("action") {
+		return errors.New("--action is required")
+	}
+
+	data := map[string]any{}
+	if applyErr := applyTaskFlags(cmd, data, true); applyErr != nil {
+		return applyErr
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	task, err := client.CreateScheduleTask(ctx, args[0], scheduleID, data)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Task added successfully")
+	return formatter.Print(task)
+}
+
+func runScheduleTaskUpdate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID, err := parseResourceID("schedule", args[1])
+	if err != nil {
+		return err
+	}
+	taskID, err := parseResourceID("task", args[2])
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	// The panel requires the full task, so start from the current values.
+	schedule, err := client.GetSchedule(ctx, args[0], scheduleID)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	var data map[string]any
+	for _, task := range api.ScheduleTasks(schedule) {
+		attrs := resourceAttributes(task)
+		if fmt.Sprintf("%v", attrs["id"]) != args[2] {
+			continue
+		}
+		data = map[string]any{
+			"action":              attrs["action"],
+			"payload":             attrs["payload"],
+			"time_offset":         attrs["time_offset"],
+			"continue_on_failure": attrs["continue_on_failure"],
+			"sequence_id":         attrs["sequence_id"],
+		}
+		break
+	}
+	if data == nil {
+		return fmt.Errorf("task %d not found in schedule %d", taskID, scheduleID)
+	}
+
+	if applyErr := applyTaskFlags(cmd, data, false); applyErr != nil {
+		return applyErr
+	}
+
+	task, err := client.UpdateScheduleTask(ctx, args[0], scheduleID, taskID, data)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Task updated successfully")
+	return formatter.Print(task)
+}
+
+func runScheduleTaskRemove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID, err := parseResourceID("schedule", args[1])
+	if err != nil {
+		return err
+	}
+	taskID, err := parseResourceID("task", args[2])
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	message := fmt.Sprintf("This will remove task %d from schedule %d.", taskID, scheduleID)
+	shouldContinue, err := output.Confirm(cmd, formatter, message)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	if err := client.DeleteScheduleTask(ctx, args[0], scheduleID, taskID); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("Task %d removed", taskID)
+	formatter.PrintAffected(strconv.Itoa(taskID))
+	return nil
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/carapace-sh/carapace"
 	"github.com/spf13/cobra"
@@ -14,12 +18,11 @@ import (
 	"go.lostcrafters.com/pelicanctl/internal/completion"
 	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
 	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/watch"
 )
 
-const (
-	statusSuccess = "success"
-	statusError   = "error"
-)
+// defaultCaptureTimeout is how long command --capture reads console output after the command.
+const defaultCaptureTimeout = 5 * time.Second
 
 func newServerCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -33,6 +36,8 @@ func newServerCmd() *cobra.Command {
 		Short: "List all servers",
 		RunE:  runServerList,
 	}
+	addListFlags(listCmd)
+	watch.AddFlags(listCmd)
 
 	viewCmd := &cobra.Command{
 		Use:   "view <id|uuid>",
@@ -52,10 +57,14 @@ func newServerCmd() *cobra.Command {
 	resourcesCmd := &cobra.Command{
 		Use:   "resources <id|uuid>",
 		Short: "View server resource usage",
-		Long:  "View server resource usage by ID (integer) or UUID (string)",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runServerResources,
+		Long: "View server resource usage by ID (integer) or UUID (string). " +
+			"Use --watch to re-poll it, or --follow to stream live stats from the server's websocket.",
+		Args: cobra.ExactArgs(1),
+		RunE: runServerResources,
 	}
+	watch.AddFlags(resourcesCmd)
+	resourcesCmd.Flags().Bool("follow", false,
+		"stream live stats over the server websocket, with sparklines of the recent history")
 	resourcesCmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		completions, err := completion.CompleteServers("client", toComplete)
 		if err != nil || len(completions) == 0 {
@@ -73,9 +82,13 @@ func newServerCmd() *cobra.Command {
 	}
 	commandCmd.Flags().String("command", "", "The command to send to the server console (required)")
 	_ = commandCmd.MarkFlagRequired("command")
+	commandCmd.Flags().Bool("capture", false,
+		"print the console output of the command, read over the server websocket (needs the client API)")
+	commandCmd.Flags().Duration("capture-timeout", defaultCaptureTimeout, "with --capture, how long to read console output")
 	setupBulkFlags(commandCmd)
-	commandCmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		completions, err := completion.CompleteServers("client", toComplete)
+	addAPIFlag(commandCmd)
+	commandCmd.ValidArgsFunction = func(c *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions, err := completeServers(c, toComplete)
 		if err != nil || len(completions) == 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
@@ -95,7 +108,7 @@ func newServerCmd() *cobra.Command {
 			if err != nil || len(completions) == 0 {
 				return carapace.ActionValues()
 			}
-			return carapace.ActionValues(completions...)
+			return completion.Action(completions)
 		}),
 	)
 	carapace.Gen(resourcesCmd).PositionalCompletion(
@@ -104,38 +117,56 @@ func newServerCmd() *cobra.Command {
 			if err != nil || len(completions) == 0 {
 				return carapace.ActionValues()
 			}
-			return carapace.ActionValues(completions...)
+			return completion.Action(completions)
 		}),
 	)
 	carapace.Gen(commandCmd).PositionalAnyCompletion(
 		carapace.ActionCallback(func(c carapace.Context) carapace.Action {
-			completions, err := completion.CompleteServers("client", c.Value)
+			completions, err := completeServers(commandCmd, c.Value)
 			if err != nil || len(completions) == 0 {
 				return carapace.ActionValues()
 			}
-			return carapace.ActionValues(completions...)
+			return completion.Action(completions)
 		}),
 	)
 
 	return cmd
 }
 
-func runServerList(cmd *cobra.Command, _ []string) error {
-	client, err := api.NewClientAPI()
+func runServerList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	opts, err := getListOptions(cmd)
 	if err != nil {
 		return err
 	}
 
-	servers, err := client.ListServers()
+	interval, err := watch.Interval(cmd)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return err
 	}
 
-	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
-	return formatter.PrintWithConfig(servers, output.ResourceTypeClientServer)
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	render := func(out io.Writer) error {
+		servers, listErr := client.ListServersWithOptions(ctx, opts)
+		if listErr != nil {
+			return apierrors.Handle(listErr)
+		}
+
+		formatter := output.NewFormatter(getOutputFormat(cmd), out)
+		return formatter.PrintWithConfig(servers, output.ResourceTypeClientServer)
+	}
+	if interval > 0 {
+		return watch.Run(cmd.Context(), os.Stdout, watch.Title(cmd, args), interval, render)
+	}
+	return render(os.Stdout)
 }
 
 func runServerView(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	uuid := args[0]
 
 	client, err := api.NewClientAPI()
@@ -143,9 +174,9 @@ func runServerView(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	server, err := client.GetServer(uuid)
+	server, err := client.GetServer(ctx, uuid)
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return apierrors.Handle(err)
 	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
@@ -153,38 +184,61 @@ func runServerView(cmd *cobra.Command, args []string) error {
 }
 
 func runServerResources(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	uuid := args[0]
 
-	client, err := api.NewClientAPI()
+	if follow, _ := cmd.Flags().GetBool("follow"); follow {
+		if watching, _ := cmd.Flags().GetBool("watch"); watching {
+			return errors.New("--follow cannot be combined with --watch")
+		}
+		return runServerResourcesFollow(cmd, args)
+	}
+
+	interval, err := watch.Interval(cmd)
 	if err != nil {
 		return err
 	}
 
-	resources, err := client.GetServerResources(uuid)
+	client, err := api.NewClientAPI()
 	if err != nil {
-		return fmt.Errorf("%s", apierrors.HandleError(err))
+		return err
 	}
 
-	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
-	return formatter.PrintWithConfig(resources, output.ResourceTypeServerResource)
+	render := func(out io.Writer) error {
+		resources, resourcesErr := client.GetServerResources(ctx, uuid)
+		if resourcesErr != nil {
+			return apierrors.Handle(resourcesErr)
+		}
+
+		formatter := output.NewFormatter(getOutputFormat(cmd), out)
+		return formatter.PrintWithConfig(resources, output.ResourceTypeServerResource)
+	}
+	if interval > 0 {
+		return watch.Run(cmd.Context(), os.Stdout, watch.Title(cmd, args), interval, render)
+	}
+	return render(os.Stdout)
 }
 
 func runServerCommand(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 	command, _ := cmd.Flags().GetString("command")
 	if command == "" {
 		return errors.New("--command flag is required")
 	}
 
-	all, _ := cmd.Flags().GetBool("all")
-	fromFile, _ := cmd.Flags().GetString("from-file")
 	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
 	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
 	failFast, _ := cmd.Flags().GetBool("fail-fast")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	capture, _ := cmd.Flags().GetBool("capture")
+	if capture && apiType(cmd) != api.TypeClient {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("--capture needs the client API, whose websocket streams the console"))
+	}
 
 	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
 
-	uuids, err := getServerUUIDs(cmd, args, all, fromFile)
+	uuids, err := getServerUUIDs(ctx, cmd, args)
 	if err != nil {
 		return err
 	}
@@ -201,80 +255,141 @@ func runServerCommand(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	client, err := api.NewClientAPI()
+	client, err := newServerAPI(cmd)
 	if err != nil {
 		return err
 	}
 
-	ctx := context.Background()
-	results := executeCommandOperations(ctx, client, uuids, command, maxConcurrency, continueOnError, failFast)
+	var captured *commandCapture
+	if capture {
+		consoleClient, clientErr := api.NewClientAPI()
+		if clientErr != nil {
+			return clientErr
+		}
+		timeout, _ := cmd.Flags().GetDuration("capture-timeout")
+		captured = &commandCapture{client: consoleClient, timeout: timeout, lines: make(map[string][]string)}
+	}
+
+	executor := newExecutor(cmd, maxConcurrency, continueOnError, failFast)
+	results := executeCommandOperations(ctx, client, executor, uuids, command, captured)
 
 	// Handle JSON output specially
-	if getOutputFormat(cmd) == output.OutputFormatJSON {
+	if getOutputFormat(cmd).IsJSON() {
 		summary := bulk.GetSummary(results)
-		return printCommandResultsJSON(formatter, results, command, summary, continueOnError)
+		return printCommandResultsJSON(formatter, results, command, captured, summary, continueOnError)
 	}
 
 	printCommandResults(formatter, results, command)
+	printCapturedOutput(results, captured)
 
 	return handleCommandSummary(formatter, results, continueOnError)
 }
 
 func executeCommandOperations(
 	ctx context.Context,
-	client *api.ClientAPI,
+	client api.ServerAPI,
+	executor *bulk.Executor,
 	uuids []string,
 	command string,
-	maxConcurrency int,
-	continueOnError bool,
-	failFast bool,
+	captured *commandCapture,
 ) []bulk.Result {
+	resolution := client.ResolveServers(ctx, uuids)
 	operations := make([]bulk.Operation, len(uuids))
 	for i, uuid := range uuids {
 		operations[i] = bulk.Operation{
 			ID:   uuid,
 			Name: uuid,
-			Exec: func() error {
-				return client.SendCommand(uuid, command)
+			Exec: func(opCtx context.Context) error {
+				serverUUID, err := resolution.Lookup(uuid)
+				if err != nil {
+					return err
+				}
+				if captured == nil {
+					return client.SendCommand(opCtx, serverUUID, command)
+				}
+				return captured.run(opCtx, uuid, serverUUID, command)
 			},
 		}
 	}
 
-	executor := bulk.NewExecutor(maxConcurrency, continueOnError, failFast)
-	return executor.Execute(ctx, operations)
+	return executor.WithRecord(commandRecord(command, captured)).Execute(ctx, operations)
+}
+
+// commandCapture reads the console output of commands sent with --capture.
+type commandCapture struct {
+	client  *api.ClientAPI
+	timeout time.Duration
+
+	mu sync.Mutex
+	// lines are the console lines captured per server, keyed by operation ID.
+	lines map[string][]string
+}
+
+// run sends the command to a server and keeps the console lines it printed.
+func (c *commandCapture) run(ctx context.Context, id, serverUUID, command string) error {
+	lines, err := c.client.CaptureCommand(ctx, serverUUID, command, c.timeout)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines[id] = lines
+	return err
+}
+
+// output returns the console lines captured for an operation.
+func (c *commandCapture) output(id string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lines, ok := c.lines[id]
+	return lines, ok
+}
+
+// commandRecord returns the JSON record of a result of sending a console command, with the captured
+// console output if the command was sent with --capture.
+func commandRecord(command string, captured *commandCapture) bulk.RecordFunc {
+	return func(result bulk.Result) map[string]any {
+		record := bulk.ServerRecord(result)
+		record["command"] = command
+		if lines, ok := captured.output(result.Operation.ID); ok {
+			if lines == nil {
+				lines = []string{}
+			}
+			record["output"] = lines
+		}
+		return record
+	}
+}
+
+// printCapturedOutput prints the console output captured with --capture, prefixed with the server
+// when there are several.
+func printCapturedOutput(results []bulk.Result, captured *commandCapture) {
+	for _, result := range results {
+		lines, _ := captured.output(result.Operation.ID)
+		for _, line := range lines {
+			if len(results) > 1 {
+				line = output.ServerLabel(result.Operation.ID) + " | " + line
+			}
+			_, _ = fmt.Fprintln(os.Stdout, line)
+		}
+	}
 }
 
 func printCommandResultsJSON(
 	formatter *output.Formatter,
 	results []bulk.Result,
 	command string,
+	captured *commandCapture,
 	summary bulk.Summary,
 	continueOnError bool,
 ) error {
-	outputData := make([]map[string]any, 0, len(results))
-
+	record := commandRecord(command, captured)
+	records := make([]map[string]any, 0, len(results))
 	for _, result := range results {
-		resultData := map[string]any{
-			"server_identifier": result.Operation.ID,
-			"command":           command,
-		}
-		if result.Success {
-			resultData["status"] = statusSuccess
-		} else {
-			resultData["status"] = statusError
-			resultData["error"] = result.Error.Error()
-		}
-		outputData = append(outputData, resultData)
+		records = append(records, record(result))
 	}
 
-	// Include summary in the output
-	response := map[string]any{
-		"results": outputData,
-		"summary": map[string]any{
-			"succeeded": summary.Success,
-			"failed":    summary.Failed,
-		},
-	}
+	response := bulk.Response(records, summary)
 
 	if err := formatter.Print(response); err != nil {
 		return err
@@ -282,7 +397,7 @@ func printCommandResultsJSON(
 
 	// Check failures based on continue-on-error flag
 	if summary.Failed > 0 && !continueOnError {
-		return fmt.Errorf("%d operation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
@@ -291,19 +406,19 @@ func printCommandResultsJSON(
 func printCommandResults(formatter *output.Formatter, results []bulk.Result, command string) {
 	for _, result := range results {
 		if result.Success {
-			formatter.PrintSuccess("%s: command '%s' sent", result.Operation.ID, command)
+			formatter.PrintSuccess("%s: command '%s' sent", output.ServerLabel(result.Operation.ID), command)
 		} else {
-			formatter.PrintError("%s: %v", result.Operation.ID, result.Error)
+			formatter.PrintError("%s: %v", output.ServerLabel(result.Operation.ID), result.Error)
 		}
 	}
 }
 
 func handleCommandSummary(formatter *output.Formatter, results []bulk.Result, continueOnError bool) error {
 	summary := bulk.GetSummary(results)
-	formatter.PrintInfo("Summary: %d succeeded, %d failed", summary.Success, summary.Failed)
+	formatter.PrintInfo("Summary: %s", summary)
 
 	if summary.Failed > 0 && !continueOnError {
-		return fmt.Errorf("%d operation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
@@ -314,5 +429,52 @@ func getOutputFormat(cmd *cobra.Command) output.OutputFormat {
 	if jsonFlag {
 		return output.OutputFormatJSON
 	}
+	// --output was validated by the root command, which also set up a go-template.
+	outputFlag, _ := cmd.Root().PersistentFlags().GetString("output")
+	if format, _, err := output.ParseOutputFlag(outputFlag); err == nil {
+		return format
+	}
 	return output.OutputFormatTable
 }
+
+// addListFlags adds the pagination, filter, and include flags of a list command.
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("page", 0, "fetch only this page (starting at 1) instead of every page")
+	cmd.Flags().Int("per-page", 0, "number of items per page requested from the panel (default: panel default)")
+	cmd.Flags().Bool("all-pages", true, "follow every page of the list (--all-pages=false fetches only the first page)")
+	cmd.Flags().StringArray("filter", nil, "filter the list on the panel by attribute=value (repeatable)")
+	cmd.Flags().StringSlice("include", nil, "relationships the panel includes with every item (comma-separated)")
+	cmd.Flags().Int("max-concurrency", api.DefaultPageConcurrency, "maximum pages of the list fetched in parallel")
+}
+
+// getListOptions reads the list flags added by addListFlags.
+func getListOptions(cmd *cobra.Command) (api.ListOptions, error) {
+	page, _ := cmd.Flags().GetInt("page")
+	perPage, _ := cmd.Flags().GetInt("per-page")
+	allPages, _ := cmd.Flags().GetBool("all-pages")
+
+	if page < 0 || perPage < 0 {
+		return api.ListOptions{}, errors.New("--page and --per-page must not be negative")
+	}
+	if page > 0 && allPages && cmd.Flags().Changed("all-pages") {
+		return api.ListOptions{}, errors.New("--page cannot be combined with --all-pages")
+	}
+	if page == 0 && !allPages {
+		page = 1
+	}
+
+	filters, _ := cmd.Flags().GetStringArray("filter")
+	include, _ := cmd.Flags().GetStringSlice("include")
+	opts := api.ListOptions{Page: page, PerPage: perPage, Include: include}
+	for _, filter := range filters {
+		attribute, value, ok := strings.Cut(filter, "=")
+		if !ok || attribute == "" {
+			return api.ListOptions{}, fmt.Errorf("invalid filter %q (expected attribute=value)", filter)
+		}
+		if opts.Filter == nil {
+			opts.Filter = make(map[string]string)
+		}
+		opts.Filter[attribute] = value
+	}
+	return opts, nil
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/watch"
+)
+
+const (
+	// statsHistorySize is how many samples the sparklines of --follow show.
+	statsHistorySize = 60
+	// bytesPerKiB is the factor between binary byte units.
+	bytesPerKiB = 1024
+	// fullCPU is the CPU usage of one fully used core, in percent.
+	fullCPU = 100
+)
+
+// sparkBlocks are the bars of a sparkline, from lowest to highest.
+//
+//nolint:gochecknoglobals // Fixed rune set used to draw sparklines
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// statsSample is a stats sample and when it arrived.
+type statsSample struct {
+	stats api.ServerStats
+	at    time.Time
+}
+
+// statsView keeps the recent samples of a followed server and renders them.
+type statsView struct {
+	samples []statsSample
+}
+
+// add records a sample, dropping the oldest once the history is full.
+func (v *statsView) add(stats api.ServerStats, at time.Time) {
+	v.samples = append(v.samples, statsSample{stats: stats, at: at})
+	if len(v.samples) > statsHistorySize {
+		v.samples = v.samples[len(v.samples)-statsHistorySize:]
+	}
+}
+
+// runServerResourcesFollow streams the stats of a server over its websocket until interrupted.
+func runServerResourcesFollow(cmd *cobra.Command, args []string) error {
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	format := getOutputFormat(cmd)
+	screen := watch.NewScreen(os.Stdout, watch.Title(cmd, args))
+	view := &statsView{}
+	streamErr := client.StreamServerStats(ctx, args[0], func(stats api.ServerStats) {
+		view.add(stats, time.Now())
+
+		var frame bytes.Buffer
+		formatter := output.NewFormatter(format, &frame)
+		var renderErr error
+		if format == output.OutputFormatTable || format == output.OutputFormatWide {
+			renderErr = formatter.PrintTable(view.table())
+		} else {
+			// Other formats get every sample as it is, as a stream of documents.
+			renderErr = formatter.Print(stats)
+		}
+		screen.Draw("Following", &frame, renderErr)
+	})
+	if streamErr != nil {
+		return apierrors.Handle(streamErr)
+	}
+	return nil
+}
+
+// table renders the latest sample with sparklines of the recent history.
+func (v *statsView) table() ([]string, [][]string) {
+	latest := v.samples[len(v.samples)-1].stats
+
+	cpu := make([]float64, len(v.samples))
+	memory := make([]float64, len(v.samples))
+	for i, sample := range v.samples {
+		cpu[i] = sample.stats.CPUAbsolute
+		memory[i] = float64(sample.stats.MemoryBytes)
+	}
+	rx, tx := v.networkRates()
+
+	memoryCurrent := formatStatsBytes(float64(latest.MemoryBytes))
+	if latest.MemoryLimitBytes > 0 {
+		memoryCurrent += " / " + formatStatsBytes(float64(latest.MemoryLimitBytes))
+	}
+
+	rows := [][]string{
+		{"State", latest.State, ""},
+		{"CPU", fmt.Sprintf("%.1f%%", latest.CPUAbsolute), sparkline(cpu, math.Max(fullCPU, maxOf(cpu)))},
+		{"Memory", memoryCurrent, sparkline(memory, float64(latest.MemoryLimitBytes))},
+		{"Network In", formatRate(latest.Network.RxBytes, rx), sparkline(rx, 0)},
+		{"Network Out", formatRate(latest.Network.TxBytes, tx), sparkline(tx, 0)},
+		{"Disk", formatStatsBytes(float64(latest.DiskBytes)), ""},
+		{"Uptime", (time.Duration(latest.Uptime) * time.Millisecond).Round(time.Second).String(), ""},
+	}
+	return []string{"Metric", "Current", "History"}, rows
+}
+
+// networkRates returns the received and sent bytes per second between consecutive samples.
+func (v *statsView) networkRates() ([]float64, []float64) {
+	var rx, tx []float64
+	for i := 1; i < len(v.samples); i++ {
+		prev, cur := v.samples[i-1], v.samples[i]
+		seconds := cur.at.Sub(prev.at).Seconds()
+		if seconds <= 0 {
+			continue
+		}
+		// Counters restart with the server, which would show as a negative rate.
+		rx = append(rx, math.Max(0, float64(cur.stats.Network.RxBytes-prev.stats.Network.RxBytes)/seconds))
+		tx = append(tx, math.Max(0, float64(cur.stats.Network.TxBytes-prev.stats.Network.TxBytes)/seconds))
+	}
+	return rx, tx
+}
+
+// sparkline draws values as bars scaled to maxValue, or to the largest value if maxValue is not positive.
+func sparkline(values []float64, maxValue float64) string {
+	if maxValue <= 0 {
+		maxValue = maxOf(values)
+	}
+	bars := make([]rune, len(values))
+	for i, value := range values {
+		level := 0
+		if maxValue > 0 {
+			level = int(value / maxValue * float64(len(sparkBlocks)-1))
+		}
+		bars[i] = sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)]
+	}
+	return string(bars)
+}
+
+// maxOf returns the largest value, or 0 for none.
+func maxOf(values []float64) float64 {
+	largest := 0.0
+	for _, value := range values {
+		largest = math.Max(largest, value)
+	}
+	return largest
+}
+
+// formatRate formats a byte counter with its latest rate.
+func formatRate(total int64, rates []float64) string {
+	if len(rates) == 0 {
+		return formatStatsBytes(float64(total))
+	}
+	return fmt.Sprintf("%s (%s/s)", formatStatsBytes(float64(total)), formatStatsBytes(rates[len(rates)-1]))
+}
+
+// formatStatsBytes formats a byte count with a binary unit.
+func formatStatsBytes(v float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	unit := 0
+	for v >= bytesPerKiB && unit < len(units)-1 {
+		v /= bytesPerKiB
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", v, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", v, units[unit])
+}
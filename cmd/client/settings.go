@@ -0,0 +1,141 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/selector"
+)
+
+func newSettingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "settings",
+		Short: "Manage server settings",
+		Long:  "Rename, reinstall, or change the Docker image of a server",
+	}
+
+	renameCmd := &cobra.Command{
+		Use:   "rename <id|uuid> <name>",
+		Short: "Rename a server",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and name
+		RunE:  runSettingsRename,
+	}
+	renameCmd.ValidArgsFunction = serverOnlyValidArgs
+
+	reinstallCmd := &cobra.Command{
+		Use:   "reinstall <id|uuid>",
+		Short: "Reinstall a server",
+		Long: "Reinstall a server by re-running its egg install script. " +
+			"Some files may be deleted or modified, so a backup is recommended first.",
+		Args: cobra.ExactArgs(1),
+		RunE: runSettingsReinstall,
+	}
+	reinstallCmd.Flags().Bool("yes", false, "skip confirmation prompt")
+	selector.AddProtectedFlag(reinstallCmd)
+	reinstallCmd.ValidArgsFunction = serverOnlyValidArgs
+
+	setImageCmd := &cobra.Command{
+		Use:   "set-image <id|uuid> <image>",
+		Short: "Change the Docker image of a server",
+		Long:  "Change the Docker image of a server. The image must be one allowed by the server's egg.",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // server and image
+		RunE:  runSettingsSetImage,
+	}
+	setImageCmd.ValidArgsFunction = serverOnlyValidArgs
+
+	// Add subcommands FIRST (matching carapace example pattern)
+	cmd.AddCommand(renameCmd)
+	cmd.AddCommand(reinstallCmd)
+	cmd.AddCommand(setImageCmd)
+
+	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
+	for _, c := range []*cobra.Command{renameCmd, reinstallCmd, setImageCmd} {
+		carapace.Gen(c).PositionalCompletion(carapace.ActionCallback(clientServerCompletionAction))
+	}
+
+	return cmd
+}
+
+// serverOnlyValidArgs completes a server as the first argument and nothing after it.
+func serverOnlyValidArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return clientServerValidArgsFunction(cmd, args, toComplete)
+}
+
+func runSettingsRename(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, name := args[0], args[1]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	if err := client.RenameServer(ctx, serverUUID, name); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Server renamed to %s", name)
+	formatter.PrintAffected(serverUUID)
+	return nil
+}
+
+func runSettingsReinstall(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID := args[0]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+	if err := selector.CheckProtected(ctx, cmd, client, "reinstall", serverUUID); err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	message := fmt.Sprintf("This will reinstall server %s. Some files may be deleted or modified.", serverUUID)
+	shouldContinue, err := output.Confirm(cmd, formatter, message)
+	if err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return nil
+	}
+
+	if err := client.ReinstallServer(ctx, serverUUID); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter.PrintSuccess("Reinstall started for server %s", serverUUID)
+	formatter.PrintAffected(serverUUID)
+	return nil
+}
+
+func runSettingsSetImage(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	serverUUID, image := args[0], args[1]
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	if err := client.SetDockerImage(ctx, serverUUID, image); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Docker image of server %s set to %s", serverUUID, image)
+	formatter.PrintAffected(serverUUID)
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package export provides commands that expose panel data to monitoring systems and manifests.
+package export
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewExportCmd creates the export command group.
+func NewExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export panel data to monitoring systems and manifests",
+		Long: "Commands for serving server state and resource usage to external monitoring systems, " +
+			"and for exporting servers as manifests for apply",
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newPrometheusCmd())
+	cmd.AddCommand(newServersCmd())
+
+	return cmd
+}
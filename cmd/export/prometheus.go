@@ -0,0 +1,109 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/exporter"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+const (
+	// defaultListen is the address the exporter listens on, on the port registered for it.
+	defaultListen = ":9154"
+	// defaultScrapeInterval is how often the panel is scraped unless --interval says otherwise.
+	defaultScrapeInterval = 30 * time.Second
+	// defaultMaxConcurrency bounds the requests made in parallel during a scrape.
+	defaultMaxConcurrency = 10
+	// readHeaderTimeout bounds how long a client may take to send its request headers.
+	readHeaderTimeout = 10 * time.Second
+	// shutdownTimeout is how long in-flight requests get to finish when interrupted.
+	shutdownTimeout = 5 * time.Second
+)
+
+func newPrometheusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prometheus [--listen <addr>]",
+		Short: "Serve server state and resource usage as Prometheus metrics",
+		Long: "Periodically scrape the server list and the resource usage of every server, and serve them " +
+			"as Prometheus metrics until interrupted.\n\n" +
+			"Per server this exposes its power state, CPU, memory, disk and network usage, uptime and limits, " +
+			"labelled by uuid and name. With admin credentials the container health and crash status are " +
+			"exposed too; without them those metrics are left out.\n\n" +
+			"Metrics are served from the latest scrape, so Prometheus scrapes do not hit the panel.",
+		Args: cobra.NoArgs,
+		RunE: runPrometheusExport,
+	}
+	cmd.Flags().String("listen", defaultListen, "address to serve metrics on")
+	cmd.Flags().String("metrics-path", "/metrics", "HTTP path to serve metrics on")
+	cmd.Flags().Duration("interval", defaultScrapeInterval, "how often to scrape the panel")
+	cmd.Flags().Int("max-concurrency", defaultMaxConcurrency, "maximum requests to the panel in parallel")
+
+	return cmd
+}
+
+func runPrometheusExport(cmd *cobra.Command, _ []string) error {
+	listen, _ := cmd.Flags().GetString("listen")
+	metricsPath, _ := cmd.Flags().GetString("metrics-path")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+	if interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+	// Health needs the Application API; without admin credentials it is simply not exported.
+	admin, adminErr := api.NewApplicationAPI()
+	if adminErr != nil {
+		output.LogInfo("health metrics disabled", "reason", adminErr)
+		admin = nil
+	}
+
+	ctx := cmd.Context()
+	exp := exporter.New(client, admin, maxConcurrency)
+	// The first scrape runs before serving, so every Prometheus scrape finds metrics.
+	if err := exp.Scrape(ctx); err != nil {
+		return apierrors.Handle(err)
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, exp)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+	go exp.Run(ctx, interval)
+
+	formatter := output.NewFormatter(output.OutputFormatTable, os.Stderr)
+	formatter.PrintInfo("Serving metrics on http://%s%s every %s", listener.Addr(), metricsPath, interval)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
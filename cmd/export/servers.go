@@ -0,0 +1,214 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// manifestServer is a server in the manifest format read by `pelicanctl apply`.
+// Fields are ordered and named like the fields of a server create request.
+type manifestServer struct {
+	Name          string            `json:"name"`
+	ExternalID    string            `json:"external_id,omitempty"`
+	Description   string            `json:"description,omitempty"`
+	User          any               `json:"user"`
+	Egg           any               `json:"egg"`
+	DockerImage   string            `json:"docker_image,omitempty"`
+	Startup       string            `json:"startup,omitempty"`
+	Environment   map[string]any    `json:"environment,omitempty"`
+	Limits        map[string]any    `json:"limits,omitempty"`
+	FeatureLimits map[string]any    `json:"feature_limits,omitempty"`
+	OOMKiller     *bool             `json:"oom_killer,omitempty"`
+	Allocation    *serverAllocation `json:"allocation,omitempty"`
+}
+
+// serverAllocation holds the allocation IDs of a server, as strings like the panel takes them on create.
+type serverAllocation struct {
+	Default    string   `json:"default"`
+	Additional []string `json:"additional,omitempty"`
+}
+
+func newServersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "servers [--node <node>]",
+		Short: "Export servers as a manifest for apply",
+		Long: "Serialize servers into the manifest format read by 'pelicanctl apply': details, owner, egg, " +
+			"image, startup command, environment variables, limits and allocations. Use it to back up the " +
+			"server configuration of a panel or to migrate servers to another one.\n\n" +
+			"Owners are exported by username. Allocations are exported by ID, which only apply when a server " +
+			"is created on the same panel. Prints YAML unless -o json is given.",
+		Args: cobra.NoArgs,
+		RunE: runServersExport,
+	}
+	cmd.Flags().String("node", "", "only export the servers on this node, by ID or name")
+	completion.RegisterFlagFunc(cmd, "node", func(_ []string, toComplete string) ([]string, error) {
+		return completion.CompleteNodes(toComplete)
+	})
+	return cmd
+}
+
+func runServersExport(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	node, _ := cmd.Flags().GetString("node")
+
+	format := output.OutputFormatYAML
+	if jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json"); jsonFlag {
+		format = output.OutputFormatJSON
+	} else if outputFlag, _ := cmd.Root().PersistentFlags().GetString("output"); outputFlag != "" &&
+		outputFlag != string(output.OutputFormatYAML) {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("export servers prints yaml or json, not %s", outputFlag))
+	}
+
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+
+	nodeID, err := resolveNode(cmd, client, node)
+	if err != nil {
+		return err
+	}
+
+	servers, err := client.ListServersWithOptions(ctx, api.ListOptions{
+		Include: []string{"allocations", "user", "variables"},
+	})
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	exported := make([]manifestServer, 0, len(servers))
+	for _, server := range servers {
+		attrs := attributesOf(server)
+		if nodeID != "" && idString(attrs["node"]) != nodeID {
+			continue
+		}
+		exported = append(exported, toManifestServer(attrs))
+	}
+	sort.Slice(exported, func(i, j int) bool { return exported[i].Name < exported[j].Name })
+
+	formatter := output.NewFormatter(format, os.Stdout)
+	return formatter.Print(map[string]any{"servers": exported})
+}
+
+// resolveNode returns the ID of a node given by ID or name; an empty node stays empty.
+func resolveNode(cmd *cobra.Command, client *api.ApplicationAPI, node string) (string, error) {
+	if _, err := strconv.Atoi(node); node == "" || err == nil {
+		return node, nil
+	}
+	nodes, err := client.ListNodes(cmd.Context())
+	if err != nil {
+		return "", apierrors.Handle(err)
+	}
+	for _, n := range nodes {
+		attrs := attributesOf(n)
+		if name, _ := attrs["name"].(string); strings.EqualFold(name, node) {
+			return idString(attrs["id"]), nil
+		}
+	}
+	return "", apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("node %s not found", node))
+}
+
+// toManifestServer converts the attributes of a server, listed with its allocations, user and variables,
+// into a manifest resource.
+func toManifestServer(attrs map[string]any) manifestServer {
+	server := manifestServer{
+		User:          attrs["user"],
+		Egg:           attrs["egg"],
+		Limits:        mapField(attrs, "limits"),
+		FeatureLimits: mapField(attrs, "feature_limits"),
+	}
+	server.Name, _ = attrs["name"].(string)
+	server.ExternalID, _ = attrs["external_id"].(string)
+	server.Description, _ = attrs["description"].(string)
+	if oomKiller, ok := attrs["oom_killer"].(bool); ok {
+		server.OOMKiller = &oomKiller
+	}
+
+	container := mapField(attrs, "container")
+	server.DockerImage, _ = container["image"].(string)
+	server.Startup, _ = container["startup_command"].(string)
+
+	// Owners are exported by username, which stays the same when a panel is migrated.
+	for _, user := range related(attrs, "user") {
+		if username, _ := user["username"].(string); username != "" {
+			server.User = username
+		}
+	}
+
+	// The environment is built from the egg variables; the container environment also holds
+	// variables the panel sets itself, like SERVER_MEMORY and P_SERVER_UUID.
+	for _, variable := range related(attrs, "variables") {
+		name, _ := variable["env_variable"].(string)
+		if name == "" {
+			continue
+		}
+		if server.Environment == nil {
+			server.Environment = map[string]any{}
+		}
+		server.Environment[name] = variable["server_value"]
+	}
+
+	if primary := idString(attrs["allocation"]); primary != "" {
+		server.Allocation = &serverAllocation{Default: primary}
+		for _, allocation := range related(attrs, "allocations") {
+			if id := idString(allocation["id"]); id != "" && id != primary {
+				server.Allocation.Additional = append(server.Allocation.Additional, id)
+			}
+		}
+	}
+	return server
+}
+
+// related returns the attributes of the resources a server includes under relationships.<name>,
+// which holds a single resource or a list of them.
+func related(attrs map[string]any, name string) []map[string]any {
+	relationships := mapField(attrs, "relationships")
+	relation := mapField(relationships, name)
+	if attributes, ok := relation["attributes"].(map[string]any); ok {
+		return []map[string]any{attributes}
+	}
+	data, _ := relation["data"].([]any)
+	resources := make([]map[string]any, 0, len(data))
+	for _, item := range data {
+		if resource, ok := item.(map[string]any); ok {
+			resources = append(resources, attributesOf(resource))
+		}
+	}
+	return resources
+}
+
+// attributesOf returns the attributes of a resource as listed by the Application API.
+func attributesOf(resource map[string]any) map[string]any {
+	if attrs, ok := resource["attributes"].(map[string]any); ok {
+		return attrs
+	}
+	return resource
+}
+
+func mapField(attrs map[string]any, key string) map[string]any {
+	value, _ := attrs[key].(map[string]any)
+	return value
+}
+
+// idString formats a numeric ID from JSON as a string.
+func idString(id any) string {
+	switch v := id.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
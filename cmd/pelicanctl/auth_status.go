@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/auth"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// Token states reported by auth status and auth test.
+const (
+	tokenValid   = "valid"
+	tokenInvalid = "invalid"
+	tokenMissing = "missing"
+	// tokenForbidden is a token the panel accepts that lacks permission for the check.
+	tokenForbidden = "forbidden"
+)
+
+// tokenStatus is the result of checking the token of one API against the panel.
+type tokenStatus struct {
+	API     string `json:"api"`
+	Status  string `json:"status"`
+	Source  string `json:"source"`
+	BaseURL string `json:"base_url"`
+	// User is the account a client token belongs to; application tokens are not tied to a user.
+	// The panel version is not reported: neither API has an endpoint or header that exposes it.
+	User  string `json:"user,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func newAuthStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the configured tokens and whether the panel accepts them",
+		Long: "Check the client and admin tokens with a lightweight request each and report whether the panel " +
+			"accepts them, where each token comes from (env, keyring, encrypted-file or config), and the API " +
+			"base URL. Use it to find out why commands fail with 401. Always exits successfully; see 'auth test'.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return printTokenStatuses(cmd, checkTokens(cmd.Context(), "client", "admin"))
+		},
+	}
+}
+
+func newAuthTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test [client|admin]",
+		Short: "Test that the panel accepts the tokens",
+		Long: "Check the client and admin tokens, or only the given one, like 'auth status', " +
+			"and fail with the auth exit code if a token is missing or rejected.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			apiTypes := []string{"client", "admin"}
+			if len(args) == 1 {
+				if args[0] != "client" && args[0] != "admin" {
+					return fmt.Errorf("invalid API type: %s (must be 'client' or 'admin')", args[0])
+				}
+				apiTypes = args
+			}
+
+			statuses := checkTokens(cmd.Context(), apiTypes...)
+			if err := printTokenStatuses(cmd, statuses); err != nil {
+				return err
+			}
+			for _, status := range statuses {
+				if status.Status != tokenValid {
+					return apierrors.WithExitCode(apierrors.ExitAuth,
+						fmt.Errorf("%s token is %s", status.API, status.Status))
+				}
+			}
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"client", "admin"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	return cmd
+}
+
+func setupAuthTestCompletion(cmd *cobra.Command) {
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionValues("client", "admin"))
+}
+
+// checkTokens checks the token of every API type against the panel.
+func checkTokens(ctx context.Context, apiTypes ...string) []tokenStatus {
+	statuses := make([]tokenStatus, 0, len(apiTypes))
+	for _, apiType := range apiTypes {
+		statuses = append(statuses, checkToken(ctx, apiType))
+	}
+	return statuses
+}
+
+// checkToken makes the cheapest authenticated request of an API: the account for the client API,
+// and a single user for the application API.
+func checkToken(ctx context.Context, apiType string) tokenStatus {
+	status := tokenStatus{API: apiType, Source: auth.SourceNone}
+	if cfg := config.Get(); cfg != nil {
+		status.BaseURL = cfg.API.BaseURL
+	}
+
+	_, source, err := auth.LookupToken(apiType)
+	if err != nil {
+		status.Status = tokenInvalid
+		status.Error = err.Error()
+		return status
+	}
+	status.Source = source
+	if source == auth.SourceNone {
+		status.Status = tokenMissing
+		status.Error = fmt.Sprintf("run 'pelicanctl auth login %s'", apiType)
+		return status
+	}
+
+	if apiType == "client" {
+		err = checkClientToken(ctx, &status)
+	} else {
+		err = checkAdminToken(ctx)
+	}
+	var apiErr *apierrors.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		status.Status = tokenInvalid
+		if apiErr.StatusCode == http.StatusForbidden {
+			status.Status = tokenForbidden
+		}
+		status.Error = fmt.Sprintf("%d %s", apiErr.StatusCode, http.StatusText(apiErr.StatusCode))
+	case err != nil:
+		status.Status = tokenInvalid
+		status.Error = err.Error()
+	default:
+		status.Status = tokenValid
+	}
+	return status
+}
+
+func checkClientToken(ctx context.Context, status *tokenStatus) error {
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+	account, err := client.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	status.User, _ = account["username"].(string)
+	if admin, _ := account["admin"].(bool); admin {
+		status.User += " (admin)"
+	}
+	return nil
+}
+
+func checkAdminToken(ctx context.Context) error {
+	client, err := api.NewApplicationAPI()
+	if err != nil {
+		return err
+	}
+	_, err = client.ListUsersWithOptions(ctx, api.ListOptions{Page: 1, PerPage: 1})
+	return err
+}
+
+// getOutputFormat returns the output format of --json or --output.
+func getOutputFormat(cmd *cobra.Command) output.OutputFormat {
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	if jsonFlag {
+		return output.OutputFormatJSON
+	}
+	// --output was validated by the root command, which also set up a go-template.
+	outputFlag, _ := cmd.Root().PersistentFlags().GetString("output")
+	if format, _, err := output.ParseOutputFlag(outputFlag); err == nil {
+		return format
+	}
+	return output.OutputFormatTable
+}
+
+func printTokenStatuses(cmd *cobra.Command, statuses []tokenStatus) error {
+	format := getOutputFormat(cmd)
+	formatter := output.NewFormatter(format, os.Stdout)
+	switch format {
+	case output.OutputFormatJSON, output.OutputFormatNDJSON, output.OutputFormatYAML, output.OutputFormatGoTemplate:
+		return formatter.Print(statuses)
+	}
+
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		detail := status.User
+		if status.Error != "" {
+			detail = status.Error
+		}
+		rows = append(rows, []string{status.API, status.Status, status.Source, status.BaseURL, detail})
+	}
+	return formatter.PrintTable([]string{"API", "Status", "Source", "Base URL", "Detail"}, rows)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	"go.lostcrafters.com/pelicanctl/internal/httpcache"
+	"go.lostcrafters.com/pelicanctl/internal/index"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// newCacheCmd creates the cache command.
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local server cache",
+		Long: "pelicanctl caches the server list of the panel to resolve server names and IDs without " +
+			"listing every server for each of them. The cache expires after cache.ttl (default 10m). " +
+			"Shell completion caches its candidates next to it, and serves only those with completion.offline. " +
+			"Responses of the panel that carry an ETag are cached too, and revalidated on every request.",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Clear the cached server list",
+		Long: "Clear the cached server list, completion candidates and responses, so the next command that resolves a " +
+			"server name or ID lists the servers again. Use it after renaming, creating or deleting servers in the panel.",
+		Args: cobra.NoArgs,
+		RunE: runCacheClear,
+	})
+	return cmd
+}
+
+func runCacheClear(cmd *cobra.Command, _ []string) error {
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	format := output.OutputFormatTable
+	if jsonFlag {
+		format = output.OutputFormatJSON
+	}
+	formatter := output.NewFormatter(format, os.Stdout)
+
+	if err := index.Clear(); err != nil {
+		return err
+	}
+	if err := completion.ClearCache(); err != nil {
+		return err
+	}
+	if err := httpcache.Clear(); err != nil {
+		return err
+	}
+	formatter.PrintSuccess("Server cache cleared")
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+)
+
+// completionCmdName is the name of the completion command, which prints its script even if the config
+// cannot be read.
+const completionCmdName = "completion"
+
+// completionShells are the shells completion prints a script for.
+//
+//nolint:gochecknoglobals // Static completion values
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// newCompletionCmd creates the completion command, which prints cobra's completion scripts for shells
+// without carapace-bin. They complete through the hidden __complete command like carapace's do through
+// _carapace, so both offer the same candidates.
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   completionCmdName + " bash|zsh|fish|powershell",
+		Short: "Print the shell completion script",
+		Long: "Print the completion script of pelicanctl for a shell. Load it in the shell's startup file, e.g.\n\n" +
+			"  bash:       source <(pelicanctl completion bash)\n" +
+			"  zsh:        source <(pelicanctl completion zsh)\n" +
+			"  fish:       pelicanctl completion fish | source\n" +
+			"  powershell: pelicanctl completion powershell | Out-String | Invoke-Expression\n\n" +
+			"With carapace-bin installed, its pelicanctl completer is used instead and needs no script.",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: completionShells,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(os.Stdout)
+			default:
+				return fmt.Errorf("unsupported shell: %s", args[0])
+			}
+		},
+	}
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionValues(completionShells...))
+	return cmd
+}
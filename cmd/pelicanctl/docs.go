@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// Formats of docs generate.
+const (
+	docsFormatMan      = "man"
+	docsFormatMarkdown = "markdown"
+)
+
+// newDocsCmd creates the docs command, which generates the command reference from the command tree.
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate documentation",
+		Long:  "Generate the command reference of pelicanctl from its commands, for packages and the website",
+	}
+
+	generateCmd := &cobra.Command{
+		Use:   "generate --out <dir> [--format man|markdown]",
+		Short: "Generate man pages or markdown docs of every command",
+		Long: "Write a page for every command to a directory, which is created if needed: man pages in section 1 " +
+			"(pelicanctl.1, pelicanctl-admin.1, ...) or markdown files linking to each other (pelicanctl.md, " +
+			"pelicanctl_admin.md, ...). Markdown pages carry no generation date, and man pages are dated " +
+			"SOURCE_DATE_EPOCH when it is set, so packages can be built reproducibly.",
+		Args: cobra.NoArgs,
+		RunE: runDocsGenerate,
+	}
+	generateCmd.Flags().String("format", docsFormatMarkdown, "format of the pages: man or markdown")
+	generateCmd.Flags().String("out", "", "directory to write the pages to")
+	_ = generateCmd.MarkFlagRequired("out")
+
+	cmd.AddCommand(generateCmd)
+	completion.RegisterFlagValues(generateCmd, "format", docsFormatMan, docsFormatMarkdown)
+	return cmd
+}
+
+func runDocsGenerate(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	dir, _ := cmd.Flags().GetString("out")
+	if format != docsFormatMan && format != docsFormatMarkdown {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("invalid --format %q: must be man or markdown", format))
+	}
+	if dir == "" {
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--out must not be empty"))
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	root := cmd.Root()
+	// The "Auto generated on <date>" footer would change the pages on every build.
+	root.DisableAutoGenTag = true
+	var err error
+	if format == docsFormatMan {
+		err = doc.GenManTree(root, &doc.GenManHeader{
+			Title:   "PELICANCTL",
+			Section: "1",
+			Source:  "pelicanctl " + Version,
+			Manual:  "pelicanctl Manual",
+		}, dir)
+	} else {
+		err = doc.GenMarkdownTree(root, dir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate docs: %w", err)
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	formatter.PrintSuccess("Wrote %s pages to %s", format, dir)
+	return nil
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/auth"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// doctorCmdName is the name of the doctor command, which runs even if the config cannot be read.
+const doctorCmdName = "doctor"
+
+// Results of a doctor check.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	// checkSkip is a check that could not run because an earlier check failed.
+	checkSkip = "skip"
+)
+
+const (
+	// doctorCheckTimeout bounds each network check, so an unreachable panel does not hang doctor.
+	doctorCheckTimeout = 10 * time.Second
+	// certExpiryWarning is how long before its expiry the certificate of the panel is reported.
+	certExpiryWarning = 14 * 24 * time.Hour
+	// maxClockSkew is the difference to the panel's clock above which the local clock is reported.
+	maxClockSkew = time.Minute
+)
+
+// doctorCheck is the result of one doctor check, with what to do about it unless it passed.
+type doctorCheck struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Detail      string `json:"detail"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// newDoctorCmd creates the doctor command.
+func newDoctorCmd(cfg *appConfig) *cobra.Command {
+	return &cobra.Command{
+		Use:   doctorCmdName,
+		Short: "Diagnose the configuration and the connection to the panel",
+		Long: "Check that the config file can be read, the token store is usable, the panel is reachable " +
+			"at the API base URL with a valid TLS certificate, both tokens are accepted, and the local clock " +
+			"agrees with the panel's. Every check that does not pass says how to fix it. " +
+			"Fails if any check fails; warnings do not fail.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDoctor(cmd, cfg)
+		},
+	}
+}
+
+func runDoctor(cmd *cobra.Command, cfg *appConfig) error {
+	ctx := cmd.Context()
+	checks := []doctorCheck{checkConfig(cfg.configPath), checkTokenStore()}
+	// checkConfig loads the config again, without the TLS and proxy flags.
+	if loaded := config.Get(); loaded != nil {
+		applyConnectionSettings(cfg, loaded)
+	}
+
+	baseURL, urlCheck := checkBaseURL()
+	checks = append(checks, urlCheck)
+	remote := []string{"network", "tls", "client token", "admin token", "clock"}
+	if baseURL == nil {
+		checks = append(checks, skipChecks(remote, "no usable API base URL")...)
+		return printDoctorChecks(cmd, checks)
+	}
+
+	network := checkNetwork(ctx, baseURL)
+	checks = append(checks, network)
+	if network.Status == checkFail {
+		checks = append(checks, skipChecks(remote[1:], "panel not reachable")...)
+		return printDoctorChecks(cmd, checks)
+	}
+	checks = append(checks,
+		checkTLS(ctx, baseURL),
+		checkDoctorToken(ctx, "client"),
+		checkDoctorToken(ctx, "admin"),
+		checkClock(ctx, baseURL),
+	)
+	return printDoctorChecks(cmd, checks)
+}
+
+func skipChecks(names []string, reason string) []doctorCheck {
+	checks := make([]doctorCheck, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, doctorCheck{Name: name, Status: checkSkip, Detail: reason})
+	}
+	return checks
+}
+
+func checkConfig(configPath string) doctorCheck {
+	check := doctorCheck{Name: "config"}
+	if _, err := config.Load(configPath); err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Remediation = "fix the YAML syntax of the config file, or move it away and run 'pelicanctl auth login'"
+		return check
+	}
+
+	check.Status = checkOK
+	check.Detail = "read " + config.FileUsed()
+	if config.FileUsed() == "" {
+		check.Detail = "no config file, using environment variables"
+	}
+	return check
+}
+
+// checkTokenStore checks the token backend of auth.backend.
+func checkTokenStore() doctorCheck {
+	check := doctorCheck{Name: "token store", Status: checkOK}
+	backend, err := auth.Backend()
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Remediation = "set auth.backend in the config file to keyring, file or encrypted-file"
+		return check
+	}
+
+	switch backend {
+	case auth.BackendFile:
+		check.Status = checkWarn
+		check.Detail = "tokens are kept in plain text in the config file"
+		check.Remediation = "set auth.backend to encrypted-file, or to keyring where a keyring is available"
+	case auth.BackendEncryptedFile:
+		check.Detail = "encrypted file"
+		if path, pathErr := auth.TokenFilePath(); pathErr == nil {
+			check.Detail = "encrypted file " + path
+		}
+	default:
+		check.Detail = "keyring available"
+		if err := auth.KeyringAvailable(); err != nil {
+			check.Status = checkWarn
+			check.Detail = "keyring: " + err.Error()
+			check.Remediation = "start a Secret Service provider (e.g. gnome-keyring), or set auth.backend to " +
+				"encrypted-file; tokens otherwise fall back to the config file"
+		}
+	}
+	return check
+}
+
+// checkBaseURL checks api.base_url, returning it parsed if it can be used.
+func checkBaseURL() (*url.URL, doctorCheck) {
+	check := doctorCheck{Name: "base url"}
+	cfg := config.Get()
+	if cfg == nil {
+		check.Status = checkSkip
+		check.Detail = "config not loaded"
+		return nil, check
+	}
+	if cfg.API.BaseURL == "" {
+		check.Status = checkFail
+		check.Detail = "api.base_url is not set"
+		check.Remediation = "run 'pelicanctl auth login', or set PELICANCTL_API_BASE_URL"
+		return nil, check
+	}
+
+	baseURL, err := url.Parse(cfg.API.BaseURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		check.Status = checkFail
+		check.Detail = fmt.Sprintf("invalid api.base_url %q", cfg.API.BaseURL)
+		check.Remediation = "set api.base_url to the panel's URL, like https://panel.example.com"
+		return nil, check
+	}
+	check.Status = checkOK
+	check.Detail = cfg.API.BaseURL
+	return baseURL, check
+}
+
+// hostPort returns the address of the panel, with the default port of the scheme if the URL has none.
+func hostPort(baseURL *url.URL) string {
+	if port := baseURL.Port(); port != "" {
+		return baseURL.Host
+	}
+	if baseURL.Scheme == "https" {
+		return net.JoinHostPort(baseURL.Hostname(), "443")
+	}
+	return net.JoinHostPort(baseURL.Hostname(), "80")
+}
+
+func checkNetwork(ctx context.Context, baseURL *url.URL) doctorCheck {
+	check := doctorCheck{Name: "network"}
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	// Through a proxy only the proxy is dialed; whether it reaches the panel shows in the TLS check.
+	target, remediation := hostPort(baseURL), "check that the panel is running and that no firewall or proxy blocks "
+	if cfg := config.Get(); cfg != nil && cfg.API.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.API.ProxyURL)
+		if _, proxyErr := api.NewProxy(cfg.API.ProxyURL); proxyErr != nil || err != nil {
+			check.Status = checkFail
+			check.Detail = fmt.Sprintf("invalid proxy %q", cfg.API.ProxyURL)
+			check.Remediation = "set api.proxy_url or --proxy to a URL like socks5://bastion:1080"
+			return check
+		}
+		target, remediation = proxyHostPort(proxyURL), "check that the proxy is running and reachable at "
+	}
+
+	start := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", target)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			check.Remediation = fmt.Sprintf("check the host name; %s does not resolve", dnsErr.Name)
+		} else {
+			check.Remediation = remediation + target
+		}
+		return check
+	}
+	_ = conn.Close()
+
+	check.Status = checkOK
+	check.Detail = fmt.Sprintf("connected to %s in %s", target, time.Since(start).Round(time.Millisecond))
+	return check
+}
+
+// proxyHostPort returns the address of a proxy, with the default port of its scheme if the URL has none.
+func proxyHostPort(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		return net.JoinHostPort(proxyURL.Hostname(), "1080")
+	case "https":
+		return net.JoinHostPort(proxyURL.Hostname(), "443")
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), "80")
+}
+
+func checkTLS(ctx context.Context, baseURL *url.URL) doctorCheck {
+	check := doctorCheck{Name: "tls"}
+	if baseURL.Scheme != "https" {
+		check.Status = checkWarn
+		check.Detail = "api.base_url uses http, so tokens are sent unencrypted"
+		check.Remediation = "serve the panel over HTTPS and use an https:// base URL"
+		return check
+	}
+
+	tlsConfig, err := doctorTLSConfig()
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Remediation = "check the files of api.tls in the config file, or of --ca-cert, --client-cert and --client-key"
+		return check
+	}
+	if tlsConfig.InsecureSkipVerify {
+		check.Status = checkWarn
+		check.Detail = "certificate verification is disabled, so the panel is not authenticated"
+		check.Remediation = "trust the panel's CA with api.tls.ca_cert or --ca-cert instead of skipping verification"
+		return check
+	}
+	tlsConfig.ServerName = baseURL.Hostname()
+
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+	// A request rather than a bare handshake, so the certificate is checked through the proxy too.
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, baseURL.String(), nil)
+	if err != nil {
+		check.Status = checkSkip
+		check.Detail = err.Error()
+		return check
+	}
+	client := &http.Client{
+		Transport:     doctorTransport(tlsConfig),
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Remediation = tlsRemediation(err)
+		return check
+	}
+	_ = resp.Body.Close()
+
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		check.Status = checkOK
+		check.Detail = "handshake succeeded"
+		return check
+	}
+	expiry := resp.TLS.PeerCertificates[0].NotAfter
+	check.Status = checkOK
+	check.Detail = "certificate valid until " + expiry.Format(time.DateOnly)
+	if time.Until(expiry) < certExpiryWarning {
+		check.Status = checkWarn
+		check.Remediation = "renew the panel's certificate before it expires"
+	}
+	return check
+}
+
+// doctorTLSConfig returns the TLS settings of api.tls, with the client certificate for panels behind
+// a proxy that requires one.
+func doctorTLSConfig() (*tls.Config, error) {
+	var settings config.TLSConfig
+	if cfg := config.Get(); cfg != nil {
+		settings = cfg.API.TLS
+	}
+	tlsConfig, err := api.NewTLSConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return tlsConfig, nil
+}
+
+// doctorTransport returns a transport with tlsConfig that connects through the proxy of api.proxy_url,
+// or the proxy environment variables.
+func doctorTransport(tlsConfig *tls.Config) *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if cfg := config.Get(); cfg != nil {
+		if configured, err := api.NewProxy(cfg.API.ProxyURL); err == nil {
+			proxy = configured
+		}
+	}
+	return &http.Transport{TLSClientConfig: tlsConfig, Proxy: proxy}
+}
+
+// tlsRemediation says how to fix a failed TLS handshake.
+func tlsRemediation(err error) string {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostname         x509.HostnameError
+		invalid          x509.CertificateInvalidError
+	)
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return "the certificate is self-signed or from a private CA; add the CA to the system trust store " +
+			"or set api.tls.ca_cert"
+	case errors.As(err, &hostname):
+		return "the certificate is for another host; use the host name it was issued for in api.base_url"
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return "the certificate has expired, or the local clock is wrong; renew the certificate or fix the clock"
+	}
+	return "check that the panel serves HTTPS on this port, or use an http:// base URL if it does not"
+}
+
+func checkDoctorToken(ctx context.Context, apiType string) doctorCheck {
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+	status := checkToken(ctx, apiType)
+
+	check := doctorCheck{Name: apiType + " token", Detail: status.Status}
+	if status.Source != auth.SourceNone {
+		check.Detail = fmt.Sprintf("%s (from %s)", status.Status, status.Source)
+	}
+	switch status.Status {
+	case tokenValid:
+		check.Status = checkOK
+		if status.User != "" {
+			check.Detail += ", user " + status.User
+		}
+	case tokenMissing:
+		// Many users only work with one of the APIs.
+		check.Status = checkWarn
+		check.Remediation = fmt.Sprintf("run 'pelicanctl auth login %s' if you use %s commands", apiType, apiType)
+	case tokenForbidden:
+		check.Status = checkFail
+		check.Detail += ": " + status.Error
+		check.Remediation = "give the application API key read permission on users in the panel"
+	default:
+		check.Status = checkFail
+		check.Detail += ": " + status.Error
+		check.Remediation = fmt.Sprintf("the token was revoked or mistyped; create a new API key in the panel "+
+			"and run 'pelicanctl auth login %s'", apiType)
+	}
+	return check
+}
+
+// checkClock compares the local clock with the Date header of the panel.
+func checkClock(ctx context.Context, baseURL *url.URL) doctorCheck {
+	check := doctorCheck{Name: "clock"}
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, baseURL.String(), nil)
+	if err != nil {
+		check.Status = checkSkip
+		check.Detail = err.Error()
+		return check
+	}
+	// The clock is checked even if the certificate is not trusted; the TLS check reports that.
+	tlsConfig, err := doctorTLSConfig()
+	if err != nil {
+		check.Status = checkSkip
+		check.Detail = err.Error()
+		return check
+	}
+	tlsConfig.InsecureSkipVerify = true //nolint:gosec // Only the Date header is read
+	client := &http.Client{Transport: doctorTransport(tlsConfig)}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		check.Status = checkSkip
+		check.Detail = err.Error()
+		return check
+	}
+	_ = resp.Body.Close()
+	// The panel's clock is compared to the middle of the request, which its Date header was set during.
+	local := start.Add(time.Since(start) / 2) //nolint:mnd // Halfway through the request
+
+	remote, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		check.Status = checkSkip
+		check.Detail = "the panel sent no Date header"
+		return check
+	}
+	skew := local.Sub(remote).Round(time.Second)
+	check.Status = checkOK
+	check.Detail = "in sync with the panel"
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		check.Status = checkWarn
+		check.Detail = fmt.Sprintf("local clock is %s off the panel's", skew.Abs())
+		check.Remediation = "synchronize the clock with NTP (e.g. 'timedatectl set-ntp true'); " +
+			"schedules and certificate checks rely on it"
+	}
+	return check
+}
+
+func printDoctorChecks(cmd *cobra.Command, checks []doctorCheck) error {
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	format := output.OutputFormatTable
+	if jsonFlag {
+		format = output.OutputFormatJSON
+	}
+	formatter := output.NewFormatter(format, os.Stdout)
+
+	failed, passed := 0, 0
+	for _, check := range checks {
+		switch check.Status {
+		case checkFail:
+			failed++
+		case checkOK:
+			passed++
+		}
+	}
+
+	if jsonFlag {
+		if err := formatter.Print(checks); err != nil {
+			return err
+		}
+	} else {
+		rows := make([][]string, 0, len(checks))
+		for _, check := range checks {
+			rows = append(rows, []string{check.Name, check.Status, check.Detail})
+		}
+		if err := formatter.PrintTable([]string{"Check", "Status", "Detail"}, rows); err != nil {
+			return err
+		}
+		for _, check := range checks {
+			if check.Remediation == "" {
+				continue
+			}
+			if check.Status == checkFail {
+				formatter.PrintError("%s: %s", check.Name, check.Remediation)
+			} else {
+				formatter.PrintWarning("%s: %s", check.Name, check.Remediation)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	if !jsonFlag && passed == len(checks) {
+		formatter.PrintSuccess("All checks passed")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+)
+
+// newGetCmd creates the get command, whose subcommands print a single value for shell scripts.
+func newGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Print single values for scripts",
+		Long: "Print a single value on a line of its own, without decoration, so shell scripts can use it " +
+			"directly, e.g. id=$(pelicanctl get server-id lobby).",
+	}
+
+	serverIDCmd := &cobra.Command{
+		Use:   "server-id <name|uuid>",
+		Short: "Print the integer ID of a server",
+		Long:  "Print the integer ID of a server given by name, UUID or short identifier",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGetServer(cmd, args[0], "id")
+		},
+	}
+	serverUUIDCmd := &cobra.Command{
+		Use:   "server-uuid <id|name>",
+		Short: "Print the UUID of a server",
+		Long:  "Print the UUID of a server given by integer ID, name or short identifier",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGetServer(cmd, args[0], "uuid")
+		},
+	}
+	panelURLCmd := &cobra.Command{
+		Use:   "panel-url",
+		Short: "Print the base URL of the panel",
+		Long: "Print the base URL of the panel commands are sent to, from the config, the profile or " +
+			"PELICANCTL_API_BASE_URL",
+		Args: cobra.NoArgs,
+		RunE: runGetPanelURL,
+	}
+
+	cmd.AddCommand(serverIDCmd, serverUUIDCmd, panelURLCmd)
+	for _, serverCmd := range []*cobra.Command{serverIDCmd, serverUUIDCmd} {
+		serverCmd.Flags().String("api", api.TypeClient, "API to look the server up through: client or admin")
+		completion.RegisterFlagValues(serverCmd, "api", api.TypeClient, api.TypeAdmin)
+		serverCmd.ValidArgsFunction = getServerValidArgs
+		carapace.Gen(serverCmd).PositionalCompletion(carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			apiType, _ := serverCmd.Flags().GetString("api")
+			completions, err := completion.CompleteServers(apiType, c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return completion.Action(completions)
+		}))
+	}
+	return cmd
+}
+
+func getServerValidArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	apiType, _ := cmd.Flags().GetString("api")
+	completions, err := completion.CompleteServers(apiType, toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// runGetServer prints the id or uuid of a server, looked up through the server index.
+func runGetServer(cmd *cobra.Command, identifier, field string) error {
+	apiType, _ := cmd.Flags().GetString("api")
+	client, err := api.NewServerAPI(apiType)
+	if err != nil {
+		return err
+	}
+	entry, err := api.LookupServer(cmd.Context(), client, identifier)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	value := entry.UUID
+	if field == "id" {
+		value = entry.ID
+	}
+	if value == "" {
+		return fmt.Errorf("the %s API does not report the %s of server %s; try --api admin", apiType, field, identifier)
+	}
+	fmt.Fprintln(os.Stdout, value)
+	return nil
+}
+
+func runGetPanelURL(_ *cobra.Command, _ []string) error {
+	cfg := config.Get()
+	if cfg == nil || cfg.API.BaseURL == "" {
+		return apierrors.WithExitCode(apierrors.ExitValidation, errors.New(
+			"API base URL not configured. Set PELICANCTL_API_BASE_URL or run 'pelicanctl auth login'"))
+	}
+	fmt.Fprintln(os.Stdout, strings.TrimSuffix(cfg.API.BaseURL, "/"))
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// newGroupCmd creates the group command.
+func newGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group",
+		Short: "Manage local server groups",
+		Long: "Groups name sets of servers, so bulk commands can target them with --group instead of " +
+			"--from-file lists. The panel has no tags, so groups are kept in the config file under 'groups'. " +
+			"Servers are added by UUID, ID or name.",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a server group",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runGroupCreate,
+	}
+	createCmd.Flags().StringSlice("add", nil, "servers to add to the group, by UUID, ID or name (repeatable)")
+
+	addCmd := &cobra.Command{
+		Use:   "add <name> <server>...",
+		Short: "Add servers to a group",
+		Args:  cobra.MinimumNArgs(2), //nolint:mnd // Group name and at least one server
+		RunE:  runGroupAdd,
+	}
+	removeCmd := &cobra.Command{
+		Use:   "remove <name> <server>...",
+		Short: "Remove servers from a group",
+		Args:  cobra.MinimumNArgs(2), //nolint:mnd // Group name and at least one server
+		RunE:  runGroupRemove,
+	}
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a server group",
+		Long:  "Delete a server group. Per-server settings keyed by the group under 'servers' are kept.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runGroupDelete,
+	}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List server groups",
+		Args:  cobra.NoArgs,
+		RunE:  runGroupList,
+	}
+
+	groupNameCompletion := func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		groups, _ := completion.CompleteGroups(toComplete)
+		return groups, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, sub := range []*cobra.Command{addCmd, removeCmd, deleteCmd} {
+		sub.ValidArgsFunction = groupNameCompletion
+	}
+
+	cmd.AddCommand(createCmd, addCmd, removeCmd, deleteCmd, listCmd)
+	return cmd
+}
+
+func runGroupCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if _, exists := config.Get().GroupMembers(name); exists {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("group %q already exists. Use 'pelicanctl group add' to add servers to it", name))
+	}
+	servers, _ := cmd.Flags().GetStringSlice("add")
+
+	members := addMembers(nil, servers)
+	if err := config.SetGroup(name, members); err != nil {
+		return err
+	}
+	groupFormatter(cmd).PrintSuccess("Created group %s with %d server(s)", strings.ToLower(name), len(members))
+	return nil
+}
+
+func runGroupAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	members, err := existingGroup(name)
+	if err != nil {
+		return err
+	}
+
+	updated := addMembers(members, args[1:])
+	if err := config.SetGroup(name, updated); err != nil {
+		return err
+	}
+	groupFormatter(cmd).PrintSuccess("Added %d server(s) to group %s", len(updated)-len(members), strings.ToLower(name))
+	return nil
+}
+
+func runGroupRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	members, err := existingGroup(name)
+	if err != nil {
+		return err
+	}
+
+	formatter := groupFormatter(cmd)
+	updated := slices.Clone(members)
+	for _, server := range args[1:] {
+		before := len(updated)
+		updated = slices.DeleteFunc(updated, func(member string) bool { return strings.EqualFold(member, server) })
+		if len(updated) == before {
+			formatter.PrintWarning("Server %s is not in group %s", server, strings.ToLower(name))
+		}
+	}
+	if err := config.SetGroup(name, updated); err != nil {
+		return err
+	}
+	formatter.PrintSuccess("Removed %d server(s) from group %s", len(members)-len(updated), strings.ToLower(name))
+	return nil
+}
+
+func runGroupDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if _, err := existingGroup(name); err != nil {
+		return err
+	}
+	if err := config.DeleteGroup(name); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	groupFormatter(cmd).PrintSuccess("Deleted group %s", strings.ToLower(name))
+	return nil
+}
+
+func runGroupList(cmd *cobra.Command, _ []string) error {
+	formatter := groupFormatter(cmd)
+	cfg := config.Get()
+
+	names := make([]string, 0, len(cfg.Groups))
+	for name := range cfg.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	if jsonFlag {
+		groups := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			groups = append(groups, map[string]any{"name": name, "servers": cfg.Groups[name]})
+		}
+		return formatter.Print(groups)
+	}
+
+	if len(names) == 0 {
+		formatter.PrintInfo("No groups configured. Create one with 'pelicanctl group create'")
+		return nil
+	}
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		members := cfg.Groups[name]
+		rows = append(rows, []string{name, strconv.Itoa(len(members)), strings.Join(members, ", ")})
+	}
+	return formatter.PrintTable([]string{"Group", "Count", "Servers"}, rows)
+}
+
+// existingGroup returns the servers of a group, failing if it is not configured.
+func existingGroup(name string) ([]string, error) {
+	members, ok := config.Get().GroupMembers(name)
+	if !ok {
+		return nil, apierrors.WithExitCode(apierrors.ExitNotFound,
+			fmt.Errorf("group %q not configured. Create it with 'pelicanctl group create'", name))
+	}
+	return members, nil
+}
+
+// addMembers appends the servers that are not yet members, matching case-insensitively.
+// Comma-separated servers are split, like the server arguments of bulk commands.
+func addMembers(members, servers []string) []string {
+	updated := slices.Clone(members)
+	if updated == nil {
+		updated = []string{}
+	}
+	for _, arg := range servers {
+		for server := range strings.SplitSeq(arg, ",") {
+			server = strings.TrimSpace(server)
+			if server == "" {
+				continue
+			}
+			if !slices.ContainsFunc(updated, func(member string) bool { return strings.EqualFold(member, server) }) {
+				updated = append(updated, server)
+			}
+		}
+	}
+	return updated
+}
+
+func groupFormatter(cmd *cobra.Command) *output.Formatter {
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	format := output.OutputFormatTable
+	if jsonFlag {
+		format = output.OutputFormatJSON
+	}
+	return output.NewFormatter(format, os.Stdout)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/hooks"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// runPreHook runs the pre hook of the command from the hooks section of the config file, if it has one.
+// If the hook fails, neither the command nor its post hook runs.
+func runPreHook(cmd *cobra.Command, cfg *appConfig, loaded *config.Config) error {
+	name := hooks.Name(hooks.Pre, cmd.CommandPath())
+	if command := loaded.Hooks[name]; name != "" && command != "" {
+		if err := hooks.Run(cmd.Context(), command, hookEvent(cmd, name, loaded)); err != nil {
+			// The command line is fine; usage would only bury the output of the hook.
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+	cfg.hooksEnabled = true
+	return nil
+}
+
+// runPostHook runs the post hook of the command with its outcome, if the command ran. A hook that fails
+// is logged, without failing the command.
+func runPostHook(cfg *appConfig, cmd *cobra.Command, err error) {
+	loaded := config.Get()
+	if !cfg.hooksEnabled || cmd == nil || loaded == nil {
+		return
+	}
+	name := hooks.Name(hooks.Post, cmd.CommandPath())
+	command := loaded.Hooks[name]
+	if name == "" || command == "" {
+		return
+	}
+
+	event := hookEvent(cmd, name, loaded)
+	exitCode := apierrors.ExitCode(err)
+	event.ExitCode = &exitCode
+	event.Status = hooks.StatusSucceeded
+	if err != nil {
+		event.Status = hooks.StatusFailed
+		event.Err = err.Error()
+	}
+	// The context of the command may be over by now, e.g. after --timeout or Ctrl-C.
+	if hookErr := hooks.Run(context.Background(), command, event); hookErr != nil {
+		output.LogWarn("post hook failed", "hook", name, "error", hookErr)
+	}
+}
+
+// hookEvent describes the command to its hooks: its arguments and the flags given on the command line.
+func hookEvent(cmd *cobra.Command, name string, loaded *config.Config) hooks.Event {
+	event := hooks.Event{
+		Hook:    name,
+		Command: cmd.CommandPath(),
+		Args:    cmd.Flags().Args(),
+		Flags:   map[string]string{},
+		Panel:   strings.TrimSuffix(loaded.API.BaseURL, "/"),
+	}
+	if event.Args == nil {
+		event.Args = []string{}
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		value := flag.Value.String()
+		// Tokens stay out of the logs hooks may keep.
+		if strings.Contains(flag.Name, "token") && flag.Name != "token-file" {
+			value = "<redacted>"
+		}
+		event.Flags[flag.Name] = value
+	})
+	return event
+}
@@ -2,17 +2,36 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	// --timezone works without a time zone database on the system, as on Windows.
+	_ "time/tzdata"
 
 	"github.com/carapace-sh/carapace"
 	"github.com/spf13/cobra"
 
 	"go.lostcrafters.com/pelicanctl/cmd/admin"
 	"go.lostcrafters.com/pelicanctl/cmd/client"
+	"go.lostcrafters.com/pelicanctl/cmd/export"
+	"go.lostcrafters.com/pelicanctl/cmd/schedulelocal"
+	"go.lostcrafters.com/pelicanctl/cmd/sync"
+	"go.lostcrafters.com/pelicanctl/internal/api"
 	"go.lostcrafters.com/pelicanctl/internal/auth"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
 	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/notify"
 	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/progress"
+	"go.lostcrafters.com/pelicanctl/internal/retry"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
 )
 
 // Version is set during build via ldflags.
@@ -23,8 +42,38 @@ var Version = "dev"
 type appConfig struct {
 	configPath string
 	json       bool
+	output     string
 	verbose    bool
 	quiet      bool
+	strict     bool
+	progressFD int
+	progress   bool
+	identity   string
+	columns    []string
+	sortBy     string
+	desc       bool
+	noHeader   bool
+	noColor    bool
+	rawUnits   bool
+	utc        bool
+	timeZone   string
+	timeout    time.Duration
+	retries    int
+	retryDelay time.Duration
+	tls        config.TLSConfig
+	proxy      string
+	notifyURLs []string
+
+	// webhooks are notified of the bulk runs of the command once it is done, from --notify-webhook
+	// and the notify section of the config file.
+	webhooks []notify.Webhook
+
+	// cancelTimeout releases the deadline of --timeout once the command is done.
+	cancelTimeout context.CancelFunc
+	// deadline is when --timeout ends the command, zero without it.
+	deadline time.Time
+	// hooksEnabled is set once the command is about to run, so its post hook runs when it is done.
+	hooksEnabled bool
 }
 
 func setupRootCmd(cfg *appConfig) *cobra.Command {
@@ -40,10 +89,31 @@ backups, databases, and more.`,
 			// The _carapace command is a hidden subcommand added by carapace.Gen() and needs direct access
 			if cmd.Name() == "_carapace" {
 				// Still load config for API clients in completions, but don't initialize logger
-				_, _ = config.Load(cfg.configPath)
+				if loaded, err := config.Load(cfg.configPath); err == nil {
+					applyConnectionSettings(cfg, loaded)
+				}
 				return nil
 			}
 
+			output.SetNoColor(cfg.noColor)
+			output.SetRawUnits(cfg.rawUnits)
+			if cfg.strict {
+				strict.Enable()
+			}
+			if cfg.progressFD > 0 {
+				if err := progress.Open(cfg.progressFD, cmd.CommandPath()); err != nil {
+					return err
+				}
+			}
+			if cfg.progress && !cfg.quiet {
+				bulk.SetProgress(bulk.ProgressBar())
+			}
+
+			// --output json is equivalent to --json
+			if err := applyOutputFlag(cmd, cfg); err != nil {
+				return apierrors.WithExitCode(apierrors.ExitValidation, err)
+			}
+
 			// Suppress Cobra's default error and usage output when --json is enabled
 			// This ensures only JSON is output, not plain text errors and usage
 			// Read flag directly from command to ensure it's detected (flags are parsed before PersistentPreRunE)
@@ -54,21 +124,59 @@ backups, databases, and more.`,
 			}
 
 			// Load configuration
-			_, err := config.Load(cfg.configPath)
+			loaded, err := config.Load(cfg.configPath)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+				// doctor reports an unreadable config itself, along with how to fix it, and completion
+				// scripts don't depend on it.
+				if cmd.Name() != doctorCmdName && cmd.Name() != completionCmdName {
+					return fmt.Errorf("failed to load config: %w", err)
+				}
+				loaded = &config.Config{}
+			}
+
+			applyConnectionSettings(cfg, loaded)
+			if err := applyIdentityPolicy(cfg, loaded); err != nil {
+				return apierrors.WithExitCode(apierrors.ExitValidation, err)
+			}
+			if err := applyTableStyle(loaded); err != nil {
+				return apierrors.WithExitCode(apierrors.ExitValidation, err)
+			}
+			if err := applyTimeZone(cfg); err != nil {
+				return apierrors.WithExitCode(apierrors.ExitValidation, err)
+			}
+			if cfg.desc && cfg.sortBy == "" {
+				return apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--desc requires --sort-by"))
+			}
+			if err := applyTimeout(cmd, cfg); err != nil {
+				return apierrors.WithExitCode(apierrors.ExitValidation, err)
+			}
+			applyPageConcurrency(cmd)
+			if err := applyRetryPolicy(cfg); err != nil {
+				return apierrors.WithExitCode(apierrors.ExitValidation, err)
+			}
+			if err := applyNotifySettings(cfg, loaded); err != nil {
+				return apierrors.WithExitCode(apierrors.ExitValidation, err)
 			}
+			output.SetTableOptions(output.TableOptions{
+				Columns:  cfg.columns,
+				SortBy:   cfg.sortBy,
+				Desc:     cfg.desc,
+				NoHeader: cfg.noHeader,
+			})
 
 			// Initialize logger for normal commands
 			var format output.OutputFormat
-			if cfg.json {
+			switch {
+			case cfg.json:
 				format = output.OutputFormatJSON
-			} else {
+			case cfg.output == string(output.OutputFormatNDJSON):
+				format = output.OutputFormatNDJSON
+			default:
 				format = output.OutputFormatTable
 			}
 			output.InitLogger(cfg.verbose, cfg.quiet, format, os.Stderr)
 
-			return nil
+			return runPreHook(cmd, cfg, loaded)
 		},
 	}
 
@@ -76,10 +184,51 @@ backups, databases, and more.`,
 		&cfg.configPath, "config", "",
 		"config file (default is $XDG_CONFIG_HOME/pelicanctl/config.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&cfg.json, "json", false, "output in JSON format")
+	rootCmd.PersistentFlags().StringVarP(&cfg.output, "output", "o", "",
+		"output format (table, wide, json, ndjson, yaml, csv, tsv, porcelain, go-template=<template>)")
 	rootCmd.PersistentFlags().BoolVar(&cfg.verbose, "verbose", false, "enable verbose logging")
 	rootCmd.PersistentFlags().BoolVar(&cfg.quiet, "quiet", false, "minimal output (errors only)")
-
-	// Disable Cobra's default completion command to avoid conflicts with carapace
+	rootCmd.PersistentFlags().BoolVar(&cfg.strict, "strict", false,
+		"fail on warnings and never prompt, look up identifiers, or fall back (for automation)")
+	rootCmd.PersistentFlags().StringVar(&cfg.identity, "identity", "",
+		"server identifier shown in server tables: id, uuid, short-uuid, name (default uuid)")
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.columns, "columns", nil,
+		"columns of list tables, by header or field path (e.g. id,uuid,name,node)")
+	rootCmd.PersistentFlags().StringVar(&cfg.sortBy, "sort-by", "", "sort list tables by this column")
+	rootCmd.PersistentFlags().BoolVar(&cfg.desc, "desc", false, "with --sort-by, sort in descending order")
+	rootCmd.PersistentFlags().BoolVar(&cfg.noHeader, "no-header", false, "omit the header row of csv and tsv output")
+	rootCmd.PersistentFlags().BoolVar(&cfg.noColor, "no-color", false,
+		"print plain text without colors, also set by NO_COLOR (CLICOLOR_FORCE colors output that isn't a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.rawUnits, "raw-units", false,
+		"print bytes, CPU usage and uptimes as the panel reports them instead of e.g. 3.8 GiB, 95.0% and 2d4h")
+	rootCmd.PersistentFlags().StringVar(&cfg.timeZone, "timezone", "",
+		"time zone of the times in tables, e.g. Europe/Berlin (default is the local one)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.utc, "utc", false, "print the times in tables in UTC")
+	rootCmd.PersistentFlags().IntVar(&cfg.progressFD, "progress-fd", 0,
+		"write JSONL progress events for bulk runs and transfers to this file descriptor (e.g. 3)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.progress, "progress", false,
+		"show a progress bar of bulk runs on stderr, with the operations done, failures and ETA")
+	rootCmd.PersistentFlags().DurationVar(&cfg.timeout, "timeout", 0,
+		"give up on the command after this long (e.g. 30s, 5m); 0 means no limit")
+	rootCmd.PersistentFlags().IntVar(&cfg.retries, "retries", retry.DefaultRetries,
+		"retry requests that failed on rate limits, panel errors or network errors up to this many times")
+	rootCmd.PersistentFlags().DurationVar(&cfg.retryDelay, "retry-delay", retry.DefaultDelay,
+		"backoff before the first retry, doubled for every further one")
+	rootCmd.PersistentFlags().StringVar(&cfg.tls.CACert, "ca-cert", "",
+		"PEM file of CA certificates to trust for the panel, in addition to the system ones")
+	rootCmd.PersistentFlags().StringVar(&cfg.tls.ClientCert, "client-cert", "",
+		"PEM file of the client certificate presented to the panel (with --client-key)")
+	rootCmd.PersistentFlags().StringVar(&cfg.tls.ClientKey, "client-key", "",
+		"PEM file of the private key of --client-cert")
+	rootCmd.PersistentFlags().BoolVar(&cfg.tls.InsecureSkipVerify, "insecure-skip-tls-verify", false,
+		"accept any TLS certificate of the panel (insecure, for testing only)")
+	rootCmd.PersistentFlags().StringVar(&cfg.proxy, "proxy", "",
+		"HTTP or SOCKS proxy for requests to the panel (e.g. socks5://bastion:1080)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.notifyURLs, "notify-webhook", nil,
+		"post a summary of bulk runs to this webhook (Discord, Slack or JSON) once the command is done; repeatable")
+
+	// Disable Cobra's default completion command to avoid conflicts with carapace; newCompletionCmd
+	// replaces it for shells without carapace-bin
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	// Initialize carapace - this sets up the completion infrastructure
@@ -87,33 +236,253 @@ backups, databases, and more.`,
 	// We call this before adding subcommands to initialize the infrastructure
 	carapace.Gen(rootCmd)
 
+	statusCmd := newStatusCmd()
+
 	// Add subcommands - PositionalCompletion setups will be discovered by carapace
 	rootCmd.AddCommand(client.NewClientCmd())
 	rootCmd.AddCommand(admin.NewAdminCmd())
+	rootCmd.AddCommand(admin.NewApplyCmd())
+	rootCmd.AddCommand(admin.NewTemplateCmd())
+	rootCmd.AddCommand(sync.NewSyncCmd())
+	rootCmd.AddCommand(export.NewExportCmd())
+	rootCmd.AddCommand(schedulelocal.NewScheduleLocalCmd())
 	rootCmd.AddCommand(newAuthCmd(cfg))
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(newDoctorCmd(cfg))
+	rootCmd.AddCommand(newGroupCmd())
+	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(newVersionCmd())
+	rootCmd.AddCommand(newCompletionCmd())
+	rootCmd.AddCommand(newDocsCmd())
+	rootCmd.AddCommand(newGetCmd())
+	rootCmd.AddCommand(newPluginCmd())
 
 	// Call carapace.Gen again after all subcommands are added to ensure discovery
 	// This matches the pattern in reference examples where Gen is called multiple times
 	carapace.Gen(rootCmd)
 
+	setupStatusCompletion(statusCmd)
+
+	// Flag completions are registered once the whole tree exists
+	completion.RegisterFlagValues(rootCmd, "output", completion.OutputFormats...)
+	completion.RegisterFlagValues(rootCmd, "identity", output.IdentityPolicies...)
+	completion.RegisterPathFlags(rootCmd)
+	classifyUsageErrors(rootCmd)
+
 	return rootCmd
 }
 
+// applyOutputFlag validates --output, maps it onto the --json flag read by subcommands,
+// sets up the template of -o go-template=<template>, and streams bulk results for -o ndjson
+// and their identifiers for -o porcelain.
+func applyOutputFlag(cmd *cobra.Command, cfg *appConfig) error {
+	format, tmpl, err := output.ParseOutputFlag(cfg.output)
+	if err != nil {
+		return err
+	}
+	switch {
+	case cfg.output == "":
+		return nil
+	case format == output.OutputFormatJSON:
+		return cmd.Root().PersistentFlags().Set("json", "true")
+	case cfg.json:
+		return fmt.Errorf("--output %s conflicts with --json", format)
+	case format == output.OutputFormatNDJSON:
+		bulk.SetStream(os.Stdout)
+	case format == output.OutputFormatPorcelain:
+		bulk.SetPorcelain(os.Stdout)
+	}
+	output.SetOutputTemplate(tmpl)
+	return nil
+}
+
+// applyTimeout bounds the context of the command by --timeout.
+func applyTimeout(cmd *cobra.Command, cfg *appConfig) error {
+	switch {
+	case cfg.timeout < 0:
+		return errors.New("--timeout must not be negative")
+	case cfg.timeout == 0:
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.timeout)
+	cfg.cancelTimeout = cancel
+	cfg.deadline, _ = ctx.Deadline()
+	cmd.SetContext(ctx)
+	return nil
+}
+
+// applyTimeZone sets the time zone times print in from --timezone or --utc.
+func applyTimeZone(cfg *appConfig) error {
+	name := cfg.timeZone
+	if cfg.utc {
+		if name != "" && name != "UTC" {
+			return errors.New("--utc and --timezone cannot be used together")
+		}
+		name = "UTC"
+	}
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid --timezone %q: %w", name, err)
+	}
+	output.SetTimeZone(loc)
+	return nil
+}
+
+// applyRetryPolicy sets how failed requests are retried from --retries and --retry-delay.
+func applyRetryPolicy(cfg *appConfig) error {
+	switch {
+	case cfg.retries < 0:
+		return errors.New("--retries must not be negative")
+	case cfg.retryDelay < 0:
+		return errors.New("--retry-delay must not be negative")
+	}
+	retry.SetPolicy(retry.Policy{Retries: cfg.retries, Delay: cfg.retryDelay})
+	return nil
+}
+
+// applyPageConcurrency bounds the pages of lists fetched in parallel by the --max-concurrency of the
+// command, if it has one, so lists respect the same bound as the operations run on their items.
+func applyPageConcurrency(cmd *cobra.Command) {
+	if maxConcurrency, err := cmd.Flags().GetInt("max-concurrency"); err == nil {
+		api.SetPageConcurrency(maxConcurrency)
+	}
+}
+
+// applyConnectionSettings sets how the panel is connected to: the proxy of the profile of
+// PELICANCTL_PROFILE, and the TLS and proxy flags over the config file.
+func applyConnectionSettings(cfg *appConfig, loaded *config.Config) {
+	if profile, ok := loaded.Profile(auth.Profile()); ok && profile.ProxyURL != "" {
+		loaded.API.ProxyURL = profile.ProxyURL
+	}
+	if cfg.proxy != "" {
+		loaded.API.ProxyURL = cfg.proxy
+	}
+	applyTLSFlags(cfg, loaded)
+}
+
+// applyTLSFlags overrides api.tls of the config file with --ca-cert, --client-cert, --client-key
+// and --insecure-skip-tls-verify.
+func applyTLSFlags(cfg *appConfig, loaded *config.Config) {
+	if cfg.tls.CACert != "" {
+		loaded.API.TLS.CACert = cfg.tls.CACert
+	}
+	if cfg.tls.ClientCert != "" {
+		loaded.API.TLS.ClientCert = cfg.tls.ClientCert
+	}
+	if cfg.tls.ClientKey != "" {
+		loaded.API.TLS.ClientKey = cfg.tls.ClientKey
+	}
+	if cfg.tls.InsecureSkipVerify {
+		loaded.API.TLS.InsecureSkipVerify = true
+	}
+}
+
+// applyIdentityPolicy sets the server identifier shown in tables from --identity or the config file.
+func applyIdentityPolicy(cfg *appConfig, loaded *config.Config) error {
+	name := cfg.identity
+	if name == "" {
+		name = loaded.Output.Identity
+	}
+	if name == "" {
+		return nil
+	}
+
+	policy, err := output.ParseIdentityPolicy(name)
+	if err != nil {
+		return err
+	}
+	output.SetIdentityPolicy(policy)
+	return nil
+}
+
+// applyTableStyle sets how tables are drawn from output.table_style in the config file.
+func applyTableStyle(loaded *config.Config) error {
+	if loaded.Output.TableStyle == "" {
+		return nil
+	}
+	style, err := output.ParseTableStyle(loaded.Output.TableStyle)
+	if err != nil {
+		return err
+	}
+	output.SetTableStyle(style)
+	return nil
+}
+
 func main() {
 	cfg := &appConfig{}
 	rootCmd := setupRootCmd(cfg)
 
-	if err := rootCmd.Execute(); err != nil {
-		if cfg.json {
-			// Output error as JSON when --json flag is set
+	// Unknown commands run the plugin of their name, if there is one on PATH.
+	if path, args, flags, ok := findPlugin(rootCmd, os.Args[1:]); ok {
+		os.Exit(runPlugin(path, args, flags))
+	}
+
+	// Ctrl-C cancels the context of the command, so requests in flight are aborted and bulk runs
+	// start no further operations. Once it is canceled signals are handled as usual again, so a
+	// second Ctrl-C ends the program at once.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	interrupted := ctx.Err() != nil
+	stop()
+	if cfg.cancelTimeout != nil {
+		cfg.cancelTimeout()
+	}
+	if err == nil {
+		err = strict.Err()
+	}
+	err = classifyCancellation(err, interrupted, cfg)
+	_ = progress.Close()
+	reportTiming(cfg)
+	runPostHook(cfg, cmd, err)
+	notifyWebhooks(cfg, cmd, err)
+	if err != nil {
+		if cfg.json || cfg.output == string(output.OutputFormatNDJSON) {
+			// Output the error envelope as JSON when --json flag is set
 			formatter := output.NewFormatter(output.OutputFormatJSON, os.Stderr)
-			formatter.PrintError("%v", err)
+			_ = formatter.Print(apierrors.Envelope(err))
 		} else {
 			// Output error as plain text
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
-		os.Exit(1)
+		os.Exit(apierrors.ExitCode(err))
+	}
+}
+
+// classifyCancellation gives a command that failed once interrupted or out of time the exit code
+// of the interruption, since the errors of bulk runs do not carry the errors of their operations.
+func classifyCancellation(err error, interrupted bool, cfg *appConfig) error {
+	switch {
+	case err == nil:
+		return nil
+	case interrupted:
+		return apierrors.WithExitCode(apierrors.ExitCanceled, err)
+	case !cfg.deadline.IsZero() && !time.Now().Before(cfg.deadline):
+		return apierrors.WithExitCode(apierrors.ExitTimeout, err)
+	}
+	return err
+}
+
+// classifyUsageErrors gives invalid flags and arguments of every command the validation exit code.
+func classifyUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return apierrors.WithExitCode(apierrors.ExitValidation, err)
+	})
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(c *cobra.Command, a []string) error {
+			return apierrors.WithExitCode(apierrors.ExitValidation, args(c, a))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		classifyUsageErrors(sub)
 	}
 }
 
@@ -127,18 +496,23 @@ func newAuthCmd(cfg *appConfig) *cobra.Command {
 
 	loginCmd := &cobra.Command{
 		Use:   "login [client|admin]",
-		Short: "Login interactively and save token",
-		Long:  "Prompts for an API token and saves it to the system keyring",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		Short: "Login and save token",
+		Long: "Prompts for an API token and saves it to the system keyring. Without a terminal, as in CI jobs " +
+			"and Ansible, the token is read from --token, --token-file or stdin, and the API URL from --url.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			apiType := args[0]
 			if apiType != "client" && apiType != "admin" {
 				return fmt.Errorf("invalid API type: %s (must be 'client' or 'admin')", apiType)
 			}
 
-			return authLogin(apiType, cfg)
+			return authLogin(cmd, apiType, cfg)
 		},
 	}
+	loginCmd.Flags().String("token", "", "API token to save (visible in the process list; prefer --token-file)")
+	loginCmd.Flags().String("token-file", "", "read the API token from this file, or from stdin for \"-\"")
+	loginCmd.Flags().String("url", "", "API base URL of the panel to save (e.g. https://panel.example.com)")
+	loginCmd.MarkFlagsMutuallyExclusive("token", "token-file")
 	loginCmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"client", "admin"}, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -161,9 +535,14 @@ func newAuthCmd(cfg *appConfig) *cobra.Command {
 		return []string{"client", "admin"}, cobra.ShellCompDirectiveNoFileComp
 	}
 
+	statusCmd := newAuthStatusCmd()
+	testCmd := newAuthTestCmd()
+
 	// Add subcommands FIRST (matching carapace example pattern)
 	cmd.AddCommand(loginCmd)
 	cmd.AddCommand(logoutCmd)
+	cmd.AddCommand(statusCmd)
+	cmd.AddCommand(testCmd)
 
 	// Set up carapace completion AFTER adding to parent (matching carapace example pattern)
 	// Using direct ActionValues (no ActionCallback) to test basic functionality
@@ -173,11 +552,12 @@ func newAuthCmd(cfg *appConfig) *cobra.Command {
 	carapace.Gen(logoutCmd).PositionalCompletion(
 		carapace.ActionValues("client", "admin"),
 	)
+	setupAuthTestCompletion(testCmd)
 
 	return cmd
 }
 
-func authLogin(apiType string, cfg *appConfig) error {
+func authLogin(cmd *cobra.Command, apiType string, cfg *appConfig) error {
 	appCfg := config.Get()
 	if appCfg == nil {
 		_, err := config.Load(cfg.configPath)
@@ -196,7 +576,8 @@ func authLogin(apiType string, cfg *appConfig) error {
 	formatter := output.NewFormatter(format, os.Stdout)
 
 	// Only prompt for API URL if it's not already configured
-	// Check both config and environment variable
+	// Check --url, config and environment variable
+	apiURL, _ := cmd.Flags().GetString("url")
 	currentURL := appCfg.API.BaseURL
 	if currentURL == "" {
 		// Check environment variable
@@ -206,25 +587,31 @@ func authLogin(apiType string, cfg *appConfig) error {
 	}
 
 	// If still empty, prompt for it
-	if currentURL == "" {
-		apiURL, err := auth.PromptAPIURL("")
-		if err != nil {
+	if apiURL == "" && currentURL == "" {
+		if !auth.StdinIsTerminal() {
+			return apierrors.WithExitCode(apierrors.ExitValidation,
+				errors.New("no API base URL configured; give it with --url"))
+		}
+		var err error
+		if apiURL, err = auth.PromptAPIURL(""); err != nil {
 			return fmt.Errorf("failed to get API URL: %w", err)
 		}
+	}
+
+	// Read the token before saving anything, so a token that cannot be read leaves the config untouched
+	token, err := loginToken(cmd, apiType)
+	if err != nil {
+		return err
+	}
 
-		// Save API URL to config
+	// Save API URL to config
+	if apiURL != "" {
 		if setErr := auth.SetAPIURL(apiURL); setErr != nil {
 			formatter.PrintError("Failed to save API URL: %v", setErr)
 			return setErr
 		}
 	}
 
-	// Prompt for token
-	token, err := auth.PromptToken(apiType)
-	if err != nil {
-		return err
-	}
-
 	if setErr := auth.SetToken(apiType, token); setErr != nil {
 		formatter.PrintError("Failed to save token: %v", setErr)
 		return setErr
@@ -234,6 +621,30 @@ func authLogin(apiType string, cfg *appConfig) error {
 	return nil
 }
 
+// loginToken returns the token to log in with: from --token or --token-file, piped to stdin,
+// or else prompted for.
+func loginToken(cmd *cobra.Command, apiType string) (string, error) {
+	if cmd.Flags().Changed("token") {
+		token, _ := cmd.Flags().GetString("token")
+		if token = strings.TrimSpace(token); token == "" {
+			return "", apierrors.WithExitCode(apierrors.ExitValidation, errors.New("--token cannot be empty"))
+		}
+		return token, nil
+	}
+	path, _ := cmd.Flags().GetString("token-file")
+	if path == "" && !auth.StdinIsTerminal() {
+		path = "-"
+	}
+	if path != "" {
+		token, err := auth.ReadToken(path)
+		if err != nil {
+			return "", apierrors.WithExitCode(apierrors.ExitValidation, err)
+		}
+		return token, nil
+	}
+	return auth.PromptToken(apiType)
+}
+
 func authLogout(apiType string, cfg *appConfig) error {
 	appCfg := config.Get()
 	if appCfg == nil {
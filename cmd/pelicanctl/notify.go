@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/notify"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/timing"
+)
+
+// applyNotifySettings sets the webhooks notified once the command is done from --notify-webhook and
+// the notify section of the config file.
+func applyNotifySettings(cfg *appConfig, loaded *config.Config) error {
+	type setting struct {
+		name, url string
+		kind      notify.Kind
+	}
+	var settings []setting
+	for _, url := range cfg.notifyURLs {
+		settings = append(settings, setting{name: "--notify-webhook", url: url})
+	}
+	for _, url := range loaded.Notify.Webhooks {
+		settings = append(settings, setting{name: "notify.webhooks", url: url})
+	}
+	if loaded.Notify.Discord != "" {
+		settings = append(settings, setting{name: "notify.discord", url: loaded.Notify.Discord, kind: notify.KindDiscord})
+	}
+	if loaded.Notify.Slack != "" {
+		settings = append(settings, setting{name: "notify.slack", url: loaded.Notify.Slack, kind: notify.KindSlack})
+	}
+
+	cfg.webhooks = nil
+	for _, s := range settings {
+		webhook, err := notify.New(s.url, s.kind)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		cfg.webhooks = append(cfg.webhooks, webhook)
+	}
+	return nil
+}
+
+// notifyWebhooks posts a summary of the bulk runs of the command to the webhooks, if it ran any.
+// A webhook that cannot be notified is logged, without failing the command.
+func notifyWebhooks(cfg *appConfig, cmd *cobra.Command, err error) {
+	results := bulk.Finished()
+	if len(cfg.webhooks) == 0 || len(results) == 0 || cmd == nil {
+		return
+	}
+
+	report := notify.Report{
+		Command:  cmd.CommandPath(),
+		Results:  results,
+		Duration: timing.Elapsed(),
+		Err:      err,
+	}
+	// The context of the command may be over by now, e.g. after --timeout; sending has its own timeout.
+	for _, webhook := range cfg.webhooks {
+		if sendErr := notify.Send(context.Background(), webhook, report); sendErr != nil {
+			output.LogWarn("failed to notify webhook", "kind", webhook.Kind, "error", sendErr)
+		}
+	}
+}
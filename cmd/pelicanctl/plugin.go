@@ -0,0 +1,266 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"go.lostcrafters.com/pelicanctl/internal/auth"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
+)
+
+// pluginPrefix starts the names of plugin executables: pelicanctl-foo on PATH runs as pelicanctl foo.
+const pluginPrefix = "pelicanctl-"
+
+// binEnvVar passes plugins the path of the pelicanctl executable that ran them, to call back into.
+const binEnvVar = "PELICANCTL_BIN"
+
+// findPlugin returns the plugin executable an unknown command names, the arguments to pass it and the
+// global flags given before its name. Like kubectl, the longest run of leading words naming a
+// pelicanctl-<word>-<word> executable on PATH wins, so pelicanctl foo bar runs pelicanctl-foo-bar if it
+// exists, or else pelicanctl-foo with bar. Commands of pelicanctl itself always take precedence over plugins.
+func findPlugin(rootCmd *cobra.Command, args []string) (string, []string, *pflag.FlagSet, bool) {
+	flags := pluginFlags(rootCmd)
+	if err := flags.Parse(args); err != nil {
+		return "", nil, nil, false
+	}
+	args = flags.Args()
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", nil, nil, false
+	}
+	if _, _, err := rootCmd.Find(args); err == nil {
+		return "", nil, nil, false
+	}
+
+	var words []string
+	for _, arg := range args {
+		// Words are part of a file name, never a path of their own.
+		if strings.HasPrefix(arg, "-") || strings.ContainsAny(arg, `/\`) {
+			break
+		}
+		words = append(words, arg)
+	}
+	for n := len(words); n > 0; n-- {
+		path, err := exec.LookPath(pluginPrefix + strings.Join(words[:n], "-"))
+		if err == nil {
+			return path, args[n:], flags, true
+		}
+	}
+	return "", nil, nil, false
+}
+
+// pluginFlags returns a flag set that parses the global flags of rootCmd up to the first other argument,
+// such as --config in pelicanctl --config prod.yaml foo. The flags are parsed into values of their own,
+// so the root command parses them afresh if no plugin runs.
+func pluginFlags(rootCmd *cobra.Command) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(rootCmd.Name(), pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.SetInterspersed(false)
+	rootCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Value.Type() == "bool" {
+			flags.BoolP(flag.Name, flag.Shorthand, false, flag.Usage)
+		} else {
+			flags.StringP(flag.Name, flag.Shorthand, "", flag.Usage)
+		}
+	})
+	return flags
+}
+
+// runPlugin runs a plugin with the terminal of pelicanctl and returns its exit code. Signals are passed
+// on to the plugin, which decides when to exit. Of the global flags given before the plugin name, --config
+// and --strict apply to the settings passed to the plugin.
+func runPlugin(path string, args []string, flags *pflag.FlagSet) int {
+	configPath, _ := flags.GetString("config")
+	if strictFlag, _ := flags.GetBool("strict"); strictFlag {
+		strict.Enable()
+	}
+	plugin := exec.Command(path, args...) //nolint:gosec // Running the plugin the user named is the point
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	plugin.Env = pluginEnv(configPath)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := plugin.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to run plugin %s: %v\n", path, err)
+		return 1
+	}
+	go func() {
+		for sig := range signals {
+			// Windows cannot send signals; the plugin gets the console's Ctrl-C itself.
+			_ = plugin.Process.Signal(sig)
+		}
+	}()
+
+	err := plugin.Wait()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		return exitErr.ExitCode()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: plugin %s failed: %v\n", path, err)
+		return 1
+	}
+}
+
+// pluginEnv returns the environment of plugins: that of pelicanctl with the config file, profile, API base
+// URL and tokens it would use itself, so plugins need no configuration of their own. Tokens are left out
+// when reading them would ask for the passphrase of the token file, so running a plugin never prompts;
+// the plugin gets them by calling back into pelicanctl, or from PELICANCTL_TOKEN_PASSPHRASE.
+func pluginEnv(configPath string) []string {
+	env := append(os.Environ(), output.RequestIDEnvVar+"="+output.RequestID())
+	if bin, err := os.Executable(); err == nil {
+		env = append(env, binEnvVar+"="+bin)
+	}
+
+	// A plugin that doesn't talk to the panel works without a readable config.
+	loaded, err := config.Load(configPath)
+	if err != nil {
+		return env
+	}
+	if file := config.FileUsed(); file != "" {
+		env = append(env, config.PathEnvVar+"="+file)
+	}
+	if profile := auth.Profile(); profile != "" {
+		env = append(env, auth.ProfileEnvVar+"="+profile)
+	}
+	if loaded.API.BaseURL != "" {
+		env = append(env, "PELICANCTL_API_BASE_URL="+loaded.API.BaseURL)
+	}
+	if auth.PassphraseNeeded() {
+		return env
+	}
+	for _, apiType := range []string{"client", "admin"} {
+		if token, _, lookupErr := auth.LookupToken(apiType); lookupErr == nil && token != "" {
+			env = append(env, auth.TokenEnvVar(apiType)+"="+token)
+		}
+	}
+	return env
+}
+
+// newPluginCmd creates the plugin command, which lists the plugins on PATH.
+func newPluginCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage plugins",
+		Long: "Plugins are executables named pelicanctl-<name> on PATH, run as pelicanctl <name> with the " +
+			"remaining arguments. Dashes in the name nest commands: pelicanctl-backup-prune runs as " +
+			"pelicanctl backup prune. Plugins get the config file, API base URL and tokens of pelicanctl in " +
+			"PELICANCTL_CONFIG, PELICANCTL_API_BASE_URL, PELICANCTL_CLIENT_TOKEN and PELICANCTL_ADMIN_TOKEN, " +
+			"and the pelicanctl executable in PELICANCTL_BIN. Global flags such as --config go before the " +
+			"plugin name.",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the plugins on PATH",
+		Long: "List the plugin executables on PATH. A plugin hidden by one of the same name earlier on PATH, " +
+			"or by a command of pelicanctl, is listed with a warning and never runs.",
+		Args: cobra.NoArgs,
+		RunE: runPluginList,
+	}
+
+	cmd.AddCommand(listCmd)
+	return cmd
+}
+
+// pluginInfo is a plugin executable found on PATH.
+type pluginInfo struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Warning string `json:"warning,omitempty"`
+}
+
+func runPluginList(cmd *cobra.Command, _ []string) error {
+	plugins := listPlugins(cmd.Root())
+	format := getOutputFormat(cmd)
+	formatter := output.NewFormatter(format, os.Stdout)
+	if format.IsJSON() {
+		return formatter.Print(plugins)
+	}
+	if len(plugins) == 0 {
+		formatter.PrintInfo("No plugins found on PATH")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		rows = append(rows, []string{plugin.Name, plugin.Path, plugin.Warning})
+	}
+	return formatter.PrintTable([]string{"NAME", "PATH", "WARNING"}, rows)
+}
+
+// listPlugins finds the plugin executables on PATH, in the order of PATH.
+func listPlugins(rootCmd *cobra.Command) []pluginInfo {
+	plugins := []pluginInfo{}
+	seen := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		for _, entry := range entries {
+			name, ok := pluginName(dir, entry)
+			if !ok {
+				continue
+			}
+			plugin := pluginInfo{Name: name, Path: filepath.Join(dir, entry.Name())}
+			words := strings.Fields(name)
+			if first, hidden := seen[name]; hidden {
+				plugin.Warning = "hidden by " + first
+			} else {
+				seen[name] = plugin.Path
+				if found, _, findErr := rootCmd.Find(words); findErr == nil && found != rootCmd {
+					plugin.Warning = "hidden by the command " + found.CommandPath()
+				}
+			}
+			plugins = append(plugins, plugin)
+		}
+	}
+	return plugins
+}
+
+// pluginName returns the command a plugin executable runs as, e.g. "backup prune" for pelicanctl-backup-prune.
+func pluginName(dir string, entry os.DirEntry) (string, bool) {
+	file := entry.Name()
+	if !strings.HasPrefix(file, pluginPrefix) || entry.IsDir() {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(file)
+		if !strings.EqualFold(ext, ".exe") && !strings.EqualFold(ext, ".bat") && !strings.EqualFold(ext, ".cmd") {
+			return "", false
+		}
+		file = strings.TrimSuffix(file, ext)
+	} else if info, err := os.Stat(filepath.Join(dir, file)); err != nil || !info.Mode().IsRegular() ||
+		info.Mode().Perm()&0o111 == 0 {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, pluginPrefix)
+	if name == "" {
+		return "", false
+	}
+	return strings.ReplaceAll(name, "-", " "), true
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/timing"
+)
+
+// slowestCallsLimit is how many calls and endpoints the --verbose timing report lists.
+const slowestCallsLimit = 5
+
+// reportTiming stores the API calls of this session for `pelicanctl stats` and,
+// with --verbose, prints a timing summary to stderr.
+func reportTiming(cfg *appConfig) {
+	calls := timing.Calls()
+	if len(calls) == 0 {
+		return
+	}
+
+	if err := timing.Save(calls); err != nil {
+		output.LogDebug("failed to save timing history", "error", err)
+	}
+	if cfg.verbose {
+		timing.Report(os.Stderr, calls, timing.Elapsed(), slowestCallsLimit)
+	}
+}
+
+// newStatsCmd creates the stats command.
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show API latency from recent commands",
+		Long: "Show per-endpoint API latency recorded by recent pelicanctl commands, slowest first. " +
+			"Use it to tell whether sluggishness comes from the panel, a node, or local overhead. " +
+			"Run any command with --verbose to see the timing of that command alone.",
+		Args: cobra.NoArgs,
+		RunE: runStats,
+	}
+	const defaultStatsLimit = 20
+	cmd.Flags().Int("limit", defaultStatsLimit, "maximum number of endpoints to show (0 for all)")
+	cmd.Flags().Bool("reset", false, "clear the recorded history")
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, _ []string) error {
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	format := output.OutputFormatTable
+	if jsonFlag {
+		format = output.OutputFormatJSON
+	}
+	formatter := output.NewFormatter(format, os.Stdout)
+
+	if reset, _ := cmd.Flags().GetBool("reset"); reset {
+		if err := timing.Reset(); err != nil {
+			return err
+		}
+		formatter.PrintSuccess("Timing history cleared")
+		return nil
+	}
+
+	history, err := timing.Load()
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		formatter.PrintInfo("No API calls recorded yet")
+		return nil
+	}
+
+	endpoints := timing.Aggregate(history)
+	if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 && len(endpoints) > limit {
+		endpoints = endpoints[:limit]
+	}
+
+	if format == output.OutputFormatJSON {
+		return formatter.Print(endpoints)
+	}
+
+	headers := []string{"Calls", "Errors", "Avg", "Max", "Total", "Method", "Host", "Endpoint"}
+	rows := make([][]string, 0, len(endpoints))
+	for _, stats := range endpoints {
+		rows = append(rows, []string{
+			strconv.Itoa(stats.Count),
+			strconv.Itoa(stats.Errors),
+			formatDuration(stats.Average()),
+			formatDuration(stats.Max),
+			formatDuration(stats.Total),
+			stats.Method,
+			stats.Host,
+			stats.Endpoint,
+		})
+	}
+	if err := formatter.PrintTable(headers, rows); err != nil {
+		return err
+	}
+	formatter.PrintInfo("%d call(s) recorded across recent commands", len(history))
+	return nil
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// serverStatus is the consolidated view of one server. Sections that could not be fetched
+// are left empty and their errors collected in Errors.
+type serverStatus struct {
+	Server    map[string]any    `json:"server,omitempty"`
+	Resources map[string]any    `json:"resources,omitempty"`
+	Health    map[string]any    `json:"health,omitempty"`
+	Backups   *backupSummary    `json:"backups,omitempty"`
+	Schedules *scheduleSummary  `json:"schedules,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+type backupSummary struct {
+	Count  int            `json:"count"`
+	Latest map[string]any `json:"latest,omitempty"`
+	// LatestAge is the age of the latest backup in seconds.
+	LatestAge *int64 `json:"latest_age_seconds,omitempty"`
+}
+
+type scheduleSummary struct {
+	Count      int            `json:"count"`
+	Active     int            `json:"active"`
+	Processing int            `json:"processing"`
+	Next       map[string]any `json:"next,omitempty"`
+}
+
+// newStatusCmd creates the status command.
+func newStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status <id|uuid|name>",
+		Short: "Show everything about a server at a glance",
+		Long: "Show a consolidated view of a server: its details, live resource usage, health, " +
+			"the age of its latest backup, and a summary of its schedules. " +
+			"Health needs admin credentials and is skipped without them. " +
+			"Sections that fail are reported without failing the others.",
+		Args: cobra.ExactArgs(1),
+		RunE: runStatus,
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			completions, err := completion.CompleteServers("client", toComplete)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	return cmd
+}
+
+func setupStatusCompletion(cmd *cobra.Command) {
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+		completions, err := completion.CompleteServers("client", c.Value)
+		if err != nil || len(completions) == 0 {
+			return carapace.ActionValues()
+		}
+		return completion.Action(completions)
+	}))
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	identifier := args[0]
+
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	format := output.OutputFormatTable
+	if jsonFlag {
+		format = output.OutputFormatJSON
+	}
+	formatter := output.NewFormatter(format, os.Stdout)
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	// Resolve once so the sections don't each look the server up.
+	uuid, err := client.ResolveServerUUID(ctx, identifier)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+
+	status := collectStatus(ctx, client, uuid)
+	if status.Server == nil {
+		return fmt.Errorf("server %s: %s", output.ServerLabel(identifier), status.Errors["server"])
+	}
+
+	if format == output.OutputFormatJSON {
+		return formatter.Print(status)
+	}
+	return printStatus(formatter, status)
+}
+
+// collectStatus fetches the sections of a server status in parallel.
+func collectStatus(ctx context.Context, client *api.ClientAPI, uuid string) serverStatus {
+	status := serverStatus{Errors: map[string]string{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	fetch := func(section string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				status.Errors[section] = apierrors.HandleError(err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	fetch("server", func() error {
+		server, err := client.GetServer(ctx, uuid)
+		mu.Lock()
+		status.Server = attributesOf(server)
+		mu.Unlock()
+		return err
+	})
+	fetch("resources", func() error {
+		resources, err := client.GetServerResources(ctx, uuid)
+		mu.Lock()
+		status.Resources = attributesOf(resources)
+		mu.Unlock()
+		return err
+	})
+	fetch("health", func() error {
+		admin, err := api.NewApplicationAPI()
+		if err != nil {
+			// Without admin credentials there is no health section.
+			return nil //nolint:nilerr // health is optional
+		}
+		health, err := admin.GetServerHealth(ctx, uuid, nil, nil)
+		mu.Lock()
+		status.Health = health
+		mu.Unlock()
+		return err
+	})
+	fetch("backups", func() error {
+		backups, err := client.ListBackups(ctx, uuid)
+		if err != nil {
+			return err
+		}
+		summary := summarizeBackups(backups)
+		mu.Lock()
+		status.Backups = &summary
+		mu.Unlock()
+		return nil
+	})
+	fetch("schedules", func() error {
+		schedules, err := client.ListSchedules(ctx, uuid)
+		if err != nil {
+			return err
+		}
+		summary := summarizeSchedules(schedules)
+		mu.Lock()
+		status.Schedules = &summary
+		mu.Unlock()
+		return nil
+	})
+
+	wg.Wait()
+	return status
+}
+
+// summarizeBackups counts the backups and finds the latest completed one.
+func summarizeBackups(backups []map[string]any) backupSummary {
+	summary := backupSummary{Count: len(backups)}
+	var latestAt time.Time
+	for _, backup := range backups {
+		attrs := attributesOf(backup)
+		completedAt, ok := parseTimestamp(attrs["completed_at"])
+		if !ok || completedAt.Before(latestAt) {
+			continue
+		}
+		latestAt = completedAt
+		summary.Latest = attrs
+	}
+	if summary.Latest != nil {
+		age := int64(time.Since(latestAt).Seconds())
+		summary.LatestAge = &age
+	}
+	return summary
+}
+
+// summarizeSchedules counts the schedules and finds the next one to run.
+func summarizeSchedules(schedules []map[string]any) scheduleSummary {
+	summary := scheduleSummary{Count: len(schedules)}
+	var nextAt time.Time
+	for _, schedule := range schedules {
+		attrs := attributesOf(schedule)
+		if processing, _ := attrs["is_processing"].(bool); processing {
+			summary.Processing++
+		}
+		if active, _ := attrs["is_active"].(bool); !active {
+			continue
+		}
+		summary.Active++
+		runAt, ok := parseTimestamp(attrs["next_run_at"])
+		if !ok || (summary.Next != nil && !runAt.Before(nextAt)) {
+			continue
+		}
+		nextAt = runAt
+		summary.Next = attrs
+	}
+	return summary
+}
+
+func printStatus(formatter *output.Formatter, status serverStatus) error {
+	server := status.Server
+	name, _ := server["name"].(string)
+	formatter.PrintInfo("Server %s", name)
+	rows := [][]string{
+		{"UUID", statusValue(server["uuid"])},
+		{"Node", statusValue(server["node"])},
+		{"Status", statusValue(server["status"])},
+		{"Suspended", statusValue(server["is_suspended"])},
+	}
+	if limits, ok := server["limits"].(map[string]any); ok {
+		rows = append(rows,
+			[]string{"Memory Limit", mebibytes(limits["memory"])},
+			[]string{"Disk Limit", mebibytes(limits["disk"])},
+			[]string{"CPU Limit", output.HumanPercent(limits["cpu"])},
+		)
+	}
+	if err := formatter.PrintTable([]string{"Field", "Value"}, rows); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		title string
+		key   string
+		rows  func() [][]string
+	}{
+		{"Resources", "resources", func() [][]string { return resourceRows(status.Resources) }},
+		{"Health", "health", func() [][]string { return healthRows(formatter, status.Health) }},
+		{"Backups", "backups", func() [][]string { return backupRows(formatter, status.Backups) }},
+		{"Schedules", "schedules", func() [][]string { return scheduleRows(formatter, status.Schedules) }},
+	}
+	for _, section := range sections {
+		msg, failed := status.Errors[section.key]
+		if section.key == "health" && status.Health == nil && !failed {
+			continue
+		}
+		formatter.PrintInfo("%s", section.title)
+		if failed {
+			formatter.PrintWarning("%s unavailable: %s", section.title, msg)
+			continue
+		}
+		if err := formatter.PrintTable([]string{"Field", "Value"}, section.rows()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceRows(resources map[string]any) [][]string {
+	usage, _ := resources["resources"].(map[string]any)
+	return [][]string{
+		{"State", statusValue(resources["current_state"])},
+		{"CPU", output.HumanPercent(usage["cpu_absolute"])},
+		{"Memory", output.HumanBytes(usage["memory_bytes"])},
+		{"Disk", output.HumanBytes(usage["disk_bytes"])},
+		{"Network In", output.HumanBytes(usage["network_rx_bytes"])},
+		{"Network Out", output.HumanBytes(usage["network_tx_bytes"])},
+		{"Uptime", output.HumanMillis(usage["uptime"])},
+	}
+}
+
+func healthRows(formatter *output.Formatter, health map[string]any) [][]string {
+	container, _ := health["container"].(map[string]any)
+	return [][]string{
+		{"Container", statusValue(container["status"])},
+		{"Healthy", statusValue(container["healthy"])},
+		{"Crashed", statusValue(health["crashed"])},
+		{"Checked At", formatter.ExactTime(health["checked_at"])},
+	}
+}
+
+func backupRows(formatter *output.Formatter, summary *backupSummary) [][]string {
+	rows := [][]string{{"Count", strconv.Itoa(summary.Count)}}
+	if summary.Latest == nil {
+		return append(rows, []string{"Latest", "none"})
+	}
+	return append(rows,
+		[]string{"Latest", statusValue(summary.Latest["name"])},
+		[]string{"Completed At", formatter.ExactTime(summary.Latest["completed_at"])},
+		[]string{"Age", output.HumanDuration(time.Duration(*summary.LatestAge) * time.Second)},
+		[]string{"Successful", statusValue(summary.Latest["is_successful"])},
+		[]string{"Size", output.HumanBytes(summary.Latest["bytes"])},
+	)
+}
+
+func scheduleRows(formatter *output.Formatter, summary *scheduleSummary) [][]string {
+	rows := [][]string{
+		{"Count", strconv.Itoa(summary.Count)},
+		{"Active", strconv.Itoa(summary.Active)},
+		{"Processing", strconv.Itoa(summary.Processing)},
+	}
+	if summary.Next == nil {
+		return append(rows, []string{"Next Run", "none"})
+	}
+	return append(rows, []string{
+		"Next Run",
+		fmt.Sprintf("%s at %s", statusValue(summary.Next["name"]), formatter.ExactTime(summary.Next["next_run_at"])),
+	})
+}
+
+// attributesOf returns the attributes of an API resource, or the resource itself if it is not wrapped.
+func attributesOf(resource map[string]any) map[string]any {
+	if attrs, ok := resource["attributes"].(map[string]any); ok {
+		return attrs
+	}
+	return resource
+}
+
+func parseTimestamp(val any) (time.Time, bool) {
+	s, ok := val.(string)
+	if !ok || s == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, err == nil
+}
+
+func statusValue(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// mebibytes formats a limit given in MiB, where 0 means unlimited.
+func mebibytes(val any) string {
+	if v, ok := val.(float64); ok && v == 0 {
+		return "unlimited"
+	}
+	return output.HumanMiB(val)
+}
@@ -0,0 +1,209 @@
+// Package schedulelocal provides the commands that run pelicanctl commands on cron schedules locally.
+package schedulelocal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/localschedule"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// NewScheduleLocalCmd creates the schedule-local command group.
+func NewScheduleLocalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule-local",
+		Short: "Run pelicanctl commands on cron schedules from this machine",
+		Long: "Run pelicanctl commands, such as backups, restarts and console commands, on cron schedules " +
+			"from a jobs file, for automation driven from a machine you control rather than panel schedules.",
+	}
+
+	cmd.AddCommand(newRunCmd())
+	cmd.AddCommand(newListCmd())
+
+	return cmd
+}
+
+func addFileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("file", "f", "", "jobs file (YAML)")
+	_ = cmd.MarkFlagRequired("file")
+}
+
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run -f <jobs.yaml>",
+		Short: "Run the jobs of a jobs file",
+		Long: "Run every job of a jobs file once, or only those given with --job. With --daemon, keep running " +
+			"and run every job on its schedule until interrupted.\n\n" +
+			"Every run is a new pelicanctl process with the args of the job; its output is prefixed with the " +
+			"job name. A job is not started again while its previous run is still going.",
+		Args: cobra.NoArgs,
+		RunE: runJobs,
+	}
+	addFileFlag(cmd)
+	cmd.Flags().StringSlice("job", nil, "only run these jobs, by name (comma-separated, repeatable)")
+	cmd.Flags().Bool("daemon", false, "keep running and run the jobs on their schedules until interrupted")
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list -f <jobs.yaml>",
+		Short: "List the jobs of a jobs file with their next run",
+		Args:  cobra.NoArgs,
+		RunE:  runListJobs,
+	}
+	addFileFlag(cmd)
+	return cmd
+}
+
+// loadJobs loads the jobs file of --file, keeping the jobs of --job if given.
+func loadJobs(cmd *cobra.Command) ([]localschedule.Job, error) {
+	path, _ := cmd.Flags().GetString("file")
+	jobs, err := localschedule.Load(path)
+	if err != nil {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+
+	names, _ := cmd.Flags().GetStringSlice("job")
+	if len(names) == 0 {
+		return jobs, nil
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(jobs, func(job localschedule.Job) bool { return job.Name == name }) {
+			return nil, apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("job %q not found in %s", name, path))
+		}
+	}
+	return slices.DeleteFunc(jobs, func(job localschedule.Job) bool {
+		return !slices.Contains(names, job.Name)
+	}), nil
+}
+
+// globalArgs returns the flags of this invocation that jobs run with too: the config file, so that jobs
+// use the same panel and credentials.
+func globalArgs(cmd *cobra.Command) []string {
+	if flag := cmd.Root().PersistentFlags().Lookup("config"); flag != nil && flag.Changed {
+		return []string{"--config", flag.Value.String()}
+	}
+	return nil
+}
+
+func runJobs(cmd *cobra.Command, _ []string) error {
+	jobs, err := loadJobs(cmd)
+	if err != nil {
+		return err
+	}
+	daemon, _ := cmd.Flags().GetBool("daemon")
+	if daemon {
+		return runDaemon(cmd, jobs)
+	}
+
+	ctx := cmd.Context()
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+	args := globalArgs(cmd)
+
+	operations := make([]bulk.Operation, len(jobs))
+	for i, job := range jobs {
+		operations[i] = bulk.Operation{
+			ID:   job.Name,
+			Name: job.Name,
+			Exec: func(opCtx context.Context) error {
+				return job.Run(opCtx, args, os.Stdout, os.Stderr)
+			},
+		}
+	}
+	// Jobs run one after the other, in the order of the file, so their output does not interleave.
+	results := bulk.NewExecutor(1, true, false).WithRecord(bulk.KeyedRecord("job")).Execute(ctx, operations)
+
+	summary := bulk.GetSummary(results)
+	if getOutputFormat(cmd).IsJSON() {
+		return bulk.PrintBulkJSONWithKey(formatter, results, summary, false, "job")
+	}
+	for _, result := range results {
+		if result.Success {
+			formatter.PrintSuccess("%s: done", result.Operation.ID)
+		} else {
+			formatter.PrintError("%s: %v", result.Operation.ID, result.Error)
+		}
+	}
+	formatter.PrintInfo("Summary: %s", summary)
+	if summary.Failed > 0 {
+		return apierrors.BulkFailure(fmt.Errorf("%d job(s) failed", summary.Failed), summary.Failed, summary.Total)
+	}
+	return nil
+}
+
+// runDaemon runs the jobs on their schedules until interrupted. Failed runs are reported, not fatal.
+func runDaemon(cmd *cobra.Command, jobs []localschedule.Job) error {
+	ctx := cmd.Context()
+	// Status messages go to stderr, so stdout only carries the output of the jobs.
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stderr)
+	args := globalArgs(cmd)
+
+	formatter.PrintInfo("Running %d job(s) on their schedules until interrupted", len(jobs))
+	return localschedule.Run(ctx, jobs, func(runCtx context.Context, job localschedule.Job) {
+		formatter.PrintInfo("%s: started", job.Name)
+		start := time.Now()
+		if runErr := job.Run(runCtx, args, os.Stdout, os.Stderr); runErr != nil {
+			formatter.PrintError("%s: failed after %s: %v", job.Name, time.Since(start).Round(time.Second), runErr)
+			return
+		}
+		formatter.PrintSuccess("%s: done in %s", job.Name, time.Since(start).Round(time.Second))
+	}, func(job localschedule.Job) {
+		formatter.PrintWarning("%s: skipped, the previous run is still going", job.Name)
+	})
+}
+
+func runListJobs(cmd *cobra.Command, _ []string) error {
+	jobs, err := loadJobs(cmd)
+	if err != nil {
+		return err
+	}
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	now := time.Now()
+	items := make([]map[string]any, 0, len(jobs))
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		next, nextErr := job.Next(now)
+		nextRun := next.Format(time.RFC3339)
+		if nextErr != nil {
+			nextRun = "never"
+		}
+		items = append(items, map[string]any{
+			"name":     job.Name,
+			"schedule": job.Schedule,
+			"next_run": nextRun,
+			"args":     job.Args,
+			"timeout":  job.Timeout,
+		})
+		rows = append(rows, []string{job.Name, job.Schedule, nextRun, strings.Join(job.Args, " ")})
+	}
+
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(items)
+	}
+	return formatter.PrintTable([]string{"Name", "Schedule", "Next Run", "Command"}, rows)
+}
+
+// getOutputFormat gets the output format from command flags.
+func getOutputFormat(cmd *cobra.Command) output.OutputFormat {
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	if jsonFlag {
+		return output.OutputFormatJSON
+	}
+	// --output was validated by the root command, which also set up a go-template.
+	outputFlag, _ := cmd.Root().PersistentFlags().GetString("output")
+	if format, _, err := output.ParseOutputFlag(outputFlag); err == nil {
+		return format
+	}
+	return output.OutputFormatTable
+}
@@ -0,0 +1,379 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/completion"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/gitsync"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// gitSyncOptions holds the flags of the sync git command.
+type gitSyncOptions struct {
+	server  string
+	specs   []gitsync.PathSpec
+	prefix  string
+	message string
+	push    bool
+	repo    gitsync.Repo
+}
+
+// gitSyncResult describes the outcome of one sync run.
+type gitSyncResult struct {
+	Server  string   `json:"server"`
+	Files   int      `json:"files"`
+	Removed []string `json:"removed,omitempty"`
+	Commit  string   `json:"commit,omitempty"`
+	Pushed  bool     `json:"pushed"`
+}
+
+func newGitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "git <server-id|uuid> --paths <paths> [--repo <url>]",
+		Short: "Commit selected server files to a git repository",
+		Long: "Download selected files from a server into a git working copy and commit any changes, " +
+			"giving a versioned history of server configuration.\n\n" +
+			"Paths are comma-separated and relative to the server root: a path ending in '/' is synced " +
+			"recursively, a glob such as 'plugins/*.yml' matches files in that directory, and anything " +
+			"else is a single file. Files removed on the server are removed from the repository too.\n\n" +
+			"With --repo the working copy is cloned from (and pushed to) that remote. " +
+			"Use --interval to keep running and sync on a schedule.",
+		Args: cobra.ExactArgs(1),
+		RunE: runGitSync,
+	}
+	cmd.Flags().StringSlice("paths", nil, "comma-separated server paths to sync (e.g. config/,plugins/*.yml)")
+	cmd.Flags().String("repo", "", "git remote to clone from and push to")
+	cmd.Flags().String("dir", "", "local working copy (default: ./<server>)")
+	cmd.Flags().String("branch", "", "branch to commit to (default: the remote's default branch)")
+	cmd.Flags().String("prefix", "", "directory inside the repository to store the files in")
+	cmd.Flags().String("message", "", "commit message (default: \"Sync <server> files\")")
+	cmd.Flags().Bool("no-push", false, "commit locally without pushing to --repo")
+	cmd.Flags().Duration("interval", 0, "repeat the sync at this interval until interrupted (e.g. 1h)")
+	_ = cmd.MarkFlagRequired("paths")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		completions, err := completion.CompleteServers("client", toComplete)
+		if err != nil || len(completions) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	carapace.Gen(cmd).PositionalCompletion(carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+		completions, err := completion.CompleteServers("client", c.Value)
+		if err != nil || len(completions) == 0 {
+			return carapace.ActionValues()
+		}
+		return completion.Action(completions)
+	}))
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		"dir": carapace.ActionDirectories(),
+	})
+
+	return cmd
+}
+
+func getGitSyncOptions(cmd *cobra.Command, server string) (gitSyncOptions, error) {
+	paths, _ := cmd.Flags().GetStringSlice("paths")
+	repo, _ := cmd.Flags().GetString("repo")
+	dir, _ := cmd.Flags().GetString("dir")
+	branch, _ := cmd.Flags().GetString("branch")
+	prefix, _ := cmd.Flags().GetString("prefix")
+	message, _ := cmd.Flags().GetString("message")
+	noPush, _ := cmd.Flags().GetBool("no-push")
+
+	specs := make([]gitsync.PathSpec, 0, len(paths))
+	for _, p := range paths {
+		spec, err := gitsync.ParsePathSpec(p)
+		if err != nil {
+			return gitSyncOptions{}, err
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return gitSyncOptions{}, errors.New("no paths specified")
+	}
+
+	if dir == "" {
+		dir = server
+	}
+	if message == "" {
+		message = fmt.Sprintf("Sync %s files", server)
+	}
+	prefix = filepath.Clean(prefix)
+	if prefix == "." {
+		prefix = ""
+	}
+	// Pruning and committing only touch the files under the prefix, which must stay inside the
+	// working copy.
+	if prefix != "" && !filepath.IsLocal(prefix) {
+		return gitSyncOptions{}, apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("--prefix must be a relative path inside the repository, got %s", prefix))
+	}
+	// Pruning mirrors remote deletions into everything the specs select; at the root of the
+	// repository that would remove files that are not on the server, like its README or .gitignore.
+	for _, spec := range specs {
+		if spec.IsRoot() && prefix == "" {
+			return gitSyncOptions{}, apierrors.WithExitCode(apierrors.ExitValidation, errors.New(
+				"syncing files in the root directory of the server requires --prefix, "+
+					"so files of the repository that are not on the server are kept"))
+		}
+	}
+
+	return gitSyncOptions{
+		server:  server,
+		specs:   specs,
+		prefix:  prefix,
+		message: message,
+		push:    repo != "" && !noPush,
+		repo:    gitsync.Repo{Dir: dir, Remote: repo, Branch: branch},
+	}, nil
+}
+
+func runGitSync(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	opts, err := getGitSyncOptions(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	interval, _ := cmd.Flags().GetDuration("interval")
+
+	client, err := api.NewClientAPI()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(getOutputFormat(cmd), os.Stdout)
+
+	if interval <= 0 {
+		result, syncErr := syncServerToGit(ctx, client, opts)
+		if syncErr != nil {
+			return syncErr
+		}
+		return printGitSyncResult(cmd, formatter, result)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Scheduled runs keep going after a failed sync; the next tick retries
+		result, syncErr := syncServerToGit(ctx, client, opts)
+		if syncErr != nil {
+			formatter.PrintError("%v", syncErr)
+		} else if printErr := printGitSyncResult(cmd, formatter, result); printErr != nil {
+			return printErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func printGitSyncResult(cmd *cobra.Command, formatter *output.Formatter, result gitSyncResult) error {
+	if getOutputFormat(cmd).IsJSON() {
+		return formatter.Print(result)
+	}
+
+	if result.Commit == "" {
+		formatter.PrintInfo("%s: %d file(s) synced, no changes", result.Server, result.Files)
+		return nil
+	}
+	for _, removed := range result.Removed {
+		formatter.PrintInfo("  - removed %s", removed)
+	}
+	if result.Pushed {
+		formatter.PrintSuccess("%s: %d file(s) synced, committed and pushed %s",
+			result.Server, result.Files, result.Commit)
+	} else {
+		formatter.PrintSuccess("%s: %d file(s) synced, committed %s", result.Server, result.Files, result.Commit)
+	}
+	return nil
+}
+
+// syncServerToGit performs a single sync run.
+func syncServerToGit(ctx context.Context, client *api.ClientAPI, opts gitSyncOptions) (gitSyncResult, error) {
+	result := gitSyncResult{Server: opts.server}
+
+	if err := opts.repo.Prepare(); err != nil {
+		return result, err
+	}
+
+	files, err := collectRemoteFiles(ctx, client, opts.server, opts.specs)
+	if err != nil {
+		return result, apierrors.Handle(err)
+	}
+
+	root := filepath.Join(opts.repo.Dir, opts.prefix)
+	keep := make(map[string]bool, len(files))
+	for _, remotePath := range files {
+		localPath := filepath.Join(root, filepath.FromSlash(remotePath))
+		if err := downloadTo(ctx, client, opts.server, remotePath, localPath); err != nil {
+			return result, fmt.Errorf("failed to sync %s: %w", remotePath, err)
+		}
+		keep[remotePath] = true
+	}
+	result.Files = len(files)
+
+	removed, err := opts.repo.Prune(opts.prefix, opts.specs, keep)
+	if err != nil {
+		return result, err
+	}
+	result.Removed = removed
+
+	commit, err := opts.repo.Commit(opts.prefix, opts.message)
+	if err != nil {
+		return result, err
+	}
+	result.Commit = commit
+
+	if commit != "" && opts.push {
+		if err := opts.repo.Push(); err != nil {
+			return result, err
+		}
+		result.Pushed = true
+	}
+	return result, nil
+}
+
+// collectRemoteFiles lists the server files selected by specs, as paths relative to the server root.
+func collectRemoteFiles(
+	ctx context.Context,
+	client *api.ClientAPI,
+	server string,
+	specs []gitsync.PathSpec,
+) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		var files []string
+		var err error
+		if spec.Recursive {
+			files, err = listRemoteTree(ctx, client, server, spec.Dir)
+		} else {
+			files, err = listRemoteDir(ctx, client, server, spec.Dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if spec.Matches(file) {
+				seen[file] = true
+			}
+		}
+	}
+
+	files := make([]string, 0, len(seen))
+	for file := range seen {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// listRemoteDir lists the files (not directories) directly inside dir.
+func listRemoteDir(ctx context.Context, client *api.ClientAPI, server, dir string) ([]string, error) {
+	files, _, err := listRemoteEntries(ctx, client, server, dir)
+	return files, err
+}
+
+// listRemoteTree lists all files below dir.
+func listRemoteTree(ctx context.Context, client *api.ClientAPI, server, dir string) ([]string, error) {
+	files, dirs, err := listRemoteEntries(ctx, client, server, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range dirs {
+		subFiles, subErr := listRemoteTree(ctx, client, server, sub)
+		if subErr != nil {
+			return nil, subErr
+		}
+		files = append(files, subFiles...)
+	}
+	return files, nil
+}
+
+func listRemoteEntries(ctx context.Context, client *api.ClientAPI, server, dir string) ([]string, []string, error) {
+	if dir == "." {
+		dir = ""
+	}
+	entries, err := client.ListFiles(ctx, server, "/"+dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files, dirs []string
+	for _, entry := range entries {
+		name, _ := fileField(entry, "name").(string)
+		if name == "" {
+			continue
+		}
+		isFile, _ := fileField(entry, "is_file").(bool)
+		if isFile {
+			files = append(files, path.Join(dir, name))
+		} else {
+			dirs = append(dirs, path.Join(dir, name))
+		}
+	}
+	return files, dirs, nil
+}
+
+// fileField reads a file field from the root of the map or from its attributes.
+func fileField(file map[string]any, key string) any {
+	if val, ok := file[key]; ok {
+		return val
+	}
+	if attrs, ok := file["attributes"].(map[string]any); ok {
+		return attrs[key]
+	}
+	return nil
+}
+
+// downloadTo downloads a remote file to localPath, creating parent directories as needed.
+func downloadTo(ctx context.Context, client *api.ClientAPI, server, remotePath, localPath string) error {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	reader, err := client.DownloadFile(ctx, server, "/"+remotePath)
+	if err != nil {
+		return apierrors.Handle(err)
+	}
+	defer reader.Close()
+
+	// Write to a temporary file first so an interrupted download never leaves a truncated file to commit
+	tmp, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, copyErr := io.Copy(tmp, reader); copyErr != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", copyErr)
+	}
+	if closeErr := tmp.Close(); closeErr != nil {
+		return fmt.Errorf("failed to write file: %w", closeErr)
+	}
+	if err := os.Rename(tmp.Name(), localPath); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
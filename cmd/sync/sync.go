@@ -0,0 +1,38 @@
+// Package sync provides commands that mirror server files into external stores.
+//
+//nolint:revive // Package name matches the command name; the stdlib sync package is not used here
+package sync
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// NewSyncCmd creates the sync command group.
+func NewSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Mirror server files into external stores",
+		Long:  "Commands for keeping a versioned copy of server files outside the panel",
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newGitCmd())
+
+	return cmd
+}
+
+// getOutputFormat gets the output format from command flags.
+func getOutputFormat(cmd *cobra.Command) output.OutputFormat {
+	jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json")
+	if jsonFlag {
+		return output.OutputFormatJSON
+	}
+	// --output was validated by the root command, which also set up a go-template.
+	outputFlag, _ := cmd.Root().PersistentFlags().GetString("output")
+	if format, _, err := output.ParseOutputFlag(outputFlag); err == nil {
+		return format
+	}
+	return output.OutputFormatTable
+}
@@ -0,0 +1,152 @@
+// Package annotation keeps local notes on servers that the panel has no field for, such as why and
+// when a server was suspended.
+//
+// Notes are stored in annotations.json next to the config file, keyed by the ID of the server on the panel:
+//
+//	{
+//	  "servers": {
+//	    "12": {"suspension": {"reason": "invoice 1042 unpaid", "suspended_at": "2026-10-01T09:00:00Z"}}
+//	  }
+//	}
+package annotation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+)
+
+// fileName is the name of the annotation store in the config directory.
+const fileName = "annotations.json"
+
+// storeMutex serializes updates of the store by the parallel operations of bulk commands.
+//
+//nolint:gochecknoglobals // Guards the store file within the process
+var storeMutex sync.Mutex
+
+// Server is what is noted on a server.
+type Server struct {
+	// Suspension is set while the server is suspended through pelicanctl.
+	Suspension *Suspension `json:"suspension,omitempty"`
+}
+
+// Suspension notes why and when a server was suspended.
+type Suspension struct {
+	Reason      string    `json:"reason,omitempty"`
+	SuspendedAt time.Time `json:"suspended_at"`
+}
+
+// store is the persisted form of the annotations.
+type store struct {
+	Servers map[string]Server `json:"servers"`
+}
+
+// Path returns the file that stores the annotations.
+func Path() (string, error) {
+	configDir, err := config.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, fileName), nil
+}
+
+// Servers returns the annotations of every annotated server, keyed by server ID.
+func Servers() (map[string]Server, error) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	s, err := read()
+	if err != nil {
+		return nil, err
+	}
+	return s.Servers, nil
+}
+
+// SetSuspension notes the suspension of a server.
+func SetSuspension(serverID string, suspension Suspension) error {
+	return update(serverID, func(server *Server) {
+		server.Suspension = &suspension
+	})
+}
+
+// ClearSuspension forgets the suspension of a server, e.g. once it is unsuspended.
+func ClearSuspension(serverID string) error {
+	return update(serverID, func(server *Server) {
+		server.Suspension = nil
+	})
+}
+
+// update changes the annotations of a server and saves the store. Servers left without annotations are dropped.
+func update(serverID string, change func(*Server)) error {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	s, err := read()
+	if err != nil {
+		return err
+	}
+	server := s.Servers[serverID]
+	change(&server)
+	if server == (Server{}) {
+		delete(s.Servers, serverID)
+	} else {
+		s.Servers[serverID] = server
+	}
+	return save(s)
+}
+
+// read loads the store. A missing store is empty.
+func read() (*store, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	s := &store{}
+	encoded, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read annotations: %w", err)
+	default:
+		if err = json.Unmarshal(encoded, s); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if s.Servers == nil {
+		s.Servers = map[string]Server{}
+	}
+	return s, nil
+}
+
+// save writes the store atomically.
+func save(s *store) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	encoded, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode annotations: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".annotations-*")
+	if err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(encoded); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	return os.Rename(file.Name(), path)
+}
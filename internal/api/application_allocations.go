@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.lostcrafters.com/pelicanctl/internal/application"
+)
+
+// ListNodeAllocations lists the allocations of a node by ID, following every page.
+func (a *ApplicationAPI) ListNodeAllocations(ctx context.Context, nodeID string) ([]map[string]any, error) {
+	nodeIDInt, err := strconv.Atoi(nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node ID: %s (must be an integer)", nodeID)
+	}
+
+	return fetchPages(ListOptions{},
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return a.genClient.ApplicationAllocations(ctx, nodeIDInt, withQuery)
+		},
+		handleApplicationErrorResponse,
+	)
+}
+
+// CreateNodeAllocations creates allocations on a node by ID for an IP address.
+// Each entry of ports is a single port ("25565") or an inclusive range ("25565-25600").
+func (a *ApplicationAPI) CreateNodeAllocations(ctx context.Context, nodeID, ip, alias string, ports []string) error {
+	nodeIDInt, err := strconv.Atoi(nodeID)
+	if err != nil {
+		return fmt.Errorf("invalid node ID: %s (must be an integer)", nodeID)
+	}
+
+	req := application.AllocationStoreJSONRequestBody{Ip: ip, Ports: ports}
+	if alias != "" {
+		req.Alias = &alias
+	}
+
+	return checkApplicationEmptyResponse(a.genClient.AllocationStore(ctx, nodeIDInt, req))
+}
+
+// DeleteNodeAllocation deletes an unassigned allocation of a node by ID.
+func (a *ApplicationAPI) DeleteNodeAllocation(ctx context.Context, nodeID, allocationID string) error {
+	nodeIDInt, err := strconv.Atoi(nodeID)
+	if err != nil {
+		return fmt.Errorf("invalid node ID: %s (must be an integer)", nodeID)
+	}
+	allocationIDInt, err := strconv.Atoi(allocationID)
+	if err != nil {
+		return fmt.Errorf("invalid allocation ID: %s (must be an integer)", allocationID)
+	}
+
+	// The generated client has no delete operation for allocations; the endpoint shares
+	// its path with the view operation, so send that request as a DELETE.
+	asDelete := func(_ context.Context, req *http.Request) error {
+		req.Method = http.MethodDelete
+		return nil
+	}
+	return checkApplicationEmptyResponse(
+		a.genClient.ApplicationAllocationsView(ctx, nodeIDInt, allocationIDInt, asDelete),
+	)
+}
+
+// ListServerAllocations lists the allocations assigned to a server by UUID or integer ID.
+func (a *ApplicationAPI) ListServerAllocations(ctx context.Context, identifier string) ([]map[string]any, error) {
+	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server ID: %w", err)
+	}
+
+	// The server resource only carries its primary allocation; ask the panel to include all of them.
+	includeAllocations := func(_ context.Context, req *http.Request) error {
+		query := req.URL.Query()
+		query.Set("include", "allocations")
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+	httpResp, err := a.genClient.ApplicationServersView(ctx, serverID, includeAllocations)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, handleApplicationErrorResponse(httpResp, body)
+	}
+
+	var resp struct {
+		Attributes struct {
+			Relationships struct {
+				Allocations struct {
+					Data []struct {
+						Attributes map[string]any `json:"attributes"`
+					} `json:"data"`
+				} `json:"allocations"`
+			} `json:"relationships"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	allocations := make([]map[string]any, 0, len(resp.Attributes.Relationships.Allocations.Data))
+	for _, item := range resp.Attributes.Relationships.Allocations.Data {
+		allocations = append(allocations, item.Attributes)
+	}
+	return allocations, nil
+}
+
+// checkApplicationEmptyResponse checks an Application API response that carries no body on success.
+func checkApplicationEmptyResponse(httpResp *http.Response, err error) error {
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return handleApplicationErrorResponse(httpResp, bodyBytes)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -18,6 +19,9 @@ import (
 	"go.lostcrafters.com/pelicanctl/internal/auth"
 	"go.lostcrafters.com/pelicanctl/internal/config"
 	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
 )
 
 const (
@@ -41,7 +45,14 @@ func NewApplicationAPI() (*ApplicationAPI, error) {
 
 	token, err := auth.GetToken("admin")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get admin token: %w", err)
+		return nil, apierrors.WithExitCode(apierrors.ExitAuth, fmt.Errorf("failed to get admin token: %w", err))
+	}
+
+	if err := setPanelRateLimit(cfg.API.RateLimit); err != nil {
+		return nil, err
+	}
+	if err := configurePanelTransport(cfg.API); err != nil {
+		return nil, err
 	}
 
 	baseURL := cfg.API.BaseURL
@@ -69,6 +80,7 @@ func NewApplicationAPI() (*ApplicationAPI, error) {
 	genClient, err := application.NewClientWithResponses(
 		apiBaseURL,
 		application.WithRequestEditorFn(withAuth),
+		application.WithHTTPClient(panelHTTPClient),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create generated client: %w", err)
@@ -92,62 +104,30 @@ func extractServerID(server map[string]any) any {
 	return nil
 }
 
-// getServerIDFromIdentifier converts a server identifier (UUID string or integer ID) to an integer ID.
-//
-//nolint:gocognit // UUID lookup and ID extraction requires high cognitive complexity
-func (a *ApplicationAPI) getServerIDFromIdentifier(_ context.Context, identifier string) (int, error) {
+// getServerIDFromIdentifier converts a server identifier (UUID, short identifier, integer ID or name) to an integer ID.
+func (a *ApplicationAPI) getServerIDFromIdentifier(ctx context.Context, identifier string) (int, error) {
 	// Try to parse as integer ID first.
 	if serverID, err := strconv.Atoi(identifier); err == nil {
 		return serverID, nil
 	}
 
-	// If not an integer, treat as UUID and look it up from server list.
-	servers, err := a.ListServers()
+	// If not an integer, look it up through the server index.
+	if err := strict.Lookup("server", identifier, "an integer ID"); err != nil {
+		return 0, err
+	}
+	entry, err := lookupServer(index.SourceAdmin, identifier, func() error {
+		_, listErr := a.ListServers(ctx)
+		return listErr
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to list servers to look up UUID: %w", err)
+		return 0, err
 	}
 
-	// Find server with matching UUID.
-	for _, server := range servers {
-		var serverUUID string
-
-		// Check for uuid field (could be at root or in attributes).
-		if uuid, hasUUID := server["uuid"].(string); hasUUID {
-			serverUUID = uuid
-		} else if attrs, hasAttrs := server["attributes"].(map[string]any); hasAttrs {
-			if uuidVal, hasUUIDVal := attrs["uuid"].(string); hasUUIDVal {
-				serverUUID = uuidVal
-			}
-		}
-
-		if serverUUID == identifier {
-			// Found matching UUID, extract the integer ID.
-			idVal := extractServerID(server)
-			if idVal == nil {
-				return 0, errors.New("server ID not found in response")
-			}
-
-			// Convert to int.
-			switch v := idVal.(type) {
-			case int:
-				return v, nil
-			case int64:
-				return int(v), nil
-			case float64:
-				return int(v), nil
-			case string:
-				id, err := strconv.Atoi(v)
-				if err != nil {
-					return 0, fmt.Errorf("invalid server ID format: %w", err)
-				}
-				return id, nil
-			default:
-				return 0, fmt.Errorf("unexpected server ID type: %T", idVal)
-			}
-		}
+	serverID, err := strconv.Atoi(entry.ID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid server ID format: %w", err)
 	}
-
-	return 0, fmt.Errorf("server with UUID %s not found", identifier)
+	return serverID, nil
 }
 
 // extractErrorMessages extracts error messages from a structured error response.
@@ -179,6 +159,37 @@ func extractErrorMessages(errorResponse struct {
 	return messages
 }
 
+// withJSONBody attaches a JSON request body to operations the generated client sends without one.
+func withJSONBody(jsonData []byte) application.RequestEditorFn {
+	return func(_ context.Context, req *http.Request) error {
+		req.Body = io.NopCloser(bytes.NewReader(jsonData))
+		req.ContentLength = int64(len(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		return nil
+	}
+}
+
+// mergeUpdate overlays the changed fields on the attributes of a resource as returned by a view.
+func mergeUpdate(current, changes map[string]any) map[string]any {
+	attrs, ok := current["attributes"].(map[string]any)
+	if !ok {
+		attrs = current
+	}
+
+	merged := make(map[string]any, len(attrs)+len(changes))
+	for key, val := range attrs {
+		// Related resources are not update fields.
+		if key == "relationships" {
+			continue
+		}
+		merged[key] = val
+	}
+	for key, val := range changes {
+		merged[key] = val
+	}
+	return merged
+}
+
 // handleApplicationErrorResponse converts generated client error responses to APIError.
 func handleApplicationErrorResponse(resp *http.Response, body []byte) error {
 	statusCode := resp.StatusCode
@@ -212,42 +223,23 @@ func handleApplicationErrorResponse(resp *http.Response, body []byte) error {
 	return apierrors.NewAPIError(statusCode, errorMsg)
 }
 
-// ListNodes lists all nodes.
-func (a *ApplicationAPI) ListNodes() ([]map[string]any, error) {
-	ctx := context.Background()
-
-	httpResp, err := a.genClient.ApplicationNodes(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		return nil, handleApplicationErrorResponse(httpResp, body)
-	}
-
-	// Handle wrapped response.
-	unwrapped, unwrapErr := handleWrappedResponse(body)
-	if unwrapErr != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
-	}
+// ListNodes lists all nodes, following every page.
+func (a *ApplicationAPI) ListNodes(ctx context.Context) ([]map[string]any, error) {
+	return a.ListNodesWithOptions(ctx, ListOptions{})
+}
 
-	var nodes []any
-	if err := json.Unmarshal(unwrapped, &nodes); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	return convertInterfaceSliceToMapSlice(&nodes)
+// ListNodesWithOptions lists the nodes selected by opts.
+func (a *ApplicationAPI) ListNodesWithOptions(ctx context.Context, opts ListOptions) ([]map[string]any, error) {
+	return fetchPages(opts,
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return a.genClient.ApplicationNodes(ctx, withQuery)
+		},
+		handleApplicationErrorResponse,
+	)
 }
 
 // GetNode gets a node by ID.
-func (a *ApplicationAPI) GetNode(nodeID string) (map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) GetNode(ctx context.Context, nodeID string) (map[string]any, error) {
 	// Try to parse as integer first.
 	nodeIDInt, err := strconv.Atoi(nodeID)
 	if err != nil {
@@ -288,42 +280,33 @@ func (a *ApplicationAPI) GetNode(nodeID string) (map[string]any, error) {
 	return convertInterfaceToMap(node)
 }
 
-// ListServers lists all servers.
-func (a *ApplicationAPI) ListServers() ([]map[string]any, error) {
-	ctx := context.Background()
-
-	httpResp, err := a.genClient.ApplicationServers(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer httpResp.Body.Close()
+// ListServers lists all servers, following every page.
+func (a *ApplicationAPI) ListServers(ctx context.Context) ([]map[string]any, error) {
+	return a.ListServersWithOptions(ctx, ListOptions{})
+}
 
-	body, err := io.ReadAll(httpResp.Body)
+// ListServersWithOptions lists the servers selected by opts.
+func (a *ApplicationAPI) ListServersWithOptions(ctx context.Context, opts ListOptions) ([]map[string]any, error) {
+	list, err := fetchPages(opts,
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return a.genClient.ApplicationServers(ctx, nil, withQuery)
+		},
+		handleApplicationErrorResponse,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		return nil, handleApplicationErrorResponse(httpResp, body)
-	}
-
-	// Handle wrapped response.
-	unwrapped, unwrapErr := handleWrappedResponse(body)
-	if unwrapErr != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
+		return nil, err
 	}
-
-	var servers []any
-	if err := json.Unmarshal(unwrapped, &servers); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// Remember every server's UUID so results can name servers canonically.
+	output.RecordServers(list)
+	// Only the full, unfiltered list shows which servers no longer exist.
+	if opts.Complete() {
+		index.Update(index.SourceAdmin, list)
 	}
-	return convertInterfaceSliceToMapSlice(&servers)
+	return list, nil
 }
 
 // GetServer gets a server by UUID or integer ID.
-func (a *ApplicationAPI) GetServer(identifier string) (map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) GetServer(ctx context.Context, identifier string) (map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -366,9 +349,7 @@ func (a *ApplicationAPI) GetServer(identifier string) (map[string]any, error) {
 }
 
 // SuspendServer suspends a server by UUID or integer ID.
-func (a *ApplicationAPI) SuspendServer(identifier string) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) SuspendServer(ctx context.Context, identifier string) error {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -390,9 +371,7 @@ func (a *ApplicationAPI) SuspendServer(identifier string) error {
 }
 
 // UnsuspendServer unsuspends a server by UUID or integer ID.
-func (a *ApplicationAPI) UnsuspendServer(identifier string) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) UnsuspendServer(ctx context.Context, identifier string) error {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -414,9 +393,7 @@ func (a *ApplicationAPI) UnsuspendServer(identifier string) error {
 }
 
 // ReinstallServer reinstalls a server by UUID or integer ID.
-func (a *ApplicationAPI) ReinstallServer(identifier string) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) ReinstallServer(ctx context.Context, identifier string) error {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -438,9 +415,7 @@ func (a *ApplicationAPI) ReinstallServer(identifier string) error {
 }
 
 // SendPowerCommand sends a power command to a server by UUID or integer ID.
-func (a *ApplicationAPI) SendPowerCommand(identifier, command string) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) SendPowerCommand(ctx context.Context, identifier, command string) error {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -480,9 +455,7 @@ func (a *ApplicationAPI) SendPowerCommand(identifier, command string) error {
 }
 
 // SendCommand sends a console command to a server by UUID or integer ID.
-func (a *ApplicationAPI) SendCommand(identifier, command string) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) SendCommand(ctx context.Context, identifier, command string) error {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -507,9 +480,12 @@ func (a *ApplicationAPI) SendCommand(identifier, command string) error {
 }
 
 // GetServerHealth gets the health status of a server by UUID or integer ID.
-func (a *ApplicationAPI) GetServerHealth(identifier string, since *time.Time, window *int) (map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) GetServerHealth(
+	ctx context.Context,
+	identifier string,
+	since *time.Time,
+	window *int,
+) (map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -551,46 +527,26 @@ func (a *ApplicationAPI) GetServerHealth(identifier string, since *time.Time, wi
 	return healthData, nil
 }
 
-// ListUsers lists all users.
-func (a *ApplicationAPI) ListUsers() ([]map[string]any, error) {
-	ctx := context.Background()
-
-	httpResp, err := a.genClient.ApplicationUsers(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		return nil, handleApplicationErrorResponse(httpResp, body)
-	}
-
-	// Handle wrapped response.
-	unwrapped, unwrapErr := handleWrappedResponse(body)
-	if unwrapErr != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
-	}
-
-	var users []any
-	if err := json.Unmarshal(unwrapped, &users); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	return convertInterfaceSliceToMapSlice(&users)
+// ListUsers lists all users, following every page.
+func (a *ApplicationAPI) ListUsers(ctx context.Context) ([]map[string]any, error) {
+	return a.ListUsersWithOptions(ctx, ListOptions{})
 }
 
-// GetUser gets a user by ID.
-func (a *ApplicationAPI) GetUser(userID string) (map[string]any, error) {
-	ctx := context.Background()
+// ListUsersWithOptions lists the users selected by opts.
+func (a *ApplicationAPI) ListUsersWithOptions(ctx context.Context, opts ListOptions) ([]map[string]any, error) {
+	return fetchPages(opts,
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return a.genClient.ApplicationUsers(ctx, withQuery)
+		},
+		handleApplicationErrorResponse,
+	)
+}
 
-	// Try to parse as integer first.
-	userIDInt, err := strconv.Atoi(userID)
+// GetUser gets a user by ID, username or email.
+func (a *ApplicationAPI) GetUser(ctx context.Context, userID string) (map[string]any, error) {
+	userIDInt, err := a.userID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %s (must be an integer)", userID)
+		return nil, err
 	}
 
 	httpResp, err := a.genClient.ApplicationUsersView(ctx, userIDInt)
@@ -628,9 +584,7 @@ func (a *ApplicationAPI) GetUser(userID string) (map[string]any, error) {
 }
 
 // CreateNode creates a new node.
-func (a *ApplicationAPI) CreateNode(nodeData map[string]any) (map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) CreateNode(ctx context.Context, nodeData map[string]any) (map[string]any, error) {
 	// Convert map to StoreNodeRequest.
 	jsonData, err := json.Marshal(nodeData)
 	if err != nil {
@@ -671,51 +625,62 @@ func (a *ApplicationAPI) CreateNode(nodeData map[string]any) (map[string]any, er
 	return convertInterfaceToMap(node)
 }
 
-// UpdateNode updates an existing node.
-// Note: The generated client's NodeUpdate method doesn't accept a request body based on the OpenAPI spec.
-// This method may need to be updated if the API spec is updated to include a request body.
-func (a *ApplicationAPI) UpdateNode(nodeID string) (map[string]any, error) {
-	ctx := context.Background()
-
-	// Try to parse as integer first.
+// GetNodeConfiguration returns the Wings configuration of a node, as the panel sends it, so it keeps
+// the order of its fields.
+func (a *ApplicationAPI) GetNodeConfiguration(ctx context.Context, nodeID string) (json.RawMessage, error) {
 	nodeIDInt, err := strconv.Atoi(nodeID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid node ID: %s (must be an integer)", nodeID)
 	}
 
-	httpResp, err := a.genClient.NodeUpdateWithResponse(ctx, nodeIDInt)
+	httpResp, err := a.genClient.NodesNodeConfiguration(ctx, nodeIDInt)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
-	defer httpResp.HTTPResponse.Body.Close()
+	defer httpResp.Body.Close()
 
-	if httpResp.HTTPResponse.StatusCode != http.StatusOK {
-		return nil, handleApplicationErrorResponse(httpResp.HTTPResponse, httpResp.Body)
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Handle wrapped response.
-	unwrapped, unwrapErr := handleWrappedResponse(httpResp.Body)
-	if unwrapErr != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, handleApplicationErrorResponse(httpResp, body)
+	}
+	if !json.Valid(body) {
+		return nil, errors.New("failed to decode response: not JSON")
 	}
+	return body, nil
+}
 
-	var node any
-	if err := json.Unmarshal(unwrapped, &node); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// UpdateNode updates an existing node with the changed fields in nodeData.
+// The panel validates an update like a new node, so the fields are merged over the node's current attributes.
+func (a *ApplicationAPI) UpdateNode(
+	ctx context.Context,
+	nodeID string,
+	nodeData map[string]any,
+) (map[string]any, error) {
+	// Try to parse as integer first.
+	nodeIDInt, err := strconv.Atoi(nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node ID: %s (must be an integer)", nodeID)
 	}
 
-	// If it's a slice with one item, extract it.
-	if arr, ok := node.([]any); ok && len(arr) > 0 {
-		return convertInterfaceToMap(arr[0])
+	current, err := a.GetNode(ctx, nodeID)
+	if err != nil {
+		return nil, err
 	}
 
-	return convertInterfaceToMap(node)
+	jsonData, err := json.Marshal(mergeUpdate(current, nodeData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal node data: %w", err)
+	}
+
+	return readApplicationResourceResponse(a.genClient.NodeUpdate(ctx, nodeIDInt, withJSONBody(jsonData)))
 }
 
 // DeleteNode deletes a node by ID.
-func (a *ApplicationAPI) DeleteNode(nodeID string) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) DeleteNode(ctx context.Context, nodeID string) error {
 	// Try to parse as integer first.
 	nodeIDInt, err := strconv.Atoi(nodeID)
 	if err != nil {
@@ -737,21 +702,15 @@ func (a *ApplicationAPI) DeleteNode(nodeID string) error {
 }
 
 // CreateServer creates a new server.
-func (a *ApplicationAPI) CreateServer(serverData map[string]any) (map[string]any, error) {
-	ctx := context.Background()
-
-	// Convert map to StoreServerRequest.
+func (a *ApplicationAPI) CreateServer(ctx context.Context, serverData map[string]any) (map[string]any, error) {
+	// The data is sent as given: the panel takes the environment as an object of variables,
+	// which StoreServerRequest cannot hold.
 	jsonData, err := json.Marshal(serverData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal server data: %w", err)
 	}
 
-	var serverReq application.StoreServerRequest
-	if err := json.Unmarshal(jsonData, &serverReq); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server request: %w", err)
-	}
-
-	httpResp, err := a.genClient.ServerStoreWithResponse(ctx, serverReq)
+	httpResp, err := a.genClient.ServerStoreWithBodyWithResponse(ctx, "application/json", bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -776,9 +735,7 @@ func (a *ApplicationAPI) CreateServer(serverData map[string]any) (map[string]any
 }
 
 // DeleteServer deletes a server by UUID or integer ID.
-func (a *ApplicationAPI) DeleteServer(identifier string, force bool) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) DeleteServer(ctx context.Context, identifier string, force bool) error {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -805,9 +762,7 @@ func (a *ApplicationAPI) DeleteServer(identifier string, force bool) error {
 }
 
 // CreateUser creates a new user.
-func (a *ApplicationAPI) CreateUser(userData map[string]any) (map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) CreateUser(ctx context.Context, userData map[string]any) (map[string]any, error) {
 	// Convert map to StoreUserRequest.
 	jsonData, err := json.Marshal(userData)
 	if err != nil {
@@ -848,54 +803,36 @@ func (a *ApplicationAPI) CreateUser(userData map[string]any) (map[string]any, er
 	return convertInterfaceToMap(user)
 }
 
-// UpdateUser updates an existing user.
-// Note: Similar to NodeUpdate, the generated client's UserUpdate method may not accept a request body.
-func (a *ApplicationAPI) UpdateUser(userID string) (map[string]any, error) {
-	ctx := context.Background()
-
-	// Try to parse as integer first.
-	userIDInt, err := strconv.Atoi(userID)
+// UpdateUser updates an existing user, by ID, username or email, with the changed fields in userData.
+// The panel validates an update like a new user, so the fields are merged over the user's current attributes.
+func (a *ApplicationAPI) UpdateUser(
+	ctx context.Context,
+	userID string,
+	userData map[string]any,
+) (map[string]any, error) {
+	userIDInt, err := a.userID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %s (must be an integer)", userID)
+		return nil, err
 	}
 
-	httpResp, err := a.genClient.UserUpdateWithResponse(ctx, userIDInt)
+	current, err := a.GetUser(ctx, strconv.Itoa(userIDInt))
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer httpResp.HTTPResponse.Body.Close()
-
-	if httpResp.HTTPResponse.StatusCode != http.StatusOK {
-		return nil, handleApplicationErrorResponse(httpResp.HTTPResponse, httpResp.Body)
-	}
-
-	// Handle wrapped response.
-	unwrapped, unwrapErr := handleWrappedResponse(httpResp.Body)
-	if unwrapErr != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
-	}
-
-	var user any
-	if err := json.Unmarshal(unwrapped, &user); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
 
-	// If it's a slice with one item, extract it.
-	if arr, ok := user.([]any); ok && len(arr) > 0 {
-		return convertInterfaceToMap(arr[0])
+	jsonData, err := json.Marshal(mergeUpdate(current, userData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user data: %w", err)
 	}
 
-	return convertInterfaceToMap(user)
+	return readApplicationResourceResponse(a.genClient.UserUpdate(ctx, userIDInt, withJSONBody(jsonData)))
 }
 
-// DeleteUser deletes a user by ID.
-func (a *ApplicationAPI) DeleteUser(userID string) error {
-	ctx := context.Background()
-
-	// Try to parse as integer first.
-	userIDInt, err := strconv.Atoi(userID)
+// DeleteUser deletes a user by ID, username or email.
+func (a *ApplicationAPI) DeleteUser(ctx context.Context, userID string) error {
+	userIDInt, err := a.userID(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("invalid user ID: %s (must be an integer)", userID)
+		return err
 	}
 
 	httpResp, err := a.genClient.UserDeleteWithResponse(ctx, userIDInt)
@@ -913,9 +850,7 @@ func (a *ApplicationAPI) DeleteUser(userID string) error {
 }
 
 // ListBackups lists all backups for a server by UUID or integer ID.
-func (a *ApplicationAPI) ListBackups(identifier string) ([]map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) ListBackups(ctx context.Context, identifier string) ([]map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -976,9 +911,11 @@ func (a *ApplicationAPI) ListBackups(identifier string) ([]map[string]any, error
 }
 
 // CreateBackup creates a backup for a server by UUID or integer ID.
-func (a *ApplicationAPI) CreateBackup(identifier string, backupData map[string]any) (map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) CreateBackup(
+	ctx context.Context,
+	identifier string,
+	backupData map[string]any,
+) (map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -1022,9 +959,7 @@ func (a *ApplicationAPI) CreateBackup(identifier string, backupData map[string]a
 }
 
 // GetBackup gets a backup by server UUID/ID and backup UUID.
-func (a *ApplicationAPI) GetBackup(serverIdentifier, backupUUID string) (map[string]any, error) {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) GetBackup(ctx context.Context, serverIdentifier, backupUUID string) (map[string]any, error) {
 	// Convert server identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -1085,9 +1020,7 @@ func extractBackupFromObject(obj map[string]any) any {
 }
 
 // DeleteBackup deletes a backup by server UUID/ID and backup UUID.
-func (a *ApplicationAPI) DeleteBackup(serverIdentifier, backupUUID string) error {
-	ctx := context.Background()
-
+func (a *ApplicationAPI) DeleteBackup(ctx context.Context, serverIdentifier, backupUUID string) error {
 	// Convert server identifier (UUID or integer ID) to integer ID.
 	serverID, err := a.getServerIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.lostcrafters.com/pelicanctl/internal/application"
+)
+
+// ListDatabaseHosts lists all database hosts, following every page.
+func (a *ApplicationAPI) ListDatabaseHosts(ctx context.Context) ([]map[string]any, error) {
+	return fetchPages(ListOptions{},
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return a.genClient.ApplicationDatabasehosts(ctx, withQuery)
+		},
+		handleApplicationErrorResponse,
+	)
+}
+
+// GetDatabaseHost gets a database host by ID.
+func (a *ApplicationAPI) GetDatabaseHost(ctx context.Context, hostID string) (map[string]any, error) {
+	hostIDInt, err := strconv.Atoi(hostID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database host ID: %s (must be an integer)", hostID)
+	}
+
+	return readApplicationResourceResponse(a.genClient.ApplicationDatabasehostsView(ctx, hostIDInt))
+}
+
+// CreateDatabaseHost creates a new database host.
+func (a *ApplicationAPI) CreateDatabaseHost(ctx context.Context, hostData map[string]any) (map[string]any, error) {
+	// Convert map to StoreDatabaseHostRequest.
+	jsonData, err := json.Marshal(hostData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal database host data: %w", err)
+	}
+
+	var hostReq application.StoreDatabaseHostRequest
+	if err := json.Unmarshal(jsonData, &hostReq); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal database host request: %w", err)
+	}
+
+	return readApplicationResourceResponse(a.genClient.DatabaseHostStore(ctx, hostReq))
+}
+
+// UpdateDatabaseHost updates an existing database host with the given fields.
+func (a *ApplicationAPI) UpdateDatabaseHost(
+	ctx context.Context,
+	hostID string,
+	hostData map[string]any,
+) (map[string]any, error) {
+	hostIDInt, err := strconv.Atoi(hostID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database host ID: %s (must be an integer)", hostID)
+	}
+
+	jsonData, err := json.Marshal(hostData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal database host data: %w", err)
+	}
+
+	return readApplicationResourceResponse(a.genClient.DatabaseHostUpdate(ctx, hostIDInt, withJSONBody(jsonData)))
+}
+
+// DeleteDatabaseHost deletes a database host by ID.
+func (a *ApplicationAPI) DeleteDatabaseHost(ctx context.Context, hostID string) error {
+	hostIDInt, err := strconv.Atoi(hostID)
+	if err != nil {
+		return fmt.Errorf("invalid database host ID: %s (must be an integer)", hostID)
+	}
+
+	return checkApplicationEmptyResponse(a.genClient.DatabaseHostDelete(ctx, hostIDInt))
+}
+
+// readApplicationResourceResponse reads an Application API response carrying a single resource.
+func readApplicationResourceResponse(httpResp *http.Response, err error) (map[string]any, error) {
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
+		return nil, handleApplicationErrorResponse(httpResp, body)
+	}
+
+	return decodeSingle(body)
+}
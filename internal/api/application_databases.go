@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+)
+
+// DatabaseCredentials holds what is needed to connect to a server database.
+type DatabaseCredentials struct {
+	Host     string
+	Port     int
+	Database string
+	Username string
+	Password string
+}
+
+// GetServerDatabaseCredentials finds a database of a server by UUID or integer ID and returns its credentials.
+// The database is matched by ID, by its full name, or by the name it was created with (without the s<id>_ prefix).
+func (a *ApplicationAPI) GetServerDatabaseCredentials(
+	ctx context.Context,
+	serverIdentifier, database string,
+) (DatabaseCredentials, error) {
+	serverID, err := a.getServerIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return DatabaseCredentials{}, fmt.Errorf("failed to get server ID: %w", err)
+	}
+
+	// Database resources only reference their host; ask the panel to include it and the password.
+	includeCredentials := func(_ context.Context, req *http.Request) error {
+		query := req.URL.Query()
+		query.Set("include", "password,host")
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+	httpResp, err := a.genClient.ApplicationServersDatabases(ctx, serverID, includeCredentials)
+	if err != nil {
+		return DatabaseCredentials{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return DatabaseCredentials{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return DatabaseCredentials{}, handleApplicationErrorResponse(httpResp, body)
+	}
+
+	var resp struct {
+		Data []struct {
+			Attributes struct {
+				ID            int    `json:"id"`
+				Database      string `json:"database"`
+				Username      string `json:"username"`
+				Relationships struct {
+					Password struct {
+						Attributes struct {
+							Password string `json:"password"`
+						} `json:"attributes"`
+					} `json:"password"`
+					Host struct {
+						Attributes struct {
+							Host string `json:"host"`
+							Port int    `json:"port"`
+						} `json:"attributes"`
+					} `json:"host"`
+				} `json:"relationships"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return DatabaseCredentials{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	names := make([]string, 0, len(resp.Data))
+	for _, item := range resp.Data {
+		attrs := item.Attributes
+		names = append(names, attrs.Database)
+		if strconv.Itoa(attrs.ID) != database && attrs.Database != database &&
+			!strings.HasSuffix(attrs.Database, "_"+database) {
+			continue
+		}
+		return DatabaseCredentials{
+			Host:     attrs.Relationships.Host.Attributes.Host,
+			Port:     attrs.Relationships.Host.Attributes.Port,
+			Database: attrs.Database,
+			Username: attrs.Username,
+			Password: attrs.Relationships.Password.Attributes.Password,
+		}, nil
+	}
+
+	if len(names) == 0 {
+		return DatabaseCredentials{}, fmt.Errorf("server %s has no databases", serverIdentifier)
+	}
+	return DatabaseCredentials{}, apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf(
+		"database %q not found on server %s (available: %s)", database, serverIdentifier, strings.Join(names, ", ")))
+}
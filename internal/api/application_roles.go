@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.lostcrafters.com/pelicanctl/internal/application"
+)
+
+// ListRoles lists all roles, following every page.
+func (a *ApplicationAPI) ListRoles(ctx context.Context) ([]map[string]any, error) {
+	return fetchPages(ListOptions{},
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return a.genClient.ApplicationRoles(ctx, withQuery)
+		},
+		handleApplicationErrorResponse,
+	)
+}
+
+// GetRole gets a role by ID.
+func (a *ApplicationAPI) GetRole(ctx context.Context, roleID string) (map[string]any, error) {
+	roleIDInt, err := strconv.Atoi(roleID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid role ID: %s (must be an integer)", roleID)
+	}
+
+	return readApplicationResourceResponse(a.genClient.ApplicationRolesView(ctx, roleIDInt))
+}
+
+// CreateRole creates a new role. Fields beyond the name, such as permissions, are sent as given.
+func (a *ApplicationAPI) CreateRole(ctx context.Context, roleData map[string]any) (map[string]any, error) {
+	jsonData, err := json.Marshal(roleData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal role data: %w", err)
+	}
+
+	return readApplicationResourceResponse(
+		a.genClient.RoleStoreWithBody(ctx, "application/json", bytes.NewReader(jsonData)),
+	)
+}
+
+// UpdateRole updates an existing role with the changed fields in roleData.
+func (a *ApplicationAPI) UpdateRole(
+	ctx context.Context,
+	roleID string,
+	roleData map[string]any,
+) (map[string]any, error) {
+	roleIDInt, err := strconv.Atoi(roleID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid role ID: %s (must be an integer)", roleID)
+	}
+
+	// The panel validates an update like a new role, so the fields are merged over the role's current attributes.
+	current, err := a.GetRole(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(mergeUpdate(current, roleData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal role data: %w", err)
+	}
+
+	return readApplicationResourceResponse(
+		a.genClient.RoleUpdateWithBody(ctx, roleIDInt, "application/json", bytes.NewReader(jsonData)),
+	)
+}
+
+// DeleteRole deletes a role by ID.
+func (a *ApplicationAPI) DeleteRole(ctx context.Context, roleID string) error {
+	roleIDInt, err := strconv.Atoi(roleID)
+	if err != nil {
+		return fmt.Errorf("invalid role ID: %s (must be an integer)", roleID)
+	}
+
+	return checkApplicationEmptyResponse(a.genClient.RoleDelete(ctx, roleIDInt))
+}
+
+// AssignUserRoles gives a user by ID, username or email the roles with the given IDs.
+func (a *ApplicationAPI) AssignUserRoles(ctx context.Context, userID string, roleIDs []int) error {
+	userIDInt, err := a.userID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	req := application.UserAssignRolesJSONRequestBody{Roles: roleIDs}
+	return checkApplicationEmptyResponse(a.genClient.UserAssignRoles(ctx, userIDInt, req))
+}
+
+// RemoveUserRoles takes the roles with the given IDs from a user by ID, username or email.
+func (a *ApplicationAPI) RemoveUserRoles(ctx context.Context, userID string, roleIDs []int) error {
+	userIDInt, err := a.userID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	req := application.UserRemoveRolesJSONRequestBody{Roles: roleIDs}
+	return checkApplicationEmptyResponse(a.genClient.UserRemoveRoles(ctx, userIDInt, req))
+}
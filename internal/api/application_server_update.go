@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ServerDetailFields are the fields of a server UpdateServerDetails changes.
+//
+//nolint:gochecknoglobals // Fixed list of API fields
+var ServerDetailFields = []string{"name", "description", "external_id", "user"}
+
+// ServerBuildFields are the fields of a server UpdateServerBuild changes.
+//
+//nolint:gochecknoglobals // Fixed list of API fields
+var ServerBuildFields = []string{"limits", "feature_limits", "oom_killer"}
+
+// UpdateServerDetails updates the name, description, external ID or owner of a server by UUID or integer ID.
+// The panel validates an update like a new server, so the fields are merged over the server's current details.
+func (a *ApplicationAPI) UpdateServerDetails(
+	ctx context.Context,
+	identifier string,
+	changes map[string]any,
+) (map[string]any, error) {
+	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server ID: %w", err)
+	}
+	current, err := a.GetServer(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(pickFields(mergeUpdate(current, changes), ServerDetailFields))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server data: %w", err)
+	}
+	return readApplicationResourceResponse(a.genClient.ApplicationServersDetailsWithBody(
+		ctx, serverID, "application/json", bytes.NewReader(jsonData)))
+}
+
+// UpdateServerBuild updates the resource and feature limits of a server by UUID or integer ID.
+// Limits not given in changes keep their current value.
+func (a *ApplicationAPI) UpdateServerBuild(
+	ctx context.Context,
+	identifier string,
+	changes map[string]any,
+) (map[string]any, error) {
+	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server ID: %w", err)
+	}
+	current, err := a.GetServer(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := mergeUpdate(current, nil)
+	build := pickFields(attrs, ServerBuildFields)
+	// The panel requires the primary allocation with every build update.
+	build["allocation"] = attrs["allocation"]
+	for key, val := range changes {
+		// Limits are objects; merge them so a change to one limit keeps the others.
+		if nested, ok := val.(map[string]any); ok {
+			if currentNested, ok := build[key].(map[string]any); ok {
+				build[key] = mergeUpdate(currentNested, nested)
+				continue
+			}
+		}
+		build[key] = val
+	}
+
+	jsonData, err := json.Marshal(build)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server data: %w", err)
+	}
+	return readApplicationResourceResponse(a.genClient.ApplicationServersBuildWithBody(
+		ctx, serverID, "application/json", bytes.NewReader(jsonData)))
+}
+
+// pickFields returns the given fields of attrs that are set.
+func pickFields(attrs map[string]any, fields []string) map[string]any {
+	picked := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if val, ok := attrs[field]; ok {
+			picked[field] = val
+		}
+	}
+	return picked
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.lostcrafters.com/pelicanctl/internal/application"
+)
+
+// TransferStatus is the state of a server transfer as reported by the panel.
+type TransferStatus struct {
+	// Node is the node the server is currently assigned to.
+	Node int
+	// Pending is true while a transfer exists that has not finished.
+	Pending bool
+	// Failed is true when the latest transfer finished unsuccessfully.
+	Failed bool
+}
+
+// TransferServer starts a transfer of a server by UUID or integer ID to a node by ID,
+// using allocationID on the target node as its primary allocation.
+func (a *ApplicationAPI) TransferServer(ctx context.Context, identifier, nodeID string, allocationID int) error {
+	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
+	if err != nil {
+		return fmt.Errorf("failed to get server ID: %w", err)
+	}
+
+	nodeIDInt, err := strconv.Atoi(nodeID)
+	if err != nil {
+		return fmt.Errorf("invalid node ID: %s (must be an integer)", nodeID)
+	}
+
+	req := application.ApplicationServersTransferJSONRequestBody{
+		NodeId:       nodeIDInt,
+		AllocationId: allocationID,
+	}
+	return checkApplicationEmptyResponse(a.genClient.ApplicationServersTransfer(ctx, serverID, req))
+}
+
+// GetServerTransferStatus reports the node of a server by UUID or integer ID and the state of its latest transfer.
+func (a *ApplicationAPI) GetServerTransferStatus(ctx context.Context, identifier string) (TransferStatus, error) {
+	serverID, err := a.getServerIDFromIdentifier(ctx, identifier)
+	if err != nil {
+		return TransferStatus{}, fmt.Errorf("failed to get server ID: %w", err)
+	}
+
+	// The server resource only references its transfer; ask the panel to include it.
+	includeTransfer := func(_ context.Context, req *http.Request) error {
+		query := req.URL.Query()
+		query.Set("include", "transfer")
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+	httpResp, err := a.genClient.ApplicationServersView(ctx, serverID, includeTransfer)
+	if err != nil {
+		return TransferStatus{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return TransferStatus{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return TransferStatus{}, handleApplicationErrorResponse(httpResp, body)
+	}
+
+	var resp struct {
+		Attributes struct {
+			Node          int `json:"node"`
+			Relationships struct {
+				// A server without a transfer has a null resource here, whose attributes are null.
+				Transfer struct {
+					Attributes *struct {
+						Successful *bool `json:"successful"`
+					} `json:"attributes"`
+				} `json:"transfer"`
+			} `json:"relationships"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return TransferStatus{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	status := TransferStatus{Node: resp.Attributes.Node}
+	if transfer := resp.Attributes.Relationships.Transfer.Attributes; transfer != nil {
+		successful := transfer.Successful
+		status.Pending = successful == nil
+		status.Failed = successful != nil && !*successful
+	}
+	return status, nil
+}
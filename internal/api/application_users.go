@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
+)
+
+// ResolveUserID returns the ID of a user given by ID, username or email. Usernames and emails are
+// looked up with the panel's user filters, which match parts of them, so only an exact (case-insensitive)
+// match is taken.
+func (a *ApplicationAPI) ResolveUserID(ctx context.Context, identifier string) (string, error) {
+	if _, err := strconv.Atoi(identifier); err == nil {
+		return identifier, nil
+	}
+	if err := strict.Lookup("user", identifier, "an integer ID"); err != nil {
+		return "", apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+
+	field := "username"
+	if strings.Contains(identifier, "@") {
+		field = "email"
+	}
+	users, err := a.ListUsersWithOptions(ctx, ListOptions{Filter: map[string]string{field: identifier}})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up user %s: %w", identifier, err)
+	}
+	for _, user := range users {
+		attrs := user
+		if nested, ok := user["attributes"].(map[string]any); ok {
+			attrs = nested
+		}
+		if value, _ := attrs[field].(string); !strings.EqualFold(value, identifier) {
+			continue
+		}
+		if id, ok := attrs["id"].(float64); ok {
+			return strconv.FormatInt(int64(id), 10), nil
+		}
+		return fmt.Sprintf("%v", attrs["id"]), nil
+	}
+	return "", apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("user %s not found", identifier))
+}
+
+// userID resolves a user given by ID, username or email to the integer ID the API addresses users by.
+func (a *ApplicationAPI) userID(ctx context.Context, identifier string) (int, error) {
+	resolved, err := a.ResolveUserID(ctx, identifier)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(resolved)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID: %s", resolved)
+	}
+	return id, nil
+}
@@ -0,0 +1,8 @@
+package api
+
+import "context"
+
+// GetAccount gets the account the client token belongs to.
+func (c *ClientAPI) GetAccount(ctx context.Context) (map[string]any, error) {
+	return readResourceResponse(c.genClient.ApiClientAccount(ctx))
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"time"
+)
+
+// systemActor is the actor of activity the panel logged without a user, e.g. of schedules.
+const systemActor = "system"
+
+// Activity is an entry of the activity log of the panel.
+type Activity struct {
+	// Server is the server the activity was logged for, set by callers reading the log of several servers.
+	Server      string         `json:"server,omitempty"`
+	Event       string         `json:"event"`
+	Actor       string         `json:"actor"`
+	ActorEmail  string         `json:"actor_email,omitempty"`
+	IP          string         `json:"ip"`
+	IsAPI       bool           `json:"is_api"`
+	Description string         `json:"description,omitempty"`
+	Properties  map[string]any `json:"properties,omitempty"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+// ActivityOptions selects the entries of an activity log.
+type ActivityOptions struct {
+	ListOptions
+	// Event filters the log on the panel to events containing it, e.g. "server:power".
+	Event string
+	// Since drops entries logged before it; the zero time keeps every entry.
+	Since time.Time
+}
+
+// ListServerActivity lists the activity log of a server by UUID or integer ID, newest first.
+func (c *ClientAPI) ListServerActivity(
+	ctx context.Context,
+	serverIdentifier string,
+	opts ActivityOptions,
+) ([]Activity, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := opts.ListOptions
+	listOpts.Include = append([]string{"actor"}, listOpts.Include...)
+	if opts.Event != "" {
+		listOpts.Filter = maps.Clone(listOpts.Filter)
+		if listOpts.Filter == nil {
+			listOpts.Filter = make(map[string]string)
+		}
+		listOpts.Filter["event"] = opts.Event
+	}
+	if !opts.Since.IsZero() {
+		// The log is sorted newest first, so no later page has newer entries than a page ending before Since.
+		listOpts.Until = func(items []map[string]any) bool {
+			if len(items) == 0 {
+				return true
+			}
+			last, decodeErr := decodeActivity(items[len(items)-1])
+			return decodeErr == nil && last.Timestamp.Before(opts.Since)
+		}
+	}
+
+	items, err := fetchPages(listOpts,
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return c.genClient.ApiClientServerActivity(ctx, serverUUID, nil, withQuery)
+		},
+		handleErrorResponse,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]Activity, 0, len(items))
+	for _, item := range items {
+		entry, decodeErr := decodeActivity(item)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		if entry.Timestamp.Before(opts.Since) {
+			continue
+		}
+		activity = append(activity, entry)
+	}
+	return activity, nil
+}
+
+// decodeActivity decodes an entry of an activity log, with its actor included.
+func decodeActivity(item map[string]any) (Activity, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return Activity{}, fmt.Errorf("failed to decode activity: %w", err)
+	}
+	var raw struct {
+		Attributes struct {
+			Event       string `json:"event"`
+			IP          string `json:"ip"`
+			IsAPI       bool   `json:"is_api"`
+			Description string `json:"description"`
+			// Properties is an object, or an empty array if there are none.
+			Properties    any       `json:"properties"`
+			Timestamp     time.Time `json:"timestamp"`
+			Relationships struct {
+				Actor struct {
+					Attributes struct {
+						Username string `json:"username"`
+						Email    string `json:"email"`
+					} `json:"attributes"`
+				} `json:"actor"`
+			} `json:"relationships"`
+		} `json:"attributes"`
+	}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return Activity{}, fmt.Errorf("failed to decode activity: %w", err)
+	}
+
+	attrs := raw.Attributes
+	actor := attrs.Relationships.Actor.Attributes
+	entry := Activity{
+		Event:       attrs.Event,
+		Actor:       actor.Username,
+		ActorEmail:  actor.Email,
+		IP:          attrs.IP,
+		IsAPI:       attrs.IsAPI,
+		Description: attrs.Description,
+		Timestamp:   attrs.Timestamp,
+	}
+	entry.Properties, _ = attrs.Properties.(map[string]any)
+	if entry.Actor == "" {
+		entry.Actor = systemActor
+	}
+	return entry, nil
+}
@@ -1,19 +1,30 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.lostcrafters.com/pelicanctl/internal/auth"
 	"go.lostcrafters.com/pelicanctl/internal/client"
 	"go.lostcrafters.com/pelicanctl/internal/config"
 	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/progress"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
 )
 
 // ClientAPI wraps the Client API endpoints using the generated OpenAPI client.
@@ -30,7 +41,14 @@ func NewClientAPI() (*ClientAPI, error) {
 
 	token, err := auth.GetToken("client")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get client token: %w", err)
+		return nil, apierrors.WithExitCode(apierrors.ExitAuth, fmt.Errorf("failed to get client token: %w", err))
+	}
+
+	if err := setPanelRateLimit(cfg.API.RateLimit); err != nil {
+		return nil, err
+	}
+	if err := configurePanelTransport(cfg.API); err != nil {
+		return nil, err
 	}
 
 	baseURL := cfg.API.BaseURL
@@ -58,6 +76,7 @@ func NewClientAPI() (*ClientAPI, error) {
 	genClient, err := client.NewClientWithResponses(
 		apiBaseURL,
 		client.WithRequestEditorFn(withAuth),
+		client.WithHTTPClient(panelHTTPClient),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create generated client: %w", err)
@@ -170,96 +189,67 @@ func handleWrappedResponse(body []byte) ([]byte, error) {
 	return body, nil
 }
 
-// ListServers lists all servers available to the client.
-func (c *ClientAPI) ListServers() ([]map[string]any, error) {
-	ctx := context.Background()
+// ListServers lists all servers available to the client, following every page.
+func (c *ClientAPI) ListServers(ctx context.Context) ([]map[string]any, error) {
+	return c.ListServersWithOptions(ctx, ListOptions{})
+}
 
-	// Use the raw request method to avoid generated client parsing failures with wrapped responses.
-	body, err := makeRawRequest(c.genClient.ApiClientIndex(ctx, nil))
+// ListServersWithOptions lists the servers available to the client selected by opts.
+func (c *ClientAPI) ListServersWithOptions(ctx context.Context, opts ListOptions) ([]map[string]any, error) {
+	list, err := fetchPages(opts,
+		func(withQuery func(context.Context, *http.Request) error) (*http.Response, error) {
+			return c.genClient.ApiClientIndex(ctx, nil, withQuery)
+		},
+		handleErrorResponse,
+	)
 	if err != nil {
 		return nil, err
 	}
-
-	// Handle wrapped response (e.g., {"data": [...]}).
-	unwrapped, unwrapErr := handleWrappedResponse(body)
-	if unwrapErr != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
+	// Remember every server's UUID so results can name servers canonically.
+	output.RecordServers(list)
+	// Only the full, unfiltered list shows which servers no longer exist.
+	if opts.Complete() {
+		index.Update(index.SourceClient, list)
 	}
-
-	var servers []any
-	if err := json.Unmarshal(unwrapped, &servers); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	return convertInterfaceSliceToMapSlice(&servers)
+	return list, nil
 }
 
-// getServerUUIDFromIdentifier converts a server identifier (UUID string or integer ID) to a UUID.
-// Client API only accepts UUIDs, so if an integer ID is provided, we look it up.
-func (c *ClientAPI) getServerUUIDFromIdentifier(_ context.Context, identifier string) (string, error) {
+// getServerUUIDFromIdentifier converts a server identifier (UUID, short identifier, integer ID or name) to a UUID.
+// Client API only accepts UUIDs and short identifiers, so integer IDs and names are resolved through the server index.
+func (c *ClientAPI) getServerUUIDFromIdentifier(ctx context.Context, identifier string) (string, error) {
 	// Check if it looks like a UUID (contains hyphens).
 	if strings.Contains(identifier, "-") {
 		return identifier, nil
 	}
 
-	// Try to parse as integer ID.
-	if _, err := strconv.Atoi(identifier); err != nil {
-		// Not an integer, assume it's a UUID and pass through.
-		return identifier, nil //nolint:nilerr // Intentionally returning identifier even if parse fails
+	// Short identifiers are accepted by the panel as they are.
+	_, atoiErr := strconv.Atoi(identifier)
+	isID := atoiErr == nil
+	if !isID && shortIdentifierPattern.MatchString(identifier) {
+		return identifier, nil
 	}
 
-	// It's an integer ID, need to look it up.
-	servers, err := c.ListServers()
-	if err != nil {
-		return "", fmt.Errorf("failed to list servers to look up UUID: %w", err)
+	// It's an integer ID or a name, need to look it up.
+	if err := strict.Lookup("server", identifier, "a UUID"); err != nil {
+		return "", err
 	}
-
-	// Find server with matching ID.
-	for _, server := range servers {
-		var serverID any
-
-		// Check for id field (could be at root or in attributes).
-		if id, hasID := server["id"]; hasID {
-			serverID = id
-		} else if attrs, hasAttrs := server["attributes"].(map[string]any); hasAttrs {
-			if idVal, hasIDVal := attrs["id"]; hasIDVal {
-				serverID = idVal
-			}
-		}
-
-		// Compare IDs (handle float64 from JSON).
-		var idInt int
-		switch v := serverID.(type) {
-		case int:
-			idInt = v
-		case int64:
-			idInt = int(v)
-		case float64:
-			idInt = int(v)
-		case string:
-			parsed, err := strconv.Atoi(v)
-			if err != nil {
-				continue
-			}
-			idInt = parsed
-		default:
-			continue
-		}
-
-		targetID, _ := strconv.Atoi(identifier)
-		if idInt == targetID {
-			if uuid, ok := server["uuid"].(string); ok {
-				return uuid, nil
-			}
-		}
+	entry, err := lookupServer(index.SourceClient, identifier, func() error {
+		_, listErr := c.ListServers(ctx)
+		return listErr
+	})
+	if err != nil {
+		return "", err
 	}
+	return entry.UUID, nil
+}
 
-	return "", fmt.Errorf("server with ID %s not found", identifier)
+// ResolveServerUUID converts a server identifier (UUID or integer ID) to a UUID.
+func (c *ClientAPI) ResolveServerUUID(ctx context.Context, identifier string) (string, error) {
+	return c.getServerUUIDFromIdentifier(ctx, identifier)
 }
 
 // GetServer gets a server by UUID or integer ID.
-func (c *ClientAPI) GetServer(identifier string) (map[string]any, error) {
-	ctx := context.Background()
-
+func (c *ClientAPI) GetServer(ctx context.Context, identifier string) (map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to UUID..
 	uuid, err := c.getServerUUIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -291,9 +281,7 @@ func (c *ClientAPI) GetServer(identifier string) (map[string]any, error) {
 }
 
 // GetServerResources gets server resource usage by UUID or integer ID.
-func (c *ClientAPI) GetServerResources(identifier string) (map[string]any, error) {
-	ctx := context.Background()
-
+func (c *ClientAPI) GetServerResources(ctx context.Context, identifier string) (map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to UUID..
 	uuid, err := c.getServerUUIDFromIdentifier(ctx, identifier)
 	if err != nil {
@@ -319,10 +307,22 @@ func (c *ClientAPI) GetServerResources(identifier string) (map[string]any, error
 	return convertInterfaceToMap(resources)
 }
 
-// ListFiles lists files in a directory by server UUID or integer ID.
-func (c *ClientAPI) ListFiles(serverIdentifier, directory string) ([]map[string]any, error) {
-	ctx := context.Background()
+// ServerState extracts the power state (e.g. "running", "offline") from a resources response.
+func ServerState(resources map[string]any) string {
+	attrs := resources
+	if nested, ok := resources["attributes"].(map[string]any); ok {
+		attrs = nested
+	}
+	for _, key := range []string{"current_state", "state"} {
+		if state, ok := attrs[key].(string); ok {
+			return state
+		}
+	}
+	return ""
+}
 
+// ListFiles lists files in a directory by server UUID or integer ID.
+func (c *ClientAPI) ListFiles(ctx context.Context, serverIdentifier, directory string) ([]map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to UUID.
 	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -353,9 +353,7 @@ func (c *ClientAPI) ListFiles(serverIdentifier, directory string) ([]map[string]
 }
 
 // SendPowerCommand sends a power command to a server by UUID or integer ID.
-func (c *ClientAPI) SendPowerCommand(serverIdentifier, command string) error {
-	ctx := context.Background()
-
+func (c *ClientAPI) SendPowerCommand(ctx context.Context, serverIdentifier, command string) error {
 	// Convert identifier (UUID or integer ID) to UUID.
 	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -396,9 +394,7 @@ func (c *ClientAPI) SendPowerCommand(serverIdentifier, command string) error {
 }
 
 // SendCommand sends a console command to a server by UUID or integer ID.
-func (c *ClientAPI) SendCommand(serverIdentifier, command string) error {
-	ctx := context.Background()
-
+func (c *ClientAPI) SendCommand(ctx context.Context, serverIdentifier, command string) error {
 	// Convert identifier (UUID or integer ID) to UUID.
 	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -423,9 +419,7 @@ func (c *ClientAPI) SendCommand(serverIdentifier, command string) error {
 }
 
 // ListBackups lists backups for a server by UUID or integer ID.
-func (c *ClientAPI) ListBackups(serverIdentifier string) ([]map[string]any, error) {
-	ctx := context.Background()
-
+func (c *ClientAPI) ListBackups(ctx context.Context, serverIdentifier string) ([]map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to UUID.
 	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -451,9 +445,7 @@ func (c *ClientAPI) ListBackups(serverIdentifier string) ([]map[string]any, erro
 }
 
 // CreateBackup creates a backup for a server by UUID or integer ID.
-func (c *ClientAPI) CreateBackup(serverIdentifier string) (map[string]any, error) {
-	ctx := context.Background()
-
+func (c *ClientAPI) CreateBackup(ctx context.Context, serverIdentifier string) (map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to UUID.
 	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -494,10 +486,182 @@ func (c *ClientAPI) CreateBackup(serverIdentifier string) (map[string]any, error
 	return convertInterfaceToMap(backup)
 }
 
-// ListDatabases lists databases for a server by UUID or integer ID.
-func (c *ClientAPI) ListDatabases(serverIdentifier string) ([]map[string]any, error) {
-	ctx := context.Background()
+// GetBackup gets a single backup for a server by UUID or integer ID.
+func (c *ClientAPI) GetBackup(ctx context.Context, serverIdentifier, backupUUID string) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := makeRawRequest(c.genClient.BackupView(ctx, serverUUID, backupUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSingle(body)
+}
+
+// DeleteBackup deletes a backup for a server by UUID or integer ID.
+func (c *ClientAPI) DeleteBackup(ctx context.Context, serverIdentifier, backupUUID string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	httpResp, err := c.genClient.BackupDelete(ctx, serverUUID, backupUUID)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
 
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return handleErrorResponse(httpResp, bodyBytes)
+	}
+
+	return nil
+}
+
+// SetBackupLocked locks or unlocks a backup for a server by UUID or integer ID.
+// The panel only exposes a toggle, so the current state is checked first and the
+// toggle is skipped when the backup is already in the requested state.
+func (c *ClientAPI) SetBackupLocked(
+	ctx context.Context,
+	serverIdentifier, backupUUID string,
+	locked bool,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := makeRawRequest(c.genClient.BackupView(ctx, serverUUID, backupUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := decodeSingle(body)
+	if err != nil {
+		return nil, err
+	}
+
+	isLocked, hasLocked := backup["is_locked"].(bool)
+	if attrs, hasAttrs := backup["attributes"].(map[string]any); !hasLocked && hasAttrs {
+		isLocked, _ = attrs["is_locked"].(bool)
+	}
+	if isLocked == locked {
+		return backup, nil
+	}
+
+	body, err = makeRawRequest(c.genClient.BackupToggleLock(ctx, serverUUID, backupUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSingle(body)
+}
+
+// DownloadBackup opens a stream of a backup archive for a server by UUID or integer ID.
+// The caller is responsible for closing the returned body.
+func (c *ClientAPI) DownloadBackup(ctx context.Context, serverIdentifier, backupUUID string) (io.ReadCloser, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.downloadBackupStream(ctx, serverUUID, backupUUID)
+}
+
+// downloadBackupStream resolves the signed download URL for a backup and opens it.
+func (c *ClientAPI) downloadBackupStream(ctx context.Context, serverUUID, backupUUID string) (io.ReadCloser, error) {
+	body, err := makeRawRequest(c.genClient.BackupDownload(ctx, serverUUID, backupUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	signedURL, err := extractSignedURL(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	httpResp, err := panelHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return nil, handleErrorResponse(httpResp, bodyBytes)
+	}
+
+	return progress.NewReader(httpResp.Body, "download", backupUUID, httpResp.ContentLength), nil
+}
+
+// CopyBackup streams a backup archive from one server into remoteDir on another server.
+// The archive is piped straight from the source download into the destination upload.
+// If extract is set, the archive is decompressed into remoteDir once uploaded.
+// Returns the remote path of the uploaded archive.
+func (c *ClientAPI) CopyBackup(
+	ctx context.Context,
+	srcIdentifier, backupUUID, dstIdentifier, remoteDir string,
+	extract bool,
+) (string, error) {
+	// Convert identifiers (UUID or integer ID) to UUIDs.
+	srcUUID, err := c.getServerUUIDFromIdentifier(ctx, srcIdentifier)
+	if err != nil {
+		return "", err
+	}
+	dstUUID, err := c.getServerUUIDFromIdentifier(ctx, dstIdentifier)
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := c.downloadBackupStream(ctx, srcUUID, backupUUID)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	fileName := backupUUID + ".tar.gz"
+	if err := c.uploadStream(ctx, dstUUID, fileName, remoteDir, archive); err != nil {
+		return "", err
+	}
+
+	archivePath := path.Join("/", remoteDir, fileName)
+	if !extract {
+		return archivePath, nil
+	}
+
+	root := path.Join("/", remoteDir)
+	httpResp, err := c.genClient.FileDecompress(ctx, dstUUID, client.FileDecompressJSONRequestBody{
+		File: fileName,
+		Root: &root,
+	})
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return "", handleErrorResponse(httpResp, bodyBytes)
+	}
+
+	return archivePath, nil
+}
+
+// ListDatabases lists databases for a server by UUID or integer ID.
+func (c *ClientAPI) ListDatabases(ctx context.Context, serverIdentifier string) ([]map[string]any, error) {
 	// Convert identifier (UUID or integer ID) to UUID.
 	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -522,10 +686,147 @@ func (c *ClientAPI) ListDatabases(serverIdentifier string) ([]map[string]any, er
 	return convertInterfaceSliceToMapSlice(&databases)
 }
 
-// DownloadFile downloads a file from the server by UUID or integer ID.
-func (c *ClientAPI) DownloadFile(serverIdentifier, filePath string) (io.ReadCloser, error) {
-	ctx := context.Background()
+// CreateDatabase creates a database for a server by UUID or integer ID.
+// remote is the host pattern allowed to connect (e.g. "%" for any host).
+func (c *ClientAPI) CreateDatabase(ctx context.Context, serverIdentifier, name, remote string) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := c.genClient.DatabaseStore(ctx, serverUUID, client.DatabaseStoreJSONRequestBody{
+		Database: name,
+		Remote:   remote,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
 
+	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
+		return nil, handleErrorResponse(httpResp, body)
+	}
+
+	return decodeSingle(body)
+}
+
+// DeleteDatabase deletes a database for a server by UUID or integer ID.
+// The database may be given by ID or name.
+func (c *ClientAPI) DeleteDatabase(ctx context.Context, serverIdentifier, databaseIdentifier string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := c.getDatabaseIDFromIdentifier(ctx, serverUUID, databaseIdentifier)
+	if err != nil {
+		return err
+	}
+
+	httpResp, err := c.genClient.DatabaseDelete(ctx, serverUUID, databaseID)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return handleErrorResponse(httpResp, bodyBytes)
+	}
+
+	return nil
+}
+
+// RotateDatabasePassword rotates the password of a database for a server by UUID or integer ID.
+// The database may be given by ID or name. Returns the database including the new password.
+func (c *ClientAPI) RotateDatabasePassword(
+	ctx context.Context,
+	serverIdentifier, databaseIdentifier string,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	databaseID, err := c.getDatabaseIDFromIdentifier(ctx, serverUUID, databaseIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := makeRawRequest(c.genClient.DatabaseRotatePassword(ctx, serverUUID, databaseID))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSingle(body)
+}
+
+// getDatabaseIDFromIdentifier converts a database identifier (integer ID or name) to an integer ID.
+func (c *ClientAPI) getDatabaseIDFromIdentifier(ctx context.Context, serverUUID, identifier string) (int, error) {
+	if id, err := strconv.Atoi(identifier); err == nil {
+		return id, nil
+	}
+	if err := strict.Lookup("database", identifier, "an ID"); err != nil {
+		return 0, err
+	}
+
+	databases, err := c.ListDatabases(ctx, serverUUID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list databases to look up ID: %w", err)
+	}
+
+	for _, database := range databases {
+		attrs := database
+		if nested, ok := database["attributes"].(map[string]any); ok {
+			attrs = nested
+		}
+		if name, _ := attrs["name"].(string); name != identifier {
+			continue
+		}
+		switch id := attrs["id"].(type) {
+		case float64:
+			return int(id), nil
+		case string:
+			if parsed, parseErr := strconv.Atoi(id); parseErr == nil {
+				return parsed, nil
+			}
+		}
+		return 0, fmt.Errorf("database %s has no numeric ID", identifier)
+	}
+
+	return 0, apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("database %s not found", identifier))
+}
+
+// decodeSingle decodes a single resource from a wrapped or plain response body.
+func decodeSingle(body []byte) (map[string]any, error) {
+	unwrapped, unwrapErr := handleWrappedResponse(body)
+	if unwrapErr != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
+	}
+
+	var resource any
+	if err := json.Unmarshal(unwrapped, &resource); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// If it's a slice with one item, extract it.
+	if arr, ok := resource.([]any); ok && len(arr) > 0 {
+		return convertInterfaceToMap(arr[0])
+	}
+
+	return convertInterfaceToMap(resource)
+}
+
+// DownloadFile downloads a file from the server by UUID or integer ID.
+func (c *ClientAPI) DownloadFile(ctx context.Context, serverIdentifier, filePath string) (io.ReadCloser, error) {
 	// Convert identifier (UUID or integer ID) to UUID.
 	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
 	if err != nil {
@@ -550,11 +851,182 @@ func (c *ClientAPI) DownloadFile(serverIdentifier, filePath string) (io.ReadClos
 	}
 
 	// Return the response body - caller is responsible for closing.
-	return httpResp.Body, nil
+	return progress.NewReader(httpResp.Body, "download", filePath, httpResp.ContentLength), nil
+}
+
+// GetFileContents reads a file from the server by UUID or integer ID.
+// A file that doesn't exist is reported as exists=false rather than as an error.
+func (c *ClientAPI) GetFileContents(ctx context.Context, serverIdentifier, filePath string) ([]byte, bool, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, false, err
+	}
+
+	params := &client.FileContentsParams{File: filePath}
+	httpResp, err := c.genClient.FileContents(ctx, serverUUID, params)
+	if err != nil {
+		return nil, false, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+		return body, true, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, handleErrorResponse(httpResp, body)
+	}
+}
+
+// BackupFile saves content as the previous version of filePath, next to it as <name>.bak-<timestamp>.
+// It returns the remote path of the backup.
+func (c *ClientAPI) BackupFile(ctx context.Context, serverIdentifier, filePath string, content []byte) (string, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return "", err
+	}
+
+	filePath = path.Clean("/" + filePath)
+	backupName := path.Base(filePath) + ".bak-" + time.Now().Format("20060102-150405")
+	remoteDir := path.Dir(filePath)
+	if err := c.uploadStream(ctx, serverUUID, backupName, remoteDir, bytes.NewReader(content)); err != nil {
+		return "", err
+	}
+	return path.Join(remoteDir, backupName), nil
+}
+
+// DeleteFile deletes a single file or directory from the server by UUID or integer ID.
+func (c *ClientAPI) DeleteFile(ctx context.Context, serverIdentifier, filePath string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	// The API deletes names relative to a root directory.
+	root := path.Dir(path.Clean("/" + filePath))
+	body := client.FileDeleteJSONRequestBody{
+		Root:  &root,
+		Files: []string{path.Base(filePath)},
+	}
+
+	httpResp, err := c.genClient.FileDelete(ctx, serverUUID, body)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return handleErrorResponse(httpResp, bodyBytes)
+	}
+
+	return nil
 }
 
-// UploadFile uploads a file to the server.
-func (c *ClientAPI) UploadFile(_, _, _ string) error {
-	// This is a simplified version - actual implementation would need multipart form data.
-	return errors.New("file upload not yet implemented")
+// extractSignedURL extracts the URL from a signed URL response ({"object": "signed_url", "attributes": {"url": ...}}).
+func extractSignedURL(body []byte) (string, error) {
+	var signed struct {
+		Attributes struct {
+			URL string `json:"url"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(body, &signed); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if signed.Attributes.URL == "" {
+		return "", errors.New("response did not contain a signed URL")
+	}
+	return signed.Attributes.URL, nil
+}
+
+// UploadFile uploads a local file into remoteDir on the server by UUID or integer ID.
+// The file is streamed to the signed upload URL returned by the panel without buffering it in memory.
+func (c *ClientAPI) UploadFile(ctx context.Context, serverIdentifier, localPath, remoteDir string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open local file: %w", err)
+	}
+	defer file.Close()
+
+	var size int64
+	if info, statErr := file.Stat(); statErr == nil {
+		size = info.Size()
+	}
+	content := progress.NewReader(file, "upload", localPath, size)
+
+	return c.uploadStream(ctx, serverUUID, filepath.Base(localPath), remoteDir, content)
+}
+
+// uploadStream streams content as a multipart upload named fileName into remoteDir.
+func (c *ClientAPI) uploadStream(ctx context.Context, serverUUID, fileName, remoteDir string, content io.Reader) error {
+	body, err := makeRawRequest(c.genClient.ServersFileUpload(ctx, serverUUID))
+	if err != nil {
+		return err
+	}
+
+	signedURL, err := extractSignedURL(body)
+	if err != nil {
+		return err
+	}
+
+	uploadURL, err := url.Parse(signedURL)
+	if err != nil {
+		return fmt.Errorf("invalid upload URL: %w", err)
+	}
+	if remoteDir != "" {
+		query := uploadURL.Query()
+		query.Set("directory", remoteDir)
+		uploadURL.RawQuery = query.Encode()
+	}
+
+	// Stream the multipart body through a pipe so large files are never held in memory.
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+	go func() {
+		part, partErr := writer.CreateFormFile("files", fileName)
+		if partErr != nil {
+			pipeWriter.CloseWithError(partErr)
+			return
+		}
+		if _, copyErr := io.Copy(part, content); copyErr != nil {
+			pipeWriter.CloseWithError(copyErr)
+			return
+		}
+		pipeWriter.CloseWithError(writer.Close())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL.String(), pipeReader)
+	if err != nil {
+		_ = pipeReader.Close()
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	httpResp, err := panelHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return handleErrorResponse(httpResp, bodyBytes)
+	}
+
+	return nil
 }
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.lostcrafters.com/pelicanctl/internal/client"
+)
+
+// ListAPIKeys lists the Client API keys of the authenticated user.
+func (c *ClientAPI) ListAPIKeys(ctx context.Context) ([]map[string]any, error) {
+	body, err := makeRawRequest(c.genClient.ApiKeyIndex(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle wrapped response.
+	unwrapped, unwrapErr := handleWrappedResponse(body)
+	if unwrapErr != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
+	}
+
+	var keys []any
+	if err := json.Unmarshal(unwrapped, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return convertInterfaceSliceToMapSlice(&keys)
+}
+
+// CreateAPIKey creates a Client API key for the authenticated user.
+// allowedIPs restricts the addresses the key may be used from; empty allows any address.
+// The returned key includes the full token under "meta.secret_token", which the panel only reveals once.
+func (c *ClientAPI) CreateAPIKey(ctx context.Context, description string, allowedIPs []string) (map[string]any, error) {
+	req := client.ApiKeyStoreJSONRequestBody{Description: &description}
+	if len(allowedIPs) > 0 {
+		req.AllowedIps = &allowedIPs
+	}
+
+	return readResourceResponse(c.genClient.ApiKeyStore(ctx, req))
+}
+
+// DeleteAPIKey deletes a Client API key of the authenticated user by its identifier.
+func (c *ClientAPI) DeleteAPIKey(ctx context.Context, identifier string) error {
+	return checkEmptyResponse(c.genClient.ApiKeyDelete(ctx, identifier))
+}
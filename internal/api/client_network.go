@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.lostcrafters.com/pelicanctl/internal/client"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
+)
+
+// ListAllocations lists the network allocations of a server by UUID or integer ID.
+func (c *ClientAPI) ListAllocations(ctx context.Context, serverIdentifier string) ([]map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := makeRawRequest(c.genClient.NetworkAllocationIndex(ctx, serverUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle wrapped response.
+	unwrapped, unwrapErr := handleWrappedResponse(body)
+	if unwrapErr != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
+	}
+
+	var allocations []any
+	if err := json.Unmarshal(unwrapped, &allocations); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return convertInterfaceSliceToMapSlice(&allocations)
+}
+
+// AssignAllocation assigns a new allocation from the node's pool to a server by UUID or integer ID.
+// The panel picks the allocation, so this only succeeds when automatic allocation is enabled.
+func (c *ClientAPI) AssignAllocation(ctx context.Context, serverIdentifier string) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return readResourceResponse(c.genClient.NetworkAllocationStore(ctx, serverUUID))
+}
+
+// UnassignAllocation removes an allocation from a server by UUID or integer ID.
+// The allocation may be given by ID, port or ip:port. The primary allocation cannot be removed.
+func (c *ClientAPI) UnassignAllocation(ctx context.Context, serverIdentifier, allocationIdentifier string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	allocationID, err := c.getAllocationIDFromIdentifier(ctx, serverUUID, allocationIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return checkEmptyResponse(c.genClient.NetworkAllocationDelete(ctx, serverUUID, allocationID))
+}
+
+// SetPrimaryAllocation makes an allocation the primary allocation of a server by UUID or integer ID.
+// The allocation may be given by ID, port or ip:port.
+func (c *ClientAPI) SetPrimaryAllocation(
+	ctx context.Context,
+	serverIdentifier, allocationIdentifier string,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	allocationID, err := c.getAllocationIDFromIdentifier(ctx, serverUUID, allocationIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return readResourceResponse(c.genClient.NetworkAllocationSetPrimary(ctx, serverUUID, allocationID))
+}
+
+// SetAllocationNote sets the note of an allocation for a server by UUID or integer ID.
+// The allocation may be given by ID, port or ip:port. An empty note clears it.
+func (c *ClientAPI) SetAllocationNote(
+	ctx context.Context,
+	serverIdentifier, allocationIdentifier, note string,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	allocationID, err := c.getAllocationIDFromIdentifier(ctx, serverUUID, allocationIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	req := client.NetworkAllocationUpdateJSONRequestBody{}
+	if note != "" {
+		req.Notes = &note
+	}
+
+	return readResourceResponse(c.genClient.NetworkAllocationUpdate(ctx, serverUUID, allocationID, req))
+}
+
+// getAllocationIDFromIdentifier converts an allocation identifier to an allocation ID.
+// Accepted forms are "ip:port", ":port", an allocation ID, or a port number.
+//
+// In strict mode only allocation IDs are accepted, since a bare number could also be a port.
+func (c *ClientAPI) getAllocationIDFromIdentifier(ctx context.Context, serverUUID, identifier string) (int, error) {
+	if strict.Enabled() {
+		if id, err := strconv.Atoi(identifier); err == nil {
+			return id, nil
+		}
+		return 0, strict.Lookup("allocation", identifier, "an ID")
+	}
+
+	allocations, err := c.ListAllocations(ctx, serverUUID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list allocations to look up ID: %w", err)
+	}
+
+	ip, port, hasIP := strings.Cut(identifier, ":")
+	if !hasIP {
+		ip, port = "", identifier
+	}
+	number, err := strconv.Atoi(port)
+	if err != nil {
+		return 0, fmt.Errorf("invalid allocation: %s (use an ID, port or ip:port)", identifier)
+	}
+
+	// A bare number is matched against allocation IDs first, then against ports.
+	if !hasIP {
+		for _, allocation := range allocations {
+			if allocationInt(allocation, "id") == number {
+				return number, nil
+			}
+		}
+	}
+	for _, allocation := range allocations {
+		if allocationInt(allocation, "port") != number {
+			continue
+		}
+		if ip != "" {
+			allocIP, _ := allocationField(allocation, "ip").(string)
+			alias, _ := allocationField(allocation, "ip_alias").(string)
+			if ip != allocIP && ip != alias {
+				continue
+			}
+		}
+		return allocationInt(allocation, "id"), nil
+	}
+
+	return 0, apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("allocation %s not found", identifier))
+}
+
+// allocationField reads a field from the root of an allocation or from its attributes.
+func allocationField(allocation map[string]any, key string) any {
+	if nested, ok := allocation["attributes"].(map[string]any); ok {
+		return nested[key]
+	}
+	return allocation[key]
+}
+
+// allocationInt reads a numeric allocation field, returning -1 when it is missing.
+func allocationInt(allocation map[string]any, key string) int {
+	switch v := allocationField(allocation, key).(type) {
+	case float64:
+		return int(v)
+	case string:
+		if parsed, err := strconv.Atoi(v); err == nil {
+			return parsed
+		}
+	}
+	return -1
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.lostcrafters.com/pelicanctl/internal/client"
+)
+
+// ListSchedules lists schedules for a server by UUID or integer ID.
+func (c *ClientAPI) ListSchedules(ctx context.Context, serverIdentifier string) ([]map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := makeRawRequest(c.genClient.ScheduleIndex(ctx, serverUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle wrapped response.
+	unwrapped, unwrapErr := handleWrappedResponse(body)
+	if unwrapErr != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", unwrapErr)
+	}
+
+	var schedules []any
+	if err := json.Unmarshal(unwrapped, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return convertInterfaceSliceToMapSlice(&schedules)
+}
+
+// GetSchedule gets a schedule, including its tasks, for a server by UUID or integer ID.
+func (c *ClientAPI) GetSchedule(ctx context.Context, serverIdentifier string, scheduleID int) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := makeRawRequest(c.genClient.ScheduleView(ctx, serverUUID, scheduleID))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSingle(body)
+}
+
+// CreateSchedule creates a schedule for a server by UUID or integer ID.
+func (c *ClientAPI) CreateSchedule(
+	ctx context.Context,
+	serverIdentifier string,
+	scheduleData map[string]any,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var scheduleReq client.StoreScheduleRequest
+	if err := convertMapToRequest(scheduleData, &scheduleReq); err != nil {
+		return nil, err
+	}
+
+	return readResourceResponse(c.genClient.ScheduleStore(ctx, serverUUID, scheduleReq))
+}
+
+// UpdateSchedule updates a schedule for a server by UUID or integer ID.
+// The panel expects the full schedule, so scheduleData must include all cron fields and the name.
+func (c *ClientAPI) UpdateSchedule(
+	ctx context.Context,
+	serverIdentifier string,
+	scheduleID int,
+	scheduleData map[string]any,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var scheduleReq client.UpdateScheduleRequest
+	if err := convertMapToRequest(scheduleData, &scheduleReq); err != nil {
+		return nil, err
+	}
+
+	return readResourceResponse(c.genClient.ScheduleUpdate(ctx, serverUUID, scheduleID, scheduleReq))
+}
+
+// DeleteSchedule deletes a schedule for a server by UUID or integer ID.
+func (c *ClientAPI) DeleteSchedule(ctx context.Context, serverIdentifier string, scheduleID int) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return checkEmptyResponse(c.genClient.ScheduleDelete(ctx, serverUUID, scheduleID))
+}
+
+// ExecuteSchedule triggers a schedule to run now for a server by UUID or integer ID.
+func (c *ClientAPI) ExecuteSchedule(ctx context.Context, serverIdentifier string, scheduleID int) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return checkEmptyResponse(c.genClient.ScheduleExecute(ctx, serverUUID, scheduleID))
+}
+
+// CreateScheduleTask adds a task to a schedule for a server by UUID or integer ID.
+func (c *ClientAPI) CreateScheduleTask(
+	ctx context.Context,
+	serverIdentifier string,
+	scheduleID int,
+	taskData map[string]any,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskReq client.ScheduleTaskStoreJSONRequestBody
+	if err := convertMapToRequest(taskData, &taskReq); err != nil {
+		return nil, err
+	}
+
+	return readResourceResponse(c.genClient.ScheduleTaskStore(ctx, serverUUID, scheduleID, taskReq))
+}
+
+// UpdateScheduleTask updates a task of a schedule for a server by UUID or integer ID.
+func (c *ClientAPI) UpdateScheduleTask(
+	ctx context.Context,
+	serverIdentifier string,
+	scheduleID, taskID int,
+	taskData map[string]any,
+) (map[string]any, error) {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskReq client.ScheduleTaskUpdateJSONRequestBody
+	if err := convertMapToRequest(taskData, &taskReq); err != nil {
+		return nil, err
+	}
+
+	return readResourceResponse(c.genClient.ScheduleTaskUpdate(ctx, serverUUID, scheduleID, taskID, taskReq))
+}
+
+// DeleteScheduleTask removes a task from a schedule for a server by UUID or integer ID.
+func (c *ClientAPI) DeleteScheduleTask(ctx context.Context, serverIdentifier string, scheduleID, taskID int) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return checkEmptyResponse(c.genClient.ScheduleTaskDelete(ctx, serverUUID, scheduleID, taskID))
+}
+
+// ScheduleTasks extracts the tasks embedded in a schedule response (relationships.tasks.data).
+func ScheduleTasks(schedule map[string]any) []map[string]any {
+	attrs := schedule
+	if nested, ok := schedule["attributes"].(map[string]any); ok {
+		attrs = nested
+	}
+
+	relationships, _ := attrs["relationships"].(map[string]any)
+	tasksRel, _ := relationships["tasks"].(map[string]any)
+	data, _ := tasksRel["data"].([]any)
+
+	tasks := make([]map[string]any, 0, len(data))
+	for _, item := range data {
+		if task, ok := item.(map[string]any); ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// convertMapToRequest converts a generic map into a generated request body via JSON.
+func convertMapToRequest(data map[string]any, target any) error {
+	if data == nil {
+		return nil
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request data: %w", err)
+	}
+	if err := json.Unmarshal(jsonData, target); err != nil {
+		return fmt.Errorf("failed to unmarshal request data: %w", err)
+	}
+	return nil
+}
+
+// readResourceResponse reads a create/update response (200 or 201) and decodes the returned resource.
+func readResourceResponse(httpResp *http.Response, err error) (map[string]any, error) {
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
+		return nil, handleErrorResponse(httpResp, body)
+	}
+
+	return decodeSingle(body)
+}
+
+// checkEmptyResponse checks a response that carries no body on success (e.g. 204 No Content).
+func checkEmptyResponse(httpResp *http.Response, err error) error {
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(httpResp.Body)
+		return handleErrorResponse(httpResp, bodyBytes)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+
+	"go.lostcrafters.com/pelicanctl/internal/client"
+)
+
+// RenameServer renames a server by UUID or integer ID.
+func (c *ClientAPI) RenameServer(ctx context.Context, serverIdentifier, name string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return checkEmptyResponse(c.genClient.SettingsRename(ctx, serverUUID, client.SettingsRenameJSONRequestBody{
+		Name: name,
+	}))
+}
+
+// ReinstallServer reinstalls a server by UUID or integer ID, re-running its egg install script.
+func (c *ClientAPI) ReinstallServer(ctx context.Context, serverIdentifier string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return checkEmptyResponse(c.genClient.SettingsReinstall(ctx, serverUUID))
+}
+
+// SetDockerImage changes the Docker image of a server by UUID or integer ID.
+// The image must be one of the images allowed by the server's egg.
+func (c *ClientAPI) SetDockerImage(ctx context.Context, serverIdentifier, image string) error {
+	// Convert identifier (UUID or integer ID) to UUID.
+	serverUUID, err := c.getServerUUIDFromIdentifier(ctx, serverIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return checkEmptyResponse(c.genClient.SettingsDockerImage(ctx, serverUUID, client.SettingsDockerImageJSONRequestBody{
+		DockerImage: image,
+	}))
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/websocket"
+)
+
+// ServerStats is a resource usage sample of a server, streamed by Wings over the server websocket.
+type ServerStats struct {
+	State            string  `json:"state"`
+	MemoryBytes      int64   `json:"memory_bytes"`
+	MemoryLimitBytes int64   `json:"memory_limit_bytes"`
+	CPUAbsolute      float64 `json:"cpu_absolute"`
+	Network          struct {
+		RxBytes int64 `json:"rx_bytes"`
+		TxBytes int64 `json:"tx_bytes"`
+	} `json:"network"`
+	DiskBytes int64 `json:"disk_bytes"`
+	// Uptime is in milliseconds.
+	Uptime int64 `json:"uptime"`
+}
+
+// ansiEscape matches the ANSI escape sequences coloring console output.
+//
+//nolint:gochecknoglobals // Compiled once, like a constant
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// websocketCredentials are the socket URL and token the panel hands out for a server's websocket.
+type websocketCredentials struct {
+	Token  string `json:"token"`
+	Socket string `json:"socket"`
+}
+
+// websocketEvent is a message of the Wings websocket protocol.
+type websocketEvent struct {
+	Event string `json:"event"`
+	Args  []any  `json:"args"`
+}
+
+// StreamServerStats connects to the websocket of a server by UUID or short identifier and calls onStats
+// for every stats sample until ctx is done. The socket token is renewed when Wings reports it expiring.
+func (c *ClientAPI) StreamServerStats(ctx context.Context, identifier string, onStats func(ServerStats)) error {
+	uuid, err := c.getServerUUIDFromIdentifier(ctx, identifier)
+	if err != nil {
+		return fmt.Errorf("failed to get server UUID: %w", err)
+	}
+
+	return c.streamServerEvents(ctx, uuid, func(conn *websocket.Conn) error {
+		// Ask for a sample right away instead of waiting for the next periodic one.
+		if err := sendWebsocketEvent(conn, "send stats", nil); err != nil {
+			return fmt.Errorf("failed to request stats: %w", err)
+		}
+		return nil
+	}, func(event websocketEvent) {
+		if event.Event != "stats" {
+			return
+		}
+		if stats, ok := parseServerStats(event.Args); ok {
+			onStats(stats)
+		}
+	})
+}
+
+// CaptureCommand sends a console command to a server by UUID or short identifier over its websocket and
+// returns the console lines the server prints during the capture time after it, without ANSI colors.
+// Unlike SendCommand, which only hands the command to the panel, this shows what the command answered.
+func (c *ClientAPI) CaptureCommand(
+	ctx context.Context,
+	identifier, command string,
+	capture time.Duration,
+) ([]string, error) {
+	uuid, err := c.getServerUUIDFromIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server UUID: %w", err)
+	}
+
+	captureCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lines []string
+	err = c.streamServerEvents(captureCtx, uuid, func(conn *websocket.Conn) error {
+		// The command is sent once the socket is subscribed, so none of its output is missed.
+		if err := sendWebsocketEvent(conn, "send command", command); err != nil {
+			return fmt.Errorf("failed to send command: %w", err)
+		}
+		time.AfterFunc(capture, cancel)
+		return nil
+	}, func(event websocketEvent) {
+		if event.Event != "console output" || len(event.Args) == 0 {
+			return
+		}
+		if line, ok := event.Args[0].(string); ok {
+			lines = append(lines, ansiEscape.ReplaceAllString(line, ""))
+		}
+	})
+	if err != nil {
+		return lines, err
+	}
+	// The stream also ends without error when ctx is done, which is not the end of the capture time.
+	if ctx.Err() != nil {
+		return lines, ctx.Err()
+	}
+	return lines, nil
+}
+
+// streamServerEvents connects to the websocket of a server, calls onAuth once it is authenticated and onEvent
+// for every other event until ctx is done. The socket token is renewed when Wings reports it expiring.
+func (c *ClientAPI) streamServerEvents(
+	ctx context.Context,
+	uuid string,
+	onAuth func(*websocket.Conn) error,
+	onEvent func(websocketEvent),
+) error {
+	creds, err := c.getWebsocketCredentials(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	// Wings only accepts connections that come from the panel.
+	header := http.Header{}
+	header.Set("Origin", strings.TrimSuffix(config.Get().API.BaseURL, "/"))
+	conn, err := websocket.Dial(ctx, creds.Socket, header)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Reading blocks, so closing the connection is what ends the stream when ctx is done.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := sendWebsocketEvent(conn, "auth", creds.Token); err != nil {
+		return fmt.Errorf("failed to authenticate websocket: %w", err)
+	}
+
+	authenticated := false
+	for {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, websocket.ErrClosed) {
+				return errors.New("websocket closed by the server")
+			}
+			return fmt.Errorf("websocket read failed: %w", err)
+		}
+
+		var event websocketEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			continue
+		}
+
+		switch event.Event {
+		case "auth success":
+			// Wings confirms every renewed token too, but the stream is only set up once.
+			if authenticated {
+				continue
+			}
+			authenticated = true
+			if err := onAuth(conn); err != nil {
+				return err
+			}
+		case "token expiring", "token expired":
+			renewed, err := c.getWebsocketCredentials(ctx, uuid)
+			if err != nil {
+				return err
+			}
+			if err := sendWebsocketEvent(conn, "auth", renewed.Token); err != nil {
+				return fmt.Errorf("failed to renew websocket token: %w", err)
+			}
+		case "jwt error":
+			return fmt.Errorf("websocket authentication failed: %v", event.Args)
+		default:
+			onEvent(event)
+		}
+	}
+}
+
+// getWebsocketCredentials gets the socket URL and token of a server's websocket.
+func (c *ClientAPI) getWebsocketCredentials(ctx context.Context, uuid string) (websocketCredentials, error) {
+	body, err := makeRawRequest(c.genClient.ApiClientServerWs(ctx, uuid))
+	if err != nil {
+		return websocketCredentials{}, err
+	}
+
+	var resp struct {
+		Data websocketCredentials `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return websocketCredentials{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.Data.Socket == "" || resp.Data.Token == "" {
+		return websocketCredentials{}, errors.New("panel returned no websocket credentials")
+	}
+	return resp.Data, nil
+}
+
+// sendWebsocketEvent sends an event with a single argument.
+func sendWebsocketEvent(conn *websocket.Conn, event string, arg any) error {
+	data, err := json.Marshal(websocketEvent{Event: event, Args: []any{arg}})
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(data)
+}
+
+// parseServerStats decodes the stats event, whose argument is the sample encoded as a JSON string.
+func parseServerStats(args []any) (ServerStats, bool) {
+	if len(args) == 0 {
+		return ServerStats{}, false
+	}
+	raw, ok := args[0].(string)
+	if !ok {
+		return ServerStats{}, false
+	}
+	var stats ServerStats
+	if err := json.Unmarshal([]byte(raw), &stats); err != nil {
+		return ServerStats{}, false
+	}
+	return stats, true
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// DryRunRequest is a request a dry run stopped before it was sent. The API call that would have sent it
+// returns it as its error. Body holds the body of JSON requests; of others, such as file uploads, only the
+// Size is kept.
+type DryRunRequest struct {
+	Method      string
+	URL         string
+	ContentType string
+	Body        []byte
+	Size        int64
+}
+
+// Error implements error.
+func (r *DryRunRequest) Error() string {
+	return fmt.Sprintf("dry run: %s %s was not sent", r.Method, r.URL)
+}
+
+// Output returns the request as it is printed, with a JSON body decoded.
+func (r *DryRunRequest) Output() output.Request {
+	req := output.Request{Method: r.Method, URL: r.URL}
+	var body any
+	switch {
+	case r.Body != nil && json.Unmarshal(r.Body, &body) == nil:
+		req.Body = body
+	case r.Body != nil:
+		req.Body = string(r.Body)
+	case r.Size > 0:
+		// The boundary of a multipart body and other parameters say nothing about what is sent.
+		mediaType, _, err := mime.ParseMediaType(r.ContentType)
+		if err != nil {
+			mediaType = r.ContentType
+		}
+		req.Body = fmt.Sprintf("%s body of %d bytes", mediaType, r.Size)
+	}
+	return req
+}
+
+// dryRunKey marks the context of a dry run.
+type dryRunKey struct{}
+
+// WithDryRun returns a context in which requests that change anything are not sent, for --dry-run. Reads
+// still go out, so identifiers resolve and checks run as usual; the first other request ends the API call
+// with a *DryRunRequest holding the exact request.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// AsDryRun returns the request a dry run stopped, if err is one.
+func AsDryRun(err error) (*DryRunRequest, bool) {
+	var req *DryRunRequest
+	ok := errors.As(err, &req)
+	return req, ok
+}
+
+// dryRunTransport is an http.RoundTripper that stops every request other than a read in the context of a
+// dry run, before it is retried, cached or sent.
+type dryRunTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	dryRun, _ := req.Context().Value(dryRunKey{}).(bool)
+	if !dryRun || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req) //nolint:wrapcheck // Transport must pass errors through unchanged
+	}
+
+	stopped := &DryRunRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		ContentType: req.Header.Get("Content-Type"),
+	}
+	if req.Body == nil {
+		return nil, stopped
+	}
+
+	// A RoundTripper must close the body, even when it doesn't send the request.
+	defer req.Body.Close()
+	if mediaType, _, _ := mime.ParseMediaType(stopped.ContentType); mediaType != "application/json" {
+		// Uploads are read to the end, so a body streamed through a pipe is finished, but not kept.
+		size, err := io.Copy(io.Discard, req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		stopped.Size = size
+		return nil, stopped
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	stopped.Body = body
+	stopped.Size = int64(len(body))
+	return nil, stopped
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/httpcache"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/retry"
+	"go.lostcrafters.com/pelicanctl/internal/timing"
+)
+
+var (
+	// panelLimiter spaces out the requests to the panel, shared by every client and bulk operation.
+	//
+	//nolint:gochecknoglobals // Rate limit applies to the whole process, like the panel's limit on the token
+	panelLimiter = &rateLimiter{}
+
+	// panelHTTPClient sends every request to the panel: stopped in a dry run unless it only reads,
+	// revalidated with the ETag of a cached response, retried on failure, with every attempt held to the
+	// rate limit and recorded for the timing summary.
+	//
+	//nolint:gochecknoglobals // Shared by every panel client so connections and the rate limit are shared too
+	panelHTTPClient = &http.Client{Transport: &dryRunTransport{base: &httpcache.Transport{Base: &retry.Transport{
+		Base: &rateLimitTransport{limiter: panelLimiter, base: &timing.Transport{
+			Base: &requestIDTransport{base: panelTransport},
+		}},
+	}}}}
+
+	// externalHTTPClient sends the requests that don't go to the panel, to Wings daemons and egg URLs,
+	// which the panel's rate limit does not cover.
+	//
+	//nolint:gochecknoglobals // Shared by every client so connections are shared too
+	externalHTTPClient = &http.Client{Transport: &dryRunTransport{base: &retry.Transport{
+		Base: &timing.Transport{Base: &requestIDTransport{base: http.DefaultTransport}},
+	}}}
+)
+
+// setPanelRateLimit limits the requests to the panel to perMinute, from api.rate_limit.
+// Zero means no limit.
+func setPanelRateLimit(perMinute int) error {
+	if perMinute < 0 {
+		return apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("api.rate_limit must not be negative, got %d", perMinute))
+	}
+	panelLimiter.setRate(perMinute)
+	return nil
+}
+
+// rateLimiter is a token bucket holding a single token: requests are spaced out evenly, so no
+// minute sees more than the configured number of them.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// setRate sets the requests allowed per minute; zero disables the limiter.
+func (l *rateLimiter) setRate(perMinute int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.interval = 0
+	if perMinute > 0 {
+		l.interval = time.Minute / time.Duration(perMinute)
+	}
+}
+
+// wait blocks until the next request may be sent, or returns the error of ctx if it is done first.
+// Every caller reserves its own slot, so parallel bulk operations queue up in turn.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.interval == 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitTransport is an http.RoundTripper that holds every request to the rate limit.
+type rateLimitTransport struct {
+	limiter *rateLimiter
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req) //nolint:wrapcheck // Transport must pass errors through unchanged
+}
+
+// requestIDTransport is an http.RoundTripper that tags every request with the request ID of the command.
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it was given.
+	tagged := req.Clone(req.Context())
+	tagged.Header.Set(output.RequestIDHeader, output.RequestID())
+	return t.base.RoundTrip(tagged) //nolint:wrapcheck // Transport must pass errors through unchanged
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ListOptions selects the pages a list fetches and the panel-side filters and includes applied to it.
+// The zero value follows every page of the unfiltered list.
+type ListOptions struct {
+	// Page fetches this single page (starting at 1) instead of every page.
+	Page int
+	// PerPage sets the page size; 0 keeps the panel's default.
+	PerPage int
+	// Filter maps attributes to the values the panel filters the list by (filter[<attribute>]=<value>).
+	Filter map[string]string
+	// Include names the relationships the panel includes with every item.
+	Include []string
+	// Until stops following pages once it reports true for the items of a page, e.g. once a list
+	// sorted by time reaches items older than wanted.
+	Until func(items []map[string]any) bool
+}
+
+// AllPages reports whether every page is fetched.
+func (o ListOptions) AllPages() bool {
+	return o.Page == 0
+}
+
+// Complete reports whether the options select the full, unfiltered list.
+func (o ListOptions) Complete() bool {
+	return o.AllPages() && len(o.Filter) == 0
+}
+
+// DefaultPageConcurrency bounds the pages of a list fetched in parallel, unless --max-concurrency sets it.
+const DefaultPageConcurrency = 10
+
+var (
+	//nolint:gochecknoglobals // Set once from --max-concurrency, read by every list of the command
+	pageConcurrency = DefaultPageConcurrency
+	//nolint:gochecknoglobals // Global mutex needed to protect pageConcurrency
+	pageConcurrencyMutex sync.Mutex
+)
+
+// SetPageConcurrency sets how many pages of a list are fetched in parallel, like bulk operations: 1 fetches
+// them one after another, and 0 or less keeps the default.
+func SetPageConcurrency(n int) {
+	pageConcurrencyMutex.Lock()
+	defer pageConcurrencyMutex.Unlock()
+	pageConcurrency = DefaultPageConcurrency
+	if n > 0 {
+		pageConcurrency = n
+	}
+}
+
+func currentPageConcurrency() int {
+	pageConcurrencyMutex.Lock()
+	defer pageConcurrencyMutex.Unlock()
+	return pageConcurrency
+}
+
+// pageFetcher performs one list request with the given request editor, which sets the page, filters, and includes.
+type pageFetcher func(withQuery func(context.Context, *http.Request) error) (*http.Response, error)
+
+// fetchPages runs a paginated list request for every page selected by opts and returns the items of all of them.
+// The first page tells how many there are; the others are then fetched in parallel, and their items merged
+// in page order. With opts.Until, which decides on every page whether to go on, pages are fetched in turn.
+func fetchPages(
+	opts ListOptions,
+	fetch pageFetcher,
+	handleError func(*http.Response, []byte) error,
+) ([]map[string]any, error) {
+	page := max(opts.Page, 1)
+	concurrency := currentPageConcurrency()
+	var items []map[string]any
+	for {
+		pageItems, totalPages, err := fetchPage(opts, page, fetch, handleError)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+
+		if !opts.AllPages() || page >= totalPages || (opts.Until != nil && opts.Until(pageItems)) {
+			return items, nil
+		}
+		if opts.Until == nil && concurrency > 1 {
+			rest, err := fetchPagesConcurrently(opts, page+1, totalPages, concurrency, fetch, handleError)
+			if err != nil {
+				return nil, err
+			}
+			return append(items, rest...), nil
+		}
+		page++
+	}
+}
+
+// fetchPagesConcurrently fetches the pages first to last, at most concurrency at a time, and returns their
+// items in page order. Once a page fails no further ones are started, and the error of the earliest
+// failed page is returned.
+func fetchPagesConcurrently(
+	opts ListOptions,
+	first, last, concurrency int,
+	fetch pageFetcher,
+	handleError func(*http.Response, []byte) error,
+) ([]map[string]any, error) {
+	pages := make([][]map[string]any, last-first+1)
+	errs := make([]error, len(pages))
+	sem := make(chan struct{}, concurrency)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i := range pages {
+		sem <- struct{}{}
+		if failed.Load() {
+			<-sem
+			break
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			pages[i], _, errs[i] = fetchPage(opts, first+i, fetch, handleError)
+			if errs[i] != nil {
+				failed.Store(true)
+			}
+		})
+	}
+	wg.Wait()
+
+	var items []map[string]any
+	for i, pageItems := range pages {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		items = append(items, pageItems...)
+	}
+	return items, nil
+}
+
+// fetchPage fetches one page of a list, returning its items and the total number of pages.
+func fetchPage(
+	opts ListOptions,
+	page int,
+	fetch pageFetcher,
+	handleError func(*http.Response, []byte) error,
+) ([]map[string]any, int, error) {
+	withQuery := func(_ context.Context, req *http.Request) error {
+		query := req.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		if opts.PerPage > 0 {
+			query.Set("per_page", strconv.Itoa(opts.PerPage))
+		}
+		for attribute, value := range opts.Filter {
+			query.Set("filter["+attribute+"]", value)
+		}
+		if len(opts.Include) > 0 {
+			query.Set("include", strings.Join(opts.Include, ","))
+		}
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+
+	httpResp, err := fetch(withQuery)
+	return readPage(httpResp, err, handleError)
+}
+
+// readPage decodes one page of a list and the total number of pages from its pagination metadata.
+// A response without pagination metadata counts as a single page.
+func readPage(
+	httpResp *http.Response,
+	err error,
+	handleError func(*http.Response, []byte) error,
+) ([]map[string]any, int, error) {
+	if err != nil {
+		return nil, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, 0, handleError(httpResp, body)
+	}
+
+	// Handle wrapped response.
+	unwrapped, unwrapErr := handleWrappedResponse(body)
+	if unwrapErr != nil {
+		return nil, 0, fmt.Errorf("failed to decode response: %w", unwrapErr)
+	}
+
+	var list []any
+	if err := json.Unmarshal(unwrapped, &list); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	items, err := convertInterfaceSliceToMapSlice(&list)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var meta struct {
+		Meta struct {
+			Pagination struct {
+				TotalPages int `json:"total_pages"`
+			} `json:"pagination"`
+		} `json:"meta"`
+	}
+	// Lists without metadata are not paginated.
+	_ = json.Unmarshal(body, &meta)
+	return items, max(meta.Meta.Pagination.TotalPages, 1), nil
+}
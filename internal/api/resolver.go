@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/index"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
+)
+
+// Resolution is the outcome of resolving many server identifiers at once.
+type Resolution struct {
+	resolved map[string]string
+	failed   map[string]error
+}
+
+// Lookup returns what identifier resolved to: the integer ID of the server for the Application API,
+// its UUID or short identifier for the Client API. Identifiers that were not resolved are returned as they are.
+func (r Resolution) Lookup(identifier string) (string, error) {
+	if err, ok := r.failed[identifier]; ok {
+		return "", err
+	}
+	if resolved, ok := r.resolved[identifier]; ok {
+		return resolved, nil
+	}
+	return identifier, nil
+}
+
+// ResolveServerIDs resolves server identifiers (integer IDs, UUIDs, short identifiers or names) to the
+// integer IDs the Application API addresses servers by, with at most one server list for all of them.
+// Bulk commands resolve their servers up front this way instead of looking each one up in its operation.
+func (a *ApplicationAPI) ResolveServerIDs(ctx context.Context, identifiers []string) Resolution {
+	return resolveServers(index.SourceAdmin, identifiers,
+		func(identifier string) bool {
+			_, err := strconv.Atoi(identifier)
+			return err != nil
+		},
+		func(entry index.Entry) string { return entry.ID },
+		"an integer ID",
+		func() error {
+			_, err := a.ListServers(ctx)
+			return err
+		})
+}
+
+// ResolveServerUUIDs resolves server identifiers (UUIDs, short identifiers, integer IDs or names) to the
+// UUIDs the Client API addresses servers by, with at most one server list for all of them.
+func (c *ClientAPI) ResolveServerUUIDs(ctx context.Context, identifiers []string) Resolution {
+	return resolveServers(index.SourceClient, identifiers,
+		func(identifier string) bool {
+			if strings.Contains(identifier, "-") {
+				return false
+			}
+			_, err := strconv.Atoi(identifier)
+			return err == nil || !shortIdentifierPattern.MatchString(identifier)
+		},
+		func(entry index.Entry) string { return entry.UUID },
+		"a UUID",
+		func() error {
+			_, err := c.ListServers(ctx)
+			return err
+		})
+}
+
+// resolveServers resolves the identifiers that needLookup through the server index of source, refreshing
+// the index with refresh at most once. Identifiers the API accepts as they are are left alone.
+func resolveServers(
+	source string,
+	identifiers []string,
+	needLookup func(string) bool,
+	value func(index.Entry) string,
+	expected string,
+	refresh func() error,
+) Resolution {
+	resolution := Resolution{resolved: map[string]string{}, failed: map[string]error{}}
+	start := time.Now()
+
+	for _, identifier := range identifiers {
+		if _, done := resolution.resolved[identifier]; done || !needLookup(identifier) {
+			continue
+		}
+		if _, done := resolution.failed[identifier]; done {
+			continue
+		}
+		if err := strict.Lookup("server", identifier, expected); err != nil {
+			resolution.failed[identifier] = err
+			continue
+		}
+
+		entry, err := indexLookup(source, identifier)
+		if errors.Is(err, index.ErrNotIndexed) {
+			entry, err = lookupServerSince(source, identifier, start, refresh)
+		}
+		if err != nil {
+			resolution.failed[identifier] = err
+			continue
+		}
+		resolution.resolved[identifier] = value(entry)
+	}
+	return resolution
+}
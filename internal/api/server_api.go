@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
+)
+
+// API types, as taken by --api. They match the index sources the APIs list servers into.
+const (
+	TypeClient = index.SourceClient
+	TypeAdmin  = index.SourceAdmin
+)
+
+// ServerAPI is what the Client and Application APIs have in common for acting on servers. Commands that work
+// the same through either API take a ServerAPI, so one implementation serves both --api client and --api admin.
+type ServerAPI interface {
+	// Type returns TypeClient or TypeAdmin.
+	Type() string
+	ListServers(ctx context.Context) ([]map[string]any, error)
+	GetServer(ctx context.Context, identifier string) (map[string]any, error)
+	SendPowerCommand(ctx context.Context, identifier, command string) error
+	SendCommand(ctx context.Context, identifier, command string) error
+	ListBackups(ctx context.Context, identifier string) ([]map[string]any, error)
+	GetBackup(ctx context.Context, serverIdentifier, backupUUID string) (map[string]any, error)
+	DeleteBackup(ctx context.Context, serverIdentifier, backupUUID string) error
+	// ResolveServers resolves server identifiers to what the API addresses servers by,
+	// like ResolveServerUUIDs and ResolveServerIDs.
+	ResolveServers(ctx context.Context, identifiers []string) Resolution
+}
+
+var (
+	_ ServerAPI = (*ClientAPI)(nil)
+	_ ServerAPI = (*ApplicationAPI)(nil)
+)
+
+// NewServerAPI creates the API client of apiType, TypeClient or TypeAdmin.
+func NewServerAPI(apiType string) (ServerAPI, error) {
+	switch apiType {
+	case TypeClient:
+		client, err := NewClientAPI()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case TypeAdmin:
+		client, err := NewApplicationAPI()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+		fmt.Errorf("invalid API %q (must be %s or %s)", apiType, TypeClient, TypeAdmin))
+}
+
+// Type returns TypeClient.
+func (c *ClientAPI) Type() string {
+	return TypeClient
+}
+
+// ResolveServers resolves server identifiers to UUIDs, see ResolveServerUUIDs.
+func (c *ClientAPI) ResolveServers(ctx context.Context, identifiers []string) Resolution {
+	return c.ResolveServerUUIDs(ctx, identifiers)
+}
+
+// Type returns TypeAdmin.
+func (a *ApplicationAPI) Type() string {
+	return TypeAdmin
+}
+
+// ResolveServers resolves server identifiers to integer IDs, see ResolveServerIDs.
+func (a *ApplicationAPI) ResolveServers(ctx context.Context, identifiers []string) Resolution {
+	return a.ResolveServerIDs(ctx, identifiers)
+}
+
+// ServerEntries lists the servers of an API as server index entries, e.g. for selectors.
+func ServerEntries(ctx context.Context, client ServerAPI) ([]index.Entry, error) {
+	// Listing the servers refreshes the index the entries are read from.
+	if _, err := client.ListServers(ctx); err != nil {
+		return nil, err
+	}
+	entries, _ := index.Servers(client.Type())
+	return entries, nil
+}
+
+// LookupServer returns the server index entry of a server given by UUID, short identifier, integer ID or
+// name, listing the servers of the API once if the index is stale or doesn't know it.
+func LookupServer(ctx context.Context, client ServerAPI, identifier string) (index.Entry, error) {
+	return lookupServer(client.Type(), identifier, func() error {
+		_, err := client.ListServers(ctx)
+		return err
+	})
+}
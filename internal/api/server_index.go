@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// maxSuggestions bounds the "did you mean" list of an unknown server.
+const maxSuggestions = 3
+
+// shortIdentifierPattern matches the short server identifier, the first block of a server UUID.
+var shortIdentifierPattern = regexp.MustCompile(`^[0-9a-f]{8}$`)
+
+// refreshMutex serializes the server lists fetched to look up identifiers, so the lookups of a
+// bulk run that miss the index at once wait for one list instead of each listing the servers.
+//
+//nolint:gochecknoglobals // Shared by every client, like the index itself
+var refreshMutex sync.Mutex
+
+// lookupServer resolves a server identifier through the server index of source, refreshing the
+// index with refresh once if the identifier is unknown or the index is stale.
+func lookupServer(source, identifier string, refresh func() error) (index.Entry, error) {
+	missed := time.Now()
+	entry, err := indexLookup(source, identifier)
+	if !errors.Is(err, index.ErrNotIndexed) {
+		return entry, err
+	}
+	return lookupServerSince(source, identifier, missed, refresh)
+}
+
+// lookupServerSince looks up an identifier missing from the index, refreshing the index unless it
+// was listed since the given time already.
+func lookupServerSince(source, identifier string, since time.Time, refresh func() error) (index.Entry, error) {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	// A list fetched since then is as recent as a new one would be.
+	if !index.UpdatedAt(source).After(since) {
+		if err := refresh(); err != nil {
+			return index.Entry{}, fmt.Errorf("failed to list servers to look up %s: %w", identifier, err)
+		}
+	}
+	entry, err := indexLookup(source, identifier)
+	if errors.Is(err, index.ErrNotIndexed) {
+		return matchServerName(source, identifier)
+	}
+	return entry, err
+}
+
+// indexLookup looks up an identifier in the server index, marking an ambiguous name as invalid input.
+func indexLookup(source, identifier string) (index.Entry, error) {
+	entry, err := index.Lookup(source, identifier)
+	var ambiguous *index.AmbiguousError
+	if errors.As(err, &ambiguous) {
+		return index.Entry{}, apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+	return entry, err
+}
+
+// matchServerName resolves an identifier that is no server's UUID, ID or name by part of a name:
+// the server whose name contains it, if there is only one. Otherwise the error lists the servers
+// it could mean.
+func matchServerName(source, identifier string) (index.Entry, error) {
+	matches := index.Search(source, identifier)
+
+	var containing []index.Entry
+	for _, entry := range matches {
+		if strings.Contains(strings.ToLower(entry.Name), strings.ToLower(identifier)) {
+			containing = append(containing, entry)
+		}
+	}
+	switch len(containing) {
+	case 0:
+	case 1:
+		output.LogInfo("resolved server by partial name",
+			"name", identifier, "server", containing[0].Name, "uuid", containing[0].UUID)
+		return containing[0], nil
+	default:
+		return index.Entry{}, apierrors.WithExitCode(apierrors.ExitValidation,
+			&index.AmbiguousError{Name: identifier, Matches: containing})
+	}
+
+	if len(matches) == 0 {
+		return index.Entry{}, apierrors.WithExitCode(apierrors.ExitNotFound, fmt.Errorf("server %s not found", identifier))
+	}
+	names := make([]string, 0, maxSuggestions)
+	for _, entry := range matches[:min(len(matches), maxSuggestions)] {
+		names = append(names, fmt.Sprintf("%s (%s)", entry.Name, entry.UUID))
+	}
+	return index.Entry{}, apierrors.WithExitCode(apierrors.ExitNotFound,
+		fmt.Errorf("server %s not found; did you mean %s?", identifier, strings.Join(names, ", ")))
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+)
+
+// NewTLSConfig builds the TLS settings for the panel from api.tls. It returns nil if nothing is set,
+// so the defaults apply.
+func NewTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	if cfg == (config.TLSConfig{}) {
+		return nil, nil //nolint:nilnil // No settings means the defaults
+	}
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+			errors.New("api.tls.client_cert and api.tls.client_key must be set together"))
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		//nolint:gosec // Only when asked for with --insecure-skip-tls-verify
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		// The CA is trusted in addition to the system roots, so other hosts keep working.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("no PEM certificates found in %s", cfg.CACert))
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("failed to load client certificate: %w", err))
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+)
+
+var (
+	// panelTransport opens the connections to the panel, with the TLS settings of api.tls and the
+	// proxy of api.proxy_url.
+	//
+	//nolint:gochecknoglobals // Shared by both generated clients so they use the same certificates and proxy
+	panelTransport = http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // Always a *http.Transport
+
+	//nolint:gochecknoglobals // Transport settings are read once per process
+	panelTransportOnce sync.Once
+	//nolint:gochecknoglobals // Error of panelTransportOnce, returned by every client constructor
+	panelTransportErr error
+)
+
+// configurePanelTransport applies api.tls and api.proxy_url to the connections to the panel.
+// The settings are only read once, by the first client created.
+func configurePanelTransport(cfg config.APIConfig) error {
+	panelTransportOnce.Do(func() {
+		var tlsConfig *tls.Config
+		if tlsConfig, panelTransportErr = NewTLSConfig(cfg.TLS); panelTransportErr != nil {
+			return
+		}
+		if tlsConfig != nil {
+			panelTransport.TLSClientConfig = tlsConfig
+		}
+		panelTransport.Proxy, panelTransportErr = NewProxy(cfg.ProxyURL)
+	})
+	return panelTransportErr
+}
+
+// NewProxy returns the proxy function for requests to the panel: proxyURL if set, an http, https,
+// socks5 or socks5h URL, or else the proxy of HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+func NewProxy(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || parsed.Host == "" {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation, fmt.Errorf("invalid proxy URL %q", proxyURL))
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+			fmt.Errorf("unsupported proxy scheme %q (must be http, https, socks5 or socks5h)", parsed.Scheme))
+	}
+	return http.ProxyURL(parsed), nil
+}
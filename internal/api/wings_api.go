@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+)
+
+// WingsAPI talks directly to the Wings daemon running on a node.
+// It is intended for diagnostics the panel does not expose and requires the node's daemon token.
+type WingsAPI struct {
+	node    string
+	baseURL string
+	token   string
+}
+
+// NewWingsAPI creates a Wings API client for a node configured under "wings" in the config file.
+func NewWingsAPI(node string) (*WingsAPI, error) {
+	cfg := config.Get()
+	if cfg == nil {
+		return nil, errors.New("config not loaded")
+	}
+
+	// Viper lowercases map keys, so node names are matched case-insensitively.
+	nodeCfg, ok := cfg.Wings[strings.ToLower(node)]
+	if !ok {
+		return nil, fmt.Errorf("wings node %q not configured. Add it under 'wings' in the config file", node)
+	}
+	if nodeCfg.URL == "" {
+		return nil, fmt.Errorf("wings node %q has no url configured", node)
+	}
+	if nodeCfg.Token == "" {
+		return nil, fmt.Errorf("wings node %q has no token configured", node)
+	}
+
+	return &WingsAPI{
+		node:    node,
+		baseURL: strings.TrimSuffix(nodeCfg.URL, "/"),
+		token:   nodeCfg.Token,
+	}, nil
+}
+
+// WingsNodes returns the names of all nodes configured for direct Wings access.
+func WingsNodes() []string {
+	cfg := config.Get()
+	if cfg == nil {
+		return nil
+	}
+
+	nodes := make([]string, 0, len(cfg.Wings))
+	for name := range cfg.Wings {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// GetSystemInfo gets system information (versions, CPU, memory, Docker) from Wings.
+func (w *WingsAPI) GetSystemInfo(ctx context.Context) (map[string]any, error) {
+	body, err := w.do(ctx, http.MethodGet, "/api/system?v=2")
+	if err != nil {
+		return nil, err
+	}
+
+	var info map[string]any
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return info, nil
+}
+
+// PruneDockerImages removes dangling Docker images on the node and returns the prune report.
+func (w *WingsAPI) PruneDockerImages(ctx context.Context) (map[string]any, error) {
+	body, err := w.do(ctx, http.MethodDelete, "/api/system/docker/image/prune")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) == 0 {
+		return map[string]any{}, nil
+	}
+	var report map[string]any
+	if err := json.Unmarshal(body, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return report, nil
+}
+
+// GetTransferStatus gets the transfer state of a server as seen by Wings on this node.
+// Wings has no dedicated transfer endpoint, so this reads the server's details and
+// reports its state together with the transfer flag Wings exposes.
+func (w *WingsAPI) GetTransferStatus(ctx context.Context, serverUUID string) (map[string]any, error) {
+	body, err := w.do(ctx, http.MethodGet, "/api/servers/"+url.PathEscape(serverUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	var server map[string]any
+	if err := json.Unmarshal(body, &server); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	transferring, _ := server["is_transferring"].(bool)
+	return map[string]any{
+		"node":            w.node,
+		"uuid":            serverUUID,
+		"state":           server["state"],
+		"is_transferring": transferring,
+		"is_suspended":    server["is_suspended"],
+	}, nil
+}
+
+// do performs an authenticated request against the Wings API and returns the response body.
+func (w *WingsAPI) do(ctx context.Context, method, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, w.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+w.token)
+	req.Header.Set("Accept", "application/json")
+
+	httpResp, err := externalHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		return nil, handleErrorResponse(httpResp, body)
+	}
+
+	return body, nil
+}
@@ -5,7 +5,10 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 
@@ -13,6 +16,7 @@ import (
 	"golang.org/x/term"
 
 	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
 )
 
 const (
@@ -31,11 +35,55 @@ var (
 	warnedMutex sync.Mutex
 )
 
-// getKeyringKey returns the keyring user/account key for the given API type.
+// ProfileEnvVar names the profile whose tokens are used, so scripts against different panels
+// keep their tokens apart.
+const ProfileEnvVar = "PELICANCTL_PROFILE"
+
+// Profile returns the profile set with PELICANCTL_PROFILE, empty if none.
+func Profile() string {
+	return strings.TrimSpace(os.Getenv(ProfileEnvVar))
+}
+
+// getKeyringKey returns the keyring user/account key for the given API type, also the key of the token
+// in the encrypted token file. Tokens are kept per profile, or else per API base URL.
 func getKeyringKey(apiType string) string {
+	key := legacyKeyringKey(apiType)
+	if profile := Profile(); profile != "" {
+		return key + "@profile:" + profile
+	}
+	if cfg := config.Get(); cfg != nil && cfg.API.BaseURL != "" {
+		return key + "@" + normalizeBaseURL(cfg.API.BaseURL)
+	}
+	return key
+}
+
+// legacyKeyringKey returns the key tokens were kept under before they were kept per base URL.
+func legacyKeyringKey(apiType string) string {
 	return fmt.Sprintf("%s-token", apiType)
 }
 
+// keyringKeys returns the keys the token of apiType is looked up under, in order. Tokens saved before
+// they were kept per base URL are still found, unless a profile is set.
+func keyringKeys(apiType string) []string {
+	key := getKeyringKey(apiType)
+	if legacy := legacyKeyringKey(apiType); Profile() == "" && key != legacy {
+		return []string{key, legacy}
+	}
+	return []string{key}
+}
+
+// normalizeBaseURL returns baseURL without a trailing slash and with a lowercase scheme and host,
+// so spellings of the same panel URL share their tokens.
+func normalizeBaseURL(baseURL string) string {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return baseURL
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	return parsed.String()
+}
+
 // warnIfTokenInConfig warns the user if a token is found in the config file.
 // Only warns once per API type per session.
 func warnIfTokenInConfig(apiType string) {
@@ -53,43 +101,186 @@ func warnIfTokenInConfig(apiType string) {
 		apiType)
 }
 
+// Token sources, in the order GetToken checks them.
+const (
+	SourceEnv           = "env"
+	SourceKeyring       = "keyring"
+	SourceEncryptedFile = "encrypted-file"
+	SourceConfig        = "config"
+	SourceNone          = "none"
+)
+
 // GetToken retrieves the token for the specified API type.
 func GetToken(apiType string) (string, error) {
+	token, source, err := LookupToken(apiType)
+	if err != nil {
+		return "", err
+	}
+
+	// With auth.backend: file the config file is where tokens are meant to be.
+	if backend, _ := Backend(); source == SourceConfig && backend != BackendFile {
+		if strict.Enabled() {
+			return "", fmt.Errorf("%s token is only set in the config file; strict mode requires %s or the keyring",
+				apiType, TokenEnvVar(apiType))
+		}
+		warnIfTokenInConfig(apiType)
+	}
+
+	// Return token from config (may be empty)
+	return token, nil
+}
+
+// LookupToken returns the token for the specified API type like GetToken, together with the source
+// it came from, without warning about tokens in the config file.
+func LookupToken(apiType string) (string, string, error) {
 	cfg := config.Get()
 	if cfg == nil {
-		return "", errors.New("config not loaded")
+		return "", "", errors.New("config not loaded")
 	}
 
 	// 1. Check environment variable first (highest priority)
-	envVar := fmt.Sprintf("PELICANCTL_%s_TOKEN", strings.ToUpper(apiType))
-	if envToken := os.Getenv(envVar); envToken != "" {
-		return envToken, nil
+	if envToken := os.Getenv(TokenEnvVar(apiType)); envToken != "" {
+		return envToken, SourceEnv, nil
 	}
 
-	// 2. Try keyring (for developer machines)
-	keyringToken, err := keyring.Get(keyringService, getKeyringKey(apiType))
-	if err == nil && keyringToken != "" {
-		return keyringToken, nil
+	// 2. Try the configured token store (keyring for developer machines)
+	backend, err := Backend()
+	if err != nil {
+		return "", "", err
+	}
+	switch backend {
+	case BackendKeyring:
+		for _, key := range keyringKeys(apiType) {
+			keyringToken, keyringErr := keyring.Get(keyringService, key)
+			if keyringErr == nil && keyringToken != "" {
+				return keyringToken, SourceKeyring, nil
+			}
+		}
+		// Silently continue if keyring unavailable or token not found
+	case BackendEncryptedFile:
+		fileToken, fileErr := readEncryptedToken(apiType)
+		if fileErr != nil {
+			return "", "", fileErr
+		}
+		if fileToken != "" {
+			return fileToken, SourceEncryptedFile, nil
+		}
 	}
-	// Silently continue if keyring unavailable or token not found
 
 	// 3. Check config file (fallback with warning)
-	var token string
-	switch apiType {
-	case apiTypeClient:
-		token = cfg.Client.Token
-	case apiTypeAdmin:
-		token = cfg.Admin.Token
-	default:
+	token, err := configToken(cfg, apiType)
+	if err != nil {
+		return "", "", err
+	}
+	if token == "" {
+		return "", SourceNone, nil
+	}
+	return token, SourceConfig, nil
+}
+
+// configToken returns the token of apiType saved to the config file for the profile, or else for the
+// API base URL. The shared client.token and admin.token are only used without a profile.
+func configToken(cfg *config.Config, apiType string) (string, error) {
+	if apiType != apiTypeClient && apiType != apiTypeAdmin {
 		return "", fmt.Errorf("invalid API type: %s", apiType)
 	}
+	if entry := configTokenEntry(cfg, false); entry != nil {
+		if token := entryToken(entry, apiType); token != "" {
+			return token, nil
+		}
+	}
+	if Profile() != "" {
+		return "", nil
+	}
+	if apiType == apiTypeClient {
+		return cfg.Client.Token, nil
+	}
+	return cfg.Admin.Token, nil
+}
 
-	if token != "" {
-		warnIfTokenInConfig(apiType)
+// setConfigToken saves the token of apiType to the config file for the profile, or else for the API
+// base URL, removing it if token is empty. Without a profile the shared token is cleared, as the token
+// of the base URL replaces it.
+func setConfigToken(cfg *config.Config, apiType, token string) {
+	if Profile() == "" {
+		if cfg.API.BaseURL == "" {
+			setSharedConfigToken(cfg, apiType, token)
+			return
+		}
+		setSharedConfigToken(cfg, apiType, "")
 	}
 
-	// Return token from config (may be empty)
-	return token, nil
+	entry := configTokenEntry(cfg, token != "")
+	if entry == nil {
+		return
+	}
+	if apiType == apiTypeClient {
+		entry.Client = token
+	} else {
+		entry.Admin = token
+	}
+	if entry.Client == "" && entry.Admin == "" {
+		cfg.Tokens = slices.DeleteFunc(cfg.Tokens, func(e config.TokenConfig) bool {
+			return e.Client == "" && e.Admin == ""
+		})
+	}
+}
+
+func setSharedConfigToken(cfg *config.Config, apiType, token string) {
+	if apiType == apiTypeClient {
+		cfg.Client.Token = token
+	} else {
+		cfg.Admin.Token = token
+	}
+}
+
+// configTokenEntry returns the config file tokens of the profile, or else of the API base URL, nil if
+// there are none and create is false, or if there is neither a profile nor a base URL.
+func configTokenEntry(cfg *config.Config, create bool) *config.TokenConfig {
+	profile := Profile()
+	baseURL := ""
+	if profile == "" {
+		if cfg.API.BaseURL == "" {
+			return nil
+		}
+		baseURL = normalizeBaseURL(cfg.API.BaseURL)
+	}
+
+	for i := range cfg.Tokens {
+		entry := &cfg.Tokens[i]
+		if entry.Profile != profile {
+			continue
+		}
+		if profile != "" || normalizeBaseURL(entry.BaseURL) == baseURL {
+			return entry
+		}
+	}
+	if !create {
+		return nil
+	}
+	cfg.Tokens = append(cfg.Tokens, config.TokenConfig{Profile: profile, BaseURL: baseURL})
+	return &cfg.Tokens[len(cfg.Tokens)-1]
+}
+
+func entryToken(entry *config.TokenConfig, apiType string) string {
+	if apiType == apiTypeClient {
+		return entry.Client
+	}
+	return entry.Admin
+}
+
+// KeyringAvailable reports whether the system keyring can be read, returning the error of the keyring if not.
+func KeyringAvailable() error {
+	_, err := keyring.Get(keyringService, getKeyringKey(apiTypeClient))
+	if err == nil || errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err //nolint:wrapcheck // Keyring errors describe themselves
+}
+
+// TokenEnvVar returns the environment variable holding the token for the given API type.
+func TokenEnvVar(apiType string) string {
+	return fmt.Sprintf("PELICANCTL_%s_TOKEN", strings.ToUpper(apiType))
 }
 
 // SetToken sets the token for the specified API type and saves it to keyring.
@@ -107,20 +298,33 @@ func SetToken(apiType, token string) error {
 		return fmt.Errorf("invalid API type: %s", apiType)
 	}
 
-	// Save to keyring
-	if err := keyring.Set(keyringService, getKeyringKey(apiType), token); err != nil {
-		// Log warning but don't fail - fallback to config if keyring unavailable
-		_, _ = fmt.Fprintf(os.Stderr, "Warning: Failed to save to keyring: %v\n", err)
+	backend, err := Backend()
+	if err != nil {
+		return err
 	}
 
-	// Clear token from config file
-	switch apiType {
-	case apiTypeClient:
-		cfg.Client.Token = ""
-	case apiTypeAdmin:
-		cfg.Admin.Token = ""
+	// Save to the token store, and clear the token from the config file
+	fileToken := ""
+	switch backend {
+	case BackendKeyring:
+		if err := keyring.Set(keyringService, getKeyringKey(apiType), token); err != nil {
+			if strict.Enabled() {
+				return fmt.Errorf("failed to save to keyring: %w", err)
+			}
+			// Log warning but don't fail - fallback to config if keyring unavailable, as on CI runners
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: Failed to save to keyring, saving to config file instead: %v\n"+
+				"  Set auth.backend to encrypted-file to keep tokens encrypted without a keyring.\n", err)
+			fileToken = token
+		}
+	case BackendFile:
+		fileToken = token
+	case BackendEncryptedFile:
+		if err := writeEncryptedToken(apiType, token); err != nil {
+			return fmt.Errorf("failed to save to token file: %w", err)
+		}
 	}
 
+	setConfigToken(cfg, apiType, fileToken)
 	return config.Save()
 }
 
@@ -140,21 +344,27 @@ func DeleteToken(apiType string) error {
 	}
 
 	// Delete from keyring (ignore errors - keyring may not have token)
-	_ = keyring.Delete(keyringService, getKeyringKey(apiType))
+	for _, key := range keyringKeys(apiType) {
+		_ = keyring.Delete(keyringService, key)
+	}
 
-	// Clear from config
-	switch apiType {
-	case apiTypeClient:
-		cfg.Client.Token = ""
-	case apiTypeAdmin:
-		cfg.Admin.Token = ""
+	if backend, _ := Backend(); backend == BackendEncryptedFile {
+		if err := writeEncryptedToken(apiType, ""); err != nil {
+			return fmt.Errorf("failed to remove from token file: %w", err)
+		}
 	}
 
+	// Clear from config
+	setConfigToken(cfg, apiType, "")
 	return config.Save()
 }
 
 // PromptAPIURL prompts the user for an API base URL with a default value.
 func PromptAPIURL(defaultURL string) (string, error) {
+	if err := strict.Prompt("set PELICANCTL_API_BASE_URL or api.base_url in the config file"); err != nil {
+		return "", err
+	}
+
 	prompt := "Enter API base URL"
 	if defaultURL != "" {
 		_, _ = fmt.Fprintf(os.Stderr, "%s [%s]: ", prompt, defaultURL)
@@ -186,6 +396,10 @@ func PromptAPIURL(defaultURL string) (string, error) {
 // PromptToken prompts the user for a token interactively.
 // Supports pasting on all modern terminals.
 func PromptToken(apiType string) (string, error) {
+	if err := strict.Prompt(fmt.Sprintf("set PELICANCTL_%s_TOKEN", strings.ToUpper(apiType))); err != nil {
+		return "", err
+	}
+
 	_, _ = fmt.Fprintf(os.Stderr, "Enter %s API token: ", apiType)
 
 	// Read from stdin with password masking - supports pasting
@@ -203,6 +417,34 @@ func PromptToken(apiType string) (string, error) {
 	return token, nil
 }
 
+// ReadToken reads a token from the file at path, or from stdin for "-", for logging in without a terminal.
+// Surrounding whitespace, like the trailing newline of a file, is not part of the token.
+func ReadToken(path string) (string, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token: %w", err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", errors.New("token cannot be empty")
+	}
+	return token, nil
+}
+
+// StdinIsTerminal reports whether stdin is a terminal, so tokens can be prompted for.
+func StdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
 // SetAPIURL sets the API base URL in the configuration.
 func SetAPIURL(baseURL string) error {
 	cfg := config.Get()
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/strict"
+)
+
+// Token backends, selected with auth.backend.
+const (
+	// BackendKeyring keeps tokens in the system keyring, falling back to the config file if it is unavailable.
+	BackendKeyring = "keyring"
+	// BackendFile keeps tokens in plain text in the config file.
+	BackendFile = "file"
+	// BackendEncryptedFile keeps tokens in a file next to the config file, encrypted with a passphrase.
+	BackendEncryptedFile = "encrypted-file"
+)
+
+// PassphraseEnvVar holds the passphrase of the encrypted token file, for use without a terminal.
+const PassphraseEnvVar = "PELICANCTL_TOKEN_PASSPHRASE"
+
+const (
+	tokenFileName    = "tokens.enc"
+	tokenFileVersion = 1
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	pbkdf2Iterations = 600000
+	saltSize         = 16
+	keySize          = 32
+)
+
+// passphrase is read once per process, so it is asked for at most once.
+//
+//nolint:gochecknoglobals // Passphrase is cached for the whole session
+var passphrase struct {
+	once  sync.Once
+	value string
+	err   error
+}
+
+// encryptedTokenFile is the on-disk format of the encrypted token file. Data is the AES-256-GCM encryption of
+// the JSON object of tokens by keyring key, under a key derived from the passphrase and Salt with PBKDF2.
+type encryptedTokenFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// Backend returns the token backend configured with auth.backend.
+func Backend() (string, error) {
+	cfg := config.Get()
+	if cfg == nil || cfg.Auth.Backend == "" {
+		return BackendKeyring, nil
+	}
+	switch cfg.Auth.Backend {
+	case BackendKeyring, BackendFile, BackendEncryptedFile:
+		return cfg.Auth.Backend, nil
+	}
+	return "", fmt.Errorf("invalid auth.backend %q (must be %s, %s or %s)",
+		cfg.Auth.Backend, BackendKeyring, BackendFile, BackendEncryptedFile)
+}
+
+// TokenFilePath returns the path of the encrypted token file.
+func TokenFilePath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(dir, tokenFileName), nil
+}
+
+// readEncryptedToken returns the token of apiType from the encrypted token file, looked up like in the keyring,
+// empty if there is none.
+func readEncryptedToken(apiType string) (string, error) {
+	tokens, err := loadEncryptedTokens()
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keyringKeys(apiType) {
+		if token := tokens[key]; token != "" {
+			return token, nil
+		}
+	}
+	return "", nil
+}
+
+// writeEncryptedToken sets the token of apiType in the encrypted token file, removing every key it is
+// looked up under if token is empty.
+func writeEncryptedToken(apiType, token string) error {
+	tokens, err := loadEncryptedTokens()
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		tokens[getKeyringKey(apiType)] = token
+		return saveEncryptedTokens(tokens)
+	}
+
+	removed := false
+	for _, key := range keyringKeys(apiType) {
+		if _, ok := tokens[key]; ok {
+			delete(tokens, key)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return saveEncryptedTokens(tokens)
+}
+
+func loadEncryptedTokens() (map[string]string, error) {
+	path, err := TokenFilePath()
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var file encryptedTokenFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse token file %s: %w", path, err)
+	}
+	if file.Version != tokenFileVersion {
+		return nil, fmt.Errorf("unsupported token file version %d in %s", file.Version, path)
+	}
+
+	pass, err := getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newTokenCipher(pass, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token file %s: wrong passphrase or corrupted file", path)
+	}
+
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted token file: %w", err)
+	}
+	return tokens, nil
+}
+
+func saveEncryptedTokens(tokens map[string]string) error {
+	path, err := TokenFilePath()
+	if err != nil {
+		return err
+	}
+	// A new file is encrypted under a passphrase nobody typed before, so a typo would lock the tokens away.
+	_, statErr := os.Stat(path)
+	pass, err := getPassphrase(errors.Is(statErr, os.ErrNotExist))
+	if err != nil {
+		return err
+	}
+
+	// A fresh salt and nonce for every write, so no key and nonce pair is ever reused.
+	file := encryptedTokenFile{Version: tokenFileVersion, Salt: make([]byte, saltSize)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := newTokenCipher(pass, file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to encode tokens: %w", err)
+	}
+	file.Data = gcm.Seal(nil, file.Nonce, plain, nil)
+
+	encoded, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(encoded, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return nil
+}
+
+func newTokenCipher(pass string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, pass, salt, pbkdf2Iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
+
+// getPassphrase returns the passphrase of the encrypted token file from PELICANCTL_TOKEN_PASSPHRASE,
+// or prompts for it if stdin is a terminal. With confirm, a prompted passphrase must be entered twice.
+func getPassphrase(confirm bool) (string, error) {
+	passphrase.once.Do(func() {
+		if pass := os.Getenv(PassphraseEnvVar); pass != "" {
+			passphrase.value = pass
+			return
+		}
+		if err := strict.Prompt("set " + PassphraseEnvVar); err != nil {
+			passphrase.err = err
+			return
+		}
+		if !StdinIsTerminal() {
+			passphrase.err = fmt.Errorf("the token file is encrypted; set %s", PassphraseEnvVar)
+			return
+		}
+
+		passphrase.value, passphrase.err = readPassphrase("Enter token file passphrase: ")
+		if passphrase.err != nil || !confirm {
+			return
+		}
+		again, err := readPassphrase("Confirm token file passphrase: ")
+		switch {
+		case err != nil:
+			passphrase.value, passphrase.err = "", err
+		case again != passphrase.value:
+			passphrase.value, passphrase.err = "", errors.New("passphrases do not match")
+		}
+	})
+	return passphrase.value, passphrase.err
+}
+
+// PassphraseNeeded reports whether reading a token would ask for the passphrase of the encrypted token file:
+// the file is the configured backend and exists, and PELICANCTL_TOKEN_PASSPHRASE is not set. Tokens from
+// the environment are not read from the file at all.
+func PassphraseNeeded() bool {
+	if backend, err := Backend(); err != nil || backend != BackendEncryptedFile {
+		return false
+	}
+	if os.Getenv(PassphraseEnvVar) != "" {
+		return false
+	}
+	path, err := TokenFilePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// readPassphrase prompts for a passphrase on the terminal without echoing it.
+func readPassphrase(prompt string) (string, error) {
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	passBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	pass := strings.TrimSpace(string(passBytes))
+	if pass == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+	return pass, nil
+}
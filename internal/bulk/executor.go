@@ -3,24 +3,60 @@ package bulk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
 	"go.lostcrafters.com/pelicanctl/internal/output"
+	"go.lostcrafters.com/pelicanctl/internal/progress"
 )
 
 // Operation represents a single operation to execute.
 type Operation struct {
 	ID   string
 	Name string
-	Exec func() error
+	// Exec runs the operation. ctx is cancelled when the run is, and requests made with it are aborted.
+	// With retries Exec is called once per attempt, so it must be safe to run again after a failure.
+	Exec func(ctx context.Context) error
+	// RetryCount, RetryBackoff and Timeout override the defaults of the executor for this operation,
+	// see Executor.WithRetry and Executor.WithTimeout. Zero keeps the default.
+	RetryCount   int
+	RetryBackoff time.Duration
+	Timeout      time.Duration
 }
 
+// Result statuses, as reported in JSON records and progress events.
+const (
+	StatusSuccess   = "success"
+	StatusError     = "error"
+	StatusCancelled = "cancelled"
+)
+
 // Result represents the result of an operation.
 type Result struct {
 	Operation Operation
 	Success   bool
+	// Cancelled is set for operations that were not started, or were aborted, because the run was
+	// cancelled. They are not successful, but did not fail on their own either.
+	Cancelled bool
 	Error     error
+	// Attempts is how often the operation was run, more than once if it was retried. It is zero for
+	// operations that were never started.
+	Attempts int
+}
+
+// Status returns StatusSuccess, StatusError or StatusCancelled.
+func (r Result) Status() string {
+	switch {
+	case r.Success:
+		return StatusSuccess
+	case r.Cancelled:
+		return StatusCancelled
+	default:
+		return StatusError
+	}
 }
 
 // Executor executes operations in parallel.
@@ -28,6 +64,11 @@ type Executor struct {
 	maxConcurrency  int
 	continueOnError bool
 	failFast        bool
+	record          RecordFunc
+	onProgress      ProgressFunc
+	retryCount      int
+	retryBackoff    time.Duration
+	timeout         time.Duration
 }
 
 // NewExecutor creates a new bulk executor.
@@ -39,71 +80,242 @@ func NewExecutor(maxConcurrency int, continueOnError bool, failFast bool) *Execu
 		maxConcurrency:  maxConcurrency,
 		continueOnError: continueOnError,
 		failFast:        failFast,
+		record:          ServerRecord,
 	}
 }
 
+// WithRecord sets the record streamed for each result in ndjson output, ServerRecord by default.
+// A nil record keeps the results of the executor out of the stream, for steps such as lookups
+// that are not results of the command.
+func (e *Executor) WithRecord(record RecordFunc) *Executor {
+	e.record = record
+	return e
+}
+
+// WithProgress sets the function told about every finished operation, e.g. to render a progress bar.
+// Without one the executor reports to the function set with SetProgress, if any.
+func (e *Executor) WithProgress(fn ProgressFunc) *Executor {
+	e.onProgress = fn
+	return e
+}
+
+// WithRetry makes failed operations run again up to count times, waiting backoff before the first
+// retry and twice as long before every further one. Operations failing on invalid input, a missing
+// resource or authentication are not retried, as another attempt would fail the same way.
+func (e *Executor) WithRetry(count int, backoff time.Duration) *Executor {
+	e.retryCount = max(count, 0)
+	e.retryBackoff = max(backoff, 0)
+	return e
+}
+
+// WithTimeout limits every attempt of an operation to timeout, so one hanging node does not hold up
+// the run. An attempt that times out fails, and is retried like any other failure. Zero means no limit.
+func (e *Executor) WithTimeout(timeout time.Duration) *Executor {
+	e.timeout = max(timeout, 0)
+	return e
+}
+
+// Skip records an operation that was not executed, streaming it like an executed one.
+func (e *Executor) Skip(operation Operation, err error) Result {
+	result := Result{Operation: operation, Success: false, Error: err}
+	emit(e.record, result)
+	return result
+}
+
+// Cancel records an operation that was not executed because the run was aborted, streaming it as cancelled.
+func (e *Executor) Cancel(operation Operation, cause error) Result {
+	result := Result{Operation: operation, Cancelled: true, Error: fmt.Errorf("cancelled: %w", cause)}
+	emit(e.record, result)
+	return result
+}
+
 // Execute executes a list of operations in parallel.
-func (e *Executor) Execute(_ context.Context, operations []Operation) []Result {
+//
+// Operations run under a context derived from ctx. It is cancelled when ctx is done, e.g. on Ctrl-C,
+// and with fail-fast on the first failure, which aborts the requests of running operations. Operations
+// that had not started by then, and running ones that fail once it is cancelled, are marked as cancelled.
+func (e *Executor) Execute(ctx context.Context, operations []Operation) []Result {
 	results := make([]Result, len(operations))
 
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
 	// Semaphore for limiting concurrency
 	sem := make(chan struct{}, e.maxConcurrency)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	var hasError bool
+	var completed, failed int
+
+	onProgress := e.onProgress
+	if onProgress == nil {
+		onProgress = getDefaultProgress()
+	}
+	start := time.Now()
+	// finish records a result; the caller holds mu.
+	finish := func(idx int, result Result) {
+		results[idx] = result
+		completed++
+		if !result.Success {
+			failed++
+		}
+		progress.Item(result.Operation.ID, result.Status(), result.Error, completed, len(operations))
+		emit(e.record, result)
+		if onProgress != nil {
+			onProgress(newProgress(result, completed, failed, len(operations), start))
+		}
+	}
+
+	progress.Start(len(operations))
 
 	for i, op := range operations {
-		// Check if we should fail fast
-		if e.failFast && hasError {
-			// Mark remaining operations as not executed
+		if !acquire(runCtx, sem) {
+			// Mark the operations that were not started as cancelled.
+			mu.Lock()
 			for j := i; j < len(operations); j++ {
-				results[j] = Result{
-					Operation: operations[j],
-					Success:   false,
-					Error:     fmt.Errorf("skipped due to previous error"), //nolint:perfsprint // Error message
-				}
+				finish(j, cancelledResult(operations[j], runCtx))
 			}
+			mu.Unlock()
 			break
 		}
 
 		wg.Add(1)
-		sem <- struct{}{} // Acquire semaphore
 
 		go func(idx int, operation Operation) {
 			defer wg.Done()
 			defer func() { <-sem }() // Release semaphore
 
-			result := Result{
-				Operation: operation,
-			}
-
-			// Execute operation
-			if err := operation.Exec(); err != nil {
-				result.Success = false
-				result.Error = err
-				mu.Lock()
-				hasError = true
-				mu.Unlock()
-			} else {
-				result.Success = true
+			attempts, err := e.run(runCtx, operation)
+			result := Result{Operation: operation, Success: true, Attempts: attempts}
+			if err != nil {
+				result = Result{Operation: operation, Error: err, Attempts: attempts}
+				if runCtx.Err() != nil {
+					// The operation was aborted, or failed while the run was being cancelled.
+					result = cancelledResult(operation, runCtx)
+					result.Attempts = attempts
+				} else if e.failFast {
+					cancel(fmt.Errorf("operation %s failed", operation.ID))
+				}
 			}
 
 			mu.Lock()
-			results[idx] = result
+			finish(idx, result)
 			mu.Unlock()
 		}(i, op)
 	}
 
 	wg.Wait()
+
+	summary := GetSummary(results)
+	progress.Done(summary.Success, summary.Failed)
 	return results
 }
 
+// run executes an operation with its timeout and retries, returning how many attempts it took
+// and the error of the last one.
+func (e *Executor) run(runCtx context.Context, operation Operation) (int, error) {
+	retryCount, backoff, timeout := e.retryCount, e.retryBackoff, e.timeout
+	if operation.RetryCount > 0 {
+		retryCount = operation.RetryCount
+	}
+	if operation.RetryBackoff > 0 {
+		backoff = operation.RetryBackoff
+	}
+	if operation.Timeout > 0 {
+		timeout = operation.Timeout
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := attemptOnce(runCtx, operation, timeout)
+		if err == nil || attempt > retryCount || runCtx.Err() != nil || !retryable(err) {
+			return attempt, err
+		}
+		output.LogDebug("retrying operation", "id", operation.ID, "attempt", attempt, "wait", backoff, "error", err)
+		if sleepErr := sleep(runCtx, backoff); sleepErr != nil {
+			return attempt, err
+		}
+		backoff *= 2
+	}
+}
+
+// attemptOnce runs an operation once, limited to timeout if it is positive.
+func attemptOnce(runCtx context.Context, operation Operation, timeout time.Duration) error {
+	if timeout <= 0 {
+		return operation.Exec(runCtx)
+	}
+	attemptCtx, cancel := context.WithTimeout(runCtx, timeout)
+	defer cancel()
+	err := operation.Exec(attemptCtx)
+	if err != nil && runCtx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	return err
+}
+
+// retryable reports whether another attempt of an operation that failed with err may succeed.
+func retryable(err error) bool {
+	switch apierrors.ExitCode(err) {
+	case apierrors.ExitAuth, apierrors.ExitNotFound, apierrors.ExitValidation, apierrors.ExitCanceled:
+		return false
+	}
+	return true
+}
+
+// sleep waits for d, or returns the error of ctx if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelledResult is the result of an operation cancelled with runCtx, naming why.
+func cancelledResult(operation Operation, runCtx context.Context) Result {
+	return Result{
+		Operation: operation,
+		Cancelled: true,
+		Error:     fmt.Errorf("cancelled: %w", context.Cause(runCtx)),
+	}
+}
+
+// acquire takes a slot of sem, or reports false if ctx is done first.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+	// A slot may have been free at the time ctx was done too.
+	if ctx.Err() != nil {
+		<-sem
+		return false
+	}
+	return true
+}
+
 // Summary returns a summary of results.
 type Summary struct {
 	Total   int
 	Success int
-	Failed  int
-	Results []Result
+	// Failed counts every operation that did not succeed, including the cancelled ones.
+	Failed    int
+	Cancelled int
+	Results   []Result
+}
+
+// String describes the summary for humans, e.g. "8 succeeded, 2 failed (1 cancelled)".
+func (s Summary) String() string {
+	text := fmt.Sprintf("%d succeeded, %d failed", s.Success, s.Failed)
+	if s.Cancelled > 0 {
+		text += fmt.Sprintf(" (%d cancelled)", s.Cancelled)
+	}
+	return text
 }
 
 // GetSummary returns a summary of the execution results.
@@ -119,43 +331,47 @@ func GetSummary(results []Result) Summary {
 		} else {
 			summary.Failed++
 		}
+		if result.Cancelled {
+			summary.Cancelled++
+		}
 	}
 
 	return summary
 }
 
+// serverIdentifierKey is the result key of the server identifier an operation was given.
+const serverIdentifierKey = "server_identifier"
+
 // PrintBulkJSON prints bulk operation results in minimal JSON format.
-// Each result contains only server_identifier, status ("success" | "error"), and optional error.
+// Each result contains only server_identifier, the server_uuid it resolved to, status ("success" | "error"),
+// and optional error.
 func PrintBulkJSON(formatter *output.Formatter, results []Result, summary Summary, continueOnError bool) error {
-	outputData := make([]map[string]any, 0, len(results))
+	return PrintBulkJSONWithKey(formatter, results, summary, continueOnError, serverIdentifierKey)
+}
 
+// PrintBulkJSONWithKey prints bulk operation results like PrintBulkJSON, but stores each
+// operation ID under idKey instead of server_identifier (e.g. "path" for file operations).
+func PrintBulkJSONWithKey(
+	formatter *output.Formatter,
+	results []Result,
+	summary Summary,
+	continueOnError bool,
+	idKey string,
+) error {
+	record := KeyedRecord(idKey)
+	records := make([]map[string]any, 0, len(results))
 	for _, result := range results {
-		resultData := map[string]any{
-			"server_identifier": result.Operation.ID,
-		}
-		if result.Success {
-			resultData["status"] = "success"
-		} else {
-			resultData["status"] = "error"
-			resultData["error"] = result.Error.Error()
-		}
-		outputData = append(outputData, resultData)
+		records = append(records, record(result))
 	}
 
-	response := map[string]any{
-		"results": outputData,
-		"summary": map[string]any{
-			"succeeded": summary.Success,
-			"failed":    summary.Failed,
-		},
-	}
+	response := Response(records, summary)
 
 	if err := formatter.Print(response); err != nil {
 		return err
 	}
 
 	if summary.Failed > 0 && !continueOnError {
-		return fmt.Errorf("%d operation(s) failed", summary.Failed)
+		return apierrors.BulkFailure(fmt.Errorf("%d operation(s) failed", summary.Failed), summary.Failed, summary.Total)
 	}
 
 	return nil
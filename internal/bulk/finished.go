@@ -0,0 +1,27 @@
+package bulk
+
+import "sync"
+
+var (
+	//nolint:gochecknoglobals // Results of every executor of the process, notified once the command is done
+	finished []Result
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the finished results
+	finishedMutex sync.Mutex
+)
+
+// remember keeps a result for Finished.
+func remember(result Result) {
+	finishedMutex.Lock()
+	defer finishedMutex.Unlock()
+	finished = append(finished, result)
+}
+
+// Finished returns the results of every operation of the command so far, in the order they finished,
+// e.g. to notify a webhook of them. Like the result stream it leaves out executors without a record,
+// whose operations are steps such as lookups rather than results of the command.
+func Finished() []Result {
+	finishedMutex.Lock()
+	defer finishedMutex.Unlock()
+	return append([]Result(nil), finished...)
+}
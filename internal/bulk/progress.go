@@ -0,0 +1,103 @@
+package bulk
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressBarWidth is the number of cells of the bar drawn by ProgressBar.
+const progressBarWidth = 30
+
+// Progress is the state of a bulk run after one of its operations finished.
+type Progress struct {
+	// Result is the result of the operation that just finished.
+	Result    Result
+	Completed int
+	Total     int
+	Failed    int
+	Elapsed   time.Duration
+	// ETA estimates the time left from the average time an operation took so far. It is zero once
+	// the run is done.
+	ETA time.Duration
+}
+
+// Done reports whether every operation of the run has finished.
+func (p Progress) Done() bool {
+	return p.Completed >= p.Total
+}
+
+// ProgressFunc is called after each operation of a bulk run finishes, one call at a time and in the
+// order the operations finished, so it can render progress without locking.
+type ProgressFunc func(Progress)
+
+//nolint:gochecknoglobals // Progress of every executor, set up once by the root command with --progress
+var defaultProgress struct {
+	mu sync.Mutex
+	fn ProgressFunc
+}
+
+// SetProgress makes executors without their own progress function report to fn, e.g. the bar
+// shown with --progress. A nil fn turns it off.
+func SetProgress(fn ProgressFunc) {
+	defaultProgress.mu.Lock()
+	defer defaultProgress.mu.Unlock()
+	defaultProgress.fn = fn
+}
+
+func getDefaultProgress() ProgressFunc {
+	defaultProgress.mu.Lock()
+	defer defaultProgress.mu.Unlock()
+	return defaultProgress.fn
+}
+
+// newProgress computes the progress of a run started at start.
+func newProgress(result Result, completed, failed, total int, start time.Time) Progress {
+	p := Progress{
+		Result:    result,
+		Completed: completed,
+		Total:     total,
+		Failed:    failed,
+		Elapsed:   time.Since(start),
+	}
+	if completed > 0 && completed < total {
+		p.ETA = p.Elapsed / time.Duration(completed) * time.Duration(total-completed)
+	}
+	return p
+}
+
+// ProgressBar returns a progress function drawing a bar with the operations done, the failures and the
+// ETA on stderr. On a terminal the bar is redrawn in place; otherwise every update is printed on its own line.
+func ProgressBar() ProgressFunc {
+	redraw := term.IsTerminal(int(os.Stderr.Fd()))
+	return func(p Progress) {
+		filled := 0
+		if p.Total > 0 {
+			filled = progressBarWidth * p.Completed / p.Total
+		}
+		line := fmt.Sprintf("[%s%s] %d/%d done", strings.Repeat("=", filled),
+			strings.Repeat(" ", progressBarWidth-filled), p.Completed, p.Total)
+		if p.Failed > 0 {
+			line += fmt.Sprintf(", %d failed", p.Failed)
+		}
+		if p.Done() {
+			line += ", took " + p.Elapsed.Round(time.Second).String()
+		} else if p.ETA > 0 {
+			line += ", ETA " + p.ETA.Round(time.Second).String()
+		}
+
+		switch {
+		case !redraw:
+			_, _ = fmt.Fprintln(os.Stderr, line)
+		case p.Done():
+			// Clear what is left of a longer previous line, then leave the final bar in place.
+			_, _ = fmt.Fprintf(os.Stderr, "\r%s\033[K\n", line)
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "\r%s\033[K", line)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package bulk
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// RecordFunc builds the JSON record of an operation result.
+type RecordFunc func(Result) map[string]any
+
+var (
+	//nolint:gochecknoglobals // Result stream is process-wide, set up once by the root command
+	stream io.Writer
+
+	//nolint:gochecknoglobals // Identifier stream is process-wide, set up once by the root command
+	porcelain io.Writer
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the result stream
+	streamMutex sync.Mutex
+)
+
+// SetStream makes executors write the record of every result to w as a JSON line as soon as the
+// operation completes, for ndjson output. A nil writer turns streaming off.
+func SetStream(w io.Writer) {
+	streamMutex.Lock()
+	defer streamMutex.Unlock()
+	stream = w
+}
+
+// SetPorcelain makes executors write the identifier of every operation that succeeded to w, one per line,
+// as soon as it completes, for porcelain output: the UUID of a server, or else the operation ID. A nil
+// writer turns it off.
+func SetPorcelain(w io.Writer) {
+	streamMutex.Lock()
+	defer streamMutex.Unlock()
+	porcelain = w
+}
+
+// Streaming reports whether results are streamed, in which case printing them again at the end
+// is left to the summary alone.
+func Streaming() bool {
+	streamMutex.Lock()
+	defer streamMutex.Unlock()
+	return stream != nil
+}
+
+// emit writes the record of a result to the stream, if streaming is on, and keeps the result for Finished.
+// Write errors are ignored, like those of progress events, so they don't turn a successful operation into
+// a failed one.
+func emit(record RecordFunc, result Result) {
+	if record == nil {
+		return
+	}
+	remember(result)
+	streamMutex.Lock()
+	defer streamMutex.Unlock()
+	if porcelain != nil && result.Success {
+		_, _ = io.WriteString(porcelain, porcelainID(record(result), result)+"\n")
+	}
+	if stream == nil {
+		return
+	}
+	data, err := json.Marshal(record(result))
+	if err != nil {
+		return
+	}
+	_, _ = stream.Write(append(data, '\n'))
+}
+
+// porcelainID returns the identifier of a result in porcelain output: the UUID the server of its record
+// resolved to, or else its operation ID.
+func porcelainID(record map[string]any, result Result) string {
+	if uuid, ok := record["server_uuid"].(string); ok && uuid != "" {
+		return uuid
+	}
+	return result.Operation.ID
+}
+
+// ServerRecord is the minimal record of a result of an operation on a server: the server_identifier
+// it was given, the server_uuid it resolved to, its status ("success" | "error" | "cancelled"), the
+// error, if any, and the attempts it took if it was retried.
+func ServerRecord(result Result) map[string]any {
+	return KeyedRecord(serverIdentifierKey)(result)
+}
+
+// KeyedRecord returns a record function like ServerRecord, but storing each operation ID under idKey
+// (e.g. "path" for file operations).
+func KeyedRecord(idKey string) RecordFunc {
+	return func(result Result) map[string]any {
+		record := map[string]any{
+			idKey: result.Operation.ID,
+		}
+		if idKey == serverIdentifierKey {
+			record["server_uuid"] = output.CanonicalServer(result.Operation.ID)
+		}
+		record["status"] = result.Status()
+		if !result.Success {
+			record["error"] = result.Error.Error()
+		}
+		if result.Attempts > 1 {
+			record["attempts"] = result.Attempts
+		}
+		return record
+	}
+}
+
+// Response is the JSON document of a bulk run: its records and a summary. While results are streamed
+// the records have already been written, so the response is only the summary.
+func Response(records []map[string]any, summary Summary) map[string]any {
+	response := map[string]any{
+		"summary": map[string]any{
+			"succeeded": summary.Success,
+			"failed":    summary.Failed,
+			"cancelled": summary.Cancelled,
+		},
+	}
+	if !Streaming() {
+		response["results"] = records
+	}
+	return response
+}
@@ -0,0 +1,103 @@
+// Package capacity checks whether a node can fit the resources requested for a new server,
+// using the same over-allocation rules as the panel.
+package capacity
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Unlimited is returned by Resource.Limit when a resource is not capped on the node.
+const Unlimited = -1
+
+// Resource is one capped resource (memory or disk, in MiB) on a node.
+type Resource struct {
+	Name      string `json:"name"`
+	Total     int64  `json:"total"`
+	Allocated int64  `json:"allocated"`
+	Requested int64  `json:"requested"`
+	// Overallocate is the percentage the node may be over-committed by; -1 disables the check.
+	Overallocate int64 `json:"overallocate"`
+}
+
+// Limit returns the most that may be allocated on the node including over-allocation,
+// or Unlimited when the node does not cap this resource.
+func (r Resource) Limit() int64 {
+	if r.Total <= 0 || r.Overallocate < 0 {
+		return Unlimited
+	}
+	//nolint:mnd // over-allocation is a percentage
+	return r.Total * (100 + r.Overallocate) / 100
+}
+
+// Free returns how much is left before the limit, or Unlimited.
+func (r Resource) Free() int64 {
+	limit := r.Limit()
+	if limit == Unlimited {
+		return Unlimited
+	}
+	return limit - r.Allocated
+}
+
+// Fits reports whether the requested amount fits within the limit.
+func (r Resource) Fits() bool {
+	limit := r.Limit()
+	return limit == Unlimited || r.Allocated+r.Requested <= limit
+}
+
+// Report is the capacity of a node for a requested server.
+type Report struct {
+	NodeID    int        `json:"node_id"`
+	Node      string     `json:"node"`
+	Resources []Resource `json:"resources"`
+}
+
+// Fits reports whether every resource fits.
+func (r Report) Fits() bool {
+	for _, resource := range r.Resources {
+		if !resource.Fits() {
+			return false
+		}
+	}
+	return true
+}
+
+// Headers returns the table headers matching Rows.
+func (r Report) Headers() []string {
+	return []string{"Resource", "Total", "Limit", "Allocated", "Free", "Requested", "Fits"}
+}
+
+// Rows returns one table row per resource, amounts in MiB.
+func (r Report) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Resources))
+	for _, resource := range r.Resources {
+		rows = append(rows, []string{
+			resource.Name,
+			formatMiB(resource.Total),
+			formatMiB(resource.Limit()),
+			formatMiB(resource.Allocated),
+			formatMiB(resource.Free()),
+			formatMiB(resource.Requested),
+			strconv.FormatBool(resource.Fits()),
+		})
+	}
+	return rows
+}
+
+// Err describes the first resource that does not fit, or returns nil if all do.
+func (r Report) Err() error {
+	for _, resource := range r.Resources {
+		if !resource.Fits() {
+			return fmt.Errorf("node %s does not have enough %s: %d MiB requested, %d MiB free",
+				r.Node, resource.Name, resource.Requested, resource.Free())
+		}
+	}
+	return nil
+}
+
+func formatMiB(value int64) string {
+	if value == Unlimited {
+		return "unlimited"
+	}
+	return strconv.FormatInt(value, 10) + " MiB"
+}
@@ -1,23 +1,46 @@
 package completion
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
 )
 
 const (
 	defaultCacheTTL = 5 * time.Minute
+	// cacheFileName is the file of the cache in the pelicanctl user cache directory, next to the server index.
+	cacheFileName = "completions.json"
 )
 
+// Every completion runs in a process of its own, so candidates are kept on disk (with cache.persist) for
+// the next one. Offline, the cached candidates are served however old they are.
+
 type cacheEntry struct {
-	data      []string
-	timestamp time.Time
+	Data      []string  `json:"data"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// cacheData is the persisted form of the cache.
+type cacheData struct {
+	// Panel is the base URL the candidates were listed from; candidates of another panel are discarded.
+	Panel   string                `json:"panel"`
+	Entries map[string]cacheEntry `json:"entries"`
 }
 
 var (
-	cache     sync.Map
-	cacheTTL  = defaultCacheTTL
-	cacheLock sync.RWMutex
+	//nolint:gochecknoglobals // Cache is loaded once per process and shared by every completion
+	cached *cacheData
+	//nolint:gochecknoglobals // Overridable TTL, see SetCacheTTL
+	cacheTTL = defaultCacheTTL
+	//nolint:gochecknoglobals // Global mutex needed to protect the shared cache
+	cacheLock sync.Mutex
 )
 
 // getCacheKey generates a cache key from API type and resource type.
@@ -25,31 +48,32 @@ func getCacheKey(apiType, resourceType string) string {
 	return apiType + ":" + resourceType
 }
 
-// getCached retrieves cached data if it's still valid.
+// getCached retrieves cached data if it's still valid, or however old it is when offline.
 func getCached(key string) []string {
-	entry, ok := cache.Load(key)
-	if !ok {
-		return nil
-	}
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
 
-	ce, ok := entry.(cacheEntry)
+	entry, ok := loadCache().Entries[key]
 	if !ok {
 		return nil
 	}
-	if time.Since(ce.timestamp) > cacheTTL {
-		cache.Delete(key)
+	if !offline() && time.Since(entry.Timestamp) > cacheTTL {
+		delete(cached.Entries, key)
 		return nil
 	}
-
-	return ce.data
+	return entry.Data
 }
 
 // setCached stores data in the cache.
 func setCached(key string, data []string) {
-	cache.Store(key, cacheEntry{
-		data:      data,
-		timestamp: time.Now(),
-	})
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+
+	loadCache().Entries[key] = cacheEntry{Data: data, Timestamp: time.Now()}
+	if persistentCache() {
+		// Completion works without the file, so a failure to write it is ignored.
+		_ = saveCache(cached)
+	}
 }
 
 // SetCacheTTL sets the cache TTL (for testing or configuration).
@@ -58,3 +82,104 @@ func SetCacheTTL(ttl time.Duration) {
 	defer cacheLock.Unlock()
 	cacheTTL = ttl
 }
+
+// CachePath returns the file that stores the cached completion candidates.
+func CachePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "pelicanctl", cacheFileName), nil
+}
+
+// ClearCache forgets every cached candidate, in memory and on disk.
+func ClearCache() error {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+
+	cached = nil
+	path, err := CachePath()
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to clear completion cache: %w", err)
+	}
+	return nil
+}
+
+// loadCache returns the cache of the current panel, reading it from disk the first time. Callers hold cacheLock.
+func loadCache() *cacheData {
+	panel := ""
+	if cfg := config.Get(); cfg != nil {
+		panel = strings.TrimSuffix(cfg.API.BaseURL, "/")
+	}
+	if cached != nil && cached.Panel == panel {
+		return cached
+	}
+
+	var stored *cacheData
+	if persistentCache() {
+		stored = readCache()
+	}
+	if stored == nil || stored.Panel != panel {
+		stored = &cacheData{Panel: panel}
+	}
+	if stored.Entries == nil {
+		stored.Entries = map[string]cacheEntry{}
+	}
+	cached = stored
+	return cached
+}
+
+// persistentCache reports whether the cache is kept on disk, from cache.persist.
+func persistentCache() bool {
+	cfg := config.Get()
+	return cfg == nil || cfg.Cache.Persist
+}
+
+// readCache loads the stored cache. A missing or unreadable cache is nil.
+func readCache() *cacheData {
+	path, err := CachePath()
+	if err != nil {
+		return nil
+	}
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var data cacheData
+	if err = json.Unmarshal(encoded, &data); err != nil {
+		return nil
+	}
+	return &data
+}
+
+// saveCache writes the cache atomically.
+func saveCache(data *cacheData) error {
+	path, err := CachePath()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode completion cache: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".completions-*")
+	if err != nil {
+		return fmt.Errorf("failed to write completion cache: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(encoded); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write completion cache: %w", err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to write completion cache: %w", err)
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -1,74 +1,94 @@
 // Package completion provides completions for the CLI.
+//
+// Completions run outside of a command invocation, so their API calls use a background context,
+// bounded by completion.timeout. With completion.offline they are served from cached candidates only.
 package completion
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
 	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/index"
 )
 
-// CompleteServers returns server UUIDs and IDs for client or admin API.
+// CompleteServers returns server UUIDs, IDs and names for client or admin API, each described by
+// the name (or UUID) and node of the server. Servers come from the server index, which is only
+// refreshed from the API when stale.
 func CompleteServers(apiType string, toComplete string) ([]string, error) {
-	cacheKey := getCacheKey(apiType, "servers")
-	if cached := getCached(cacheKey); cached != nil {
-		return filterCompletions(cached, toComplete), nil
-	}
-
-	var servers []map[string]any
-	var err error
-
+	source := index.SourceAdmin
 	if apiType == "client" {
-		var client *api.ClientAPI
-		client, err = api.NewClientAPI()
-		if err != nil {
-			return nil, nil
-		}
-		servers, err = client.ListServers()
-	} else {
-		var client *api.ApplicationAPI
-		client, err = api.NewApplicationAPI()
-		if err != nil {
-			return nil, nil
-		}
-		servers, err = client.ListServers()
+		source = index.SourceClient
 	}
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "completion error: failed to list servers: %v\n", err)
-		return nil, nil
+	// A stale index still completes when completion is offline or the panel does not answer in time.
+	entries, fresh := index.Servers(source)
+	if !fresh {
+		if err := refreshServers(source); err == nil {
+			entries, _ = index.Servers(source)
+		} else if !errors.Is(err, errOffline) {
+			fmt.Fprintf(os.Stderr, "completion error: failed to list servers: %v\n", err)
+		}
 	}
 
+	var nodeNames map[string]string
 	var identifiers []string
-	for _, server := range servers {
-		if uuid, ok := server["uuid"].(string); ok {
-			identifiers = append(identifiers, uuid)
+	for _, entry := range entries {
+		node := entry.NodeName
+		if node == "" && entry.Node != "" {
+			// The Application API lists servers with the ID of their node only.
+			if nodeNames == nil {
+				nodeNames = completeNodeNames()
+			}
+			node = nodeNames[entry.Node]
+			if node == "" {
+				node = "node " + entry.Node
+			}
+		}
+		description := entry.Name
+		if node != "" {
+			description += " (" + node + ")"
 		}
-		if id, ok := server["id"]; ok {
-			identifiers = append(identifiers, fmt.Sprintf("%v", id))
+		identifiers = append(identifiers, described(entry.UUID, description))
+		if entry.ID != "" {
+			identifiers = append(identifiers, described(entry.ID, description))
+		}
+		// Names with whitespace don't survive shell completion.
+		if entry.Name != "" && !strings.ContainsAny(entry.Name, " \t") {
+			nameDescription := entry.UUID
+			if node != "" {
+				nameDescription += " (" + node + ")"
+			}
+			identifiers = append(identifiers, described(entry.Name, nameDescription))
 		}
 	}
 
-	setCached(cacheKey, identifiers)
 	return filterCompletions(identifiers, toComplete), nil
 }
 
-// CompleteNodes returns node IDs for admin API.
+// CompleteNodes returns node IDs for admin API, described by their names.
 func CompleteNodes(toComplete string) ([]string, error) {
 	cacheKey := getCacheKey("admin", "nodes")
 	if cached := getCached(cacheKey); cached != nil {
 		return filterCompletions(cached, toComplete), nil
 	}
 
-	client, err := api.NewApplicationAPI()
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newApplicationAPI()
 	if err != nil {
 		return nil, nil
 	}
 
 	var nodes []map[string]any
-	nodes, err = client.ListNodes()
+	nodes, err = client.ListNodes(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "completion error: failed to list nodes: %v\n", err)
 		return nil, nil
@@ -76,8 +96,8 @@ func CompleteNodes(toComplete string) ([]string, error) {
 
 	var identifiers []string
 	for _, node := range nodes {
-		if id, ok := node["id"]; ok {
-			identifiers = append(identifiers, fmt.Sprintf("%v", id))
+		if id := fieldString(node, "id"); id != "" {
+			identifiers = append(identifiers, described(id, fieldString(node, "name")))
 		}
 	}
 
@@ -85,20 +105,67 @@ func CompleteNodes(toComplete string) ([]string, error) {
 	return filterCompletions(identifiers, toComplete), nil
 }
 
-// CompleteUsers returns user IDs for admin API.
+// refreshServers lists the servers of source, which updates the server index.
+func refreshServers(source string) error {
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newServerAPI(source)
+	if err != nil {
+		return err
+	}
+	_, err = client.ListServers(ctx)
+	return err
+}
+
+// completeNodeNames returns the names of the nodes by ID, from the node completions.
+func completeNodeNames() map[string]string {
+	candidates, _ := CompleteNodes("")
+	names := make(map[string]string, len(candidates))
+	for _, candidate := range candidates {
+		id, name, _ := strings.Cut(candidate, "\t")
+		names[id] = name
+	}
+	return names
+}
+
+// CompleteWingsNodes returns the nodes configured for direct Wings access.
+func CompleteWingsNodes(toComplete string) ([]string, error) {
+	return filterCompletions(api.WingsNodes(), toComplete), nil
+}
+
+// CompleteGroups returns the server groups defined in the config file.
+func CompleteGroups(toComplete string) ([]string, error) {
+	cfg := config.Get()
+	if cfg == nil {
+		return nil, nil
+	}
+
+	groups := make([]string, 0, len(cfg.Groups))
+	for name := range cfg.Groups {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+	return filterCompletions(groups, toComplete), nil
+}
+
+// CompleteUsers returns user IDs, usernames and emails for admin API, each described by the others.
 func CompleteUsers(toComplete string) ([]string, error) {
 	cacheKey := getCacheKey("admin", "users")
 	if cached := getCached(cacheKey); cached != nil {
 		return filterCompletions(cached, toComplete), nil
 	}
 
-	client, err := api.NewApplicationAPI()
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newApplicationAPI()
 	if err != nil {
 		return nil, nil
 	}
 
 	var users []map[string]any
-	users, err = client.ListUsers()
+	users, err = client.ListUsers(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "completion error: failed to list users: %v\n", err)
 		return nil, nil
@@ -106,8 +173,48 @@ func CompleteUsers(toComplete string) ([]string, error) {
 
 	var identifiers []string
 	for _, user := range users {
-		if id, ok := user["id"]; ok {
-			identifiers = append(identifiers, fmt.Sprintf("%v", id))
+		username, email := fieldString(user, "username"), fieldString(user, "email")
+		if id := fieldString(user, "id"); id != "" {
+			identifiers = append(identifiers, described(id, fmt.Sprintf("%s <%s>", username, email)))
+		}
+		// Commands take users by username or email as well.
+		if username != "" {
+			identifiers = append(identifiers, described(username, email))
+		}
+		if email != "" {
+			identifiers = append(identifiers, described(email, username))
+		}
+	}
+
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
+}
+
+// CompleteDatabaseHosts returns database host IDs for admin API, described by their names.
+func CompleteDatabaseHosts(toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("admin", "database-hosts")
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newApplicationAPI()
+	if err != nil {
+		return nil, nil
+	}
+
+	hosts, err := client.ListDatabaseHosts(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to list database hosts: %v\n", err)
+		return nil, nil
+	}
+
+	var identifiers []string
+	for _, host := range hosts {
+		if id := fieldString(host, "id"); id != "" {
+			identifiers = append(identifiers, described(id, fieldString(host, "name")))
 		}
 	}
 
@@ -115,25 +222,92 @@ func CompleteUsers(toComplete string) ([]string, error) {
 	return filterCompletions(identifiers, toComplete), nil
 }
 
-// CompleteBackups returns backup UUIDs for a server.
+// CompleteEggs returns egg IDs for admin API, described by their names.
+func CompleteEggs(toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("admin", "eggs")
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newApplicationAPI()
+	if err != nil {
+		return nil, nil
+	}
+
+	eggs, err := client.ListEggs(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to list eggs: %v\n", err)
+		return nil, nil
+	}
+
+	var identifiers []string
+	for _, egg := range eggs {
+		if id := fieldString(egg, "id"); id != "" {
+			identifiers = append(identifiers, described(id, fieldString(egg, "name")))
+		}
+	}
+
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
+}
+
+// CompleteRoles returns role IDs for admin API, described by their names.
+func CompleteRoles(toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("admin", "roles")
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newApplicationAPI()
+	if err != nil {
+		return nil, nil
+	}
+
+	roles, err := client.ListRoles(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to list roles: %v\n", err)
+		return nil, nil
+	}
+
+	var identifiers []string
+	for _, role := range roles {
+		if id := fieldString(role, "id"); id != "" {
+			identifiers = append(identifiers, described(id, fieldString(role, "name")))
+		}
+	}
+
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
+}
+
+// CompleteBackups returns backup UUIDs for a server, described by their names.
 func CompleteBackups(serverIdentifier, toComplete string) ([]string, error) {
 	cacheKey := getCacheKey("client", "backups:"+serverIdentifier)
 	if cached := getCached(cacheKey); cached != nil {
 		return filterCompletions(cached, toComplete), nil
 	}
 
-	client, err := api.NewClientAPI()
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newClientAPI()
 	if err != nil {
 		return nil, nil
 	}
 
 	var serverUUID string
-	serverUUID, err = getServerUUID(client, serverIdentifier)
+	serverUUID, err = getServerUUID(ctx, client, serverIdentifier)
 	if err != nil {
 		return nil, nil
 	}
 
-	backups, err := client.ListBackups(serverUUID)
+	backups, err := client.ListBackups(ctx, serverUUID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "completion error: failed to list backups: %v\n", err)
 		return nil, nil
@@ -141,9 +315,9 @@ func CompleteBackups(serverIdentifier, toComplete string) ([]string, error) {
 
 	var identifiers []string
 	for _, backup := range backups {
-		if uuid, ok := backup["uuid"].(string); ok {
-			identifiers = append(identifiers, uuid)
-		} else if name, okName := backup["name"].(string); okName {
+		if uuid := fieldString(backup, "uuid"); uuid != "" {
+			identifiers = append(identifiers, described(uuid, fieldString(backup, "name")))
+		} else if name := fieldString(backup, "name"); name != "" {
 			identifiers = append(identifiers, name)
 		}
 	}
@@ -152,6 +326,38 @@ func CompleteBackups(serverIdentifier, toComplete string) ([]string, error) {
 	return filterCompletions(identifiers, toComplete), nil
 }
 
+// CompleteAdminBackups returns backup UUIDs for a server using the admin API, described by their names.
+func CompleteAdminBackups(serverIdentifier, toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("admin", "backups:"+serverIdentifier)
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newApplicationAPI()
+	if err != nil {
+		return nil, nil
+	}
+
+	backups, err := client.ListBackups(ctx, serverIdentifier)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to list backups: %v\n", err)
+		return nil, nil
+	}
+
+	var identifiers []string
+	for _, backup := range backups {
+		if uuid := fieldString(backup, "uuid"); uuid != "" {
+			identifiers = append(identifiers, described(uuid, fieldString(backup, "name")))
+		}
+	}
+
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
+}
+
 // CompleteDatabases returns database names for a server.
 func CompleteDatabases(serverIdentifier, toComplete string) ([]string, error) {
 	cacheKey := getCacheKey("client", "databases:"+serverIdentifier)
@@ -159,18 +365,21 @@ func CompleteDatabases(serverIdentifier, toComplete string) ([]string, error) {
 		return filterCompletions(cached, toComplete), nil
 	}
 
-	client, err := api.NewClientAPI()
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newClientAPI()
 	if err != nil {
 		return nil, nil
 	}
 
 	var serverUUID string
-	serverUUID, err = getServerUUID(client, serverIdentifier)
+	serverUUID, err = getServerUUID(ctx, client, serverIdentifier)
 	if err != nil {
 		return nil, nil
 	}
 
-	databases, err := client.ListDatabases(serverUUID)
+	databases, err := client.ListDatabases(ctx, serverUUID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "completion error: failed to list databases: %v\n", err)
 		return nil, nil
@@ -187,99 +396,237 @@ func CompleteDatabases(serverIdentifier, toComplete string) ([]string, error) {
 	return filterCompletions(names, toComplete), nil
 }
 
-// CompleteFiles returns file paths for a server and directory.
-func CompleteFiles(serverIdentifier, directory, toComplete string) ([]string, error) {
-	// Don't cache file listings as they change frequently
-	client, err := api.NewClientAPI()
+// CompleteSchedules returns schedule IDs for a server, described by their names.
+func CompleteSchedules(serverIdentifier, toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("client", "schedules:"+serverIdentifier)
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newClientAPI()
 	if err != nil {
 		return nil, nil
 	}
 
-	var serverUUID string
-	serverUUID, err = getServerUUID(client, serverIdentifier)
+	schedules, err := client.ListSchedules(ctx, serverIdentifier)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to list schedules: %v\n", err)
+		return nil, nil
+	}
+
+	var identifiers []string
+	for _, schedule := range schedules {
+		if id := fieldString(schedule, "id"); id != "" {
+			identifiers = append(identifiers, described(id, fieldString(schedule, "name")))
+		}
+	}
+
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
+}
+
+// CompleteAllocations returns allocation IDs for a server, described by their addresses.
+func CompleteAllocations(serverIdentifier, toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("client", "allocations:"+serverIdentifier)
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newClientAPI()
 	if err != nil {
 		return nil, nil
 	}
 
-	files, err := client.ListFiles(serverUUID, directory)
+	allocations, err := client.ListAllocations(ctx, serverIdentifier)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "completion error: failed to list files: %v\n", err)
+		fmt.Fprintf(os.Stderr, "completion error: failed to list allocations: %v\n", err)
 		return nil, nil
 	}
 
-	var paths []string
-	for _, file := range files {
-		if name, ok := file["name"].(string); ok {
-			// Build full path if directory is provided
-			if directory != "" {
-				paths = append(paths, strings.TrimSuffix(directory, "/")+"/"+name)
-			} else {
-				paths = append(paths, name)
-			}
+	var identifiers []string
+	for _, allocation := range allocations {
+		if id := fieldString(allocation, "id"); id != "" {
+			identifiers = append(identifiers, described(id, allocationAddress(allocation)))
 		}
 	}
 
-	return filterCompletions(paths, toComplete), nil
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
 }
 
-// getServerUUID converts a server identifier (UUID or ID) to UUID using the client API.
-// This is a helper that uses the ClientAPI's internal method.
-func getServerUUID(client *api.ClientAPI, identifier string) (string, error) {
-	// Check if it looks like a UUID (contains hyphens)
-	if strings.Contains(identifier, "-") {
-		return identifier, nil
+// CompleteAPIKeys returns the identifiers of the user's Client API keys, described by their descriptions.
+func CompleteAPIKeys(toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("client", "apikeys")
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
 	}
 
-	// Try to parse as integer ID - if it fails, assume it's a UUID
-	if _, atoiErr := strconv.Atoi(identifier); atoiErr != nil {
-		return identifier, nil
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newClientAPI()
+	if err != nil {
+		return nil, nil
 	}
 
-	// It's an integer ID, need to look it up from server list
-	servers, err := client.ListServers()
+	keys, err := client.ListAPIKeys(ctx)
 	if err != nil {
-		return "", err
+		fmt.Fprintf(os.Stderr, "completion error: failed to list API keys: %v\n", err)
+		return nil, nil
 	}
 
-	// Find server with matching ID
-	for _, server := range servers {
-		var serverID any
-		if id, hasID := server["id"]; hasID {
-			serverID = id
-		} else if attrs, hasAttrs := server["attributes"].(map[string]any); hasAttrs {
-			if idVal, hasIDVal := attrs["id"]; hasIDVal {
-				serverID = idVal
-			}
+	var identifiers []string
+	for _, key := range keys {
+		if identifier := fieldString(key, "identifier"); identifier != "" {
+			identifiers = append(identifiers, described(identifier, fieldString(key, "description")))
 		}
+	}
 
-		// Compare IDs
-		var idInt int
-		switch v := serverID.(type) {
-		case int:
-			idInt = v
-		case int64:
-			idInt = int(v)
-		case float64:
-			idInt = int(v)
-		case string:
-			parsed, parseErr := strconv.Atoi(v)
-			if parseErr != nil {
-				continue
-			}
-			idInt = parsed
-		default:
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
+}
+
+// CompleteNodeAllocations returns allocation IDs of a node, described by their addresses.
+func CompleteNodeAllocations(nodeID, toComplete string) ([]string, error) {
+	cacheKey := getCacheKey("admin", "allocations:"+nodeID)
+	if cached := getCached(cacheKey); cached != nil {
+		return filterCompletions(cached, toComplete), nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newApplicationAPI()
+	if err != nil {
+		return nil, nil
+	}
+
+	allocations, err := client.ListNodeAllocations(ctx, nodeID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to list allocations: %v\n", err)
+		return nil, nil
+	}
+
+	var identifiers []string
+	for _, allocation := range allocations {
+		id := fieldString(allocation, "id")
+		if id == "" {
 			continue
 		}
+		description := allocationAddress(allocation)
+		if assigned, _ := lookupField(allocation, "assigned").(bool); assigned {
+			description += " (assigned)"
+		}
+		identifiers = append(identifiers, described(id, description))
+	}
+
+	setCached(cacheKey, identifiers)
+	return filterCompletions(identifiers, toComplete), nil
+}
+
+// CompleteScheduleTasks returns task IDs for a schedule of a server, described by their actions.
+func CompleteScheduleTasks(serverIdentifier, scheduleID, toComplete string) ([]string, error) {
+	id, err := strconv.Atoi(scheduleID)
+	if err != nil {
+		return nil, nil
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newClientAPI()
+	if err != nil {
+		return nil, nil
+	}
+
+	schedule, err := client.GetSchedule(ctx, serverIdentifier, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to get schedule: %v\n", err)
+		return nil, nil
+	}
+
+	var identifiers []string
+	for _, task := range api.ScheduleTasks(schedule) {
+		if taskID := fieldString(task, "id"); taskID != "" {
+			identifiers = append(identifiers,
+				described(taskID, fieldString(task, "action")+" "+fieldString(task, "payload")))
+		}
+	}
+
+	return filterCompletions(identifiers, toComplete), nil
+}
+
+// allocationAddress returns the ip:port of an allocation, followed by its alias or notes if set.
+func allocationAddress(allocation map[string]any) string {
+	address := fieldString(allocation, "ip") + ":" + fieldString(allocation, "port")
+	if alias := fieldString(allocation, "alias"); alias != "" {
+		address += " " + alias
+	}
+	if notes := fieldString(allocation, "notes"); notes != "" {
+		address += " " + notes
+	}
+	return address
+}
+
+// lookupField reads a field from the root of a resource or from its attributes.
+func lookupField(resource map[string]any, key string) any {
+	if val, ok := resource[key]; ok {
+		return val
+	}
+	if attrs, ok := resource["attributes"].(map[string]any); ok {
+		return attrs[key]
+	}
+	return nil
+}
+
+// CompleteFiles returns file paths for a server and directory.
+func CompleteFiles(serverIdentifier, directory, toComplete string) ([]string, error) {
+	// Don't cache file listings as they change frequently
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	client, err := newClientAPI()
+	if err != nil {
+		return nil, nil
+	}
+
+	var serverUUID string
+	serverUUID, err = getServerUUID(ctx, client, serverIdentifier)
+	if err != nil {
+		return nil, nil
+	}
+
+	files, err := client.ListFiles(ctx, serverUUID, directory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "completion error: failed to list files: %v\n", err)
+		return nil, nil
+	}
 
-		targetID, _ := strconv.Atoi(identifier)
-		if idInt == targetID {
-			if uuid, ok := server["uuid"].(string); ok {
-				return uuid, nil
+	var paths []string
+	for _, file := range files {
+		if name, ok := file["name"].(string); ok {
+			// Build full path if directory is provided
+			if directory != "" {
+				paths = append(paths, strings.TrimSuffix(directory, "/")+"/"+name)
+			} else {
+				paths = append(paths, name)
 			}
 		}
 	}
 
-	return "", fmt.Errorf("server with ID %s not found", identifier)
+	return filterCompletions(paths, toComplete), nil
+}
+
+// getServerUUID converts a server identifier (UUID, ID or name) to UUID using the client API.
+// This is a helper that uses the ClientAPI's internal method.
+func getServerUUID(ctx context.Context, client *api.ClientAPI, identifier string) (string, error) {
+	return client.ResolveServerUUID(ctx, identifier)
 }
 
 // filterCompletions filters completion results based on the prefix to complete.
@@ -295,7 +642,7 @@ func filterCompletions(completions []string, toComplete string) []string {
 
 	var filtered []string
 	for _, completion := range completions {
-		if strings.HasPrefix(completion, toComplete) {
+		if strings.HasPrefix(candidateValue(completion), toComplete) {
 			filtered = append(filtered, completion)
 		}
 	}
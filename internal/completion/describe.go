@@ -0,0 +1,50 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carapace-sh/carapace"
+)
+
+// Candidates are in cobra's "value\tdescription" form, so shells show what an ID or UUID stands for;
+// cobra passes them on as they are and Action splits them for carapace.
+
+// described returns the candidate value with description; an empty description leaves the bare value.
+func described(value, description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if description == "" {
+		return value
+	}
+	return value + "\t" + description
+}
+
+// candidateValue returns the value of a candidate, without its description.
+func candidateValue(candidate string) string {
+	value, _, _ := strings.Cut(candidate, "\t")
+	return value
+}
+
+// Action returns the carapace action completing the candidates of a Complete function.
+func Action(candidates []string) carapace.Action {
+	pairs := make([]string, 0, 2*len(candidates)) //nolint:mnd // value and description of every candidate
+	for _, candidate := range candidates {
+		value, description, _ := strings.Cut(candidate, "\t")
+		pairs = append(pairs, value, description)
+	}
+	return carapace.ActionValuesDescribed(pairs...)
+}
+
+// fieldString reads a field of a resource as a string, empty if it is missing.
+func fieldString(resource map[string]any, key string) string {
+	switch value := lookupField(resource, key).(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%.0f", value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
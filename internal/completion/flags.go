@@ -0,0 +1,96 @@
+package completion
+
+import (
+	"github.com/carapace-sh/carapace"
+	"github.com/spf13/cobra"
+)
+
+// OutputFormats lists the values accepted by --output.
+//
+//nolint:gochecknoglobals // Static completion values
+var OutputFormats = []string{"table", "wide", "json", "ndjson", "yaml", "csv", "tsv", "porcelain"}
+
+// ScheduleActions lists the task actions accepted by schedules.
+//
+//nolint:gochecknoglobals // Static completion values
+var ScheduleActions = []string{"command", "power", "backup", "delete_files"}
+
+// PowerSignals lists the power signals accepted by the panel.
+//
+//nolint:gochecknoglobals // Static completion values
+var PowerSignals = []string{"start", "stop", "restart", "kill"}
+
+// fileFlags are flags that take a local file path.
+//
+//nolint:gochecknoglobals // Static flag names
+var fileFlags = []string{"config", "file", "from-file", "ignore-file", "save-pairs", "ca-cert", "client-cert", "client-key"}
+
+// dirFlags are flags that take a local directory path.
+//
+//nolint:gochecknoglobals // Static flag names
+var dirFlags = []string{"output-dir", "out"}
+
+// RegisterFlagValues registers static completion values for a flag on cmd.
+// Call it after cmd has been added to its parent (matching carapace example pattern).
+func RegisterFlagValues(cmd *cobra.Command, flag string, values ...string) {
+	_ = cmd.RegisterFlagCompletionFunc(flag, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		flag: carapace.ActionValues(values...),
+	})
+}
+
+// RegisterFlagFunc registers a dynamic completion function for a flag on cmd.
+// The function receives the positional arguments given so far.
+func RegisterFlagFunc(cmd *cobra.Command, flag string, fn func(args []string, toComplete string) ([]string, error)) {
+	_ = cmd.RegisterFlagCompletionFunc(
+		flag,
+		func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			completions, err := fn(args, toComplete)
+			if err != nil || len(completions) == 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{
+		flag: carapace.ActionCallback(func(c carapace.Context) carapace.Action {
+			completions, err := fn(c.Args, c.Value)
+			if err != nil || len(completions) == 0 {
+				return carapace.ActionValues()
+			}
+			return Action(completions)
+		}),
+	})
+}
+
+// RegisterPathFlags walks the command tree and registers local file and directory
+// completion for well-known path flags that have no completion yet.
+func RegisterPathFlags(root *cobra.Command) {
+	for _, name := range fileFlags {
+		registerPathFlag(root, name, false)
+	}
+	for _, name := range dirFlags {
+		registerPathFlag(root, name, true)
+	}
+	for _, sub := range root.Commands() {
+		RegisterPathFlags(sub)
+	}
+}
+
+func registerPathFlag(cmd *cobra.Command, name string, dirsOnly bool) {
+	// Only the command that defines the flag registers it; persistent flags are inherited.
+	if cmd.LocalNonPersistentFlags().Lookup(name) == nil && cmd.PersistentFlags().Lookup(name) == nil {
+		return
+	}
+	if _, exists := cmd.GetFlagCompletionFunc(name); exists {
+		return
+	}
+
+	if dirsOnly {
+		_ = cmd.MarkFlagDirname(name)
+		carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{name: carapace.ActionDirectories()})
+		return
+	}
+	_ = cmd.MarkFlagFilename(name)
+	carapace.Gen(cmd).FlagCompletion(carapace.ActionMap{name: carapace.ActionFiles()})
+}
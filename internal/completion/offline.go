@@ -0,0 +1,62 @@
+package completion
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+)
+
+// errOffline is returned instead of an API client when completion.offline is set.
+var errOffline = errors.New("completion is offline")
+
+var (
+	//nolint:gochecknoglobals // One deadline bounds all requests of a completion
+	deadline time.Time
+	//nolint:gochecknoglobals // Sets deadline once, at the first request
+	deadlineOnce sync.Once
+)
+
+// offline reports whether completion serves cached candidates only, from completion.offline.
+func offline() bool {
+	cfg := config.Get()
+	return cfg != nil && cfg.Completion.Offline
+}
+
+// requestContext returns the context of a request to the panel. All requests of a completion share the
+// deadline of completion.timeout, counted from the first, so an unreachable panel cannot hang the shell.
+func requestContext() (context.Context, context.CancelFunc) {
+	deadlineOnce.Do(func() {
+		timeout := config.DefaultCompletionTimeout
+		if cfg := config.Get(); cfg != nil && cfg.Completion.Timeout > 0 {
+			timeout = cfg.Completion.Timeout
+		}
+		deadline = time.Now().Add(timeout)
+	})
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// newApplicationAPI, newClientAPI and newServerAPI create API clients unless completion is offline.
+func newApplicationAPI() (*api.ApplicationAPI, error) {
+	if offline() {
+		return nil, errOffline
+	}
+	return api.NewApplicationAPI()
+}
+
+func newClientAPI() (*api.ClientAPI, error) {
+	if offline() {
+		return nil, errOffline
+	}
+	return api.NewClientAPI()
+}
+
+func newServerAPI(source string) (api.ServerAPI, error) {
+	if offline() {
+		return nil, errOffline
+	}
+	return api.NewServerAPI(source)
+}
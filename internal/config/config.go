@@ -7,20 +7,72 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// DefaultCacheTTL is how long a server list stays usable for lookups unless cache.ttl says otherwise.
+const DefaultCacheTTL = 10 * time.Minute
+
+// DefaultCompletionTimeout is how long a completion waits for the panel unless completion.timeout says otherwise.
+const DefaultCompletionTimeout = 500 * time.Millisecond
+
 // Config holds the application configuration.
 type Config struct {
 	API    APIConfig    `mapstructure:"api"`
+	Auth   AuthConfig   `mapstructure:"auth"`
 	Client ClientConfig `mapstructure:"client"`
 	Admin  AdminConfig  `mapstructure:"admin"`
+	Output OutputConfig `mapstructure:"output"`
+	Cache  CacheConfig  `mapstructure:"cache"`
+	Notify NotifyConfig `mapstructure:"notify"`
+	// Hooks maps hook names like pre_power_stop or post_backup_create to shell commands run before and
+	// after the command, with the command as JSON on stdin.
+	Hooks map[string]string `mapstructure:"hooks"`
+	// Completion holds settings of shell completion.
+	Completion CompletionConfig `mapstructure:"completion"`
+	// Wings holds optional direct Wings daemon access, keyed by node ID or name.
+	Wings map[string]WingsNodeConfig `mapstructure:"wings"`
+	// Groups maps a group name to the server UUIDs or IDs it contains.
+	Groups map[string][]string `mapstructure:"groups"`
+	// Servers holds per-server settings, keyed by server UUID, ID or group name.
+	Servers map[string]ServerConfig `mapstructure:"servers"`
+	// Profiles holds settings that apply when PELICANCTL_PROFILE names the profile, keyed by its name in
+	// lower case.
+	Profiles map[string]ProfileConfig `mapstructure:"profiles"`
+	// Tokens holds the tokens saved to the config file per profile or API base URL.
+	// Client.Token and Admin.Token are used without a profile or base URL.
+	Tokens []TokenConfig `mapstructure:"tokens"`
 }
 
 // APIConfig holds API-related configuration.
 type APIConfig struct {
 	BaseURL string `mapstructure:"base_url"`
+	// RateLimit is the maximum number of requests per minute sent to the panel; 0 means no limit.
+	RateLimit int `mapstructure:"rate_limit"`
+	// ProxyURL is the HTTP or SOCKS proxy requests to the panel go through, like socks5://bastion:1080.
+	// Without it the proxy environment variables apply.
+	ProxyURL string `mapstructure:"proxy_url"`
+	// TLS holds the certificates for panels behind an internal CA or a proxy requiring client certificates.
+	TLS TLSConfig `mapstructure:"tls"`
+}
+
+// TLSConfig holds the TLS settings of connections to the panel.
+type TLSConfig struct {
+	// CACert is a PEM file of CA certificates trusted in addition to the system ones.
+	CACert string `mapstructure:"ca_cert"`
+	// ClientCert and ClientKey are the PEM files of the client certificate presented to the panel.
+	ClientCert string `mapstructure:"client_cert"`
+	ClientKey  string `mapstructure:"client_key"`
+	// InsecureSkipVerify accepts any certificate of the panel. Only meant for testing.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// AuthConfig holds token storage configuration.
+type AuthConfig struct {
+	// Backend is where tokens are saved: keyring, file (the config file) or encrypted-file.
+	Backend string `mapstructure:"backend"`
 }
 
 // ClientConfig holds client API token configuration.
@@ -33,19 +85,110 @@ type AdminConfig struct {
 	Token string `mapstructure:"token"`
 }
 
+// OutputConfig holds output preferences.
+type OutputConfig struct {
+	// Identity is the server identifier shown in server tables: id, uuid, short-uuid or name.
+	Identity string `mapstructure:"identity"`
+	// TableStyle is how tables are drawn: plain, light, colored or markdown.
+	TableStyle string `mapstructure:"table_style"`
+}
+
+// CacheConfig holds settings of the server index, which caches server identifier lookups.
+type CacheConfig struct {
+	// TTL is how long a server list stays usable for lookups before servers are listed again.
+	TTL time.Duration `mapstructure:"ttl"`
+	// Persist keeps the server index on disk, so it is shared between commands.
+	Persist bool `mapstructure:"persist"`
+}
+
+// CompletionConfig holds settings of shell completion, which must never keep the shell waiting.
+type CompletionConfig struct {
+	// Timeout bounds the requests to the panel of one completion; candidates not fetched by then are left out.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Offline completes from cached candidates only, never sending a request to the panel.
+	Offline bool `mapstructure:"offline"`
+}
+
+// NotifyConfig holds the webhooks posted a summary of the bulk runs of every command.
+type NotifyConfig struct {
+	// Webhooks are URLs whose format, Discord, Slack or generic JSON, is detected from the URL.
+	Webhooks []string `mapstructure:"webhooks"`
+	// Discord and Slack are webhook URLs of these services whose format is not detected from the URL,
+	// e.g. behind a relay.
+	Discord string `mapstructure:"discord"`
+	Slack   string `mapstructure:"slack"`
+}
+
+// Profile returns the settings of the named profile. Profile names are not case-sensitive, as config keys.
+func (c *Config) Profile(name string) (ProfileConfig, bool) {
+	profile, ok := c.Profiles[strings.ToLower(name)]
+	return profile, ok
+}
+
+// ProfileConfig holds the settings of a profile, which take precedence over the ones of api.
+type ProfileConfig struct {
+	// ProxyURL replaces api.proxy_url, for panels only reachable through a bastion.
+	ProxyURL string `mapstructure:"proxy_url"`
+}
+
+// TokenConfig holds the tokens saved to the config file for one profile, or for one API base URL
+// if Profile is empty.
+type TokenConfig struct {
+	Profile string `mapstructure:"profile"`
+	BaseURL string `mapstructure:"base_url"`
+	Client  string `mapstructure:"client"`
+	Admin   string `mapstructure:"admin"`
+}
+
+// WingsNodeConfig holds direct Wings daemon access for a single node.
+type WingsNodeConfig struct {
+	URL   string `mapstructure:"url"`
+	Token string `mapstructure:"token"`
+}
+
+// ServerConfig holds local settings for a single server or for every server in a group.
+type ServerConfig struct {
+	// DependsOn lists servers that must be running before this server is started.
+	// It is only read from settings keyed by the server itself.
+	DependsOn []string `mapstructure:"depends_on"`
+	// Protected servers are skipped by destructive actions unless --include-protected is given.
+	Protected *bool `mapstructure:"protected"`
+	// BackupIgnore lists the default ignore patterns for backups of the server.
+	BackupIgnore []string `mapstructure:"backup_ignore"`
+}
+
 var (
 	globalConfig *Config
 	globalViper  *viper.Viper
 )
 
+// PathEnvVar names the config file to read when --config is not given, e.g. for plugins run by pelicanctl.
+const PathEnvVar = "PELICANCTL_CONFIG"
+
 // Load loads configuration from file, environment variables, and flags.
 func Load(configPath string) (*Config, error) {
 	v := viper.New()
+	if configPath == "" {
+		configPath = os.Getenv(PathEnvVar)
+	}
 
 	// Set defaults
 	v.SetDefault("api.base_url", "")
+	v.SetDefault("api.rate_limit", 0)
+	v.SetDefault("api.proxy_url", "")
+	v.SetDefault("api.tls.ca_cert", "")
+	v.SetDefault("api.tls.client_cert", "")
+	v.SetDefault("api.tls.client_key", "")
+	v.SetDefault("api.tls.insecure_skip_verify", false)
+	v.SetDefault("auth.backend", "keyring")
 	v.SetDefault("client.token", "")
 	v.SetDefault("admin.token", "")
+	v.SetDefault("output.identity", "")
+	v.SetDefault("output.table_style", "")
+	v.SetDefault("cache.ttl", DefaultCacheTTL)
+	v.SetDefault("cache.persist", true)
+	v.SetDefault("completion.timeout", DefaultCompletionTimeout)
+	v.SetDefault("completion.offline", false)
 
 	// Set config type
 	v.SetConfigType("yaml")
@@ -132,11 +275,34 @@ func Save() error {
 		globalViper.Set("api.base_url", globalConfig.API.BaseURL)
 		globalViper.Set("client.token", globalConfig.Client.Token)
 		globalViper.Set("admin.token", globalConfig.Admin.Token)
+		if globalConfig.Groups != nil {
+			globalViper.Set("groups", globalConfig.Groups)
+		}
+		if globalConfig.Tokens != nil {
+			globalViper.Set("tokens", tokenSettings(globalConfig.Tokens))
+		}
 	}
 
 	return globalViper.WriteConfig()
 }
 
+// tokenSettings converts the token entries to the keys they are written under, leaving out empty fields.
+func tokenSettings(tokens []TokenConfig) []map[string]string {
+	settings := make([]map[string]string, 0, len(tokens))
+	for _, entry := range tokens {
+		setting := map[string]string{}
+		for key, value := range map[string]string{
+			"profile": entry.Profile, "base_url": entry.BaseURL, "client": entry.Client, "admin": entry.Admin,
+		} {
+			if value != "" {
+				setting[key] = value
+			}
+		}
+		settings = append(settings, setting)
+	}
+	return settings
+}
+
 // GetConfigDir returns the platform-specific config directory.
 func getConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir()
@@ -146,6 +312,25 @@ func getConfigDir() (string, error) {
 	return filepath.Join(configDir, "pelicanctl"), nil
 }
 
+// Dir returns the directory of the config file in use, where local data like server templates is kept.
+// Without a loaded config it is the platform-specific config directory.
+func Dir() (string, error) {
+	if globalViper != nil {
+		if used := globalViper.ConfigFileUsed(); used != "" {
+			return filepath.Dir(used), nil
+		}
+	}
+	return getConfigDir()
+}
+
+// FileUsed returns the path of the config file that was read, empty if there was none.
+func FileUsed() string {
+	if globalViper == nil {
+		return ""
+	}
+	return globalViper.ConfigFileUsed()
+}
+
 // GetConfigPath returns the full path to the config file.
 func GetConfigPath() (string, error) {
 	configDir, err := getConfigDir()
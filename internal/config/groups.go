@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GroupMembers returns the servers of a group. Group names are matched case-insensitively,
+// as viper lowercases map keys. A nil config has no groups.
+func (c *Config) GroupMembers(name string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	members, ok := c.Groups[strings.ToLower(name)]
+	return members, ok
+}
+
+// SetGroup replaces the servers of a group, creating it if needed, and saves the config file.
+func SetGroup(name string, members []string) error {
+	if globalConfig == nil {
+		return errors.New("config not loaded")
+	}
+	if err := validateGroupName(name); err != nil {
+		return err
+	}
+	if globalConfig.Groups == nil {
+		globalConfig.Groups = map[string][]string{}
+	}
+	globalConfig.Groups[strings.ToLower(name)] = members
+	return Save()
+}
+
+// DeleteGroup removes a group and saves the config file. Settings keyed by the group under
+// servers are left alone.
+func DeleteGroup(name string) error {
+	if globalConfig == nil {
+		return errors.New("config not loaded")
+	}
+	if _, ok := globalConfig.GroupMembers(name); !ok {
+		return fmt.Errorf("group %q not configured", name)
+	}
+	delete(globalConfig.Groups, strings.ToLower(name))
+	return Save()
+}
+
+// validateGroupName rejects names that can't be config keys.
+func validateGroupName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return errors.New("group name cannot be empty")
+	case strings.ContainsAny(name, ". \t"):
+		return fmt.Errorf("invalid group name %q: it must not contain dots or whitespace", name)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// protectedActions are the actions that skip protected servers unless they are explicitly included.
+//
+//nolint:gochecknoglobals // Fixed list of destructive actions
+var protectedActions = []string{"stop", "restart", "kill", "reinstall", "suspend", "delete"}
+
+// ServerSettings returns the local settings of a server.
+//
+// identifiers are the ways the server may be referred to (UUID, ID). Settings keyed by a group the
+// server belongs to are applied first, in group name order, and settings keyed by the server itself
+// override them. A nil config has no settings.
+func (c *Config) ServerSettings(identifiers ...string) ServerConfig {
+	var settings ServerConfig
+	if c == nil {
+		return settings
+	}
+
+	for _, group := range c.groupsOf(identifiers) {
+		if groupCfg, ok := c.Servers[group]; ok {
+			settings.merge(groupCfg, false)
+		}
+	}
+	for _, identifier := range identifiers {
+		if serverCfg, ok := c.Servers[strings.ToLower(identifier)]; ok {
+			settings.merge(serverCfg, true)
+			break
+		}
+	}
+	return settings
+}
+
+// IsProtected reports whether a server is marked as protected.
+func (c *Config) IsProtected(identifiers ...string) bool {
+	protected := c.ServerSettings(identifiers...).Protected
+	return protected != nil && *protected
+}
+
+// HasProtected reports whether any server or group is marked as protected, so callers can skip looking up
+// the identifiers of servers when nothing is.
+func (c *Config) HasProtected() bool {
+	if c == nil {
+		return false
+	}
+	for _, serverCfg := range c.Servers {
+		if serverCfg.Protected != nil && *serverCfg.Protected {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitProtected splits servers into the ones an action may run on and the protected ones it must skip.
+// Actions that are not destructive never skip servers. identifiers returns every way a server may be
+// referred to (UUID, short identifier, ID, name), so it is found protected whichever of them it was given
+// by and whichever of them the config names it by.
+func (c *Config) SplitProtected(
+	action string,
+	servers []string,
+	identifiers func(server string) []string,
+) ([]string, []string) {
+	if !slices.Contains(protectedActions, action) || !c.HasProtected() {
+		return servers, nil
+	}
+
+	var allowed, skipped []string
+	for _, server := range servers {
+		if c.IsProtected(identifiers(server)...) {
+			skipped = append(skipped, server)
+		} else {
+			allowed = append(allowed, server)
+		}
+	}
+	return allowed, skipped
+}
+
+// groupsOf returns the names of the groups containing any of the identifiers, sorted.
+func (c *Config) groupsOf(identifiers []string) []string {
+	var groups []string
+	for name, members := range c.Groups {
+		for _, member := range members {
+			if slices.ContainsFunc(identifiers, func(id string) bool { return strings.EqualFold(id, member) }) {
+				groups = append(groups, name)
+				break
+			}
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// merge applies the fields set in other on top of s.
+func (s *ServerConfig) merge(other ServerConfig, direct bool) {
+	if direct && other.DependsOn != nil {
+		s.DependsOn = other.DependsOn
+	}
+	if other.Protected != nil {
+		s.Protected = other.Protected
+	}
+	if other.BackupIgnore != nil {
+		s.BackupIgnore = other.BackupIgnore
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+// testServer is one server known by every form it can be addressed by.
+var testServer = []string{"1a2b3c4d-0000-0000-0000-000000000000", "1a2b3c4d", "7", "smp-lobby"}
+
+// testIdentifiers returns every identifier of testServer when given any of them, like the lookup through
+// the server index, and only the identifier given for other servers.
+func testIdentifiers(server string) []string {
+	if slices.Contains(testServer, server) {
+		return append([]string{server}, testServer...)
+	}
+	return []string{server}
+}
+
+func TestSplitProtectedAddressingForms(t *testing.T) {
+	forms := map[string]string{
+		"uuid":     testServer[0],
+		"short id": testServer[1],
+		"id":       testServer[2],
+		"name":     testServer[3],
+	}
+	protected := true
+
+	for protectedBy, key := range forms {
+		for addressedBy, server := range forms {
+			t.Run("protected by "+protectedBy+", addressed by "+addressedBy, func(t *testing.T) {
+				cfg := &Config{Servers: map[string]ServerConfig{key: {Protected: &protected}}}
+				allowed, skipped := cfg.SplitProtected("delete", []string{server, "other"}, testIdentifiers)
+				if !slices.Equal(skipped, []string{server}) || !slices.Equal(allowed, []string{"other"}) {
+					t.Errorf("SplitProtected() = %v, %v; want [other], [%s]", allowed, skipped, server)
+				}
+			})
+		}
+	}
+}
+
+func TestSplitProtected(t *testing.T) {
+	protected, unprotected := true, false
+	tests := []struct {
+		name        string
+		cfg         *Config
+		action      string
+		servers     []string
+		wantSkipped []string
+	}{
+		{
+			name:    "nil config",
+			action:  "kill",
+			servers: []string{"smp-lobby"},
+		},
+		{
+			name:    "non-destructive action",
+			cfg:     &Config{Servers: map[string]ServerConfig{"smp-lobby": {Protected: &protected}}},
+			action:  "start",
+			servers: []string{"1a2b3c4d"},
+		},
+		{
+			name: "protected by group, addressed by name",
+			cfg: &Config{
+				Groups:  map[string][]string{"smp": {"1a2b3c4d-0000-0000-0000-000000000000"}},
+				Servers: map[string]ServerConfig{"smp": {Protected: &protected}},
+			},
+			action:      "reinstall",
+			servers:     []string{"smp-lobby"},
+			wantSkipped: []string{"smp-lobby"},
+		},
+		{
+			name: "server setting overrides its group",
+			cfg: &Config{
+				Groups: map[string][]string{"smp": {"7"}},
+				Servers: map[string]ServerConfig{
+					"smp":      {Protected: &protected},
+					"1a2b3c4d": {Protected: &unprotected},
+				},
+			},
+			action:  "kill",
+			servers: []string{"smp-lobby"},
+		},
+		{
+			name:        "unknown server keeps its identifier",
+			cfg:         &Config{Servers: map[string]ServerConfig{"survival": {Protected: &protected}}},
+			action:      "stop",
+			servers:     []string{"survival", "smp-lobby"},
+			wantSkipped: []string{"survival"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, skipped := tt.cfg.SplitProtected(tt.action, tt.servers, testIdentifiers)
+			if !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+			if len(allowed)+len(skipped) != len(tt.servers) {
+				t.Errorf("allowed = %v, skipped = %v; want every server in one of them", allowed, skipped)
+			}
+		})
+	}
+}
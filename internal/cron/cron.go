@@ -0,0 +1,199 @@
+// Package cron parses cron expressions and computes when they next fire.
+//
+// Expressions have the five fields of crontab(5): minute, hour, day of month, month and day of week.
+// Fields take *, numbers, ranges (1-5), lists (1,15), steps (*/15, 0-30/10) and, for months and weekdays,
+// three-letter names (jan, mon). As with cron, a day matches if either day field does when both are
+// restricted. The shorthands @yearly, @monthly, @weekly, @daily, @hourly and @every <duration> are accepted too.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far ahead Next looks for a match, e.g. for 29 February.
+const searchYears = 5
+
+// field is the range of values a cron field takes.
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+//nolint:gochecknoglobals // Fixed definitions of the five cron fields
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12,
+		names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+//nolint:gochecknoglobals // Fixed shorthands of cron expressions
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are set for day fields starting with *, which leave the day to the other field.
+	domAny, dowAny bool
+	// every is the interval of @every, which fires relative to the previous run rather than the clock.
+	every time.Duration
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid @every interval %q: %w", rest, err)
+		}
+		if every < time.Minute {
+			return Schedule{}, fmt.Errorf("@every interval %s is shorter than a minute", every)
+		}
+		return Schedule{every: every}, nil
+	}
+	if full, ok := shorthands[strings.ToLower(expr)]; ok {
+		expr = full
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return Schedule{}, fmt.Errorf("cron expression %q must have %d fields, got %d", expr, len(fields), len(parts))
+	}
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return Schedule{}, err
+		}
+		sets[i] = set
+	}
+	// Sunday is 0 and 7.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	return Schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// parseField parses one field into the set of values it matches.
+func parseField(part string, f field) (uint64, error) {
+	var set uint64
+	for item := range strings.SplitSeq(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q in %s field %q", stepPart, f.name, part)
+			}
+		}
+
+		low, high := f.min, f.max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = f.value(lowPart); err != nil {
+				return 0, fmt.Errorf("%w in %s field %q", err, f.name, part)
+			}
+			high = low
+			if isRange {
+				if high, err = f.value(highPart); err != nil {
+					return 0, fmt.Errorf("%w in %s field %q", err, f.name, part)
+				}
+			} else if hasStep {
+				// A single value with a step, e.g. 5/15, runs from it to the end of the range.
+				high = f.max
+			}
+			if high < low {
+				return 0, fmt.Errorf("range %q in %s field is backwards", rangePart, f.name)
+			}
+		}
+		for v := low; v <= high; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a number or name of a field.
+func (f field) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// ErrNoMatch is returned by Next for schedules that never fire, e.g. on 31 February.
+var ErrNoMatch = errors.New("schedule never fires")
+
+// Next returns the first time after t the schedule fires, in the location of t.
+func (s Schedule) Next(t time.Time) (time.Time, error) {
+	if s.every > 0 {
+		return t.Add(s.every), nil
+	}
+
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(searchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !has(s.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrNoMatch
+}
+
+// dayMatches applies the day fields like cron: if both are restricted, either may match.
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<v) != 0
+}
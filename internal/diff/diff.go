@@ -0,0 +1,211 @@
+// Package diff renders line-based unified diffs for previewing file changes, and lists the fields an
+// update or a manifest changes.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	// contextLines is the number of unchanged lines shown around each change.
+	contextLines = 3
+	// maxCells bounds the LCS table; larger changed regions are shown as a full replacement.
+	maxCells = 4 << 20
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// IsBinary reports whether content looks binary, i.e. contains a NUL byte.
+func IsBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// Unified returns a unified diff from oldContent to newContent, or "" if they are equal.
+func Unified(oldName, newName string, oldContent, newContent []byte) string {
+	if bytes.Equal(oldContent, newContent) {
+		return ""
+	}
+
+	ops := lineOps(splitLines(string(oldContent)), splitLines(string(newContent)))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(ops) {
+		writeHunk(&b, ops, h)
+	}
+	return b.String()
+}
+
+// splitLines splits content into lines, keeping a missing final newline visible in the diff.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	last := lines[len(lines)-1]
+	if !strings.HasSuffix(last, "\n") {
+		lines[len(lines)-1] = last + "\n\\ No newline at end of file\n"
+	}
+	return lines
+}
+
+// lineOps computes the edit script between two line slices.
+func lineOps(a, b []string) []op {
+	// Common prefix and suffix are kept out of the LCS table.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{opEqual, line})
+	}
+	ops = append(ops, middleOps(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{opEqual, line})
+	}
+	return ops
+}
+
+// middleOps diffs the changed region with a longest common subsequence table.
+func middleOps(a, b []string) []op {
+	var ops []op
+	if (len(a)+1)*(len(b)+1) > maxCells {
+		for _, line := range a {
+			ops = append(ops, op{opDelete, line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{opInsert, line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{opDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, op{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{opDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{opInsert, b[j]})
+	}
+	return ops
+}
+
+// hunk is a range of ops, [start, end), printed together.
+type hunk struct {
+	start, end int
+}
+
+// hunks groups changes whose context overlaps into hunks.
+func hunks(ops []op) []hunk {
+	var result []hunk
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+		start := max(i-contextLines, 0)
+		end := min(i+1+contextLines, len(ops))
+		if n := len(result); n > 0 && start <= result[n-1].end {
+			result[n-1].end = end
+			continue
+		}
+		result = append(result, hunk{start, end})
+	}
+	return result
+}
+
+func writeHunk(b *strings.Builder, ops []op, h hunk) {
+	// Line numbers of the hunk start in the old and new files.
+	oldLine, newLine := 1, 1
+	for _, o := range ops[:h.start] {
+		if o.kind != opInsert {
+			oldLine++
+		}
+		if o.kind != opDelete {
+			newLine++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, o := range ops[h.start:h.end] {
+		if o.kind != opInsert {
+			oldCount++
+		}
+		if o.kind != opDelete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+	for _, o := range ops[h.start:h.end] {
+		switch o.kind {
+		case opDelete:
+			b.WriteString("-")
+		case opInsert:
+			b.WriteString("+")
+		default:
+			b.WriteString(" ")
+		}
+		b.WriteString(o.line)
+	}
+}
+
+// hunkRange formats a hunk range; an empty range starts at the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
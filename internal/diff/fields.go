@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// FieldChange is a field whose value an update changes.
+type FieldChange struct {
+	// Field names the field, with nested fields as dotted paths such as limits.memory.
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Fields returns the fields of changes, sorted, whose value differs from the one in current. Objects are
+// compared field by field, only for the fields changes sets, so a change to one limit of a server lists
+// just that limit. Values are compared as JSON, so an int from a flag equals the same number decoded from
+// the panel. Both --diff of updates and the plans of apply compare through it.
+func Fields(current, changes map[string]any) []FieldChange {
+	normalized, ok := normalize(changes).(map[string]any)
+	if !ok {
+		normalized = changes
+	}
+	var result []FieldChange
+	collectFields(&result, "", current, normalized)
+	sort.Slice(result, func(i, j int) bool { return result[i].Field < result[j].Field })
+	return result
+}
+
+// collectFields appends the fields of changes that differ from current, named under prefix.
+func collectFields(result *[]FieldChange, prefix string, current, changes map[string]any) {
+	for key, val := range changes {
+		field := prefix + key
+		old := current[key]
+		if nested, ok := val.(map[string]any); ok {
+			if oldNested, ok := old.(map[string]any); ok {
+				collectFields(result, field+".", oldNested, nested)
+				continue
+			}
+		}
+		if !reflect.DeepEqual(normalize(old), val) {
+			*result = append(*result, FieldChange{Field: field, Old: old, New: val})
+		}
+	}
+}
+
+// normalize returns a value as it reads back from JSON.
+func normalize(val any) any {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return val
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return val
+	}
+	return out
+}
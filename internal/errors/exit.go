@@ -0,0 +1,169 @@
+//nolint:revive // Package name conflicts with stdlib but is intentional for domain-specific errors
+package errors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Exit codes by failure class, so scripts can branch on why a command failed.
+const (
+	// ExitGeneral is any failure without a more specific class.
+	ExitGeneral = 1
+	// ExitUnhealthy is a health check that found unhealthy servers, the CRITICAL status of Nagios checks.
+	ExitUnhealthy = 2
+	// ExitAuth is a missing, invalid or insufficient API token.
+	ExitAuth = 3
+	// ExitNotFound is a server or other resource that does not exist.
+	ExitNotFound = 4
+	// ExitValidation is invalid input: bad flags or arguments, or a request the panel rejected as invalid.
+	ExitValidation = 5
+	// ExitPartial is a bulk run in which some, but not all, operations failed.
+	ExitPartial = 6
+	// ExitServer is an error of the panel itself (HTTP 5xx).
+	ExitServer = 7
+	// ExitNetwork is a panel that could not be reached.
+	ExitNetwork = 8
+	// ExitTimeout is a command that ran out of the time given by --timeout, as with timeout(1).
+	ExitTimeout = 124
+	// ExitCanceled is a command interrupted with Ctrl-C, as with a shell killed by SIGINT.
+	ExitCanceled = 130
+)
+
+// exitClasses names the failure class of each exit code in the JSON error envelope.
+//
+//nolint:gochecknoglobals // Static mapping of exit codes to class names
+var exitClasses = map[int]string{
+	ExitGeneral:    "general",
+	ExitUnhealthy:  "unhealthy",
+	ExitAuth:       "auth",
+	ExitNotFound:   "not_found",
+	ExitValidation: "validation",
+	ExitPartial:    "partial_failure",
+	ExitServer:     "server",
+	ExitNetwork:    "network",
+	ExitTimeout:    "timeout",
+	ExitCanceled:   "canceled",
+}
+
+// ExitError is an error with the exit code it should end the program with.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// WithExitCode marks err to end the program with code.
+func WithExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// BulkFailure marks the error of a bulk run in which failed of total operations failed:
+// ExitPartial if some succeeded, ExitGeneral if none did.
+func BulkFailure(err error, failed, total int) error {
+	if failed < total {
+		return WithExitCode(ExitPartial, err)
+	}
+	return WithExitCode(ExitGeneral, err)
+}
+
+// handledError is an error reworded by HandleError that keeps the original for classification.
+type handledError struct {
+	message string
+	err     error
+}
+
+// Error implements the error interface.
+func (e *handledError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original error.
+func (e *handledError) Unwrap() error {
+	return e.err
+}
+
+// Handle returns err with the user-friendly message of HandleError, keeping err in the chain
+// so its exit code can still be determined.
+func Handle(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &handledError{message: HandleError(err), err: err}
+}
+
+// ExitCode returns the exit code for err: 0 for nil, the code of an ExitError in its chain,
+// or else a code derived from a canceled context, an API error or a network failure.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	// Checked before network errors, as a request that timed out is one too.
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, context.Canceled):
+		return ExitCanceled
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.IsUnauthorized():
+			return ExitAuth
+		case apiErr.IsNotFound():
+			return ExitNotFound
+		case apiErr.StatusCode == http.StatusBadRequest,
+			apiErr.StatusCode == http.StatusConflict,
+			apiErr.StatusCode == http.StatusUnprocessableEntity:
+			return ExitValidation
+		case apiErr.StatusCode >= http.StatusInternalServerError:
+			return ExitServer
+		}
+		return ExitGeneral
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitNetwork
+	}
+
+	return ExitGeneral
+}
+
+// Envelope is the machine-readable form of err written to stderr in JSON output:
+// its message, exit code and failure class, and the HTTP status of an API error.
+func Envelope(err error) map[string]any {
+	code := ExitCode(err)
+	envelope := map[string]any{
+		"status":    "error",
+		"message":   err.Error(),
+		"exit_code": code,
+		"class":     exitClasses[code],
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		envelope["http_status"] = apiErr.StatusCode
+	}
+	return envelope
+}
@@ -0,0 +1,293 @@
+// Package exporter scrapes the servers of the panel and exposes their state and resource usage
+// as Prometheus metrics.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// contentType is the content type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	// bytesPerMiB converts the MiB limits of the panel to bytes.
+	bytesPerMiB = 1024 * 1024
+	// millisecondsPerSecond converts the uptime reported by Wings to seconds.
+	millisecondsPerSecond = 1000
+)
+
+// powerStates are the states a server can be in, each exposed as a sample of pelican_server_state.
+//
+//nolint:gochecknoglobals // Fixed set of Wings power states
+var powerStates = []string{"offline", "starting", "running", "stopping"}
+
+// Exporter scrapes the panel and serves the metrics of the latest scrape.
+type Exporter struct {
+	client         *api.ClientAPI
+	admin          *api.ApplicationAPI
+	maxConcurrency int
+
+	mu      sync.RWMutex
+	metrics []byte
+}
+
+// New creates an exporter. Health metrics are only scraped if admin is not nil, since they need
+// the Application API. maxConcurrency bounds the requests made in parallel during a scrape.
+func New(client *api.ClientAPI, admin *api.ApplicationAPI, maxConcurrency int) *Exporter {
+	return &Exporter{client: client, admin: admin, maxConcurrency: maxConcurrency}
+}
+
+// serverScrape is what a scrape found out about one server.
+type serverScrape struct {
+	attrs     map[string]any
+	resources map[string]any
+	health    map[string]any
+}
+
+// Scrape collects the metrics of all servers and replaces the served metrics with them.
+// Failures of single servers are logged and counted; the returned error means the server list
+// itself could not be fetched, in which case only the exporter's own metrics are served.
+func (e *Exporter) Scrape(ctx context.Context) error {
+	start := time.Now()
+
+	servers, listErr := e.client.ListServers(ctx)
+	scrapes := make([]serverScrape, len(servers))
+	operations := make([]bulk.Operation, len(servers))
+	for i, server := range servers {
+		attrs := attributesOf(server)
+		scrapes[i].attrs = attrs
+		uuid := stringValue(attrs, "uuid")
+		operations[i] = bulk.Operation{
+			ID:   uuid,
+			Name: stringValue(attrs, "name"),
+			Exec: func(opCtx context.Context) error { return e.scrapeServer(opCtx, uuid, &scrapes[i]) },
+		}
+	}
+
+	failed := 0
+	// Scrapes are internal to the exporter, not results to stream.
+	executor := bulk.NewExecutor(e.maxConcurrency, true, false).WithRecord(nil)
+	for _, result := range executor.Execute(ctx, operations) {
+		if result.Error != nil {
+			failed++
+			output.LogWarn("failed to scrape server", "server", result.Operation.ID, "error", result.Error)
+		}
+	}
+
+	families := serverFamilies(scrapes, e.admin != nil)
+	families = append(families, exporterFamilies(listErr == nil, failed, time.Since(start), time.Now())...)
+
+	var buf bytes.Buffer
+	if err := writeFamilies(&buf, families); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.metrics = buf.Bytes()
+	e.mu.Unlock()
+
+	if listErr != nil {
+		return fmt.Errorf("failed to list servers: %w", listErr)
+	}
+	return nil
+}
+
+// scrapeServer fetches the resource usage and, with admin access, the health of a server.
+// Whatever was fetched before an error is kept.
+func (e *Exporter) scrapeServer(ctx context.Context, uuid string, scrape *serverScrape) error {
+	resources, err := e.client.GetServerResources(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+	scrape.resources = attributesOf(resources)
+
+	if e.admin == nil {
+		return nil
+	}
+	// The internal ID saves the Application API a server lookup.
+	identifier := uuid
+	if id, ok := numberValue(scrape.attrs, "internal_id"); ok {
+		identifier = strconv.Itoa(int(id))
+	}
+	health, err := e.admin.GetServerHealth(ctx, identifier, nil, nil)
+	if err != nil {
+		return fmt.Errorf("health: %w", err)
+	}
+	scrape.health = health
+	return nil
+}
+
+// Run scrapes every interval until ctx is done. A failed scrape is logged and retried on the next tick.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := e.Scrape(ctx); err != nil {
+			output.LogError("scrape failed", "error", err)
+		}
+	}
+}
+
+// ServeHTTP serves the metrics of the latest scrape.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	e.mu.RLock()
+	metrics := e.metrics
+	e.mu.RUnlock()
+
+	if metrics == nil {
+		http.Error(w, "no scrape has completed yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(metrics)
+}
+
+// serverFamilies builds the per-server metrics.
+func serverFamilies(scrapes []serverScrape, withHealth bool) []*family {
+	info := &family{name: "pelican_server_info", typ: typeGauge,
+		help: "Information about the server; always 1."}
+	state := &family{name: "pelican_server_state", typ: typeGauge,
+		help: "Power state of the server; 1 for the current state."}
+	suspended := &family{name: "pelican_server_suspended", typ: typeGauge,
+		help: "Whether the server is suspended."}
+	cpu := &family{name: "pelican_server_cpu_percent", typ: typeGauge,
+		help: "CPU usage of the server, in percent of one core."}
+	memory := &family{name: "pelican_server_memory_bytes", typ: typeGauge,
+		help: "Memory used by the server."}
+	memoryLimit := &family{name: "pelican_server_memory_limit_bytes", typ: typeGauge,
+		help: "Memory limit of the server; absent if unlimited."}
+	disk := &family{name: "pelican_server_disk_bytes", typ: typeGauge,
+		help: "Disk space used by the server."}
+	diskLimit := &family{name: "pelican_server_disk_limit_bytes", typ: typeGauge,
+		help: "Disk limit of the server; absent if unlimited."}
+	rx := &family{name: "pelican_server_network_receive_bytes_total", typ: typeCounter,
+		help: "Bytes received by the server since it started."}
+	tx := &family{name: "pelican_server_network_transmit_bytes_total", typ: typeCounter,
+		help: "Bytes sent by the server since it started."}
+	uptime := &family{name: "pelican_server_uptime_seconds", typ: typeGauge,
+		help: "Time since the server started."}
+	healthy := &family{name: "pelican_server_healthy", typ: typeGauge,
+		help: "Whether the container of the server is healthy."}
+	crashed := &family{name: "pelican_server_crashed", typ: typeGauge,
+		help: "Whether the server crashed recently."}
+
+	for _, scrape := range scrapes {
+		uuid := label{"uuid", stringValue(scrape.attrs, "uuid")}
+		name := label{"name", stringValue(scrape.attrs, "name")}
+
+		info.add(1, uuid, name,
+			label{"identifier", stringValue(scrape.attrs, "identifier")},
+			label{"node", stringValue(scrape.attrs, "node")})
+		if v, ok := scrape.attrs["is_suspended"].(bool); ok {
+			suspended.add(boolValue(v), uuid, name)
+		}
+		limits, _ := scrape.attrs["limits"].(map[string]any)
+		if v, ok := numberValue(limits, "memory"); ok && v > 0 {
+			memoryLimit.add(v*bytesPerMiB, uuid, name)
+		}
+		if v, ok := numberValue(limits, "disk"); ok && v > 0 {
+			diskLimit.add(v*bytesPerMiB, uuid, name)
+		}
+
+		if scrape.resources != nil {
+			current := api.ServerState(scrape.resources)
+			for _, s := range powerStates {
+				state.add(boolValue(s == current), uuid, name, label{"state", s})
+			}
+			usage, _ := scrape.resources["resources"].(map[string]any)
+			addNumber(cpu, usage, "cpu_absolute", 1, uuid, name)
+			addNumber(memory, usage, "memory_bytes", 1, uuid, name)
+			addNumber(disk, usage, "disk_bytes", 1, uuid, name)
+			addNumber(rx, usage, "network_rx_bytes", 1, uuid, name)
+			addNumber(tx, usage, "network_tx_bytes", 1, uuid, name)
+			addNumber(uptime, usage, "uptime", millisecondsPerSecond, uuid, name)
+		}
+
+		if withHealth && scrape.health != nil {
+			container, _ := scrape.health["container"].(map[string]any)
+			if v, ok := container["healthy"].(bool); ok {
+				healthy.add(boolValue(v), uuid, name)
+			}
+			if v, ok := scrape.health["crashed"].(bool); ok {
+				crashed.add(boolValue(v), uuid, name)
+			}
+		}
+	}
+
+	return []*family{
+		info, state, suspended, cpu, memory, memoryLimit, disk, diskLimit, rx, tx, uptime, healthy, crashed,
+	}
+}
+
+// exporterFamilies builds the metrics about the scrape itself.
+func exporterFamilies(success bool, failed int, duration time.Duration, now time.Time) []*family {
+	successFamily := &family{name: "pelican_exporter_scrape_success", typ: typeGauge,
+		help: "Whether the last scrape could list the servers."}
+	successFamily.add(boolValue(success))
+	errorsFamily := &family{name: "pelican_exporter_scrape_errors", typ: typeGauge,
+		help: "Servers that could not be scraped completely in the last scrape."}
+	errorsFamily.add(float64(failed))
+	durationFamily := &family{name: "pelican_exporter_scrape_duration_seconds", typ: typeGauge,
+		help: "Duration of the last scrape."}
+	durationFamily.add(duration.Seconds())
+	timestampFamily := &family{name: "pelican_exporter_last_scrape_timestamp_seconds", typ: typeGauge,
+		help: "Unix time of the last scrape."}
+	timestampFamily.add(float64(now.UnixNano()) / float64(time.Second))
+	return []*family{successFamily, errorsFamily, durationFamily, timestampFamily}
+}
+
+// addNumber adds the number at key of values, divided by divisor, if it is present.
+func addNumber(f *family, values map[string]any, key string, divisor float64, labels ...label) {
+	if v, ok := numberValue(values, key); ok {
+		f.add(v/divisor, labels...)
+	}
+}
+
+// attributesOf returns the attributes of a panel resource, or the value itself if it is not wrapped.
+func attributesOf(value map[string]any) map[string]any {
+	if attrs, ok := value["attributes"].(map[string]any); ok {
+		return attrs
+	}
+	return value
+}
+
+// stringValue returns the value at key as a string, or "" if it is missing.
+func stringValue(values map[string]any, key string) string {
+	switch v := values[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// numberValue returns the number at key, if there is one.
+func numberValue(values map[string]any, key string) (float64, bool) {
+	v, ok := values[key].(float64)
+	return v, ok
+}
+
+// boolValue maps a bool to a sample value.
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
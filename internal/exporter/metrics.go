@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Metric types of the Prometheus text exposition format.
+const (
+	typeGauge   = "gauge"
+	typeCounter = "counter"
+)
+
+// label is a label of a sample.
+type label struct {
+	name  string
+	value string
+}
+
+// sample is a value of a metric with its labels.
+type sample struct {
+	labels []label
+	value  float64
+}
+
+// family is a metric with its samples.
+type family struct {
+	name    string
+	help    string
+	typ     string
+	samples []sample
+}
+
+// add appends a sample to the family.
+func (f *family) add(value float64, labels ...label) {
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// writeFamilies writes metric families in the Prometheus text exposition format (version 0.0.4).
+// Families without samples are left out.
+func writeFamilies(w io.Writer, families []*family) error {
+	var b strings.Builder
+	for _, f := range families {
+		if len(f.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.name, f.typ)
+		for _, s := range f.samples {
+			b.WriteString(f.name)
+			if len(s.labels) > 0 {
+				b.WriteByte('{')
+				for i, l := range s.labels {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", l.name, escapeLabelValue(l.value))
+				}
+				b.WriteByte('}')
+			}
+			b.WriteByte(' ')
+			b.WriteString(formatValue(s.value))
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatValue formats a sample value, spelling out the special values as Prometheus expects them.
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// escapeHelp escapes a help text.
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// escapeLabelValue escapes a label value.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
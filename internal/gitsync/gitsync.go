@@ -0,0 +1,223 @@
+// Package gitsync mirrors selected server files into a git working copy.
+package gitsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PathSpec selects remote files to mirror.
+//
+// A spec ending in "/" selects a directory recursively, a spec whose last element contains
+// glob characters selects matching files in that directory, and anything else is a single file.
+type PathSpec struct {
+	Dir       string
+	Pattern   string
+	Recursive bool
+}
+
+// ParsePathSpec parses a path spec such as "config/", "plugins/*.yml" or "server.properties".
+func ParsePathSpec(spec string) (PathSpec, error) {
+	spec = strings.TrimPrefix(strings.TrimSpace(spec), "/")
+	if spec == "" {
+		return PathSpec{}, errors.New("empty path")
+	}
+
+	if strings.HasSuffix(spec, "/") {
+		return PathSpec{Dir: path.Clean(spec), Recursive: true}, nil
+	}
+
+	dir, pattern := path.Split(spec)
+	dir = path.Clean("/" + dir)[1:]
+	if strings.ContainsAny(dir, "*?[") {
+		return PathSpec{}, fmt.Errorf("glob is only supported in the last path element: %s", spec)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return PathSpec{}, fmt.Errorf("invalid pattern %s: %w", spec, err)
+	}
+	return PathSpec{Dir: dir, Pattern: pattern}, nil
+}
+
+// IsRoot reports whether the spec selects files in the root directory of the server: all of them
+// recursively, like "./", or some directly in it, like "*.yml" or "server.properties".
+func (p PathSpec) IsRoot() bool {
+	return p.Dir == "." || p.Dir == ""
+}
+
+// Matches reports whether a slash-separated path relative to the server root is selected by the spec.
+func (p PathSpec) Matches(filePath string) bool {
+	dir, name := path.Split(filePath)
+	dir = strings.TrimSuffix(dir, "/")
+
+	if p.Recursive {
+		return p.Dir == "." || filePath == p.Dir || strings.HasPrefix(filePath, p.Dir+"/")
+	}
+	if dir != p.Dir {
+		return false
+	}
+	matched, _ := path.Match(p.Pattern, name)
+	return matched
+}
+
+// Repo is a local git working copy, optionally tracking a remote.
+type Repo struct {
+	Dir    string
+	Remote string
+	Branch string
+}
+
+// Prepare makes sure the working copy exists and is up to date with its remote.
+// It clones the remote (or initializes an empty repository) when Dir is not a git repository yet.
+func (r *Repo) Prepare() error {
+	if _, err := os.Stat(filepath.Join(r.Dir, ".git")); err != nil {
+		if err := r.create(); err != nil {
+			return err
+		}
+	}
+
+	if r.Branch != "" {
+		current, _ := r.git("rev-parse", "--abbrev-ref", "HEAD")
+		if current != r.Branch {
+			if _, err := r.git("checkout", "-B", r.Branch); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A freshly cloned empty remote has no upstream yet, so there is nothing to pull
+	if r.Remote != "" {
+		if _, err := r.git("rev-parse", "--abbrev-ref", "@{upstream}"); err == nil {
+			if _, pullErr := r.git("pull", "--ff-only"); pullErr != nil {
+				return pullErr
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Repo) create() error {
+	if err := os.MkdirAll(r.Dir, 0750); err != nil {
+		return fmt.Errorf("failed to create repository directory: %w", err)
+	}
+	if r.Remote == "" {
+		_, err := r.git("init")
+		return err
+	}
+
+	args := []string{"clone"}
+	if r.Branch != "" && r.remoteHasBranch() {
+		args = append(args, "--branch", r.Branch)
+	}
+	args = append(args, r.Remote, ".")
+	_, err := r.git(args...)
+	return err
+}
+
+func (r *Repo) remoteHasBranch() bool {
+	cmd := exec.Command("git", "ls-remote", "--exit-code", "--heads", r.Remote, r.Branch)
+	return cmd.Run() == nil
+}
+
+// Prune removes files under root that are selected by specs but are not in keep.
+// This mirrors remote deletions into the working copy. keep holds slash-separated paths relative to root.
+func (r *Repo) Prune(root string, specs []PathSpec, keep map[string]bool) ([]string, error) {
+	base := filepath.Join(r.Dir, root)
+	var removed []string
+
+	err := filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(base, p)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+		if keep[rel] || !matchesAny(specs, rel) {
+			return nil
+		}
+		if removeErr := os.Remove(p); removeErr != nil {
+			return fmt.Errorf("failed to remove %s: %w", rel, removeErr)
+		}
+		removed = append(removed, rel)
+		return nil
+	})
+	return removed, err
+}
+
+func matchesAny(specs []PathSpec, filePath string) bool {
+	for _, spec := range specs {
+		if spec.Matches(filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit stages everything under root and commits it.
+// It returns the new commit hash, or an empty string when there was nothing to commit.
+func (r *Repo) Commit(root, message string) (string, error) {
+	if root == "" {
+		root = "."
+	}
+	if _, err := r.git("add", "-A", "--", root); err != nil {
+		return "", err
+	}
+
+	status, err := r.git("status", "--porcelain", "--", root)
+	if err != nil {
+		return "", err
+	}
+	if status == "" {
+		return "", nil
+	}
+
+	if _, err := r.git("commit", "-m", message, "--", root); err != nil {
+		return "", err
+	}
+	return r.git("rev-parse", "--short", "HEAD")
+}
+
+// Push pushes the current branch to the remote and sets it as upstream.
+func (r *Repo) Push() error {
+	if r.Remote == "" {
+		return nil
+	}
+	_, err := r.git("push", "--set-upstream", "origin", "HEAD")
+	return err
+}
+
+// git runs a git command in the working copy and returns its trimmed stdout.
+func (r *Repo) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
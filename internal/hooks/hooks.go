@@ -0,0 +1,100 @@
+// Package hooks runs the local scripts the hooks section of the config file sets to run before and
+// after commands, for site-specific automation such as pausing monitoring before restarts.
+//
+// Hooks are named after their command: pre_<command> runs before it and post_<command> after it, where
+// <command> is its path below client or admin with underscores for spaces and dashes, e.g. pre_power_stop
+// for pelicanctl client power stop. A hook is a shell command, which gets the command as JSON on stdin:
+//
+//	{
+//	  "hook": "post_power_stop",
+//	  "command": "pelicanctl client power stop",
+//	  "args": ["lobby"],
+//	  "flags": {"wait": "true"},
+//	  "panel": "https://panel.example.com",
+//	  "status": "failed",
+//	  "exit_code": 4,
+//	  "error": "Resource not found: ..."
+//	}
+//
+// Only post hooks get status, exit_code and error, the last only for failed commands.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// When a hook runs.
+const (
+	Pre  = "pre"
+	Post = "post"
+)
+
+// Statuses of the commands post hooks run for.
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// EnvVar passes hooks their own name, for scripts serving several hooks.
+const EnvVar = "PELICANCTL_HOOK"
+
+// Event describes the command a hook runs for.
+type Event struct {
+	Hook    string            `json:"hook"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Flags   map[string]string `json:"flags"`
+	Panel   string            `json:"panel,omitempty"`
+	// Status, ExitCode and Err are the outcome of the command, for post hooks.
+	Status   string `json:"status,omitempty"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Err      string `json:"error,omitempty"`
+}
+
+// Name returns the name of the hook run when (Pre or Post) for a command path like
+// "pelicanctl client power stop", empty for the root and API commands themselves.
+func Name(when, commandPath string) string {
+	words := strings.Fields(commandPath)
+	if len(words) > 0 {
+		words = words[1:]
+	}
+	if len(words) > 0 && (words[0] == "client" || words[0] == "admin") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	return when + "_" + strings.ReplaceAll(strings.Join(words, "_"), "-", "_")
+}
+
+// Run runs the shell command of a hook with the event as JSON on stdin. Its output goes to stderr, so
+// stdout keeps to the output of pelicanctl. A hook that exits with an error fails.
+func Run(ctx context.Context, command string, event Event) error {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s hook input: %w", event.Hook, err)
+	}
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	hook := exec.CommandContext(ctx, shell, flag, command) //nolint:gosec // Hooks are commands the user configured
+	hook.Stdin = bytes.NewReader(input)
+	hook.Stdout = os.Stderr
+	hook.Stderr = os.Stderr
+	hook.Env = append(os.Environ(), EnvVar+"="+event.Hook, output.RequestIDEnvVar+"="+output.RequestID())
+	if err = hook.Run(); err != nil {
+		return fmt.Errorf("%s hook failed: %w", event.Hook, err)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+// Package httpcache caches the GET responses of the panel that carry an ETag and revalidates them with
+// If-None-Match, so a list that hasn't changed comes back as an empty 304 instead of the whole payload.
+//
+// Every command runs in a process of its own, so responses are kept on disk (with cache.persist), one
+// file per URL and token in the http directory of the pelicanctl user cache directory. Responses of one
+// token are never served to another.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// maxEntrySize bounds the bodies that are cached, so file contents read through the panel aren't.
+const maxEntrySize = 4 << 20
+
+// entry is a cached response.
+type entry struct {
+	URL    string      `json:"url"`
+	ETag   string      `json:"etag"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+var (
+	//nolint:gochecknoglobals // Responses are shared by every client of the process
+	memory = map[string]*entry{}
+	//nolint:gochecknoglobals // Global mutex needed to protect memory
+	memoryMutex sync.Mutex
+)
+
+// Transport is an http.RoundTripper that revalidates cached GET responses with their ETag and answers
+// a 304 Not Modified from the cache. Other requests and responses pass through unchanged.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.Base.RoundTrip(req) //nolint:wrapcheck // Transport must pass errors through unchanged
+	}
+
+	key := cacheKey(req)
+	cached := lookup(key)
+	sent := req
+	if cached != nil {
+		sent = req.Clone(req.Context())
+		sent.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := t.Base.RoundTrip(sent)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Transport must pass errors through unchanged
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		discard(resp)
+		output.LogDebug("served response from cache", "url", req.URL.Redacted())
+		return cachedResponse(req, cached), nil
+	case resp.StatusCode == http.StatusOK && cacheable(resp):
+		return store(req, key, resp)
+	default:
+		return resp, nil
+	}
+}
+
+// cacheable reports whether a response may be cached: it has an ETag and the panel doesn't forbid it.
+func cacheable(resp *http.Response) bool {
+	return resp.Header.Get("ETag") != "" &&
+		!strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") &&
+		resp.ContentLength <= maxEntrySize
+}
+
+// store reads the body of a response to cache it, and returns the response with the body read again.
+func store(req *http.Request, key string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEntrySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err //nolint:wrapcheck // Transport must pass errors through unchanged
+	}
+	if len(body) > maxEntrySize {
+		// Too large to cache: hand on what was read, followed by the rest.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	save(key, &entry{
+		URL:    req.URL.Redacted(),
+		ETag:   resp.Header.Get("ETag"),
+		Header: resp.Header.Clone(),
+		Body:   body,
+	})
+	return resp, nil
+}
+
+// cachedResponse answers a request from the cache.
+func cachedResponse(req *http.Request, cached *entry) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cached.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a response by URL and by the token and content type asked for.
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\n" + req.Header.Get("Authorization") + "\n" +
+		req.Header.Get("Accept")))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookup returns the cached response of key, reading it from disk if this process hasn't yet.
+func lookup(key string) *entry {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	if cached, ok := memory[key]; ok {
+		return cached
+	}
+	if !persistent() {
+		return nil
+	}
+	cached := readEntry(key)
+	if cached != nil {
+		memory[key] = cached
+	}
+	return cached
+}
+
+// save caches a response, on disk too with cache.persist.
+func save(key string, cached *entry) {
+	memoryMutex.Lock()
+	memory[key] = cached
+	memoryMutex.Unlock()
+
+	if persistent() {
+		// Requests work without the cache, so a failure to write it is only logged.
+		if err := writeEntry(key, cached); err != nil {
+			output.LogDebug("failed to cache response", "url", cached.URL, "error", err)
+		}
+	}
+}
+
+// persistent reports whether responses are kept on disk, from cache.persist.
+func persistent() bool {
+	cfg := config.Get()
+	return cfg == nil || cfg.Cache.Persist
+}
+
+// Dir returns the directory of the cached responses.
+func Dir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "pelicanctl", "http"), nil
+}
+
+// Clear forgets every cached response, in memory and on disk.
+func Clear() error {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	memory = map[string]*entry{}
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err = os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear response cache: %w", err)
+	}
+	return nil
+}
+
+// readEntry loads a cached response from disk. A missing or unreadable one is nil.
+func readEntry(key string) *entry {
+	dir, err := Dir()
+	if err != nil {
+		return nil
+	}
+	encoded, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+	var cached entry
+	if err = json.Unmarshal(encoded, &cached); err != nil || cached.ETag == "" {
+		return nil
+	}
+	return &cached
+}
+
+// writeEntry writes a cached response atomically. Responses hold panel data, so only the user may read them.
+func writeEntry(key string, cached *entry) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	encoded, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, ".response-*")
+	if err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(encoded); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	if err = os.Rename(file.Name(), filepath.Join(dir, key+".json")); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+// discard drains and closes the body of a response, so its connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxEntrySize))
+	resp.Body.Close()
+}
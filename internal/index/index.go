@@ -0,0 +1,380 @@
+// Package index keeps a persisted index of the servers on the panel.
+//
+// Every server list fetched from the Client or Application API updates the index, and name
+// resolution, completion, and selectors read from it instead of re-listing servers themselves.
+// The index is a cache: entries older than the cache.ttl setting are ignored until the next list
+// refreshes them. With cache.persist off it lives in memory only, for the duration of one command.
+package index
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+)
+
+// Sources the index is updated from. The Client API only lists servers the user can access,
+// so entries remember which API listed them.
+const (
+	SourceClient = "client"
+	SourceAdmin  = "admin"
+)
+
+// shortIdentifierLength is the length of the short server identifier the panel shows and accepts.
+const shortIdentifierLength = 8
+
+// ErrNotIndexed is returned when an identifier is not in a fresh index; callers refresh it and retry.
+var ErrNotIndexed = errors.New("server not in index")
+
+// AmbiguousError is returned when a name matches more than one server.
+type AmbiguousError struct {
+	Name    string
+	Matches []Entry
+}
+
+// Error implements the error interface.
+func (e *AmbiguousError) Error() string {
+	candidates := make([]string, len(e.Matches))
+	for i, entry := range e.Matches {
+		candidates[i] = fmt.Sprintf("%s (%s)", entry.Name, entry.UUID)
+	}
+	return fmt.Sprintf("server name %q is ambiguous: it matches %s", e.Name, strings.Join(candidates, ", "))
+}
+
+// Entry describes one server. Fields the listing API doesn't provide are empty.
+type Entry struct {
+	UUID       string `json:"uuid"`
+	Identifier string `json:"identifier,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	NodeName   string `json:"node_name,omitempty"`
+	// Node, Egg and Owner are IDs, known once the Application API listed the server.
+	Node  string `json:"node,omitempty"`
+	Egg   string `json:"egg,omitempty"`
+	Owner string `json:"owner,omitempty"`
+	// Sources lists the APIs that listed the server.
+	Sources []string `json:"sources"`
+}
+
+// data is the persisted form of the index.
+type data struct {
+	// Panel is the base URL the index was built from; an index for another panel is discarded.
+	Panel     string               `json:"panel"`
+	UpdatedAt map[string]time.Time `json:"updated_at"`
+	Servers   map[string]*Entry    `json:"servers"`
+}
+
+var (
+	//nolint:gochecknoglobals // Index is loaded once per process and shared by every caller
+	current *data
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the shared index
+	indexMutex sync.Mutex
+)
+
+// Update replaces the servers listed by source with a full server list from that API.
+// The index is persisted on a best-effort basis; a failure to write it never fails a command.
+func Update(source string, servers []map[string]any) {
+	indexMutex.Lock()
+	defer indexMutex.Unlock()
+
+	idx := load()
+	listed := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		entry := entryFromServer(source, server)
+		if entry.UUID == "" {
+			continue
+		}
+		listed[entry.UUID] = true
+		idx.merge(source, entry)
+	}
+
+	// Forget servers this source no longer lists.
+	for uuid, entry := range idx.Servers {
+		if listed[uuid] {
+			continue
+		}
+		entry.Sources = slices.DeleteFunc(entry.Sources, func(s string) bool { return s == source })
+		if len(entry.Sources) == 0 {
+			delete(idx.Servers, uuid)
+		}
+	}
+
+	idx.UpdatedAt[source] = time.Now()
+	if persistent() {
+		_ = save(idx)
+	}
+}
+
+// Servers returns the servers listed by source, sorted by name, and whether that list is fresh.
+func Servers(source string) ([]Entry, bool) {
+	indexMutex.Lock()
+	defer indexMutex.Unlock()
+
+	idx := load()
+	entries := idx.entries(source)
+	return entries, idx.fresh(source)
+}
+
+// Lookup resolves a server UUID, short identifier, integer ID or name to its entry.
+// Names match case-insensitively but must be unique. Returns ErrNotIndexed when the identifier
+// is unknown or the servers of source have not been listed recently.
+func Lookup(source, identifier string) (Entry, error) {
+	indexMutex.Lock()
+	defer indexMutex.Unlock()
+
+	idx := load()
+	if !idx.fresh(source) {
+		return Entry{}, ErrNotIndexed
+	}
+
+	var byName []Entry
+	for _, entry := range idx.entries(source) {
+		if entry.UUID == identifier || entry.Identifier == identifier || entry.ID == identifier {
+			return entry, nil
+		}
+		if strings.EqualFold(entry.Name, identifier) {
+			byName = append(byName, entry)
+		}
+	}
+
+	switch len(byName) {
+	case 0:
+		return Entry{}, ErrNotIndexed
+	case 1:
+		return byName[0], nil
+	default:
+		return Entry{}, &AmbiguousError{Name: identifier, Matches: byName}
+	}
+}
+
+// UpdatedAt returns when the servers of source were last listed, zero if never.
+func UpdatedAt(source string) time.Time {
+	indexMutex.Lock()
+	defer indexMutex.Unlock()
+	return load().UpdatedAt[source]
+}
+
+// Clear forgets every indexed server, in memory and on disk.
+func Clear() error {
+	indexMutex.Lock()
+	defer indexMutex.Unlock()
+
+	current = nil
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to clear server index: %w", err)
+	}
+	return nil
+}
+
+// Search returns the servers of source whose name contains the letters of query in order,
+// best matches first. It powers "did you mean" suggestions and interactive pickers.
+func Search(source, query string) []Entry {
+	indexMutex.Lock()
+	defer indexMutex.Unlock()
+
+	type match struct {
+		entry Entry
+		score int
+	}
+	var matches []match
+	for _, entry := range load().entries(source) {
+		if score, ok := fuzzyScore(entry.Name, query); ok {
+			matches = append(matches, match{entry: entry, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+
+	results := make([]Entry, len(matches))
+	for i, m := range matches {
+		results[i] = m.entry
+	}
+	return results
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of name. Lower scores are better:
+// a substring match beats a scattered one, and an earlier match beats a later one.
+func fuzzyScore(name, query string) (int, bool) {
+	name, query = strings.ToLower(name), strings.ToLower(query)
+	if query == "" || name == "" {
+		return 0, false
+	}
+	if pos := strings.Index(name, query); pos >= 0 {
+		return pos, true
+	}
+
+	want := []rune(query)
+	matched, gaps, last := 0, 0, -1
+	for i, r := range []rune(name) {
+		if matched == len(want) {
+			break
+		}
+		if r != want[matched] {
+			continue
+		}
+		if last >= 0 {
+			gaps += i - last - 1
+		}
+		last = i
+		matched++
+	}
+	if matched < len(want) {
+		return 0, false
+	}
+	return len(name) + gaps, true
+}
+
+// merge adds or updates an entry, keeping fields only the other API provides.
+func (d *data) merge(source string, entry Entry) {
+	existing, ok := d.Servers[entry.UUID]
+	if !ok {
+		entry.Sources = []string{source}
+		d.Servers[entry.UUID] = &entry
+		return
+	}
+
+	for _, field := range []struct{ dst, src *string }{
+		{&existing.Identifier, &entry.Identifier},
+		{&existing.ID, &entry.ID},
+		{&existing.Name, &entry.Name},
+		{&existing.Node, &entry.Node},
+		{&existing.NodeName, &entry.NodeName},
+		{&existing.Egg, &entry.Egg},
+		{&existing.Owner, &entry.Owner},
+	} {
+		if *field.src != "" {
+			*field.dst = *field.src
+		}
+	}
+	if !slices.Contains(existing.Sources, source) {
+		existing.Sources = append(existing.Sources, source)
+	}
+}
+
+// entries returns copies of the entries listed by source, sorted by name.
+func (d *data) entries(source string) []Entry {
+	entries := make([]Entry, 0, len(d.Servers))
+	for _, entry := range d.Servers {
+		if slices.Contains(entry.Sources, source) {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].UUID < entries[j].UUID
+	})
+	return entries
+}
+
+func (d *data) fresh(source string) bool {
+	updated, ok := d.UpdatedAt[source]
+	return ok && time.Since(updated) < ttl()
+}
+
+// load returns the in-memory index, reading it from disk on first use. Callers hold indexMutex.
+func load() *data {
+	panel := currentPanel()
+	if current != nil && current.Panel == panel {
+		return current
+	}
+
+	var stored *data
+	var err error
+	if persistent() {
+		stored, err = read()
+	}
+	if err != nil || stored == nil || stored.Panel != panel {
+		stored = &data{Panel: panel}
+	}
+	if stored.UpdatedAt == nil {
+		stored.UpdatedAt = map[string]time.Time{}
+	}
+	if stored.Servers == nil {
+		stored.Servers = map[string]*Entry{}
+	}
+	current = stored
+	return current
+}
+
+// ttl returns how long a server list stays usable, from cache.ttl.
+func ttl() time.Duration {
+	if cfg := config.Get(); cfg != nil && cfg.Cache.TTL > 0 {
+		return cfg.Cache.TTL
+	}
+	return config.DefaultCacheTTL
+}
+
+// persistent reports whether the index is kept on disk, from cache.persist.
+func persistent() bool {
+	cfg := config.Get()
+	return cfg == nil || cfg.Cache.Persist
+}
+
+func currentPanel() string {
+	if cfg := config.Get(); cfg != nil {
+		return strings.TrimSuffix(cfg.API.BaseURL, "/")
+	}
+	return ""
+}
+
+// entryFromServer builds an entry from a server as returned by either API.
+func entryFromServer(source string, server map[string]any) Entry {
+	entry := Entry{
+		UUID:       field(server, "uuid"),
+		Identifier: field(server, "identifier"),
+		ID:         field(server, "id"),
+		Name:       field(server, "name"),
+	}
+	if entry.ID == "" {
+		entry.ID = field(server, "internal_id")
+	}
+	if entry.Identifier == "" && len(entry.UUID) >= shortIdentifierLength {
+		entry.Identifier = entry.UUID[:shortIdentifierLength]
+	}
+
+	// The Application API references the node, egg and owner by ID; the Client API names the node.
+	if source == SourceAdmin {
+		entry.Node = field(server, "node")
+		entry.Egg = field(server, "egg")
+		entry.Owner = field(server, "user")
+	} else {
+		entry.NodeName = field(server, "node")
+	}
+	return entry
+}
+
+// field reads a scalar field from the root of a server or from its attributes as a string.
+func field(server map[string]any, key string) string {
+	val, ok := server[key]
+	if !ok {
+		if attrs, isMap := server["attributes"].(map[string]any); isMap {
+			val = attrs[key]
+		}
+	}
+
+	switch v := val.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return ""
+	}
+}
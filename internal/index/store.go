@@ -0,0 +1,72 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Path returns the file that stores the index.
+func Path() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "pelicanctl", "servers.json"), nil
+}
+
+// save writes the index atomically.
+func save(idx *data) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	encoded, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode server index: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".servers-*")
+	if err != nil {
+		return fmt.Errorf("failed to write server index: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(encoded); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write server index: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write server index: %w", err)
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// read loads the stored index. A missing index is nil.
+func read() (*data, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil //nolint:nilnil // no index yet
+		}
+		return nil, fmt.Errorf("failed to read server index: %w", err)
+	}
+
+	var idx data
+	if err := json.Unmarshal(encoded, &idx); err != nil {
+		// A corrupt index is rebuilt by the next server list.
+		return nil, nil //nolint:nilnil,nilerr // discard the unreadable index
+	}
+	return &idx, nil
+}
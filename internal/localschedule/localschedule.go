@@ -0,0 +1,241 @@
+// Package localschedule runs pelicanctl commands on cron schedules from the machine pelicanctl runs on,
+// for `pelicanctl schedule-local`, as an alternative to the schedules of the panel.
+//
+// Jobs are read from a YAML file:
+//
+//	jobs:
+//	  - name: nightly-backups
+//	    schedule: "0 4 * * *"
+//	    args: [client, backup, create, --all]
+//	  - name: weekly-restart
+//	    schedule: "0 6 * * mon"
+//	    args: [client, power, restart, --match, "smp-*", --pre-command, "say Restarting", --pre-delay, 30s]
+//	    timeout: 15m
+//
+// Every run of a job is a new pelicanctl process with the args of the job, so jobs can use any command.
+package localschedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"go.lostcrafters.com/pelicanctl/internal/cron"
+)
+
+// File is a jobs file.
+type File struct {
+	Jobs []Job `yaml:"jobs"`
+}
+
+// Job is a pelicanctl command run on a schedule.
+type Job struct {
+	Name string `yaml:"name"`
+	// Schedule is a cron expression, see package cron.
+	Schedule string `yaml:"schedule"`
+	// Args are the arguments pelicanctl is run with, e.g. [client, power, restart, --all].
+	Args []string `yaml:"args"`
+	// Timeout ends a run that takes longer, e.g. "15m". Empty means no limit.
+	Timeout string `yaml:"timeout,omitempty"`
+
+	schedule cron.Schedule
+	timeout  time.Duration
+}
+
+// Load reads and validates a jobs file.
+func Load(path string) ([]Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jobs file: %w", err)
+	}
+
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse jobs file: %w", err)
+	}
+	if len(file.Jobs) == 0 {
+		return nil, errors.New("jobs file has no jobs")
+	}
+
+	seen := make(map[string]bool, len(file.Jobs))
+	for i := range file.Jobs {
+		job := &file.Jobs[i]
+		if job.Name == "" {
+			return nil, fmt.Errorf("job %d has no name", i+1)
+		}
+		if seen[job.Name] {
+			return nil, fmt.Errorf("job %q is defined twice", job.Name)
+		}
+		seen[job.Name] = true
+		if len(job.Args) == 0 {
+			return nil, fmt.Errorf("job %q has no args", job.Name)
+		}
+		if job.schedule, err = cron.Parse(job.Schedule); err != nil {
+			return nil, fmt.Errorf("job %q: %w", job.Name, err)
+		}
+		if job.Timeout != "" {
+			if job.timeout, err = time.ParseDuration(job.Timeout); err != nil || job.timeout <= 0 {
+				return nil, fmt.Errorf("job %q: invalid timeout %q", job.Name, job.Timeout)
+			}
+		}
+	}
+	return file.Jobs, nil
+}
+
+// Next returns when the job next runs after t.
+func (j Job) Next(t time.Time) (time.Time, error) {
+	return j.schedule.Next(t)
+}
+
+// Run runs the job once, as a pelicanctl process whose output lines are written to stdout and stderr
+// prefixed with the job name. globalArgs go before the args of the job, e.g. to pass on --config.
+// It returns the error of the process, e.g. its exit status.
+func (j Job) Run(ctx context.Context, globalArgs []string, stdout, stderr io.Writer) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the pelicanctl executable: %w", err)
+	}
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+
+	args := append(slices.Clone(globalArgs), j.Args...)
+	cmd := exec.CommandContext(ctx, executable, args...)
+	// Interrupt the run like Ctrl-C would, so it cancels its bulk operations cleanly.
+	cmd.Cancel = func() error { return interrupt(cmd.Process) }
+	cmd.WaitDelay = killDelay
+	outWriter := &prefixWriter{w: stdout, prefix: "[" + j.Name + "] "}
+	errWriter := &prefixWriter{w: stderr, prefix: "[" + j.Name + "] "}
+	cmd.Stdout, cmd.Stderr = outWriter, errWriter
+	err = cmd.Run()
+	outWriter.flush()
+	errWriter.flush()
+	if err != nil && ctx.Err() != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", j.timeout, err)
+	}
+	return err //nolint:wrapcheck // The exit status of the run is the error
+}
+
+// killDelay is how long an interrupted run gets to end before it is killed.
+const killDelay = 10 * time.Second
+
+// Run runs the jobs on their schedules until ctx is done, calling run for every due job. A job is not started
+// again while its previous run is still going; skipped is called instead. Run waits for running jobs to end.
+func Run(ctx context.Context, jobs []Job, run func(context.Context, Job), skipped func(Job)) error {
+	now := time.Now()
+	next := make([]time.Time, len(jobs))
+	for i, job := range jobs {
+		at, err := job.Next(now)
+		if err != nil {
+			return fmt.Errorf("job %q: %w", job.Name, err)
+		}
+		next[i] = at
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	var mu sync.Mutex
+	running := make(map[string]bool, len(jobs))
+
+	for {
+		earliest := next[0]
+		for _, at := range next[1:] {
+			if at.Before(earliest) {
+				earliest = at
+			}
+		}
+		timer := time.NewTimer(time.Until(earliest))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for i, job := range jobs {
+			if next[i].After(now) {
+				continue
+			}
+			at, err := job.Next(now)
+			if err != nil {
+				return fmt.Errorf("job %q: %w", job.Name, err)
+			}
+			next[i] = at
+
+			mu.Lock()
+			busy := running[job.Name]
+			running[job.Name] = true
+			mu.Unlock()
+			if busy {
+				skipped(job)
+				continue
+			}
+			wg.Go(func() {
+				defer func() {
+					mu.Lock()
+					delete(running, job.Name)
+					mu.Unlock()
+				}()
+				run(ctx, job)
+			})
+		}
+	}
+}
+
+// prefixWriter writes every line written to it prefixed, keeping an incomplete last line until the
+// rest of it is written or flush is called.
+type prefixWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	prefix  string
+	partial []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.partial = append(p.partial, data...)
+	for {
+		line, rest, found := bytes.Cut(p.partial, []byte{'\n'})
+		if !found {
+			break
+		}
+		if _, err := fmt.Fprintf(p.w, "%s%s\n", p.prefix, line); err != nil {
+			return 0, err
+		}
+		p.partial = rest
+	}
+	return len(data), nil
+}
+
+// flush writes the incomplete last line, if any.
+func (p *prefixWriter) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.partial) > 0 {
+		_, _ = fmt.Fprintf(p.w, "%s%s\n", p.prefix, p.partial)
+		p.partial = nil
+	}
+}
+
+// interrupt asks a run to end, or kills it where processes cannot be interrupted (Windows).
+func interrupt(process *os.Process) error {
+	if err := process.Signal(os.Interrupt); err != nil {
+		return process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package manifest reads the declarative manifests of `pelicanctl apply` and diffs their resources
+// against the panel.
+//
+// A manifest lists users, nodes and servers with the fields the Application API takes to create them,
+// so a resource of a manifest reads like the --data of the matching create command:
+//
+//	users:
+//	  - username: alice
+//	    email: alice@example.com
+//	servers:
+//	  - name: smp-lobby
+//	    user: alice
+//	    egg: 3
+//	    limits: {memory: 4096, disk: 10240, cpu: 200, swap: 0, io: 500}
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"go.lostcrafters.com/pelicanctl/internal/diff"
+)
+
+// Resource kinds, in the order they are applied: servers reference users and nodes.
+const (
+	KindUser   = "user"
+	KindNode   = "node"
+	KindServer = "server"
+)
+
+// Manifest is the desired state of the panel.
+type Manifest struct {
+	Users   []map[string]any `json:"users"   yaml:"users"`
+	Nodes   []map[string]any `json:"nodes"   yaml:"nodes"`
+	Servers []map[string]any `json:"servers" yaml:"servers"`
+}
+
+// Load reads a manifest from a YAML or JSON file, or from stdin if path is "-".
+func Load(path string) (*Manifest, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	// Decode YAML into the types JSON would give, so resources compare against API responses.
+	if err := normalize(&m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Key returns the field a resource of kind is matched with on the panel: users by username, nodes by name,
+// servers by external_id if they have one and else by name.
+func Key(kind string, resource map[string]any) string {
+	switch kind {
+	case KindUser:
+		return "username"
+	case KindServer:
+		if id, _ := resource["external_id"].(string); id != "" {
+			return "external_id"
+		}
+	}
+	return "name"
+}
+
+// Name returns the value of the key of a resource, which names it in plans and errors.
+func Name(kind string, resource map[string]any) string {
+	name, _ := resource[Key(kind, resource)].(string)
+	return name
+}
+
+// Changes returns the fields of want, sorted, whose value differs from the one in have.
+// Nested objects only compare the fields want sets, so a manifest may give part of the limits of a server.
+func Changes(want, have map[string]any, fields []string) []string {
+	wanted := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if value, ok := want[field]; ok {
+			wanted[field] = value
+		}
+	}
+
+	var changed []string
+	for _, change := range diff.Fields(have, wanted) {
+		// A change to a nested field, such as limits.memory, changes the top-level field.
+		field, _, _ := strings.Cut(change.Field, ".")
+		if !slices.Contains(changed, field) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// validate checks that every resource has a unique key.
+func (m *Manifest) validate() error {
+	for _, group := range []struct {
+		kind      string
+		resources []map[string]any
+	}{{KindUser, m.Users}, {KindNode, m.Nodes}, {KindServer, m.Servers}} {
+		seen := make(map[string]bool, len(group.resources))
+		for i, resource := range group.resources {
+			name := Name(group.kind, resource)
+			if name == "" {
+				return fmt.Errorf("%s #%d of the manifest has no %s", group.kind, i+1, Key(group.kind, resource))
+			}
+			if seen[strings.ToLower(name)] {
+				return fmt.Errorf("%s %q is listed more than once in the manifest", group.kind, name)
+			}
+			seen[strings.ToLower(name)] = true
+		}
+	}
+	if len(m.Users)+len(m.Nodes)+len(m.Servers) == 0 {
+		return errors.New("manifest lists no users, nodes or servers")
+	}
+	return nil
+}
+
+// normalize round-trips the manifest through JSON, turning numbers into float64 and lists into []any.
+func normalize(m *Manifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by Load
+	}
+	*m = Manifest{}
+	return json.Unmarshal(data, m) //nolint:wrapcheck // Wrapped by Load
+}
@@ -0,0 +1,263 @@
+// Package notify posts a summary of the bulk runs of a command to webhooks once it is done, for
+// --notify-webhook and the notify section of the config file.
+//
+// Discord and Slack webhooks get a message formatted for them; any other URL gets a JSON document:
+//
+//	{
+//	  "command": "pelicanctl client power restart",
+//	  "status": "failed",
+//	  "summary": {"total": 10, "succeeded": 8, "failed": 2, "cancelled": 1},
+//	  "duration_seconds": 42.5,
+//	  "error": "2 operation(s) failed",
+//	  "failures": [{"id": "<server>", "status": "error", "error": "..."}]
+//	}
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/bulk"
+)
+
+// Kind is the format of the messages a webhook takes.
+type Kind string
+
+// Webhook kinds.
+const (
+	KindGeneric Kind = "generic"
+	KindDiscord Kind = "discord"
+	KindSlack   Kind = "slack"
+)
+
+// Report statuses.
+const (
+	statusSuccess = "success"
+	statusFailed  = "failed"
+)
+
+const (
+	// sendTimeout bounds a webhook request, so an unreachable webhook does not hold up the command.
+	sendTimeout = 10 * time.Second
+	// maxFailures bounds the failed operations listed in Discord and Slack messages, which limit their size.
+	maxFailures = 20
+	// maxErrorLength bounds the error of a failed operation in Discord and Slack messages, keeping a full
+	// list of failures within the 4096 characters of a Discord embed.
+	maxErrorLength = 150
+)
+
+// Embed colors of Discord messages.
+const (
+	colorSuccess = 0x2ecc71
+	colorFailed  = 0xe74c3c
+)
+
+//nolint:gochecknoglobals // Shared by every notification so connections are shared too
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// Webhook is a URL notified when a command is done.
+type Webhook struct {
+	URL  string
+	Kind Kind
+}
+
+// New returns the webhook of rawURL. An empty kind is detected from the URL: Discord and Slack
+// webhook URLs get their own messages, any other URL the generic JSON document.
+func New(rawURL string, kind Kind) (Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		// The URL itself is left out of the error, as webhook URLs carry their secret.
+		return Webhook{}, errors.New("invalid webhook URL: must be an http or https URL")
+	}
+	if kind == "" {
+		kind = detectKind(parsed)
+	}
+	return Webhook{URL: rawURL, Kind: kind}, nil
+}
+
+// detectKind tells Discord and Slack webhooks from their URL.
+func detectKind(u *url.URL) Kind {
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case (host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com")) &&
+		strings.HasPrefix(u.Path, "/api/webhooks/"):
+		return KindDiscord
+	case host == "hooks.slack.com":
+		return KindSlack
+	default:
+		return KindGeneric
+	}
+}
+
+// Report is what a notification tells about a command.
+type Report struct {
+	// Command is the command path, e.g. "pelicanctl client power restart".
+	Command string
+	// Results are the results of the bulk runs of the command.
+	Results []bulk.Result
+	// Duration is how long the command ran.
+	Duration time.Duration
+	// Err is the error the command failed with, if any.
+	Err error
+}
+
+func (r Report) status() string {
+	if r.Err != nil || bulk.GetSummary(r.Results).Failed > 0 {
+		return statusFailed
+	}
+	return statusSuccess
+}
+
+func (r Report) failures() []bulk.Result {
+	var failures []bulk.Result
+	for _, result := range r.Results {
+		if !result.Success {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Send posts the report to the webhook.
+func Send(ctx context.Context, webhook Webhook, report Report) error {
+	var payload any
+	switch webhook.Kind {
+	case KindDiscord:
+		payload = discordPayload(report)
+	case KindSlack:
+		payload = slackPayload(report)
+	default:
+		payload = genericPayload(report)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("failed to create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// Errors of the client quote the URL, which carries the secret of the webhook.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+func genericPayload(report Report) map[string]any {
+	summary := bulk.GetSummary(report.Results)
+	failures := make([]map[string]any, 0, summary.Failed)
+	for _, result := range report.failures() {
+		failures = append(failures, map[string]any{
+			"id":     result.Operation.ID,
+			"status": result.Status(),
+			"error":  result.Error.Error(),
+		})
+	}
+	payload := map[string]any{
+		"command": report.Command,
+		"status":  report.status(),
+		"summary": map[string]any{
+			"total":     summary.Total,
+			"succeeded": summary.Success,
+			"failed":    summary.Failed,
+			"cancelled": summary.Cancelled,
+		},
+		"duration_seconds": report.Duration.Round(time.Millisecond).Seconds(),
+		"failures":         failures,
+	}
+	if report.Err != nil {
+		payload["error"] = report.Err.Error()
+	}
+	return payload
+}
+
+// headline describes the report in one line, e.g. "8 succeeded, 2 failed in 42s".
+func headline(report Report) string {
+	return fmt.Sprintf("%s in %s", bulk.GetSummary(report.Results), report.Duration.Round(time.Second))
+}
+
+// failureLines lists the failed operations, up to maxFailures, one per line.
+func failureLines(report Report, format func(id, err string) string) []string {
+	failures := report.failures()
+	lines := make([]string, 0, min(len(failures), maxFailures)+1)
+	for i, result := range failures {
+		if i == maxFailures {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(failures)-maxFailures))
+			break
+		}
+		lines = append(lines, format(result.Operation.ID, truncate(result.Error.Error(), maxErrorLength)))
+	}
+	return lines
+}
+
+func discordPayload(report Report) map[string]any {
+	description := headline(report)
+	if report.Err != nil {
+		description += "\n**Error:** " + truncate(report.Err.Error(), maxErrorLength)
+	}
+	if lines := failureLines(report, func(id, err string) string {
+		return fmt.Sprintf("`%s`: %s", id, err)
+	}); len(lines) > 0 {
+		description += "\n\n**Failures**\n" + strings.Join(lines, "\n")
+	}
+	color := colorSuccess
+	if report.status() == statusFailed {
+		color = colorFailed
+	}
+	return map[string]any{
+		"username": "pelicanctl",
+		"embeds": []map[string]any{{
+			"title":       report.Command,
+			"description": description,
+			"color":       color,
+			"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		}},
+	}
+}
+
+func slackPayload(report Report) map[string]any {
+	icon := ":white_check_mark:"
+	if report.status() == statusFailed {
+		icon = ":x:"
+	}
+	text := fmt.Sprintf("%s *%s*: %s", icon, report.Command, headline(report))
+	if report.Err != nil {
+		text += "\n*Error:* " + truncate(report.Err.Error(), maxErrorLength)
+	}
+	if lines := failureLines(report, func(id, err string) string {
+		return fmt.Sprintf("• `%s`: %s", id, err)
+	}); len(lines) > 0 {
+		text += "\n" + strings.Join(lines, "\n")
+	}
+	return map[string]any{"text": text}
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
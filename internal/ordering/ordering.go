@@ -0,0 +1,112 @@
+// Package ordering computes dependency-ordered plans for starting servers.
+package ordering
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Waves orders servers so that every server comes after the servers it depends on.
+//
+// The result is a list of waves: servers in the same wave have no dependencies on each other
+// and can be started in parallel once all previous waves are up. Dependencies on servers that
+// are not in the list are ignored. An error is returned if the dependencies contain a cycle.
+func Waves(servers []string, dependsOn map[string][]string) ([][]string, error) {
+	inSet := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		inSet[server] = true
+	}
+
+	// Count unmet dependencies and record the reverse edges
+	pending := make(map[string]int, len(servers))
+	dependents := make(map[string][]string)
+	for server := range inSet {
+		pending[server] = 0
+		for _, dep := range uniq(dependsOn[server]) {
+			if dep == server {
+				return nil, fmt.Errorf("dependency cycle: %s -> %s", server, server)
+			}
+			if !inSet[dep] {
+				continue
+			}
+			pending[server]++
+			dependents[dep] = append(dependents[dep], server)
+		}
+	}
+
+	var waves [][]string
+	var ready []string
+	for server, count := range pending {
+		if count == 0 {
+			ready = append(ready, server)
+		}
+	}
+
+	placed := 0
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		waves = append(waves, ready)
+		placed += len(ready)
+
+		var next []string
+		for _, server := range ready {
+			for _, dependent := range dependents[server] {
+				pending[dependent]--
+				if pending[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		ready = next
+	}
+
+	if placed < len(pending) {
+		return nil, fmt.Errorf("dependency cycle: %s", findCycle(pending, dependsOn, inSet))
+	}
+	return waves, nil
+}
+
+// findCycle returns a cycle among the servers that could not be placed, e.g. "a -> b -> a".
+func findCycle(pending map[string]int, dependsOn map[string][]string, inSet map[string]bool) string {
+	var start []string
+	for server, count := range pending {
+		if count > 0 {
+			start = append(start, server)
+		}
+	}
+	sort.Strings(start)
+
+	// Every unplaced server has an unplaced dependency, so following them must revisit a server
+	seen := make(map[string]int)
+	var path []string
+	current := start[0]
+	for {
+		if idx, ok := seen[current]; ok {
+			return strings.Join(append(path[idx:], current), " -> ")
+		}
+		seen[current] = len(path)
+		path = append(path, current)
+
+		deps := uniq(dependsOn[current])
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if inSet[dep] && pending[dep] > 0 {
+				current = dep
+				break
+			}
+		}
+	}
+}
+
+func uniq(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+//nolint:gochecknoglobals // Set once by the root command from --no-color
+var noColor atomic.Bool
+
+// colorRenderer renders styles as ANSI colors regardless of the terminal; colorEnabled decides whether
+// they are used at all.
+//
+//nolint:gochecknoglobals // Shared by the styles of status messages
+var colorRenderer = newColorRenderer()
+
+func newColorRenderer() *lipgloss.Renderer {
+	renderer := lipgloss.NewRenderer(io.Discard)
+	renderer.SetColorProfile(termenv.ANSI)
+	return renderer
+}
+
+// SetNoColor disables colored output, for --no-color.
+func SetNoColor(disabled bool) {
+	noColor.Store(disabled)
+}
+
+// colorEnabled reports whether output to w is colored. --no-color and NO_COLOR turn colors off and
+// CLICOLOR_FORCE (other than "0") turns them on; otherwise only terminals other than TERM=dumb get them,
+// so output piped to files and other programs is plain text.
+func colorEnabled(w io.Writer) bool {
+	if noColor.Load() || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// render returns text in a style when output to w is colored, and as it is otherwise.
+func render(w io.Writer, style lipgloss.Style, text string) string {
+	if !colorEnabled(w) {
+		return text
+	}
+	return style.Render(text)
+}
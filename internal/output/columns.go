@@ -0,0 +1,130 @@
+package output
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// TableOptions customizes list tables printed with PrintWithConfig for one invocation.
+type TableOptions struct {
+	// Columns replaces the configured columns. A column is a header of the resource's table
+	// (case-insensitive, "-" or "_" for spaces) or a field path in dot notation.
+	Columns []string
+	// SortBy sorts the rows by this column before printing.
+	SortBy string
+	// Desc sorts in descending order.
+	Desc bool
+	// NoHeader omits the header row of CSV and TSV output.
+	NoHeader bool
+}
+
+var (
+	//nolint:gochecknoglobals // Table options are process-wide, set up once by the root command
+	tableOptions TableOptions
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the table options
+	tableOptionsMutex sync.Mutex
+)
+
+// SetTableOptions sets the columns, sort order, and header row of list tables.
+func SetTableOptions(opts TableOptions) {
+	tableOptionsMutex.Lock()
+	defer tableOptionsMutex.Unlock()
+	tableOptions = opts
+}
+
+// currentTableOptions returns the columns, sort order, and header row of list tables.
+func currentTableOptions() TableOptions {
+	tableOptionsMutex.Lock()
+	defer tableOptionsMutex.Unlock()
+	return tableOptions
+}
+
+// printCustomTable prints a list with the columns given by the table options instead of the configured ones.
+func (f *Formatter) printCustomTable(list []map[string]any, config TableConfig, columns []string) error {
+	headerRow := make(table.Row, len(columns))
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		fields[i], headerRow[i] = resolveColumn(column, config)
+	}
+
+	rows := make([]table.Row, len(list))
+	for i, item := range list {
+		row := make(table.Row, len(fields))
+		for j, field := range fields {
+			row[j] = f.extractField(item, field)
+		}
+		rows[i] = row
+	}
+
+	return f.printPrettyTable(headerRow, rows)
+}
+
+// sortList returns a copy of list sorted by a column. Values that are both numbers compare numerically,
+// everything else compares as text; rows with equal values keep their order.
+func (f *Formatter) sortList(list []map[string]any, config TableConfig, column string, desc bool) []map[string]any {
+	field, _ := resolveColumn(column, config)
+	sorted := slices.Clone(list)
+	slices.SortStableFunc(sorted, func(a, b map[string]any) int {
+		cmp := compareValues(f.extractField(a, field), f.extractField(b, field))
+		if desc {
+			return -cmp
+		}
+		return cmp
+	})
+	return sorted
+}
+
+// compareValues compares two formatted table values, numerically if both are numbers.
+func compareValues(a, b string) int {
+	aNum, aErr := strconv.ParseFloat(a, 64)
+	bNum, bErr := strconv.ParseFloat(b, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// resolveColumn maps a column given on the command line to a field path and its header.
+// Headers and fields of the resource's table configuration are matched first, so "node" finds
+// "attributes.node" and "created-at" finds "Created At"; anything else is used as a field path.
+func resolveColumn(column string, config TableConfig) (string, string) {
+	name := strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(strings.TrimSpace(column)))
+	for i, field := range config.Fields {
+		if i < len(config.Headers) && strings.ToLower(config.Headers[i]) == name {
+			return field, config.Headers[i]
+		}
+	}
+	for i, field := range config.Fields {
+		parts := strings.Split(field, ".")
+		if strings.EqualFold(parts[len(parts)-1], column) {
+			if i < len(config.Headers) {
+				return field, config.Headers[i]
+			}
+			return field, headerForField(field)
+		}
+	}
+	return column, headerForField(column)
+}
+
+// headerForField derives a header from the last part of a field path.
+func headerForField(field string) string {
+	parts := strings.Split(field, ".")
+	lastPart := parts[len(parts)-1]
+	if len(lastPart) == 0 {
+		return lastPart
+	}
+	// Capitalize first letter
+	return strings.ToUpper(lastPart[:1]) + lastPart[1:]
+}
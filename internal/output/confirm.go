@@ -0,0 +1,30 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/strict"
+)
+
+// Confirm asks whether to go ahead with what prompt describes, such as "This will delete 3 file(s).", and
+// reads a y/N answer from stdin. It returns true without asking when --yes is given on cmd, and fails
+// instead of asking in strict mode.
+func Confirm(cmd *cobra.Command, formatter *Formatter, prompt string) (bool, error) {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return true, nil
+	}
+	if err := strict.Prompt("pass --yes to confirm"); err != nil {
+		return false, err
+	}
+
+	formatter.PrintInfo("%s Continue? (y/N): ", prompt)
+	var response string
+	if _, err := fmt.Scanln(&response); err != nil {
+		return false, fmt.Errorf("failed to read response: %w", err)
+	}
+	response = strings.ToLower(response)
+	return response == "y" || response == "yes", nil
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// printDelimited prints a table as CSV or TSV, quoting cells as needed.
+func (f *Formatter) printDelimited(headers table.Row, rows []table.Row) error {
+	w := csv.NewWriter(f.writer)
+	if f.format == OutputFormatTSV {
+		w.Comma = '\t'
+	}
+
+	if !currentTableOptions().NoHeader {
+		if err := w.Write(delimitedRecord(headers)); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		if err := w.Write(delimitedRecord(row)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// printDelimitedDetail prints a single resource as field,value rows.
+// Nested maps are flattened into dot notation, so every field has its own row.
+func (f *Formatter) printDelimitedDetail(m map[string]any) error {
+	flat := make(map[string]any)
+	flattenFields("", m, flat)
+
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := make([]table.Row, len(keys))
+	for i, k := range keys {
+		rows[i] = table.Row{k, f.formatValue(flat[k])}
+	}
+	return f.printDelimited(table.Row{"Field", "Value"}, rows)
+}
+
+// flattenFields collects the leaf values of a nested map under their dot-notation paths.
+func flattenFields(prefix string, m map[string]any, flat map[string]any) {
+	for k, v := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			flattenFields(path, nested, flat)
+			continue
+		}
+		flat[path] = v
+	}
+}
+
+// delimitedRecord converts a table row to the cells of a CSV or TSV record.
+func delimitedRecord(row table.Row) []string {
+	record := make([]string, len(row))
+	for i, cell := range row {
+		if cell != nil {
+			record[i] = fmt.Sprint(cell)
+		}
+	}
+	return record
+}
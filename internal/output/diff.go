@@ -0,0 +1,35 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go.lostcrafters.com/pelicanctl/internal/diff"
+)
+
+// PrintFieldChanges prints the fields an update changes, one "field: old -> new" line each, with values
+// as JSON so strings, numbers and unset fields (null) are told apart. Like status messages, they go to
+// stderr where stdout holds data, and JSON output gets them as one JSON object on stderr.
+func (f *Formatter) PrintFieldChanges(changes []diff.FieldChange) {
+	if f.format.IsJSON() {
+		encoder := json.NewEncoder(os.Stderr)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(map[string]any{"status": "diff", "changes": changes})
+		return
+	}
+	writer := f.messageWriter()
+	for _, change := range changes {
+		_, _ = fmt.Fprintf(writer, "  %s: %s -> %s\n", change.Field,
+			render(writer, errorStyle, diffValue(change.Old)), render(writer, successStyle, diffValue(change.New)))
+	}
+}
+
+// diffValue formats a value of a field change as compact JSON.
+func diffValue(val any) string {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(data)
+}
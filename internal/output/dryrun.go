@@ -0,0 +1,43 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Request is a request a dry run stopped, as it is printed. Body holds a JSON body decoded, a description
+// of any other body such as a file upload, or nil without a body.
+type Request struct {
+	Method string
+	URL    string
+	Body   any
+}
+
+// Data returns the request as data, for the output formats other than tables.
+func (r Request) Data() map[string]any {
+	return map[string]any{"method": r.Method, "url": r.URL, "body": r.Body}
+}
+
+// PrintDryRun prints a request a dry run stopped: its method and URL followed by its body, or all three
+// as data in the other output formats.
+func (f *Formatter) PrintDryRun(req Request) error {
+	if f.format != OutputFormatTable && f.format != OutputFormatWide {
+		return f.Print(req.Data())
+	}
+
+	f.PrintInfo("Dry run - would send %s %s", req.Method, req.URL)
+	switch body := req.Body.(type) {
+	case nil:
+		return nil
+	case string:
+		f.PrintInfo("  %s", body)
+		return nil
+	default:
+		pretty, err := json.MarshalIndent(body, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format request body: %w", err)
+		}
+		_, _ = fmt.Fprintln(f.writer, string(pretty))
+		return nil
+	}
+}
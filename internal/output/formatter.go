@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"slices"
 	"sort"
 	"strconv"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jedib0t/go-pretty/v6/table"
+
+	"go.lostcrafters.com/pelicanctl/internal/strict"
 )
 
 // OutputFormat represents the output format.
@@ -24,27 +27,65 @@ type OutputFormat string
 const (
 	OutputFormatTable OutputFormat = "table"
 	OutputFormatJSON  OutputFormat = "json"
+	OutputFormatCSV   OutputFormat = "csv"
+	OutputFormatTSV   OutputFormat = "tsv"
+	// OutputFormatWide is the table format with the extra columns of each resource type.
+	OutputFormatWide OutputFormat = "wide"
+	// OutputFormatGoTemplate prints data through the template set with SetOutputTemplate.
+	OutputFormatGoTemplate OutputFormat = "go-template"
+	// OutputFormatNDJSON prints one compact JSON document per line: lists print one item per line,
+	// and bulk results are streamed as each operation completes.
+	OutputFormatNDJSON OutputFormat = "ndjson"
+	// OutputFormatYAML prints data as YAML, with fields named as in JSON output.
+	OutputFormatYAML OutputFormat = "yaml"
+	// OutputFormatPorcelain prints only the identifiers of the resources a command acted on, one per line,
+	// with every message on stderr, for scripts.
+	OutputFormatPorcelain OutputFormat = "porcelain"
 )
 
+// IsJSON reports whether the format prints JSON (json or ndjson).
+func (o OutputFormat) IsJSON() bool {
+	return o == OutputFormatJSON || o == OutputFormatNDJSON
+}
+
+// Delimited reports whether the format prints tables as delimiter-separated values (CSV or TSV).
+func (o OutputFormat) Delimited() bool {
+	return o == OutputFormatCSV || o == OutputFormatTSV
+}
+
 // ResourceType identifies the type of resource.
 type ResourceType string
 
 const (
-	ResourceTypeClientServer   ResourceType = "client.server"
-	ResourceTypeAdminServer    ResourceType = "admin.server"
-	ResourceTypeAdminNode      ResourceType = "admin.node"
-	ResourceTypeAdminUser      ResourceType = "admin.user"
-	ResourceTypeAdminBackup    ResourceType = "admin.backup"
-	ResourceTypeClientBackup   ResourceType = "client.backup"
-	ResourceTypeClientDatabase ResourceType = "client.database"
-	ResourceTypeClientFile     ResourceType = "client.file"
-	ResourceTypeServerResource ResourceType = "client.server.resources"
+	ResourceTypeClientServer      ResourceType = "client.server"
+	ResourceTypeAdminServer       ResourceType = "admin.server"
+	ResourceTypeAdminNode         ResourceType = "admin.node"
+	ResourceTypeAdminUser         ResourceType = "admin.user"
+	ResourceTypeAdminBackup       ResourceType = "admin.backup"
+	ResourceTypeClientBackup      ResourceType = "client.backup"
+	ResourceTypeClientDatabase    ResourceType = "client.database"
+	ResourceTypeClientFile        ResourceType = "client.file"
+	ResourceTypeServerResource    ResourceType = "client.server.resources"
+	ResourceTypeClientSchedule    ResourceType = "client.schedule"
+	ResourceTypeClientTask        ResourceType = "client.schedule.task"
+	ResourceTypeClientNetwork     ResourceType = "client.network"
+	ResourceTypeClientAPIKey      ResourceType = "client.apikey"
+	ResourceTypeAdminAllocation   ResourceType = "admin.allocation"
+	ResourceTypeAdminDatabaseHost ResourceType = "admin.database-host"
+	ResourceTypeAdminEgg          ResourceType = "admin.egg"
+	ResourceTypeAdminRole         ResourceType = "admin.role"
 )
 
 // TableConfig defines which fields to show for a specific resource type.
 type TableConfig struct {
 	Fields  []string // Field names to display (supports dot notation for nested)
 	Headers []string // Display names for headers (optional, defaults to field names)
+	// Identity prepends the server identifier chosen by the identity policy, followed by the
+	// server name unless the policy already shows it, so every server table names servers alike.
+	Identity bool
+	// WideFields and WideHeaders are the extra columns appended by the wide format.
+	WideFields  []string
+	WideHeaders []string
 }
 
 const (
@@ -58,49 +99,119 @@ const (
 
 var (
 	// Styles for table output.
-	successStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
-	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
-	warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
-	infoStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
+	successStyle = colorRenderer.NewStyle().Foreground(lipgloss.Color("2"))
+	errorStyle   = colorRenderer.NewStyle().Foreground(lipgloss.Color("1"))
+	warningStyle = colorRenderer.NewStyle().Foreground(lipgloss.Color("3"))
+	infoStyle    = colorRenderer.NewStyle().Foreground(lipgloss.Color("4"))
 
 	// tableConfigs defines field mappings for each resource type.
 	tableConfigs = map[ResourceType]TableConfig{
 		ResourceTypeClientServer: {
-			Fields:  []string{"id", "uuid", "attributes.name"},
-			Headers: []string{"ID", "UUID", "Name"},
+			Identity: true,
+			WideFields: []string{
+				"attributes.node", "attributes.status", "attributes.limits.memory", "attributes.limits.disk",
+				"attributes.limits.cpu",
+			},
+			WideHeaders: []string{"Node", "Status", "Memory (MiB)", "Disk (MiB)", "CPU (%)"},
 		},
 		ResourceTypeAdminServer: {
-			Fields:  []string{"id", "uuid", "attributes.name", "attributes.node"},
-			Headers: []string{"ID", "UUID", "Name", "Node"},
+			Fields:   []string{"attributes.node"},
+			Headers:  []string{"Node"},
+			Identity: true,
+			WideFields: []string{
+				"attributes.status", "attributes.limits.memory", "attributes.limits.disk", "attributes.limits.cpu",
+				"attributes.relationships.user.attributes.email", "attributes.created_at",
+			},
+			WideHeaders: []string{"Status", "Memory (MiB)", "Disk (MiB)", "CPU (%)", "Owner", "Created At"},
 		},
 		ResourceTypeAdminNode: {
 			Fields:  []string{"id", "attributes.name"},
 			Headers: []string{"ID", "Name"},
+			WideFields: []string{
+				"attributes.fqdn", "attributes.memory", "attributes.disk", "attributes.maintenance_mode",
+				"attributes.created_at",
+			},
+			WideHeaders: []string{"FQDN", "Memory (MiB)", "Disk (MiB)", "Maintenance", "Created At"},
 		},
 		ResourceTypeAdminUser: {
-			Fields:  []string{"id", "attributes.email", "attributes.username"},
-			Headers: []string{"ID", "Email", "Username"},
+			Fields:      []string{"id", "attributes.email", "attributes.username"},
+			Headers:     []string{"ID", "Email", "Username"},
+			WideFields:  []string{"attributes.root_admin", "attributes.language", "attributes.created_at"},
+			WideHeaders: []string{"Admin", "Language", "Created At"},
 		},
 		ResourceTypeAdminBackup: {
-			Fields:  []string{"uuid", "name", "created_at", "is_successful"},
-			Headers: []string{"UUID", "Name", "Created At", "Successful"},
+			Fields:      []string{"uuid", "name", "created_at", "is_successful"},
+			Headers:     []string{"UUID", "Name", "Created At", "Successful"},
+			WideFields:  []string{"bytes", "completed_at"},
+			WideHeaders: []string{"Size (bytes)", "Completed At"},
 		},
 		ResourceTypeClientBackup: {
-			Fields:  []string{"uuid", "name", "created_at"},
-			Headers: []string{"UUID", "Name", "Created At"},
+			Fields:      []string{"uuid", "name", "is_locked", "created_at"},
+			Headers:     []string{"UUID", "Name", "Locked", "Created At"},
+			WideFields:  []string{"bytes", "is_successful", "completed_at"},
+			WideHeaders: []string{"Size (bytes)", "Successful", "Completed At"},
 		},
 		ResourceTypeClientDatabase: {
-			Fields:  []string{"name", "username"},
-			Headers: []string{"Name", "Username"},
+			Fields:      []string{"name", "username"},
+			Headers:     []string{"Name", "Username"},
+			WideFields:  []string{"host.address", "host.port", "max_connections"},
+			WideHeaders: []string{"Host", "Port", "Max Connections"},
 		},
 		ResourceTypeClientFile: {
-			Fields:  []string{"name", "type"},
-			Headers: []string{"Name", "Type"},
+			Fields:      []string{"name", "type"},
+			Headers:     []string{"Name", "Type"},
+			WideFields:  []string{"size", "mode", "modified_at"},
+			WideHeaders: []string{"Size (bytes)", "Mode", "Modified At"},
 		},
 		ResourceTypeServerResource: {
 			Fields:  []string{"state", "resources.memory_bytes", "resources.cpu_absolute"},
 			Headers: []string{"State", "Memory", "CPU"},
 		},
+		ResourceTypeClientSchedule: {
+			Fields: []string{
+				"id", "name",
+				"attributes.cron.minute", "attributes.cron.hour", "attributes.cron.day_of_month",
+				"attributes.cron.month", "attributes.cron.day_of_week",
+				"is_active", "next_run_at",
+			},
+			Headers:     []string{"ID", "Name", "Minute", "Hour", "Day", "Month", "Weekday", "Active", "Next Run"},
+			WideFields:  []string{"is_processing", "last_run_at", "created_at"},
+			WideHeaders: []string{"Processing", "Last Run", "Created At"},
+		},
+		ResourceTypeClientTask: {
+			Fields:  []string{"id", "sequence_id", "action", "payload", "time_offset", "continue_on_failure"},
+			Headers: []string{"ID", "Sequence", "Action", "Payload", "Offset (s)", "Continue On Failure"},
+		},
+		ResourceTypeClientNetwork: {
+			Fields:  []string{"id", "ip", "ip_alias", "port", "notes", "is_default"},
+			Headers: []string{"ID", "IP", "Alias", "Port", "Notes", "Primary"},
+		},
+		ResourceTypeClientAPIKey: {
+			Fields:  []string{"identifier", "description", "allowed_ips", "last_used_at", "created_at"},
+			Headers: []string{"Identifier", "Description", "Allowed IPs", "Last Used", "Created At"},
+		},
+		ResourceTypeAdminAllocation: {
+			Fields:  []string{"id", "ip", "alias", "port", "notes", "assigned"},
+			Headers: []string{"ID", "IP", "Alias", "Port", "Notes", "Assigned"},
+		},
+		ResourceTypeAdminDatabaseHost: {
+			Fields:      []string{"id", "name", "host", "port", "username", "max_databases"},
+			Headers:     []string{"ID", "Name", "Host", "Port", "Username", "Max Databases"},
+			WideFields:  []string{"created_at", "updated_at"},
+			WideHeaders: []string{"Created At", "Updated At"},
+		},
+		ResourceTypeAdminEgg: {
+			Fields:      []string{"id", "uuid", "name", "author"},
+			Headers:     []string{"ID", "UUID", "Name", "Author"},
+			WideFields:  []string{"docker_images", "created_at"},
+			WideHeaders: []string{"Images", "Created At"},
+		},
+		ResourceTypeAdminRole: {
+			Fields:      []string{"id", "name", "created_at"},
+			Headers:     []string{"ID", "Name", "Created"},
+			WideFields:  []string{"updated_at"},
+			WideHeaders: []string{"Updated"},
+		},
 	}
 )
 
@@ -123,6 +234,14 @@ func (f *Formatter) Print(data any) error {
 	switch f.format {
 	case OutputFormatJSON:
 		return f.printJSON(data)
+	case OutputFormatNDJSON:
+		return f.printNDJSON(data)
+	case OutputFormatGoTemplate:
+		return f.printTemplate(data)
+	case OutputFormatYAML:
+		return f.printYAML(data)
+	case OutputFormatPorcelain:
+		return f.printPorcelain(data)
 	case OutputFormatTable:
 		return f.printTable(data)
 	default:
@@ -132,17 +251,38 @@ func (f *Formatter) Print(data any) error {
 
 // PrintWithConfig formats and prints data with explicit resource type configuration.
 func (f *Formatter) PrintWithConfig(data any, resourceType ResourceType) error {
-	if f.format == OutputFormatJSON {
+	switch f.format {
+	case OutputFormatJSON:
 		return f.printJSON(data)
+	case OutputFormatNDJSON:
+		return f.printNDJSON(data)
+	case OutputFormatGoTemplate:
+		return f.printTemplate(data)
+	case OutputFormatYAML:
+		return f.printYAML(data)
+	case OutputFormatPorcelain:
+		return f.printPorcelain(data)
 	}
 
 	// Handle []map[string]any (list views)
 	if list, ok := data.([]map[string]any); ok && len(list) > 0 {
+		// --sort-by and --columns override the configured table for this invocation.
+		opts := currentTableOptions()
+		config, _ := f.tableConfig(resourceType)
+		if opts.SortBy != "" {
+			list = f.sortList(list, config, opts.SortBy, opts.Desc)
+		}
+		if len(opts.Columns) > 0 {
+			return f.printCustomTable(list, config, opts.Columns)
+		}
 		return f.printListTableWithConfig(list, resourceType)
 	}
 
 	// Handle map[string]any (detail views)
 	if m, ok := data.(map[string]any); ok {
+		if f.format.Delimited() {
+			return f.printDelimitedDetail(m)
+		}
 		return f.printFormattedDetail(m)
 	}
 
@@ -152,11 +292,50 @@ func (f *Formatter) PrintWithConfig(data any, resourceType ResourceType) error {
 
 // printJSON prints data as formatted JSON.
 func (f *Formatter) printJSON(data any) error {
+	if strict.Enabled() {
+		data = stableJSON(data)
+	}
 	encoder := json.NewEncoder(f.writer)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
+// printNDJSON prints data as compact JSON lines: one line per item of a list, or a single line otherwise.
+func (f *Formatter) printNDJSON(data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		// Not a list (or null, which decodes to no items) - print it as one document.
+		items = []json.RawMessage{raw}
+	} else if items == nil {
+		return nil
+	}
+
+	for _, item := range items {
+		if _, err := fmt.Fprintf(f.writer, "%s\n", item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stableJSON keeps the shape of JSON output independent of the result:
+// an empty list is encoded as [] rather than null.
+func stableJSON(data any) any {
+	if data == nil {
+		return data
+	}
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
+
 // printTable prints data as a table (fallback to JSON for complex types).
 func (f *Formatter) printTable(data any) error {
 	// Handle strings directly
@@ -172,6 +351,9 @@ func (f *Formatter) printTable(data any) error {
 
 	// Handle map[string]any (detail views)
 	if m, ok := data.(map[string]any); ok {
+		if f.format.Delimited() {
+			return f.printDelimitedDetail(m)
+		}
 		return f.printFormattedDetail(m)
 	}
 
@@ -231,12 +413,16 @@ func (f *Formatter) printListTableWithConfig(list []map[string]any, resourceType
 	}
 
 	// Get table configuration for this resource type
-	config, ok := tableConfigs[resourceType]
+	config, ok := f.tableConfig(resourceType)
 	if !ok {
 		// Fallback to generic detection if no config found
 		return f.printListTable(list)
 	}
 
+	if config.Identity {
+		return f.printIdentityTable(list, config)
+	}
+
 	// Use configured fields or fallback to all available fields
 	fields := config.Fields
 	if len(fields) == 0 {
@@ -253,14 +439,7 @@ func (f *Formatter) printListTableWithConfig(list []map[string]any, resourceType
 		headers = make([]string, len(fields))
 		for i, field := range fields {
 			// Use last part of dot notation as header name
-			parts := strings.Split(field, ".")
-			lastPart := parts[len(parts)-1]
-			// Capitalize first letter
-			if len(lastPart) > 0 {
-				headers[i] = strings.ToUpper(lastPart[:1]) + lastPart[1:]
-			} else {
-				headers[i] = lastPart
-			}
+			headers[i] = headerForField(field)
 		}
 	}
 
@@ -284,27 +463,67 @@ func (f *Formatter) printListTableWithConfig(list []map[string]any, resourceType
 	return f.printPrettyTable(headerRow, rows)
 }
 
+// tableConfig returns the table configuration of a resource type, with the wide columns appended
+// in the wide format.
+func (f *Formatter) tableConfig(resourceType ResourceType) (TableConfig, bool) {
+	config, ok := tableConfigs[resourceType]
+	if !ok || f.format != OutputFormatWide || len(config.WideFields) == 0 {
+		return config, ok
+	}
+	config.Fields = append(slices.Clone(config.Fields), config.WideFields...)
+	config.Headers = append(slices.Clone(config.Headers), config.WideHeaders...)
+	return config, true
+}
+
+// printIdentityTable prints a server list led by the identity column, then the configured fields.
+func (f *Formatter) printIdentityTable(list []map[string]any, config TableConfig) error {
+	policy := CurrentIdentityPolicy()
+	showName := policy != IdentityName
+
+	headerRow := table.Row{identityHeader(policy)}
+	if showName {
+		headerRow = append(headerRow, "Name")
+	}
+	for _, h := range config.Headers {
+		headerRow = append(headerRow, h)
+	}
+
+	rows := make([]table.Row, len(list))
+	for i, item := range list {
+		row := table.Row{identityValue(item, policy)}
+		if showName {
+			row = append(row, identityString(serverField(item, "name")))
+		}
+		for _, field := range config.Fields {
+			row = append(row, f.extractField(item, field))
+		}
+		rows[i] = row
+	}
+
+	return f.printPrettyTable(headerRow, rows)
+}
+
 // extractField extracts a field value using dot notation for nested fields.
 // Also handles fallback: if field not found, tries "attributes.{field}" path.
 func (f *Formatter) extractField(item map[string]any, fieldPath string) string {
 	// Try direct path first
 	val := f.getNestedField(item, fieldPath)
 	if val != nil {
-		return f.formatValue(val)
+		return f.formatField(fieldPath, val)
 	}
 
 	// Try attributes.{field} as fallback
-	if !strings.Contains(fieldPath, ".") {
+	if !strings.HasPrefix(fieldPath, "attributes.") {
 		attrsPath := "attributes." + fieldPath
 		val = f.getNestedField(item, attrsPath)
 		if val != nil {
-			return f.formatValue(val)
+			return f.formatField(fieldPath, val)
 		}
 	}
 
 	// Also try direct top-level field
 	if directVal, ok := item[fieldPath]; ok {
-		return f.formatValue(directVal)
+		return f.formatField(fieldPath, directVal)
 	}
 
 	return "-"
@@ -379,7 +598,7 @@ func (f *Formatter) formatNestedMap(m map[string]any, depth int, maxDepth int, k
 	for _, key := range simpleFields {
 		val := m[key]
 		formattedKey := f.formatKey(key, keyWidth, indentSize)
-		formattedVal := f.formatDetailValue(val)
+		formattedVal := f.formatDetailField(key, val)
 		result.WriteString(fmt.Sprintf("%s%s: %s\n", indent, formattedKey, formattedVal))
 	}
 
@@ -763,9 +982,9 @@ func (f *Formatter) formatValue(val any) string {
 		if err != nil {
 			return fmt.Sprintf("%v", v)
 		}
-		// Truncate if too long
+		// Truncate if too long, except in CSV and TSV, which are read by programs
 		jsonStr := string(jsonBytes)
-		if len(jsonStr) > maxTruncateLength {
+		if len(jsonStr) > maxTruncateLength && !f.format.Delimited() {
 			return jsonStr[:maxTruncateLength-3] + "..."
 		}
 		return jsonStr
@@ -774,9 +993,20 @@ func (f *Formatter) formatValue(val any) string {
 	}
 }
 
-// PrintTable prints a table with headers and rows.
+// PrintTable prints a table with headers and rows. Porcelain output prints the first column, which
+// identifies the row.
 func (f *Formatter) PrintTable(headers []string, rows [][]string) error {
-	if f.format == OutputFormatJSON {
+	if f.format == OutputFormatPorcelain {
+		for _, row := range rows {
+			if len(row) > 0 && row[0] != "" {
+				if _, err := fmt.Fprintln(f.writer, row[0]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if f.format.IsJSON() || f.format == OutputFormatGoTemplate || f.format == OutputFormatYAML {
 		// Convert table to JSON array of objects
 		data := make([]map[string]string, len(rows))
 		for i, row := range rows {
@@ -787,6 +1017,14 @@ func (f *Formatter) PrintTable(headers []string, rows [][]string) error {
 				}
 			}
 		}
+		switch f.format {
+		case OutputFormatGoTemplate:
+			return f.printTemplate(data)
+		case OutputFormatNDJSON:
+			return f.printNDJSON(data)
+		case OutputFormatYAML:
+			return f.printYAML(data)
+		}
 		return f.printJSON(data)
 	}
 
@@ -809,68 +1047,93 @@ func (f *Formatter) PrintTable(headers []string, rows [][]string) error {
 	return f.printPrettyTable(headerRow, tableRows)
 }
 
-// printPrettyTable prints a table using go-pretty.
+// printPrettyTable prints a table using go-pretty in the table style, wrapped to the width of the terminal,
+// or as CSV or TSV in those formats.
 func (f *Formatter) printPrettyTable(headers table.Row, rows []table.Row) error {
+	if f.format.Delimited() {
+		return f.printDelimited(headers, rows)
+	}
+
 	t := table.NewWriter()
 	t.SetOutputMirror(f.writer)
 	t.AppendHeader(headers)
 	t.AppendRows(rows)
-	t.SetStyle(table.StyleColoredBright)
-	t.Style().Options.SeparateRows = false
-	t.Style().Options.DrawBorder = true
-	t.Style().Options.SeparateColumns = true
+	style := currentTableStyle()
+	if style == TableStyleMarkdown {
+		t.RenderMarkdown()
+		return nil
+	}
+	applyTableStyle(t, style, colorEnabled(f.writer))
+	if width := terminalWidth(f.writer); width > 0 {
+		fitToWidth(t, headers, rows, width)
+	}
 	t.Render()
 	return nil
 }
 
+// messageWriter returns where status messages go: stderr in CSV, TSV, YAML, go-template and porcelain
+// output, so they stay out of the data.
+func (f *Formatter) messageWriter() io.Writer {
+	if f.format.Delimited() || f.format == OutputFormatGoTemplate || f.format == OutputFormatYAML ||
+		f.format == OutputFormatPorcelain {
+		return os.Stderr
+	}
+	return f.writer
+}
+
 // PrintSuccess prints a success message.
 func (f *Formatter) PrintSuccess(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	if f.format == OutputFormatJSON {
+	if f.format.IsJSON() {
 		// In JSON mode, write status messages to stderr for pipeability
 		encoder := json.NewEncoder(os.Stderr)
 		encoder.SetIndent("", "  ")
 		_ = encoder.Encode(map[string]string{"status": "success", "message": msg})
 		return
 	}
-	_, _ = fmt.Fprintln(f.writer, successStyle.Render("✓ "+msg))
+	writer := f.messageWriter()
+	_, _ = fmt.Fprintln(writer, render(writer, successStyle, "✓ "+msg))
 }
 
 // PrintError prints an error message.
 func (f *Formatter) PrintError(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	if f.format == OutputFormatJSON {
+	if f.format.IsJSON() {
 		// In JSON mode, write status messages to stderr for pipeability
 		encoder := json.NewEncoder(os.Stderr)
 		encoder.SetIndent("", "  ")
 		_ = encoder.Encode(map[string]string{"status": "error", "message": msg})
 		return
 	}
-	_, _ = fmt.Fprintln(f.writer, errorStyle.Render("✗ "+msg))
+	writer := f.messageWriter()
+	_, _ = fmt.Fprintln(writer, render(writer, errorStyle, "✗ "+msg))
 }
 
 // PrintWarning prints a warning message.
 func (f *Formatter) PrintWarning(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	if f.format == OutputFormatJSON {
+	strict.Warn(msg)
+	if f.format.IsJSON() {
 		// In JSON mode, write status messages to stderr for pipeability
 		encoder := json.NewEncoder(os.Stderr)
 		encoder.SetIndent("", "  ")
 		_ = encoder.Encode(map[string]string{"status": "warning", "message": msg})
 		return
 	}
-	_, _ = fmt.Fprintln(f.writer, warningStyle.Render("⚠ "+msg))
+	writer := f.messageWriter()
+	_, _ = fmt.Fprintln(writer, render(writer, warningStyle, "⚠ "+msg))
 }
 
 // PrintInfo prints an info message.
 func (f *Formatter) PrintInfo(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	if f.format == OutputFormatJSON {
+	if f.format.IsJSON() {
 		// In JSON mode, write status messages to stderr for pipeability
 		encoder := json.NewEncoder(os.Stderr)
 		encoder.SetIndent("", "  ")
 		_ = encoder.Encode(map[string]string{"status": "info", "message": msg})
 		return
 	}
-	_, _ = fmt.Fprintln(f.writer, infoStyle.Render("ℹ "+msg))
+	writer := f.messageWriter()
+	_, _ = fmt.Fprintln(writer, render(writer, infoStyle, "ℹ "+msg))
 }
@@ -0,0 +1,180 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// IdentityPolicy selects which server identifier is shown in server tables.
+type IdentityPolicy string
+
+// Identity policies.
+const (
+	IdentityID        IdentityPolicy = "id"
+	IdentityUUID      IdentityPolicy = "uuid"
+	IdentityShortUUID IdentityPolicy = "short-uuid"
+	IdentityName      IdentityPolicy = "name"
+)
+
+// shortUUIDLength is the length of the short server identifier the panel shows and accepts.
+const shortUUIDLength = 8
+
+// IdentityPolicies lists the valid identity policies.
+//
+//nolint:gochecknoglobals // Fixed list shared by flag validation and completion
+var IdentityPolicies = []string{
+	string(IdentityID), string(IdentityUUID), string(IdentityShortUUID), string(IdentityName),
+}
+
+var (
+	//nolint:gochecknoglobals // Identity policy is process-wide, set up once by the root command
+	identityPolicy = IdentityUUID
+
+	//nolint:gochecknoglobals // Server identifiers resolved to UUIDs during this run
+	serverUUIDs = map[string]string{}
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the identity state
+	identityMutex sync.Mutex
+)
+
+// ParseIdentityPolicy validates an identity policy name.
+func ParseIdentityPolicy(s string) (IdentityPolicy, error) {
+	policy := IdentityPolicy(strings.ToLower(strings.TrimSpace(s)))
+	switch policy {
+	case IdentityID, IdentityUUID, IdentityShortUUID, IdentityName:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid identity policy: %s (must be one of %s)", s, strings.Join(IdentityPolicies, ", "))
+	}
+}
+
+// SetIdentityPolicy sets the identifier shown in server tables.
+func SetIdentityPolicy(policy IdentityPolicy) {
+	identityMutex.Lock()
+	defer identityMutex.Unlock()
+	identityPolicy = policy
+}
+
+// CurrentIdentityPolicy returns the identifier shown in server tables.
+func CurrentIdentityPolicy() IdentityPolicy {
+	identityMutex.Lock()
+	defer identityMutex.Unlock()
+	return identityPolicy
+}
+
+// RecordServers remembers the UUIDs of servers by their integer ID and short UUID,
+// so results can name servers by UUID whichever identifier they were given by.
+func RecordServers(servers []map[string]any) {
+	identityMutex.Lock()
+	defer identityMutex.Unlock()
+	for _, server := range servers {
+		uuid, _ := serverField(server, "uuid").(string)
+		if uuid == "" {
+			continue
+		}
+		if id := identityString(serverField(server, "id")); id != "" {
+			serverUUIDs[id] = uuid
+		}
+		if short := shortUUID(server, uuid); short != "" {
+			serverUUIDs[short] = uuid
+		}
+	}
+}
+
+// RecordServerUUID remembers that identifier refers to the server with the given UUID.
+func RecordServerUUID(identifier, uuid string) {
+	if identifier == "" || uuid == "" || identifier == uuid {
+		return
+	}
+	identityMutex.Lock()
+	defer identityMutex.Unlock()
+	serverUUIDs[identifier] = uuid
+}
+
+// CanonicalServer returns the UUID of the server an identifier refers to, if it is known,
+// and the identifier itself otherwise.
+func CanonicalServer(identifier string) string {
+	identityMutex.Lock()
+	defer identityMutex.Unlock()
+	if uuid, ok := serverUUIDs[identifier]; ok {
+		return uuid
+	}
+	return identifier
+}
+
+// ServerLabel names a server in human-readable results by its canonical identifier,
+// followed by the identifier it was given by when that differs.
+func ServerLabel(identifier string) string {
+	canonical := CanonicalServer(identifier)
+	if canonical == identifier {
+		return identifier
+	}
+	return fmt.Sprintf("%s (%s)", canonical, identifier)
+}
+
+// identityHeader returns the table header of the identity column.
+func identityHeader(policy IdentityPolicy) string {
+	switch policy {
+	case IdentityID:
+		return "ID"
+	case IdentityShortUUID:
+		return "Short UUID"
+	case IdentityName:
+		return "Name"
+	default:
+		return "UUID"
+	}
+}
+
+// identityValue returns the identifier of a server under the given policy.
+func identityValue(server map[string]any, policy IdentityPolicy) string {
+	switch policy {
+	case IdentityID:
+		return identityString(serverField(server, "id"))
+	case IdentityShortUUID:
+		uuid, _ := serverField(server, "uuid").(string)
+		return shortUUID(server, uuid)
+	case IdentityName:
+		return identityString(serverField(server, "name"))
+	default:
+		return identityString(serverField(server, "uuid"))
+	}
+}
+
+// shortUUID returns the short identifier of a server, derived from its UUID if the panel doesn't send it.
+func shortUUID(server map[string]any, uuid string) string {
+	if identifier, ok := serverField(server, "identifier").(string); ok && identifier != "" {
+		return identifier
+	}
+	if len(uuid) >= shortUUIDLength {
+		return uuid[:shortUUIDLength]
+	}
+	return uuid
+}
+
+// serverField reads a field from the root of a server or from its attributes.
+func serverField(server map[string]any, key string) any {
+	if val, ok := server[key]; ok {
+		return val
+	}
+	if attrs, ok := server["attributes"].(map[string]any); ok {
+		return attrs[key]
+	}
+	return nil
+}
+
+// identityString formats an identifier, printing JSON numbers as integers.
+func identityString(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
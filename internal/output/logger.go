@@ -1,18 +1,51 @@
 package output
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"log/slog"
 	"os"
+	"strings"
+
+	"go.lostcrafters.com/pelicanctl/internal/strict"
 )
 
+// RequestIDEnvVar passes the request ID on to the commands pelicanctl runs, plugins and hooks, so the
+// pelicanctl commands they run in turn log under the same ID.
+const RequestIDEnvVar = "PELICANCTL_REQUEST_ID"
+
+// RequestIDHeader carries the request ID in every request, to find the requests of a command in the
+// logs of the panel or a proxy.
+const RequestIDHeader = "X-Request-ID"
+
 var (
 	globalLogger *slog.Logger
 	logLevel     slog.Level
+
+	//nolint:gochecknoglobals // One ID correlates every log record and request of the command run
+	requestID = newRequestID()
 )
 
-// InitLogger initializes the global logger.
+// newRequestID returns the ID of PELICANCTL_REQUEST_ID, or else a random one.
+func newRequestID() string {
+	if id := strings.TrimSpace(os.Getenv(RequestIDEnvVar)); id != "" {
+		return id
+	}
+	const idBytes = 8
+	id := make([]byte, idBytes)
+	_, _ = rand.Read(id) // Never fails, see crypto/rand.Read
+	return hex.EncodeToString(id)
+}
+
+// RequestID returns the correlation ID of the command run, which every log record carries as request_id.
+func RequestID() string {
+	return requestID
+}
+
+// InitLogger initializes the global logger. Records carry the request ID of the command run, and are JSON
+// objects for the JSON output formats, so the logs of bulk runs can be parsed and told apart.
 func InitLogger(verbose bool, quiet bool, outputFormat OutputFormat, writer io.Writer) {
 	if writer == nil {
 		writer = os.Stderr
@@ -30,7 +63,7 @@ func InitLogger(verbose bool, quiet bool, outputFormat OutputFormat, writer io.W
 
 	// Create handler based on output format
 	var handler slog.Handler
-	if outputFormat == OutputFormatJSON {
+	if outputFormat.IsJSON() {
 		handler = slog.NewJSONHandler(writer, &slog.HandlerOptions{
 			Level: logLevel,
 		})
@@ -40,7 +73,7 @@ func InitLogger(verbose bool, quiet bool, outputFormat OutputFormat, writer io.W
 		})
 	}
 
-	globalLogger = slog.New(handler)
+	globalLogger = slog.New(handler).With("request_id", requestID)
 }
 
 // GetLogger returns the global logger.
@@ -64,6 +97,7 @@ func LogInfo(msg string, args ...any) {
 
 // LogWarn logs a warning message.
 func LogWarn(msg string, args ...any) {
+	strict.Warn(msg)
 	GetLogger().Warn(msg, args...)
 }
 
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Porcelain output (-o porcelain) is the output contract for scripts, kept stable across versions: stdout
+// holds nothing but identifiers, one per line, of the resources a command created, changed, deleted or
+// listed, and of the items its bulk operations succeeded on. Messages, warnings and summaries go to stderr.
+
+// porcelainKeys are the fields identifying a resource in porcelain output, in order of preference.
+//
+//nolint:gochecknoglobals // Static lookup order
+var porcelainKeys = []string{"uuid", "id", "identifier", "name"}
+
+// printPorcelain prints the identifiers of the resources in data. The data is read in its JSON form, with
+// panel resources unwrapped to their attributes, like for go-template output.
+func (f *Formatter) printPorcelain(data any) error {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode porcelain data: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(jsonBytes, &value); err != nil {
+		return fmt.Errorf("failed to decode porcelain data: %w", err)
+	}
+	for _, id := range porcelainIDs(unwrapAttributes(value)) {
+		if _, err := fmt.Fprintln(f.writer, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintAffected prints the identifier of a resource the command created, changed or deleted without
+// printing the resource itself, in porcelain output. Other formats tell of it in their success messages.
+func (f *Formatter) PrintAffected(id string) {
+	if f.format == OutputFormatPorcelain && id != "" {
+		_, _ = fmt.Fprintln(f.writer, id)
+	}
+}
+
+// porcelainIDs returns the identifiers of a resource, of the resources of a list, or of a panel list.
+func porcelainIDs(value any) []string {
+	switch v := value.(type) {
+	case []any:
+		var ids []string
+		for _, item := range v {
+			if m, ok := item.(map[string]any); ok {
+				if id, found := porcelainID(m); found {
+					ids = append(ids, id)
+				}
+			}
+		}
+		return ids
+	case map[string]any:
+		if items, ok := v["data"].([]any); ok {
+			return porcelainIDs(unwrapAttributes(items))
+		}
+		if id, found := porcelainID(v); found {
+			return []string{id}
+		}
+	}
+	return nil
+}
+
+// porcelainID returns the identifier of a resource: its first non-empty field of porcelainKeys.
+func porcelainID(resource map[string]any) (string, bool) {
+	for _, key := range porcelainKeys {
+		switch id := resource[key].(type) {
+		case string:
+			if id != "" {
+				return id, true
+			}
+		case float64:
+			return strconv.FormatFloat(id, 'f', -1, 64), true
+		}
+	}
+	return "", false
+}
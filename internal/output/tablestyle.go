@@ -0,0 +1,198 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"golang.org/x/term"
+)
+
+// TableStyle selects how tables are drawn.
+type TableStyle string
+
+// Table styles.
+const (
+	// TableStylePlain aligns columns with spaces alone, without borders or colors.
+	TableStylePlain TableStyle = "plain"
+	// TableStyleLight draws borders with box-drawing characters, without colors.
+	TableStyleLight TableStyle = "light"
+	// TableStyleColored draws ASCII borders and a colored header and rows, the default.
+	TableStyleColored TableStyle = "colored"
+	// TableStyleMarkdown prints tables as Markdown, for pasting into issues and docs.
+	TableStyleMarkdown TableStyle = "markdown"
+)
+
+// minColumnWidth is the narrowest a column is wrapped to so a table fits the terminal.
+const minColumnWidth = 8
+
+// TableStyles lists the valid table styles.
+//
+//nolint:gochecknoglobals // Fixed list shared by config validation and completion
+var TableStyles = []string{
+	string(TableStylePlain), string(TableStyleLight), string(TableStyleColored), string(TableStyleMarkdown),
+}
+
+var (
+	//nolint:gochecknoglobals // Table style is process-wide, set up once by the root command
+	tableStyle = TableStyleColored
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the table style
+	tableStyleMutex sync.Mutex
+)
+
+// ParseTableStyle validates a table style name.
+func ParseTableStyle(s string) (TableStyle, error) {
+	style := TableStyle(strings.ToLower(strings.TrimSpace(s)))
+	switch style {
+	case TableStylePlain, TableStyleLight, TableStyleColored, TableStyleMarkdown:
+		return style, nil
+	default:
+		return "", fmt.Errorf("invalid table style: %s (must be one of %s)", s, strings.Join(TableStyles, ", "))
+	}
+}
+
+// SetTableStyle sets how tables are drawn.
+func SetTableStyle(style TableStyle) {
+	tableStyleMutex.Lock()
+	defer tableStyleMutex.Unlock()
+	tableStyle = style
+}
+
+// currentTableStyle returns how tables are drawn.
+func currentTableStyle() TableStyle {
+	tableStyleMutex.Lock()
+	defer tableStyleMutex.Unlock()
+	return tableStyle
+}
+
+// applyTableStyle sets up a table writer for a style.
+func applyTableStyle(t table.Writer, style TableStyle, colored bool) {
+	switch style {
+	case TableStylePlain:
+		t.SetStyle(table.StyleLight)
+		t.Style().Options = table.OptionsNoBordersAndSeparators
+		t.Style().Options.SeparateColumns = true
+		t.Style().Box.PaddingLeft = ""
+		t.Style().Box.PaddingRight = ""
+		t.Style().Box.MiddleVertical = "   "
+	case TableStyleLight:
+		t.SetStyle(table.StyleLight)
+	default:
+		t.SetStyle(table.StyleColoredBright)
+		t.Style().Options.SeparateRows = false
+		t.Style().Options.DrawBorder = true
+		t.Style().Options.SeparateColumns = true
+		if !colored {
+			t.Style().Color = table.ColorOptions{}
+		}
+	}
+}
+
+// fitToWidth wraps the widest columns of a table until its rows fit in width, so long rows don't wrap
+// across the terminal. Columns are first wrapped at spaces only, keeping words such as UUIDs whole, and
+// only if that's not enough are words split too, leaving no column narrower than minColumnWidth. Words
+// are never split in a table that doesn't fit either way.
+func fitToWidth(t table.Writer, headers table.Row, rows []table.Row, width int) {
+	widths := make([]int, len(headers))
+	longestWords := make([]int, len(headers))
+	measure := func(row table.Row) {
+		for i, cell := range row {
+			if i >= len(widths) {
+				break
+			}
+			for line := range strings.SplitSeq(fmt.Sprint(cell), "\n") {
+				widths[i] = max(widths[i], text.RuneWidthWithoutEscSequences(line))
+				for _, word := range strings.Fields(line) {
+					longestWords[i] = max(longestWords[i], text.RuneWidthWithoutEscSequences(word))
+				}
+			}
+		}
+	}
+	measure(headers)
+	for _, row := range rows {
+		measure(row)
+	}
+
+	natural := slices.Clone(widths)
+	excess := tableOverhead(t.Style(), len(widths)) - width
+	for _, w := range widths {
+		excess += w
+	}
+	// Words of the first column, which identifies the row, are never split.
+	wordFloors := make([]int, len(widths))
+	splitFloors := make([]int, len(widths))
+	for i, longest := range longestWords {
+		wordFloors[i] = max(longest, minColumnWidth)
+		splitFloors[i] = minColumnWidth
+	}
+	if len(widths) > 0 {
+		splitFloors[0] = wordFloors[0]
+	}
+	excess = shrinkColumns(widths, wordFloors, excess)
+	if excess > 0 {
+		split := slices.Clone(widths)
+		if shrinkColumns(split, splitFloors, excess) <= 0 {
+			widths = split
+		}
+	}
+
+	var configs []table.ColumnConfig
+	for i, w := range widths {
+		if w < natural[i] {
+			configs = append(configs, table.ColumnConfig{Number: i + 1, WidthMax: w, WidthMaxEnforcer: text.WrapSoft})
+		}
+	}
+	t.SetColumnConfigs(configs)
+}
+
+// shrinkColumns narrows the widest of the columns, none below its floor, until excess is gone, and
+// returns the excess left.
+func shrinkColumns(widths, floors []int, excess int) int {
+	for excess > 0 {
+		widest := -1
+		for i, w := range widths {
+			if w > floors[i] && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		excess--
+	}
+	return excess
+}
+
+// tableOverhead returns the width of the borders, separators and padding of a table with n columns.
+func tableOverhead(style *table.Style, n int) int {
+	box := style.Box
+	overhead := n * (text.RuneWidthWithoutEscSequences(box.PaddingLeft) +
+		text.RuneWidthWithoutEscSequences(box.PaddingRight))
+	if style.Options.SeparateColumns && n > 1 {
+		overhead += (n - 1) * text.RuneWidthWithoutEscSequences(box.MiddleVertical)
+	}
+	if style.Options.DrawBorder {
+		overhead += text.RuneWidthWithoutEscSequences(box.Left) + text.RuneWidthWithoutEscSequences(box.Right)
+	}
+	return overhead
+}
+
+// terminalWidth returns the width of the terminal w is, 0 if it isn't one.
+func terminalWidth(w io.Writer) int {
+	file, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+	width, _, err := term.GetSize(int(file.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// goTemplatePrefix introduces the template given to --output.
+const goTemplatePrefix = "go-template="
+
+var (
+	//nolint:gochecknoglobals // Output template is process-wide, set up once by the root command
+	outputTemplate *template.Template
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the output template
+	outputTemplateMutex sync.Mutex
+)
+
+// ParseOutputFlag maps a value of --output to its format. For go-template=<template> it also
+// returns the parsed template. An empty value is the table format.
+func ParseOutputFlag(value string) (OutputFormat, *template.Template, error) {
+	if text, ok := strings.CutPrefix(value, goTemplatePrefix); ok {
+		tmpl, err := template.New("output").Funcs(template.FuncMap{"json": templateJSON}).Parse(text)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid go-template: %w", err)
+		}
+		return OutputFormatGoTemplate, tmpl, nil
+	}
+
+	switch format := OutputFormat(value); format {
+	case "":
+		return OutputFormatTable, nil, nil
+	case OutputFormatTable, OutputFormatWide, OutputFormatJSON, OutputFormatNDJSON, OutputFormatYAML,
+		OutputFormatCSV, OutputFormatTSV, OutputFormatPorcelain:
+		return format, nil, nil
+	default:
+		return "", nil, fmt.Errorf(
+			"invalid output format: %s (must be one of table, wide, json, ndjson, yaml, csv, tsv, porcelain, "+
+				"%s<template>)", value, goTemplatePrefix)
+	}
+}
+
+// SetOutputTemplate sets the template printed by the go-template format.
+func SetOutputTemplate(tmpl *template.Template) {
+	outputTemplateMutex.Lock()
+	defer outputTemplateMutex.Unlock()
+	outputTemplate = tmpl
+}
+
+// currentOutputTemplate returns the template printed by the go-template format.
+func currentOutputTemplate() *template.Template {
+	outputTemplateMutex.Lock()
+	defer outputTemplateMutex.Unlock()
+	return outputTemplate
+}
+
+// printTemplate executes the output template on data. The data is passed as its JSON form, so fields
+// are named as in JSON output, except that panel resources are unwrapped to their attributes:
+// {{range .}}{{.uuid}}{{end}} works on server lists.
+func (f *Formatter) printTemplate(data any) error {
+	tmpl := currentOutputTemplate()
+	if tmpl == nil {
+		return fmt.Errorf("output format %s needs a template (%s<template>)", OutputFormatGoTemplate, goTemplatePrefix)
+	}
+
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode template data: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(jsonBytes, &value); err != nil {
+		return fmt.Errorf("failed to decode template data: %w", err)
+	}
+
+	if err := tmpl.Execute(f.writer, unwrapAttributes(value)); err != nil {
+		return fmt.Errorf("failed to execute go-template: %w", err)
+	}
+	return nil
+}
+
+// unwrapAttributes replaces panel resources ({"object": ..., "attributes": {...}}) by their attributes,
+// in a list or on their own.
+func unwrapAttributes(value any) any {
+	switch v := value.(type) {
+	case []any:
+		for i, item := range v {
+			v[i] = unwrapAttributes(item)
+		}
+		return v
+	case map[string]any:
+		if attrs, ok := v["attributes"].(map[string]any); ok {
+			if _, isResource := v["object"]; isResource {
+				return attrs
+			}
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// templateJSON is the json template function, which encodes a value as JSON.
+func templateJSON(value any) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,96 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeLayout is how exact times print in tables and details, in the output time zone.
+const timeLayout = "2006-01-02 15:04:05 MST"
+
+var (
+	//nolint:gochecknoglobals // Time zone is process-wide, set up once by the root command
+	timeZone = time.Local
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the time zone
+	timeZoneMutex sync.Mutex
+)
+
+// SetTimeZone sets the time zone times print in, for --timezone and --utc. The default is the local one.
+func SetTimeZone(loc *time.Location) {
+	timeZoneMutex.Lock()
+	defer timeZoneMutex.Unlock()
+	timeZone = loc
+}
+
+// currentTimeZone returns the time zone times print in.
+func currentTimeZone() *time.Location {
+	timeZoneMutex.Lock()
+	defer timeZoneMutex.Unlock()
+	return timeZone
+}
+
+// FormatTime formats an exact time in the output time zone, as RFC 3339 in CSV and TSV.
+func (f *Formatter) FormatTime(t time.Time) string {
+	t = t.In(currentTimeZone())
+	if f.format.Delimited() {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format(timeLayout)
+}
+
+// RelativeTime formats a timestamp of the panel relative to now, e.g. "3h12m ago" or "in 5m", for table
+// cells. CSV and TSV get the exact time instead, and values that aren't timestamps print as they are.
+func (f *Formatter) RelativeTime(val any) string {
+	t, ok := parseTime(val)
+	if !ok {
+		return f.formatValue(val)
+	}
+	if f.format.Delimited() {
+		return f.FormatTime(t)
+	}
+	return relativeTime(t, time.Now())
+}
+
+// ExactTime formats a timestamp of the panel in the output time zone followed by how long ago it was,
+// e.g. "2026-10-16 09:30:00 CEST (3h12m ago)". Values that aren't timestamps print as they are.
+func (f *Formatter) ExactTime(val any) string {
+	t, ok := parseTime(val)
+	if !ok {
+		return f.formatDetailValue(val)
+	}
+	if f.format.Delimited() {
+		return f.FormatTime(t)
+	}
+	return fmt.Sprintf("%s (%s)", f.FormatTime(t), relativeTime(t, time.Now()))
+}
+
+// relativeTime formats how long before or after now t is.
+func relativeTime(t, now time.Time) string {
+	if t.After(now) {
+		return "in " + HumanDuration(t.Sub(now))
+	}
+	return HumanDuration(now.Sub(t)) + " ago"
+}
+
+// parseTime reads a timestamp of the panel: an RFC 3339 string, or a time.Time.
+func parseTime(val any) (time.Time, bool) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// isTimeField reports whether a field holds a timestamp by its name: created_at, checked_at, timestamp
+// and the like.
+func isTimeField(field string) bool {
+	name := field[strings.LastIndex(field, ".")+1:]
+	return strings.HasSuffix(name, "_at") || name == "timestamp"
+}
@@ -0,0 +1,153 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// bytesPerKiB is the step between binary size units.
+const bytesPerKiB = 1024
+
+// hoursPerDay splits long durations into days.
+const hoursPerDay = 24
+
+//nolint:gochecknoglobals // Set once by the root command from --raw-units
+var rawUnits atomic.Bool
+
+// SetRawUnits turns off humanized units, for --raw-units: values print as the panel reports them.
+func SetRawUnits(raw bool) {
+	rawUnits.Store(raw)
+}
+
+// HumanBytes formats a number of bytes with a binary unit, e.g. "3.8 GiB".
+func HumanBytes(val any) string {
+	v, ok := val.(float64)
+	if !ok || rawUnits.Load() {
+		return rawNumber(val)
+	}
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	unit := 0
+	for v >= bytesPerKiB && unit < len(units)-1 {
+		v /= bytesPerKiB
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", v, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", v, units[unit])
+}
+
+// HumanMiB formats a number of MiB, the unit of the limits of the panel, with a binary unit.
+func HumanMiB(val any) string {
+	v, ok := val.(float64)
+	if !ok || rawUnits.Load() {
+		return rawNumber(val)
+	}
+	return HumanBytes(v * bytesPerKiB * bytesPerKiB)
+}
+
+// HumanPercent formats a percentage such as the CPU usage of a server, where 100% is one core.
+func HumanPercent(val any) string {
+	v, ok := val.(float64)
+	if !ok || rawUnits.Load() {
+		return rawNumber(val)
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64) + "%"
+}
+
+// HumanMillis formats a number of milliseconds, such as the uptime of a server, as a duration.
+func HumanMillis(val any) string {
+	v, ok := val.(float64)
+	if !ok || rawUnits.Load() {
+		return rawNumber(val)
+	}
+	return HumanDuration(time.Duration(v) * time.Millisecond)
+}
+
+// HumanDuration formats a duration in its two largest units, e.g. "2d4h", "3h12m" or "42s".
+func HumanDuration(d time.Duration) string {
+	const day = hoursPerDay * time.Hour
+	d = d.Round(time.Second)
+	days := int64(d / day)
+	hours := int64(d % day / time.Hour)
+	minutes := int64(d % time.Hour / time.Minute)
+	seconds := int64(d % time.Minute / time.Second)
+	switch {
+	case days > 0:
+		return joinUnits(days, "d", hours, "h")
+	case hours > 0:
+		return joinUnits(hours, "h", minutes, "m")
+	case minutes > 0:
+		return joinUnits(minutes, "m", seconds, "s")
+	default:
+		return strconv.FormatInt(seconds, 10) + "s"
+	}
+}
+
+// joinUnits formats an amount of a unit followed by one of the next smaller unit, left out when zero.
+func joinUnits(major int64, majorUnit string, minor int64, minorUnit string) string {
+	s := strconv.FormatInt(major, 10) + majorUnit
+	if minor > 0 {
+		s += strconv.FormatInt(minor, 10) + minorUnit
+	}
+	return s
+}
+
+// rawNumber formats a value as the panel reports it.
+func rawNumber(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// humanizeField formats the value of a field with a known unit: byte counts (*_bytes), the CPU usage of
+// servers (cpu_absolute) and their uptime in milliseconds. CSV and TSV, which are read by programs, keep
+// the numbers as they are, as does --raw-units.
+func (f *Formatter) humanizeField(field string, val any) (string, bool) {
+	if _, ok := val.(float64); !ok || rawUnits.Load() || f.format.Delimited() {
+		return "", false
+	}
+	name := field[strings.LastIndex(field, ".")+1:]
+	switch {
+	case strings.HasSuffix(name, "_bytes"):
+		return HumanBytes(val), true
+	case name == "cpu_absolute":
+		return HumanPercent(val), true
+	case name == "uptime":
+		return HumanMillis(val), true
+	default:
+		return "", false
+	}
+}
+
+// formatField formats the value of a field for a table cell, with its unit humanized and timestamps
+// relative to now.
+func (f *Formatter) formatField(field string, val any) string {
+	if isTimeField(field) {
+		return f.RelativeTime(val)
+	}
+	if s, ok := f.humanizeField(field, val); ok {
+		return s
+	}
+	return f.formatValue(val)
+}
+
+// formatDetailField formats the value of a field for detail view, with its unit humanized and timestamps
+// in the output time zone.
+func (f *Formatter) formatDetailField(field string, val any) string {
+	if isTimeField(field) {
+		return f.ExactTime(val)
+	}
+	if s, ok := f.humanizeField(field, val); ok {
+		return s
+	}
+	return f.formatDetailValue(val)
+}
@@ -0,0 +1,41 @@
+package output
+
+import (
+	"encoding/json"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// yamlIndent is the indentation of nested YAML blocks.
+const yamlIndent = 2
+
+// printYAML prints data as YAML. The data is passed as its JSON form, so fields are named and ordered
+// as in JSON output.
+func (f *Formatter) printYAML(data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	// JSON is YAML, so decoding it into a node keeps the order of struct fields.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+
+	encoder := yaml.NewEncoder(f.writer)
+	encoder.SetIndent(yamlIndent)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quoting a node has from JSON, so it prints as idiomatic YAML.
+// Strings that would read as another type stay quoted.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
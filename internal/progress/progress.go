@@ -0,0 +1,188 @@
+// Package progress emits machine-readable progress events for long operations.
+//
+// Events are written as JSON lines to a file descriptor chosen with --progress-fd, so GUIs and
+// bots wrapping pelicanctl can show progress without parsing human output. Without --progress-fd
+// every function in this package is a no-op.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	// TypeStart is emitted when a bulk run begins, with the number of operations in Total.
+	TypeStart = "start"
+	// TypeItem is emitted when one operation of a bulk run finishes.
+	TypeItem = "item"
+	// TypeDone is emitted when a bulk run finishes.
+	TypeDone = "done"
+	// TypeTransfer is emitted periodically while bytes are downloaded or uploaded.
+	TypeTransfer = "transfer"
+)
+
+// transferInterval is the minimum time between two transfer events for the same stream.
+const transferInterval = 250 * time.Millisecond
+
+// Event is one progress event.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command,omitempty"`
+	Type    string    `json:"type"`
+
+	// ID identifies the operation or transferred file.
+	ID string `json:"id,omitempty"`
+	// Status is "success", "error" or "cancelled" for items, and "running" or "complete" for transfers.
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	Completed int `json:"completed,omitempty"`
+	Total     int `json:"total,omitempty"`
+	// Succeeded and Failed are only set on done events, where zero counts matter.
+	Succeeded *int `json:"succeeded,omitempty"`
+	Failed    *int `json:"failed,omitempty"`
+
+	// Direction is "download" or "upload" for transfers.
+	Direction  string `json:"direction,omitempty"`
+	Bytes      int64  `json:"bytes,omitempty"`
+	TotalBytes int64  `json:"total_bytes,omitempty"`
+}
+
+var (
+	//nolint:gochecknoglobals // Progress sink is process-wide, set up once by the root command
+	sink io.WriteCloser
+
+	//nolint:gochecknoglobals // Command path included in every event
+	command string
+
+	//nolint:gochecknoglobals // Global mutex needed to keep events from interleaving
+	sinkMutex sync.Mutex
+)
+
+// Open starts writing events to the file descriptor fd. commandPath is included in every event.
+func Open(fd int, commandPath string) error {
+	file := os.NewFile(uintptr(fd), fmt.Sprintf("progress-fd-%d", fd))
+	if file == nil {
+		return fmt.Errorf("invalid progress file descriptor: %d", fd)
+	}
+	if _, err := file.Stat(); err != nil {
+		return fmt.Errorf("progress file descriptor %d is not open: %w", fd, err)
+	}
+
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	sink = file
+	command = commandPath
+	return nil
+}
+
+// Close stops writing events and closes the file descriptor.
+func Close() error {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	if sink == nil {
+		return nil
+	}
+	err := sink.Close()
+	sink = nil
+	return err
+}
+
+// Enabled reports whether events are being written.
+func Enabled() bool {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	return sink != nil
+}
+
+// Emit writes an event, filling in its time and command. Write errors are ignored so a
+// consumer that goes away never breaks the operation itself.
+func Emit(event Event) {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	if sink == nil {
+		return
+	}
+
+	event.Time = time.Now().UTC()
+	event.Command = command
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = sink.Write(append(line, '\n'))
+}
+
+// Start emits the start of a bulk run of total operations.
+func Start(total int) {
+	Emit(Event{Type: TypeStart, Total: total})
+}
+
+// Item emits the result of one operation of a bulk run.
+func Item(id, status string, err error, completed, total int) {
+	event := Event{Type: TypeItem, ID: id, Status: status, Completed: completed, Total: total}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	Emit(event)
+}
+
+// Done emits the end of a bulk run.
+func Done(succeeded, failed int) {
+	Emit(Event{Type: TypeDone, Total: succeeded + failed, Succeeded: &succeeded, Failed: &failed})
+}
+
+// NewReader wraps r so reading from it emits transfer events for id. total is the expected
+// size in bytes, or a value <= 0 if unknown. r is returned unchanged when events are disabled.
+func NewReader(r io.ReadCloser, direction, id string, total int64) io.ReadCloser {
+	if !Enabled() {
+		return r
+	}
+	if total < 0 {
+		total = 0
+	}
+	return &transferReader{ReadCloser: r, direction: direction, id: id, total: total}
+}
+
+// transferReader counts the bytes read through it and reports them as transfer events.
+type transferReader struct {
+	io.ReadCloser
+
+	direction string
+	id        string
+	total     int64
+	read      int64
+	lastEmit  time.Time
+	finished  bool
+}
+
+func (t *transferReader) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	t.read += int64(n)
+
+	switch {
+	case err == io.EOF && !t.finished: //nolint:errorlint // Read returns io.EOF itself, never wrapped
+		t.finished = true
+		t.emit("complete")
+	case err == nil && time.Since(t.lastEmit) >= transferInterval:
+		t.emit("running")
+	}
+	return n, err
+}
+
+func (t *transferReader) emit(status string) {
+	t.lastEmit = time.Now()
+	Emit(Event{
+		Type:       TypeTransfer,
+		ID:         t.id,
+		Status:     status,
+		Direction:  t.direction,
+		Bytes:      t.read,
+		TotalBytes: t.total,
+	})
+}
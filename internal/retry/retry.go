@@ -0,0 +1,208 @@
+// Package retry retries API requests that failed on rate limits, panel errors and network errors.
+//
+// Requests are retried with exponential backoff and jitter. A 429 or 503 response with a
+// Retry-After header is retried once the panel asks for, so bulk runs against large fleets slow
+// down instead of failing on rate limits.
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+const (
+	// DefaultRetries is how often a failed request is retried unless --retries says otherwise.
+	DefaultRetries = 3
+	// DefaultDelay is the backoff before the first retry unless --retry-delay says otherwise.
+	DefaultDelay = 500 * time.Millisecond
+	// maxDelay caps the backoff between two attempts.
+	maxDelay = 30 * time.Second
+	// maxRetryAfter caps how long a Retry-After header makes a request wait.
+	maxRetryAfter = 5 * time.Minute
+	// drainLimit bounds how much of a discarded response body is read to reuse its connection.
+	drainLimit = 64 * 1024
+)
+
+// Policy is how failed requests are retried.
+type Policy struct {
+	// Retries is the number of retries after the first attempt; 0 disables retrying.
+	Retries int
+	// Delay is the backoff before the first retry, doubled for every further one.
+	Delay time.Duration
+}
+
+var (
+	//nolint:gochecknoglobals // Retry policy is process-wide, set up once by the root command
+	policy = Policy{Retries: DefaultRetries, Delay: DefaultDelay}
+
+	//nolint:gochecknoglobals // Global mutex needed to protect the retry policy
+	policyMutex sync.RWMutex
+)
+
+// SetPolicy sets how failed requests are retried.
+func SetPolicy(p Policy) {
+	policyMutex.Lock()
+	defer policyMutex.Unlock()
+	policy = p
+}
+
+func currentPolicy() Policy {
+	policyMutex.RLock()
+	defer policyMutex.RUnlock()
+	return policy
+}
+
+// Transport is an http.RoundTripper that retries failed requests according to the policy.
+//
+// Rate limits (429) and unavailable panels (503) are retried for every request, as the panel did
+// not handle them. Other 5xx responses and network errors are only retried for idempotent methods,
+// so a power action or backup is never run twice. Requests whose body cannot be replayed are never retried.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p := currentPolicy()
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.Base.RoundTrip(attemptReq)
+		if attempt >= p.Retries || !retryable(req, resp, err) {
+			return resp, err //nolint:wrapcheck // Transport must pass errors through unchanged
+		}
+
+		wait := backoff(p.Delay, attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = after
+			}
+			discard(resp)
+		}
+		output.LogDebug("retrying request",
+			"method", req.Method, "url", req.URL.Redacted(), "attempt", attempt+1, "wait", wait,
+			"reason", reason(resp, err))
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rewind returns the request for an attempt, with a fresh body for every retry.
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Transport must pass errors through unchanged
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// retryable reports whether the outcome of an attempt is worth another one.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		// Canceled and timed out commands are not retried; other failures are network errors.
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotent(req.Method)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return idempotent(req.Method)
+	}
+	return false
+}
+
+// idempotent reports whether sending a request with method twice has the effect of sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns the wait before retry attempt+1: delay doubled per attempt, capped at maxDelay,
+// with jitter so parallel requests of a bulk run don't retry in lockstep.
+func backoff(delay time.Duration, attempt int) time.Duration {
+	wait := delay
+	for range attempt {
+		if wait >= maxDelay {
+			break
+		}
+		wait *= 2
+	}
+	wait = min(wait, maxDelay)
+	if wait <= 0 {
+		return 0
+	}
+	// Equal jitter: somewhere between half and all of the backoff.
+	half := wait / 2
+	return half + rand.N(half+1) //nolint:gosec // Jitter needs no cryptographic randomness
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP date, capped at maxRetryAfter.
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		wait = date.Sub(now)
+	} else {
+		return 0, false
+	}
+	return min(max(wait, 0), maxRetryAfter), true
+}
+
+// discard drains and closes the body of a response that is retried, so its connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, drainLimit)
+	_ = resp.Body.Close()
+}
+
+// sleep waits for d, or returns the error of ctx if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reason describes why an attempt failed, for the log.
+func reason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
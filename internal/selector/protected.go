@@ -0,0 +1,73 @@
+package selector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/api"
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// AddProtectedFlag adds the flag that lets destructive actions include protected servers.
+func AddProtectedFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("include-protected", false, "also act on servers marked as protected in the config")
+}
+
+// SkipProtected drops servers marked as protected in the config from destructive actions, with a warning
+// for each one, unless --include-protected is given. Servers are looked up through client, so a server
+// the config protects by UUID, short identifier, ID or name is skipped whichever of them it is given by.
+func SkipProtected(
+	ctx context.Context,
+	cmd *cobra.Command,
+	formatter *output.Formatter,
+	client api.ServerAPI,
+	action string,
+	servers []string,
+) []string {
+	if include, _ := cmd.Flags().GetBool("include-protected"); include {
+		return servers
+	}
+	allowed, skipped := config.Get().SplitProtected(action, servers, func(server string) []string {
+		return serverIdentifiers(ctx, client, server)
+	})
+	for _, server := range skipped {
+		formatter.PrintWarning("Skipping protected server %s (use --include-protected to %s it)", server, action)
+	}
+	return allowed
+}
+
+// CheckProtected refuses a destructive action on a single server marked as protected in the config, unless
+// --include-protected is given. The server is looked up like in SkipProtected.
+func CheckProtected(ctx context.Context, cmd *cobra.Command, client api.ServerAPI, action, server string) error {
+	if include, _ := cmd.Flags().GetBool("include-protected"); include {
+		return nil
+	}
+	if _, skipped := config.Get().SplitProtected(action, []string{server}, func(server string) []string {
+		return serverIdentifiers(ctx, client, server)
+	}); len(skipped) == 0 {
+		return nil
+	}
+	return apierrors.WithExitCode(apierrors.ExitValidation,
+		fmt.Errorf("server %s is protected; use --include-protected to %s it", server, action))
+}
+
+// serverIdentifiers returns every identifier of a server: the one it was given by and, when the server
+// index knows it, its UUID, short identifier, ID and name. A server that can't be looked up is only known
+// by the identifier given; the action itself reports that it doesn't exist.
+func serverIdentifiers(ctx context.Context, client api.ServerAPI, server string) []string {
+	identifiers := []string{server}
+	entry, err := api.LookupServer(ctx, client, server)
+	if err != nil {
+		return identifiers
+	}
+	for _, identifier := range []string{entry.UUID, entry.Identifier, entry.ID, entry.Name} {
+		if identifier != "" {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	return identifiers
+}
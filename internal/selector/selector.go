@@ -0,0 +1,158 @@
+// Package selector picks the servers of bulk operations from their arguments, --all, --from-file,
+// and by name pattern, node, owner and group, so subsets of a fleet can be targeted without
+// maintaining --from-file lists.
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
+)
+
+// Selector selects servers from the server index. Every criterion that is set must match.
+type Selector struct {
+	// Globs are shell patterns matched against the server name; a server matching any of them is selected.
+	Globs []string
+	// Regex is matched against the server name.
+	Regex *regexp.Regexp
+	// Node is the ID or name of the node the server runs on.
+	Node string
+	// Owner is the ID of the user that owns the server.
+	Owner string
+	// Group lists the UUIDs, IDs or names of the servers in the group given with --group.
+	Group []string
+}
+
+// AddFlags adds the selector flags to a bulk command. --owner is only added with withOwner,
+// as only the Application API tells who owns a server.
+func AddFlags(cmd *cobra.Command, withOwner bool) {
+	cmd.Flags().StringArray("match", nil, "select servers whose name matches this glob, e.g. 'smp-*' (repeatable)")
+	cmd.Flags().String("match-regex", "", "select servers whose name matches this regular expression")
+	cmd.Flags().String("node", "", "select servers on this node, by ID or name")
+	cmd.Flags().String("group", "", "select the servers of a group created with 'pelicanctl group create'")
+	if withOwner {
+		cmd.Flags().String("owner", "", "select servers owned by this user, by ID, username or email")
+	}
+}
+
+// flagNames are the selector flags, for Given.
+//
+//nolint:gochecknoglobals // Static list of flag names
+var flagNames = []string{"match", "match-regex", "node", "owner", "group"}
+
+// Given reports whether any selector flag but the except ones was given to cmd.
+func Given(cmd *cobra.Command, except ...string) bool {
+	for _, name := range flagNames {
+		if slices.Contains(except, name) {
+			continue
+		}
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// FromFlags builds the selector of the flags added by AddFlags. The owner is taken as given;
+// callers resolve usernames and emails to an ID.
+func FromFlags(cmd *cobra.Command) (Selector, error) {
+	globs, _ := cmd.Flags().GetStringArray("match")
+	pattern, _ := cmd.Flags().GetString("match-regex")
+	node, _ := cmd.Flags().GetString("node")
+	owner, _ := cmd.Flags().GetString("owner")
+	group, _ := cmd.Flags().GetString("group")
+
+	sel := Selector{Node: node, Owner: owner}
+	if group != "" {
+		members, ok := config.Get().GroupMembers(group)
+		if !ok {
+			return Selector{}, fmt.Errorf("group %q not configured. Create it with 'pelicanctl group create'", group)
+		}
+		sel.Group = members
+	}
+	for _, glob := range globs {
+		// Matching against an empty name is enough to reject a malformed pattern.
+		if _, err := path.Match(glob, ""); err != nil {
+			return Selector{}, fmt.Errorf("invalid --match pattern %q: %w", glob, err)
+		}
+		sel.Globs = append(sel.Globs, strings.ToLower(glob))
+	}
+	if pattern != "" {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return Selector{}, fmt.Errorf("invalid --match-regex: %w", err)
+		}
+		sel.Regex = regex
+	}
+	return sel, nil
+}
+
+// Empty reports whether no criterion is set, i.e. the command selects no servers by flags.
+func (s Selector) Empty() bool {
+	return len(s.Globs) == 0 && s.Regex == nil && s.Node == "" && s.Owner == "" && s.Group == nil
+}
+
+// Matches reports whether a server meets every criterion of the selector.
+// Globs match the name case-insensitively.
+func (s Selector) Matches(entry index.Entry) bool {
+	if len(s.Globs) > 0 {
+		name := strings.ToLower(entry.Name)
+		matched := false
+		for _, glob := range s.Globs {
+			if ok, _ := path.Match(glob, name); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if s.Regex != nil && !s.Regex.MatchString(entry.Name) {
+		return false
+	}
+	if s.Node != "" && entry.Node != s.Node && !strings.EqualFold(entry.NodeName, s.Node) {
+		return false
+	}
+	if s.Owner != "" && entry.Owner != s.Owner {
+		return false
+	}
+	if s.Group != nil && !slices.ContainsFunc(s.Group, func(member string) bool { return isEntry(entry, member) }) {
+		return false
+	}
+	return true
+}
+
+// isEntry reports whether a group member refers to entry, by UUID, ID, short identifier or name.
+func isEntry(entry index.Entry, member string) bool {
+	for _, identifier := range []string{entry.UUID, entry.ID, entry.Identifier, entry.Name} {
+		if identifier != "" && strings.EqualFold(identifier, member) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the UUIDs of the servers that match, in the order of entries.
+// It fails if none do, as a bulk command should not silently do nothing.
+func (s Selector) Select(entries []index.Entry) ([]string, error) {
+	var uuids []string
+	for _, entry := range entries {
+		if s.Matches(entry) {
+			uuids = append(uuids, entry.UUID)
+		}
+	}
+	if len(uuids) == 0 {
+		return nil, apierrors.WithExitCode(apierrors.ExitNotFound, errors.New("no servers match the given selectors"))
+	}
+	return uuids, nil
+}
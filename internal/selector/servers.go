@@ -0,0 +1,273 @@
+package selector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	apierrors "go.lostcrafters.com/pelicanctl/internal/errors"
+	"go.lostcrafters.com/pelicanctl/internal/index"
+)
+
+// Stdin is the server argument and --from-file path that read server identifiers from stdin.
+const Stdin = "-"
+
+// Source lists the servers a bulk command can act on, from the API it goes through. It is only called
+// when the servers have to be known: for --all, globs, selector flags and --exclude.
+type Source func(ctx context.Context) ([]index.Entry, error)
+
+// Resolve adjusts a selector built from the flags before it is applied, e.g. to look up a node given
+// by name. It is only called when selector flags are given.
+type Resolve func(ctx context.Context, sel *Selector) error
+
+// AddTargetFlags adds the flags that list the servers of a bulk command besides its arguments:
+// --all, --from-file and --exclude, and the selector flags, see AddFlags.
+func AddTargetFlags(cmd *cobra.Command, withOwner bool) {
+	cmd.Flags().Bool("all", false, "operate on all servers")
+	cmd.Flags().String("from-file", "",
+		"read servers from a file, one ID, UUID, name or glob per line ('-' reads stdin)")
+	cmd.Flags().StringSlice("exclude", nil,
+		"skip these servers, by ID, UUID, name or glob (comma-separated, repeatable)")
+	AddFlags(cmd, withOwner)
+}
+
+// Servers returns the servers a bulk command acts on, from one of:
+//   - the selector flags, see FromFlags;
+//   - --all;
+//   - --from-file, one server per line;
+//   - the arguments, which may be comma-separated lists.
+//
+// Arguments and lines of --from-file can be globs, matched case-insensitively against the server names,
+// and a lone "-" reads them from stdin. Servers given with --exclude are dropped from the result.
+// Identifiers are returned as given; servers found through source are returned by UUID.
+func Servers(ctx context.Context, cmd *cobra.Command, args []string, source Source, resolve Resolve) ([]string, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+
+	servers := &sourceCache{source: source}
+	var identifiers []string
+	var err error
+	switch {
+	case Given(cmd):
+		if len(args) > 0 || fromFile != "" || all {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				errors.New("selectors cannot be combined with server arguments, --all or --from-file"))
+		}
+		identifiers, err = fromSelector(ctx, cmd, servers, resolve)
+	case all:
+		if len(args) > 0 || fromFile != "" {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				errors.New("--all cannot be combined with server arguments or --from-file"))
+		}
+		identifiers, err = fromAll(ctx, servers)
+	case fromFile != "":
+		if len(args) > 0 {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				errors.New("--from-file cannot be combined with server arguments"))
+		}
+		identifiers, err = fromFileLines(ctx, fromFile, servers)
+	case len(args) == 1 && args[0] == Stdin:
+		identifiers, err = fromFileLines(ctx, Stdin, servers)
+	default:
+		identifiers, err = expand(ctx, splitList(args), servers)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(exclude) == 0 || len(identifiers) == 0 {
+		return identifiers, nil
+	}
+	return excluded(ctx, identifiers, splitList(exclude), servers)
+}
+
+// sourceCache lists the servers of a source at most once.
+type sourceCache struct {
+	source  Source
+	entries []index.Entry
+	listed  bool
+}
+
+func (c *sourceCache) list(ctx context.Context) ([]index.Entry, error) {
+	if c.listed {
+		return c.entries, nil
+	}
+	if c.source == nil {
+		return nil, errors.New("servers cannot be listed for this command")
+	}
+	entries, err := c.source(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.entries, c.listed = entries, true
+	return entries, nil
+}
+
+func fromSelector(ctx context.Context, cmd *cobra.Command, servers *sourceCache, resolve Resolve) ([]string, error) {
+	sel, err := FromFlags(cmd)
+	if err != nil {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation, err)
+	}
+	if resolve != nil {
+		if err := resolve(ctx, &sel); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := servers.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sel.Select(entries)
+}
+
+func fromAll(ctx context.Context, servers *sourceCache) ([]string, error) {
+	entries, err := servers.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, apierrors.WithExitCode(apierrors.ExitNotFound, errors.New("no servers found"))
+	}
+	uuids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		uuids = append(uuids, entry.UUID)
+	}
+	return uuids, nil
+}
+
+func fromFileLines(ctx context.Context, fromFile string, servers *sourceCache) ([]string, error) {
+	var reader io.Reader = os.Stdin
+	if fromFile != Stdin {
+		file, err := os.Open(fromFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read servers: %w", err)
+	}
+	return expand(ctx, splitList(lines), servers)
+}
+
+// splitList splits comma-separated values, e.g. "123,456 789" given as two arguments, dropping empty ones.
+func splitList(values []string) []string {
+	var parts []string
+	for _, value := range values {
+		for part := range strings.SplitSeq(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return parts
+}
+
+// isGlob reports whether a server argument is a glob rather than an identifier.
+func isGlob(value string) bool {
+	return strings.ContainsAny(value, "*?[")
+}
+
+// expand replaces the globs among identifiers with the UUIDs of the servers whose name they match.
+func expand(ctx context.Context, identifiers []string, servers *sourceCache) ([]string, error) {
+	var expanded []string
+	for _, identifier := range identifiers {
+		if !isGlob(identifier) {
+			expanded = append(expanded, identifier)
+			continue
+		}
+		glob := strings.ToLower(identifier)
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+				fmt.Errorf("invalid server pattern %q: %w", identifier, err))
+		}
+		entries, err := servers.list(ctx)
+		if err != nil {
+			return nil, err
+		}
+		matched := false
+		for _, entry := range entries {
+			if ok, _ := path.Match(glob, strings.ToLower(entry.Name)); ok && !slices.Contains(expanded, entry.UUID) {
+				expanded = append(expanded, entry.UUID)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, apierrors.WithExitCode(apierrors.ExitNotFound,
+				fmt.Errorf("no servers match %q", identifier))
+		}
+	}
+	return expanded, nil
+}
+
+// excluded drops the servers matching any of the exclusions, given by identifier or glob.
+func excluded(ctx context.Context, identifiers, exclusions []string, servers *sourceCache) ([]string, error) {
+	for _, exclusion := range exclusions {
+		if isGlob(exclusion) {
+			if _, err := path.Match(strings.ToLower(exclusion), ""); err != nil {
+				return nil, apierrors.WithExitCode(apierrors.ExitValidation,
+					fmt.Errorf("invalid --exclude pattern %q: %w", exclusion, err))
+			}
+		}
+	}
+	// Identifiers and exclusions may name the same server differently, e.g. by UUID and by name,
+	// so the servers are listed unless every exclusion is among the identifiers as given.
+	var entries []index.Entry
+	if slices.ContainsFunc(exclusions, func(exclusion string) bool {
+		return isGlob(exclusion) || !slices.ContainsFunc(identifiers, func(identifier string) bool {
+			return strings.EqualFold(identifier, exclusion)
+		})
+	}) {
+		var err error
+		if entries, err = servers.list(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	kept := slices.DeleteFunc(slices.Clone(identifiers), func(identifier string) bool {
+		entry, found := findEntry(entries, identifier)
+		return slices.ContainsFunc(exclusions, func(exclusion string) bool {
+			if strings.EqualFold(identifier, exclusion) {
+				return true
+			}
+			if !found {
+				return false
+			}
+			if isGlob(exclusion) {
+				ok, _ := path.Match(strings.ToLower(exclusion), strings.ToLower(entry.Name))
+				return ok
+			}
+			return isEntry(entry, exclusion)
+		})
+	})
+	if len(kept) == 0 {
+		return nil, apierrors.WithExitCode(apierrors.ExitValidation, errors.New("every server is excluded"))
+	}
+	return kept, nil
+}
+
+// findEntry returns the server an identifier refers to.
+func findEntry(entries []index.Entry, identifier string) (index.Entry, bool) {
+	for _, entry := range entries {
+		if isEntry(entry, identifier) {
+			return entry, true
+		}
+	}
+	return index.Entry{}, false
+}
@@ -0,0 +1,275 @@
+// Package servertemplate stores reusable server creation payloads in the config directory.
+//
+// A template is the JSON data of `admin server create`, with {{placeholders}} in its string values
+// that are filled in with --set when a server is created from it:
+//
+//	{"name": "{{name}}", "egg": 5, "limits": {"memory": "{{memory}}", ...}}
+//
+// A string that is a single placeholder takes the type of its value, so "{{memory}}" set to 4096 is a number.
+// The startup command is left alone, since the panel fills in its {{VARIABLES}} itself.
+package servertemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.lostcrafters.com/pelicanctl/internal/config"
+)
+
+// ErrNotFound is returned for templates that are not saved.
+var ErrNotFound = errors.New("template not found")
+
+//nolint:gochecknoglobals // Compiled once, read-only
+var (
+	namePattern        = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+)
+
+// startupField is the server field whose {{VARIABLES}} the panel fills in from the egg variables.
+const startupField = "startup"
+
+// Template is a saved server creation payload.
+type Template struct {
+	Name         string         `json:"name"`
+	Placeholders []string       `json:"placeholders"`
+	Data         map[string]any `json:"data"`
+}
+
+// Save stores data as the template name, replacing an existing one only with overwrite.
+func Save(name string, data map[string]any, overwrite bool) error {
+	path, err := templatePath(name)
+	if err != nil {
+		return err
+	}
+	if _, statErr := os.Stat(path); statErr == nil && !overwrite {
+		return fmt.Errorf("template %q already exists", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create template directory: %w", err)
+	}
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := os.WriteFile(path, append(encoded, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
+
+// Load reads the template name.
+func Load(name string) (Template, error) {
+	path, err := templatePath(name)
+	if err != nil {
+		return Template{}, err
+	}
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Template{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return Template{}, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return Template{}, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return Template{Name: name, Placeholders: Placeholders(data), Data: data}, nil
+}
+
+// List returns the saved templates, sorted by name.
+func List() ([]Template, error) {
+	templateDir, err := dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(templateDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		tmpl, err := Load(name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// Delete removes the template name.
+func Delete(name string) error {
+	path, err := templatePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+// Placeholders returns the names of the placeholders in the string values of data, sorted.
+func Placeholders(data map[string]any) []string {
+	seen := map[string]bool{}
+	walkStrings(withoutStartup(data), func(s string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			seen[match[1]] = true
+		}
+	})
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render fills in the placeholders of the template with values. Every placeholder needs a value,
+// and every value a placeholder, so typos in --set are caught.
+func (t Template) Render(values map[string]string) (map[string]any, error) {
+	var missing []string
+	for _, name := range t.Placeholders {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s needs values for %s (use --set %s=<value>)",
+			t.Name, strings.Join(missing, ", "), missing[0])
+	}
+	for name := range values {
+		if !slices.Contains(t.Placeholders, name) {
+			return nil, fmt.Errorf("template %s has no placeholder %s (placeholders: %s)",
+				t.Name, name, strings.Join(t.Placeholders, ", "))
+		}
+	}
+
+	rendered, _ := render(withoutStartup(t.Data), values).(map[string]any)
+	if startup, ok := t.Data[startupField]; ok {
+		rendered[startupField] = startup
+	}
+	return rendered, nil
+}
+
+// ParseValues parses --set values of the form name=value.
+func ParseValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --set %q (must be name=value)", pair)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// render returns a copy of data with the placeholders filled in.
+func render(data any, values map[string]string) any {
+	switch v := data.(type) {
+	case map[string]any:
+		rendered := make(map[string]any, len(v))
+		for key, val := range v {
+			rendered[key] = render(val, values)
+		}
+		return rendered
+	case []any:
+		rendered := make([]any, len(v))
+		for i, val := range v {
+			rendered[i] = render(val, values)
+		}
+		return rendered
+	case string:
+		// A value that is a single placeholder takes the type of its value.
+		if match := placeholderPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			return typed(values[match[1]])
+		}
+		return placeholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			return values[placeholderPattern.FindStringSubmatch(placeholder)[1]]
+		})
+	default:
+		return v
+	}
+}
+
+// typed returns a value as a number or boolean if it reads as one, and else as a string.
+func typed(value string) any {
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number
+	}
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	return value
+}
+
+// withoutStartup returns data without its startup command.
+func withoutStartup(data map[string]any) map[string]any {
+	if _, ok := data[startupField]; !ok {
+		return data
+	}
+	stripped := maps.Clone(data)
+	delete(stripped, startupField)
+	return stripped
+}
+
+func walkStrings(data any, fn func(string)) {
+	switch v := data.(type) {
+	case map[string]any:
+		for _, val := range v {
+			walkStrings(val, fn)
+		}
+	case []any:
+		for _, val := range v {
+			walkStrings(val, fn)
+		}
+	case string:
+		fn(v)
+	}
+}
+
+// dir returns the directory templates are stored in.
+func dir() (string, error) {
+	configDir, err := config.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "templates"), nil
+}
+
+// templatePath returns the file of the template name.
+func templatePath(name string) (string, error) {
+	if !namePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid template name %q: use letters, digits, dots, dashes and underscores", name)
+	}
+	templateDir, err := dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(templateDir, name+".json"), nil
+}
@@ -0,0 +1,81 @@
+// Package strict implements strict mode for production automation.
+//
+// In strict mode pelicanctl never reads from the terminal, never falls back to
+// guessing (such as looking up a UUID from an integer ID or a database name),
+// and fails the command when any warning was emitted.
+package strict
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPromptDisabled is returned instead of prompting for input in strict mode.
+var ErrPromptDisabled = errors.New("interactive prompts are disabled in strict mode")
+
+var (
+	//nolint:gochecknoglobals // Strict mode is a process-wide setting made by the root command
+	enabled atomic.Bool
+
+	//nolint:gochecknoglobals // Warnings are collected from every package for the whole session
+	warnings []string
+
+	//nolint:gochecknoglobals // Global mutex needed to protect warnings
+	warningsMutex sync.Mutex
+)
+
+// Enable turns strict mode on for the rest of the process.
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled reports whether strict mode is on.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Prompt returns ErrPromptDisabled in strict mode and nil otherwise.
+// Call it before reading a confirmation or other input from the terminal;
+// hint tells the user how to provide the input non-interactively.
+func Prompt(hint string) error {
+	if Enabled() {
+		return fmt.Errorf("%w: %s", ErrPromptDisabled, hint)
+	}
+	return nil
+}
+
+// Lookup returns an error in strict mode, where identifiers must be given in the form
+// the API takes directly instead of being resolved through an extra listing.
+func Lookup(kind, identifier, want string) error {
+	if !Enabled() {
+		return nil
+	}
+	return fmt.Errorf("%s %q must be given as %s in strict mode (no lookups)", kind, identifier, want)
+}
+
+// Warn records a warning. In strict mode the command fails once it finishes if any were recorded.
+func Warn(msg string) {
+	if !Enabled() {
+		return
+	}
+	warningsMutex.Lock()
+	defer warningsMutex.Unlock()
+	warnings = append(warnings, msg)
+}
+
+// Err returns an error summarizing the recorded warnings, or nil if there were none.
+func Err() error {
+	warningsMutex.Lock()
+	defer warningsMutex.Unlock()
+
+	switch len(warnings) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("strict mode: warning treated as error: %s", warnings[0])
+	default:
+		return fmt.Errorf("strict mode: %d warnings treated as errors (first: %s)", len(warnings), warnings[0])
+	}
+}
@@ -0,0 +1,112 @@
+package timing
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxStoredCalls bounds the history kept on disk for `pelicanctl stats`.
+const maxStoredCalls = 2000
+
+// HistoryPath returns the file that stores calls from recent sessions.
+func HistoryPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "pelicanctl", "timings.jsonl"), nil
+}
+
+// Save appends calls to the history, keeping only the most recent maxStoredCalls.
+func Save(recorded []Call) error {
+	if len(recorded) == 0 {
+		return nil
+	}
+
+	history, err := Load()
+	if err != nil {
+		return err
+	}
+	history = append(history, recorded...)
+	if len(history) > maxStoredCalls {
+		history = history[len(history)-maxStoredCalls:]
+	}
+
+	path, err := HistoryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".timings-*")
+	if err != nil {
+		return fmt.Errorf("failed to write timing history: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, call := range history {
+		if err := encoder.Encode(call); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write timing history: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write timing history: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write timing history: %w", err)
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// Load reads the calls recorded by recent sessions. A missing history is empty.
+func Load() ([]Call, error) {
+	path, err := HistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read timing history: %w", err)
+	}
+	defer file.Close()
+
+	var history []Call
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var call Call
+		// Skip lines that don't parse rather than failing on a partially written history
+		if json.Unmarshal(scanner.Bytes(), &call) == nil {
+			history = append(history, call)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read timing history: %w", err)
+	}
+	return history, nil
+}
+
+// Reset deletes the stored history.
+func Reset() error {
+	path, err := HistoryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to reset timing history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package timing records API call latency so slow endpoints can be reported.
+package timing
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.lostcrafters.com/pelicanctl/internal/output"
+)
+
+// Call is a single recorded HTTP request.
+//
+// Duration is measured until the response headers arrive, so streamed bodies
+// (downloads, uploads) are not included in it.
+type Call struct {
+	Method   string        `json:"method"`
+	Host     string        `json:"host"`
+	Endpoint string        `json:"endpoint"`
+	Status   int           `json:"status"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+// EndpointStats aggregates the calls made to one endpoint.
+type EndpointStats struct {
+	Method   string        `json:"method"`
+	Host     string        `json:"host"`
+	Endpoint string        `json:"endpoint"`
+	Count    int           `json:"count"`
+	Errors   int           `json:"errors"`
+	Total    time.Duration `json:"total"`
+	Max      time.Duration `json:"max"`
+}
+
+// Average returns the mean duration of the calls.
+func (s EndpointStats) Average() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+//nolint:gochecknoglobals // Session-wide recorder shared by every API client
+var (
+	sessionStart = time.Now()
+	mu           sync.Mutex
+	calls        []Call
+)
+
+var (
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	numericSegment = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Transport is an http.RoundTripper that records every request it sends, and logs it at debug level.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.Base.RoundTrip(req)
+
+	call := Call{
+		Method:   req.Method,
+		Host:     req.URL.Host,
+		Endpoint: Endpoint(req.URL.Path),
+		Start:    start,
+		Duration: time.Since(start),
+	}
+	if resp != nil {
+		call.Status = resp.StatusCode
+	}
+	Record(call)
+
+	attrs := []any{"method", call.Method, "host", call.Host, "path", req.URL.Path, "status", call.Status,
+		"duration", call.Duration}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	output.LogDebug("api call", attrs...)
+
+	return resp, err //nolint:wrapcheck // Transport must pass errors through unchanged
+}
+
+// Record adds a call to the session. Old calls of long-running commands are dropped,
+// keeping at least the maxStoredCalls most recent ones that the history on disk holds.
+func Record(call Call) {
+	mu.Lock()
+	defer mu.Unlock()
+	calls = append(calls, call)
+	if len(calls) > 2*maxStoredCalls {
+		calls = append(calls[:0], calls[len(calls)-maxStoredCalls:]...)
+	}
+}
+
+// Calls returns the calls recorded in this session.
+func Calls() []Call {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Call(nil), calls...)
+}
+
+// Elapsed returns the time since the session started.
+func Elapsed() time.Duration {
+	return time.Since(sessionStart)
+}
+
+// Endpoint normalizes a request path so calls to the same endpoint group together,
+// replacing UUIDs with {uuid} and numeric IDs with {id}.
+func Endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case uuidSegment.MatchString(segment):
+			segments[i] = "{uuid}"
+		case numericSegment.MatchString(segment):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Aggregate groups calls by endpoint, ordered by total time spent, slowest first.
+func Aggregate(recorded []Call) []EndpointStats {
+	byKey := make(map[string]*EndpointStats)
+	var order []string
+	for _, call := range recorded {
+		key := call.Method + " " + call.Host + call.Endpoint
+		stats, ok := byKey[key]
+		if !ok {
+			stats = &EndpointStats{Method: call.Method, Host: call.Host, Endpoint: call.Endpoint}
+			byKey[key] = stats
+			order = append(order, key)
+		}
+		stats.Count++
+		stats.Total += call.Duration
+		stats.Max = max(stats.Max, call.Duration)
+		if call.Status == 0 || call.Status >= http.StatusBadRequest {
+			stats.Errors++
+		}
+	}
+
+	result := make([]EndpointStats, 0, len(order))
+	for _, key := range order {
+		result = append(result, *byKey[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Total > result[j].Total })
+	return result
+}
+
+// Slowest returns up to limit calls, slowest first.
+func Slowest(recorded []Call, limit int) []Call {
+	sorted := append([]Call(nil), recorded...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Duration > sorted[j].Duration })
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// BusyTime returns the wall-clock time during which at least one call was in flight.
+// Parallel calls overlap, so this is usually less than the sum of their durations.
+func BusyTime(recorded []Call) time.Duration {
+	sorted := append([]Call(nil), recorded...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var busy time.Duration
+	var spanStart, spanEnd time.Time
+	for _, call := range sorted {
+		end := call.Start.Add(call.Duration)
+		if call.Start.After(spanEnd) {
+			busy += spanEnd.Sub(spanStart)
+			spanStart, spanEnd = call.Start, end
+		} else if end.After(spanEnd) {
+			spanEnd = end
+		}
+	}
+	return busy + spanEnd.Sub(spanStart)
+}
+
+// Report writes a human-readable timing summary for the session.
+func Report(w io.Writer, recorded []Call, elapsed time.Duration, limit int) {
+	if len(recorded) == 0 {
+		return
+	}
+
+	busy := BusyTime(recorded)
+	local := max(elapsed-busy, 0)
+	_, _ = fmt.Fprintf(w, "\nAPI timing: %d call(s), %s waiting on the API, %s local (%s total)\n",
+		len(recorded), round(busy), round(local), round(elapsed))
+
+	hosts := make(map[string]time.Duration)
+	for _, call := range recorded {
+		hosts[call.Host] += call.Duration
+	}
+	if len(hosts) > 1 {
+		_, _ = fmt.Fprintln(w, "By host:")
+		for _, host := range sortedKeys(hosts) {
+			_, _ = fmt.Fprintf(w, "  %10s  %s\n", round(hosts[host]), host)
+		}
+	}
+
+	_, _ = fmt.Fprintln(w, "Slowest calls:")
+	for _, call := range Slowest(recorded, limit) {
+		_, _ = fmt.Fprintf(w, "  %10s  %-6s %s%s (%s)\n",
+			round(call.Duration), call.Method, call.Host, call.Endpoint, statusText(call.Status))
+	}
+
+	_, _ = fmt.Fprintln(w, "By endpoint:")
+	endpoints := Aggregate(recorded)
+	if limit > 0 && len(endpoints) > limit {
+		endpoints = endpoints[:limit]
+	}
+	for _, stats := range endpoints {
+		_, _ = fmt.Fprintf(w, "  %4dx  avg %8s  max %8s  %-6s %s\n",
+			stats.Count, round(stats.Average()), round(stats.Max), stats.Method, stats.Endpoint)
+	}
+}
+
+func sortedKeys(m map[string]time.Duration) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return m[keys[i]] > m[keys[j]] })
+	return keys
+}
+
+func statusText(status int) string {
+	if status == 0 {
+		return "failed"
+	}
+	return fmt.Sprintf("%d", status)
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
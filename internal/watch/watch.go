@@ -0,0 +1,104 @@
+// Package watch re-runs a view at an interval and redraws it, for a lightweight top-like display.
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// DefaultInterval is how often a watched view is refreshed unless --interval says otherwise.
+const DefaultInterval = 5 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// AddFlags adds --watch and --interval to a command.
+func AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("watch", false, "re-poll and redraw the output until interrupted")
+	cmd.Flags().Duration("interval", DefaultInterval, "with --watch, how often to refresh")
+}
+
+// Interval returns the refresh interval if --watch is set, or 0 if the command runs once.
+func Interval(cmd *cobra.Command) (time.Duration, error) {
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if !watch {
+		if cmd.Flags().Changed("interval") {
+			return 0, errors.New("--interval requires --watch")
+		}
+		return 0, nil
+	}
+	if interval <= 0 {
+		return 0, errors.New("--interval must be positive")
+	}
+	return interval, nil
+}
+
+// Title names a watched command in the header of its frames.
+func Title(cmd *cobra.Command, args []string) string {
+	return strings.Join(append([]string{cmd.CommandPath()}, args...), " ")
+}
+
+// Screen draws the frames of a continuously updated view.
+type Screen struct {
+	out    *os.File
+	title  string
+	redraw bool
+}
+
+// NewScreen creates a screen for a view named by title. On a terminal each frame replaces the previous
+// one under a header; elsewhere frames are written one after another, so JSON output becomes a stream.
+func NewScreen(out *os.File, title string) *Screen {
+	return &Screen{out: out, title: title, redraw: term.IsTerminal(int(out.Fd()))}
+}
+
+// Draw shows a frame, labelled in the header by mode, and the error that ended its rendering early, if any.
+func (s *Screen) Draw(mode string, frame *bytes.Buffer, err error) {
+	if s.redraw {
+		_, _ = fmt.Fprint(s.out, clearScreen)
+		_, _ = fmt.Fprintf(s.out, "%s: %s    %s\n\n", mode, s.title, time.Now().Format(time.DateTime))
+	}
+	_, _ = frame.WriteTo(s.out)
+	if err != nil {
+		// Off a terminal the error goes to stderr, keeping the output a clean stream.
+		errOut := os.Stderr
+		if s.redraw {
+			errOut = s.out
+		}
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+	}
+}
+
+// Run renders a view every interval until ctx is done, i.e. until interrupted.
+//
+// An error of the first frame is returned; later errors are shown in their frame and the view keeps refreshing.
+func Run(ctx context.Context, out *os.File, title string, interval time.Duration, render func(io.Writer) error) error {
+	screen := NewScreen(out, title)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		// Render into a buffer first, so the screen is only cleared once the next frame is ready.
+		var frame bytes.Buffer
+		err := render(&frame)
+		if err != nil && first {
+			return err
+		}
+		screen.Draw("Every "+interval.String(), &frame, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Package websocket is a minimal RFC 6455 websocket client for the Wings server console socket.
+//
+// It supports what the console protocol needs: text messages, ping/pong, and closing.
+// Extensions and subprotocols are not negotiated.
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // SHA-1 is mandated by the websocket handshake (RFC 6455)
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Opcodes of the frames used by the client.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+const (
+	// acceptGUID is appended to the handshake key to compute Sec-WebSocket-Accept.
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// maxMessageSize bounds a single message, so a broken peer cannot exhaust memory.
+	maxMessageSize = 16 << 20
+	// Payload length markers of the frame header.
+	payloadLen16 = 126
+	payloadLen64 = 127
+	// maxControlPayload is the largest payload of a control frame.
+	maxControlPayload = 125
+	// closeNormal is the status code of a normal closure.
+	closeNormal = 1000
+)
+
+// ErrClosed is returned by ReadMessage once the peer closed the connection.
+var ErrClosed = errors.New("websocket closed")
+
+// Conn is a client websocket connection.
+type Conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// Dial opens a websocket connection to a ws:// or wss:// URL, sending header with the handshake.
+func Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket URL: %w", err)
+	}
+
+	host := u.Host
+	secure := false
+	switch u.Scheme {
+	case "wss", "https":
+		secure = true
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	case "ws", "http":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported websocket URL scheme: %s", u.Scheme)
+	}
+
+	var conn net.Conn
+	if secure {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+
+	ws, err := handshake(ctx, conn, u, header)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// handshake upgrades an open connection to the websocket protocol.
+func handshake(ctx context.Context, conn net.Conn, u *url.URL, header http.Header) (*Conn, error) {
+	nonce := make([]byte, 16) //nolint:mnd // The handshake key is a random 16-byte value
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	// Only the path and host of the URL end up in the request written to the connection.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("failed to send websocket handshake: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read websocket handshake: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxControlPayload))
+		return nil, fmt.Errorf("websocket handshake failed: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	sum := sha1.Sum([]byte(key + acceptGUID)) //nolint:gosec // Mandated by RFC 6455
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+		return nil, errors.New("websocket handshake failed: invalid Sec-WebSocket-Accept")
+	}
+
+	return &Conn{conn: conn, reader: reader}, nil
+}
+
+// ReadMessage returns the next text or binary message, answering pings on the way.
+// It returns ErrClosed once the peer closed the connection.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			_ = c.writeFrame(opClose, payload)
+			return nil, ErrClosed
+		case opText, opBinary, opContinuation:
+			message = append(message, payload...)
+			if len(message) > maxMessageSize {
+				return nil, errors.New("websocket message too large")
+			}
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, fmt.Errorf("unexpected websocket opcode %d", opcode)
+		}
+	}
+}
+
+// WriteMessage sends a text message.
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// Close sends a close frame and closes the connection.
+func (c *Conn) Close() error {
+	payload := make([]byte, 2) //nolint:mnd // A close payload starts with a 2-byte status code
+	binary.BigEndian.PutUint16(payload, closeNormal)
+	_ = c.writeFrame(opClose, payload)
+	return c.conn.Close()
+}
+
+// readFrame reads a single frame. Frames from the server are never masked.
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case payloadLen16:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case payloadLen64:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageSize {
+		return false, 0, nil, errors.New("websocket frame too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single final frame. Frames from a client are always masked.
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	frame := []byte{0x80 | opcode}
+	length := len(payload)
+	switch {
+	case length <= maxControlPayload:
+		frame = append(frame, 0x80|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 0x80|payloadLen16)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, 0x80|payloadLen64)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
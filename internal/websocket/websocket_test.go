@@ -0,0 +1,256 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // SHA-1 is mandated by the websocket handshake (RFC 6455)
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testServer accepts one websocket connection and runs script on it. accept computes the
+// Sec-WebSocket-Accept answer to the key of the client.
+func testServer(t *testing.T, accept func(key string) string, script func(conn net.Conn, r *bufio.Reader)) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			t.Errorf("handshake headers = %v", r.Header)
+		}
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("response writer cannot be hijacked")
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + accept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+		if script != nil {
+			script(conn, rw.Reader)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func validAccept(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID)) //nolint:gosec // Mandated by RFC 6455
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// serverFrame encodes an unmasked frame, as sent by a server.
+func serverFrame(fin bool, opcode byte, payload []byte) []byte {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	switch length := len(payload); {
+	case length <= maxControlPayload:
+		frame = append(frame, byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, payloadLen16)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, payloadLen64)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+	return append(frame, payload...)
+}
+
+// readClientFrame reads a frame sent by the client, which must be final and masked.
+func readClientFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		t.Errorf("read client frame: %v", err)
+		return 0, nil
+	}
+	if head[0]&0x80 == 0 || head[1]&0x80 == 0 {
+		t.Errorf("client frame header %08b %08b: want final and masked", head[0], head[1])
+	}
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case payloadLen16:
+		var ext [2]byte
+		_, _ = io.ReadFull(r, ext[:])
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case payloadLen64:
+		var ext [8]byte
+		_, _ = io.ReadFull(r, ext[:])
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	var mask [4]byte
+	_, _ = io.ReadFull(r, mask[:])
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Errorf("read client payload: %v", err)
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return head[0] & 0x0F, payload
+}
+
+func dial(t *testing.T, url string) *Conn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := Dial(ctx, url, http.Header{"Origin": {"https://panel.example.com"}})
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.conn.Close() })
+	return conn
+}
+
+func TestDialHandshake(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  func(string) string
+		wantErr bool
+	}{
+		{"valid accept", validAccept, false},
+		{"wrong accept", func(string) string { return validAccept("other key") }, true},
+		{"missing accept", func(string) string { return "" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := testServer(t, tt.accept, nil)
+			conn, err := Dial(context.Background(), url, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Dial() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if conn != nil {
+				_ = conn.conn.Close()
+			}
+		})
+	}
+}
+
+func TestDialRejectedHandshake(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "token expired", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "token expired") {
+		t.Fatalf("Dial() error = %v, want the status and body of the response", err)
+	}
+}
+
+func TestReadMessageLengths(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+	}{
+		{"7-bit length", 100},
+		{"16-bit length", 300},
+		{"64-bit length", 70000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := bytes.Repeat([]byte("a"), tt.length)
+			url := testServer(t, validAccept, func(conn net.Conn, _ *bufio.Reader) {
+				_, _ = conn.Write(serverFrame(true, opText, payload))
+			})
+			got, err := dial(t, url).ReadMessage()
+			if err != nil {
+				t.Fatalf("ReadMessage() error = %v", err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Fatalf("ReadMessage() returned %d bytes, want %d", len(got), len(payload))
+			}
+		})
+	}
+}
+
+func TestReadMessageFragmented(t *testing.T) {
+	url := testServer(t, validAccept, func(conn net.Conn, _ *bufio.Reader) {
+		_, _ = conn.Write(serverFrame(false, opText, []byte(`{"event":`)))
+		_, _ = conn.Write(serverFrame(false, opContinuation, []byte(`"stats",`)))
+		_, _ = conn.Write(serverFrame(true, opContinuation, []byte(`"args":[]}`)))
+	})
+	got, err := dial(t, url).ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if want := `{"event":"stats","args":[]}`; string(got) != want {
+		t.Fatalf("ReadMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestReadMessageAnswersPing(t *testing.T) {
+	pong := make(chan []byte, 1)
+	url := testServer(t, validAccept, func(conn net.Conn, r *bufio.Reader) {
+		_, _ = conn.Write(serverFrame(true, opPing, []byte("ping-1")))
+		opcode, payload := readClientFrame(t, r)
+		if opcode != opPong {
+			t.Errorf("client answered ping with opcode %d, want pong", opcode)
+		}
+		pong <- payload
+		_, _ = conn.Write(serverFrame(true, opText, []byte("after ping")))
+	})
+	got, err := dial(t, url).ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if string(got) != "after ping" {
+		t.Fatalf("ReadMessage() = %q, want the message after the ping", got)
+	}
+	if payload := <-pong; string(payload) != "ping-1" {
+		t.Fatalf("pong payload = %q, want the payload of the ping", payload)
+	}
+}
+
+func TestReadMessageClose(t *testing.T) {
+	echoed := make(chan byte, 1)
+	url := testServer(t, validAccept, func(conn net.Conn, r *bufio.Reader) {
+		payload := binary.BigEndian.AppendUint16(nil, closeNormal)
+		_, _ = conn.Write(serverFrame(true, opClose, payload))
+		opcode, _ := readClientFrame(t, r)
+		echoed <- opcode
+	})
+	_, err := dial(t, url).ReadMessage()
+	if !errors.Is(err, ErrClosed) {
+		t.Fatalf("ReadMessage() error = %v, want ErrClosed", err)
+	}
+	if opcode := <-echoed; opcode != opClose {
+		t.Fatalf("client answered close with opcode %d, want close", opcode)
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	message := bytes.Repeat([]byte("b"), 300)
+	received := make(chan []byte, 1)
+	url := testServer(t, validAccept, func(_ net.Conn, r *bufio.Reader) {
+		opcode, payload := readClientFrame(t, r)
+		if opcode != opText {
+			t.Errorf("opcode = %d, want text", opcode)
+		}
+		received <- payload
+	})
+	if err := dial(t, url).WriteMessage(message); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	if got := <-received; !bytes.Equal(got, message) {
+		t.Fatalf("server received %d bytes, want %d", len(got), len(message))
+	}
+}